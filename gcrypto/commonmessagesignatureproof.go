@@ -1,6 +1,8 @@
 package gcrypto
 
 import (
+	"encoding/binary"
+
 	"github.com/bits-and-blooms/bitset"
 )
 
@@ -93,6 +95,20 @@ type CommonMessageSignatureProof interface {
 	AsSparse() SparseSignatureProof
 }
 
+// TrustedSparseMerger is an optional interface for a [CommonMessageSignatureProof]
+// to merge a sparse proof without verifying its signatures.
+//
+// The merged signatures are recorded as though they had been verified,
+// so this must only be used when the sparse proof comes from a source
+// that the caller trusts for reasons outside of the proof,
+// such as committed headers from a state sync provider.
+type TrustedSparseMerger interface {
+	// MergeTrustedSparse is like MergeSparse, but skips signature verification.
+	// Key IDs that do not map into the candidate keys are still rejected,
+	// setting AllValidSignatures to false in the result.
+	MergeTrustedSparse(SparseSignatureProof) SignatureProofMergeResult
+}
+
 // SparseSignatureProof is a minimal representation of a single signature proof.
 //
 // This format is suitable for network transmission,
@@ -123,6 +139,18 @@ type SparseSignature struct {
 	Sig []byte
 }
 
+// DecodeKeyIDUint16 decodes a [SparseSignature] key ID
+// formatted as a big endian uint16,
+// as used by the signature proof implementations in this module.
+// The boolean result is false, and the value is zero,
+// unless keyID is exactly two bytes long.
+func DecodeKeyIDUint16(keyID []byte) (uint16, bool) {
+	if len(keyID) != 2 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(keyID), true
+}
+
 // CommonMessageSignatureProofScheme indicates how to create
 // CommonMessageSignatureProof instances.
 //
@@ -134,6 +162,26 @@ type CommonMessageSignatureProofScheme interface {
 	// KeyIDChecker returns a KeyIDChecker that validates sparse signatures
 	// within the given set of public keys.
 	KeyIDChecker(keys []PubKey) KeyIDChecker
+
+	// ValidKeyIDWidth reports whether keyID has a width
+	// that the scheme could possibly produce,
+	// without any knowledge of the set of public keys.
+	// This is intended as an inexpensive filter for malformed sparse signatures,
+	// before creating a KeyIDChecker or consulting a full proof.
+	ValidKeyIDWidth(keyID []byte) bool
+
+	// SchemeID returns a byte identifying the scheme and its wire format version.
+	// Proofs produced by schemes with different IDs are not compatible.
+	// Zero means the scheme has not declared an ID.
+	//
+	// Each scheme documents its ID alongside its declaration.
+	// A scheme must change its ID if it changes its proof wire format.
+	//
+	// The ID is not currently stored alongside individual proofs,
+	// so proofs from an incompatible scheme are not rejected one by one.
+	// Instead, the engine includes the ID in its scheme fingerprint,
+	// which nodes compare to detect an incompatible peer before exchanging proofs.
+	SchemeID() byte
 }
 
 // KeyIDChecker reports whether a sparse signature's key ID
@@ -153,22 +201,43 @@ type KeyIDChecker interface {
 // This allows signature proof authors to follow the more common pattern
 // of returning the concrete types in their constructor functions,
 // without writing extra boilerplate to produce a corresponding scheme.
+//
+// The returned scheme's SchemeID is zero;
+// use [LiteralCommonMessageSignatureProofSchemeWithID] to declare an ID.
 func LiteralCommonMessageSignatureProofScheme[P CommonMessageSignatureProof](
 	newFn func([]byte, []PubKey, string) (P, error),
 	keyIDCheckerFn func([]PubKey) KeyIDChecker,
+	keyIDWidthFn func([]byte) bool,
+) CommonMessageSignatureProofScheme {
+	return LiteralCommonMessageSignatureProofSchemeWithID(0, newFn, keyIDCheckerFn, keyIDWidthFn)
+}
+
+// LiteralCommonMessageSignatureProofSchemeWithID is like [LiteralCommonMessageSignatureProofScheme],
+// but the returned scheme's SchemeID method returns schemeID.
+func LiteralCommonMessageSignatureProofSchemeWithID[P CommonMessageSignatureProof](
+	schemeID byte,
+	newFn func([]byte, []PubKey, string) (P, error),
+	keyIDCheckerFn func([]PubKey) KeyIDChecker,
+	keyIDWidthFn func([]byte) bool,
 ) CommonMessageSignatureProofScheme {
 	return literalCommonMessageSignatureProofScheme{
+		schemeID: schemeID,
 		newFn: func(msg []byte, candidateKeys []PubKey, pubKeyHash string) (CommonMessageSignatureProof, error) {
 			return newFn(msg, candidateKeys, pubKeyHash)
 		},
 		keyIDCheckerFn: keyIDCheckerFn,
+		keyIDWidthFn:   keyIDWidthFn,
 	}
 }
 
 type literalCommonMessageSignatureProofScheme struct {
+	schemeID byte
+
 	newFn func([]byte, []PubKey, string) (CommonMessageSignatureProof, error)
 
 	keyIDCheckerFn func([]PubKey) KeyIDChecker
+
+	keyIDWidthFn func([]byte) bool
 }
 
 func (s literalCommonMessageSignatureProofScheme) New(msg []byte, candidateKeys []PubKey, pubKeyHash string) (CommonMessageSignatureProof, error) {
@@ -178,3 +247,11 @@ func (s literalCommonMessageSignatureProofScheme) New(msg []byte, candidateKeys
 func (s literalCommonMessageSignatureProofScheme) KeyIDChecker(keys []PubKey) KeyIDChecker {
 	return s.keyIDCheckerFn(keys)
 }
+
+func (s literalCommonMessageSignatureProofScheme) ValidKeyIDWidth(keyID []byte) bool {
+	return s.keyIDWidthFn(keyID)
+}
+
+func (s literalCommonMessageSignatureProofScheme) SchemeID() byte {
+	return s.schemeID
+}
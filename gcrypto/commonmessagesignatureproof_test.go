@@ -0,0 +1,56 @@
+package gcrypto_test
+
+import (
+	"testing"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeKeyIDUint16(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		keyID []byte
+
+		want   uint16
+		wantOK bool
+	}{
+		{name: "nil", keyID: nil},
+		{name: "short", keyID: []byte{1}},
+		{name: "exact", keyID: []byte{1, 2}, want: 0x0102, wantOK: true},
+		{name: "exact max", keyID: []byte{0xff, 0xff}, want: 0xffff, wantOK: true},
+		{name: "oversized", keyID: []byte{0, 1, 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := gcrypto.DecodeKeyIDUint16(tc.keyID)
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestLiteralCommonMessageSignatureProofScheme_SchemeID(t *testing.T) {
+	t.Parallel()
+
+	keyIDChecker := func([]gcrypto.PubKey) gcrypto.KeyIDChecker { return nil }
+	keyIDWidth := func([]byte) bool { return true }
+
+	// Without a declared ID, the scheme reports zero.
+	s := gcrypto.LiteralCommonMessageSignatureProofScheme(
+		gcrypto.NewSimpleCommonMessageSignatureProof, keyIDChecker, keyIDWidth,
+	)
+	require.Zero(t, s.SchemeID())
+
+	s = gcrypto.LiteralCommonMessageSignatureProofSchemeWithID(
+		7, gcrypto.NewSimpleCommonMessageSignatureProof, keyIDChecker, keyIDWidth,
+	)
+	require.Equal(t, byte(7), s.SchemeID())
+
+	require.Equal(
+		t,
+		gcrypto.SimpleCommonMessageSignatureProofSchemeID,
+		gcrypto.SimpleCommonMessageSignatureProofScheme.SchemeID(),
+	)
+}
@@ -0,0 +1,145 @@
+package gblsminsig
+
+import (
+	"container/list"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/bits-and-blooms/bitset"
+)
+
+// AggregatedKeyCache is a bounded, concurrency-safe LRU cache
+// of aggregated public keys, keyed on a public key hash and a signer bitset.
+// It is used with [ValidateFinalizedProof] to avoid re-aggregating public keys
+// when the same proof is validated repeatedly.
+//
+// The aggregate of a full set of keys, used when every key signed,
+// is held separately from the aggregates of partial signer sets,
+// so that churn in partial signer sets does not evict it.
+type AggregatedKeyCache struct {
+	mu sync.Mutex
+
+	maxEntries int
+
+	// Front of the list is the most recently used entry.
+	order *list.List
+	elems map[string]*list.Element
+
+	// Aggregates of all keys, keyed on the public key hash alone.
+	fullSets map[string]PubKey
+}
+
+type aggKeyCacheEntry struct {
+	key string
+	agg PubKey
+}
+
+// NewAggregatedKeyCache returns a new AggregatedKeyCache
+// holding at most maxEntries aggregated keys.
+// NewAggregatedKeyCache panics if maxEntries is not positive.
+func NewAggregatedKeyCache(maxEntries int) *AggregatedKeyCache {
+	if maxEntries <= 0 {
+		panic(fmt.Errorf(
+			"NewAggregatedKeyCache: maxEntries must be positive (got %d)", maxEntries,
+		))
+	}
+
+	return &AggregatedKeyCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		elems:      make(map[string]*list.Element, maxEntries),
+
+		fullSets: make(map[string]PubKey),
+	}
+}
+
+// Get returns the cached aggregated key for pubKeyHash and signers,
+// marking it as most recently used if found.
+func (c *AggregatedKeyCache) Get(pubKeyHash string, signers *bitset.BitSet) (PubKey, bool) {
+	key := aggKeyCacheKey(pubKeyHash, signers)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.elems[key]
+	if !ok {
+		return PubKey{}, false
+	}
+
+	c.order.MoveToFront(e)
+	return e.Value.(aggKeyCacheEntry).agg, true
+}
+
+// Add stores agg as the aggregated key for pubKeyHash and signers,
+// evicting the least recently used entry if the cache is full.
+func (c *AggregatedKeyCache) Add(pubKeyHash string, signers *bitset.BitSet, agg PubKey) {
+	key := aggKeyCacheKey(pubKeyHash, signers)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.elems[key]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+
+	if c.order.Len() >= c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.elems, oldest.Value.(aggKeyCacheEntry).key)
+	}
+
+	c.elems[key] = c.order.PushFront(aggKeyCacheEntry{key: key, agg: agg})
+}
+
+// GetFullSet returns the cached aggregate of every key
+// identified by pubKeyHash.
+func (c *AggregatedKeyCache) GetFullSet(pubKeyHash string) (PubKey, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	agg, ok := c.fullSets[pubKeyHash]
+	return agg, ok
+}
+
+// AddFullSet stores agg as the aggregate of every key identified by pubKeyHash.
+// The cache holds at most maxEntries full set aggregates;
+// if it is full, an arbitrary full set aggregate is evicted.
+// Validator sets change rarely, so only a few full sets are expected to be live.
+func (c *AggregatedKeyCache) AddFullSet(pubKeyHash string, agg PubKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.fullSets[pubKeyHash]; ok {
+		return
+	}
+
+	if len(c.fullSets) >= c.maxEntries {
+		for k := range c.fullSets {
+			delete(c.fullSets, k)
+			break
+		}
+	}
+
+	c.fullSets[pubKeyHash] = agg
+}
+
+// aggKeyCacheKey returns the map key for the given public key hash and signers.
+// The hash is length-prefixed so that it cannot run into the bitset words.
+// Trailing zero words are omitted so that equal sets of signers
+// produce the same key regardless of the bitset's capacity.
+func aggKeyCacheKey(pubKeyHash string, signers *bitset.BitSet) string {
+	words := signers.Bytes()
+	for len(words) > 0 && words[len(words)-1] == 0 {
+		words = words[:len(words)-1]
+	}
+
+	b := make([]byte, 0, binary.MaxVarintLen64+len(pubKeyHash)+8*len(words))
+	b = binary.AppendUvarint(b, uint64(len(pubKeyHash)))
+	b = append(b, pubKeyHash...)
+	for _, w := range words {
+		b = binary.BigEndian.AppendUint64(b, w)
+	}
+	return string(b)
+}
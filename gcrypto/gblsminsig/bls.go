@@ -31,6 +31,15 @@ const keyTypeName = "bls-minsig"
 // Which only leaves the SC_TAG value, which is "NUL" for the basic scheme.
 var DomainSeparationTag = []byte("BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_")
 
+// SignatureLen is the length in bytes of every signature produced by [Signer],
+// which is a compressed P1 point.
+// Aggregated signatures in a [SignatureProof] are compressed P1 points too,
+// so their sparse signatures also have this length.
+//
+// A tmconsensus.SignatureScheme for validators using these keys
+// may report this value from its SignatureLen method.
+const SignatureLen = blst.BLST_P1_COMPRESS_BYTES
+
 // Register registers the BLS minimzed-signature key type with the given Registry.
 func Register(reg *gcrypto.Registry) {
 	reg.Register(keyTypeName, PubKey{}, NewPubKey)
@@ -91,3 +91,31 @@ func TestSignAndVerify_multiple(t *testing.T) {
 	finalSig[0]++
 	require.False(t, finalKey.Verify(msg, finalSig))
 }
+
+func TestSignatureLen(t *testing.T) {
+	t.Parallel()
+
+	// Minimized-signature BLS uses compressed G1 points for signatures.
+	require.Equal(t, 48, gblsminsig.SignatureLen)
+
+	msg := []byte("hello")
+	for i := range 3 {
+		sig, err := testSigners[i].Sign(context.Background(), msg)
+		require.NoError(t, err)
+		require.Len(t, sig, gblsminsig.SignatureLen)
+	}
+
+	// Aggregated sparse signatures have the same length.
+	proof, err := gblsminsig.NewSignatureProof(msg, testPubKeys[:], "hash")
+	require.NoError(t, err)
+	for i := range 3 {
+		sig, err := testSigners[i].Sign(context.Background(), msg)
+		require.NoError(t, err)
+		require.NoError(t, proof.AddSignature(sig, testPubKeys[i]))
+	}
+	sparse := proof.AsSparse().Signatures
+	require.NotEmpty(t, sparse)
+	for _, ss := range sparse {
+		require.Len(t, ss.Sig, gblsminsig.SignatureLen)
+	}
+}
@@ -0,0 +1,110 @@
+package gblsminsig
+
+import (
+	"github.com/bits-and-blooms/bitset"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+// ValidateFinalizedProof reports whether aggSig is a valid signature for msg,
+// aggregated from the signatures of every key in trustedKeys
+// whose index is set in signers.
+//
+// Unlike merging into a [SignatureProof], which verifies signatures as they arrive,
+// this validates a proof that has already been fully aggregated,
+// such as the proof attached to a committed header.
+//
+// Aggregating the signers' public keys is the dominant cost of validation
+// when the same proof is validated repeatedly.
+// If cache is not nil, the aggregated key is read from and stored in the cache,
+// keyed on pubKeyHash and signers.
+// The caller must ensure that pubKeyHash uniquely identifies trustedKeys.
+//
+// When every trusted key signed, which is common on chains with low validator churn,
+// the signers' aggregate is the aggregate of the full key set.
+// In that case the aggregate is looked up by pubKeyHash alone,
+// with [*AggregatedKeyCache.GetFullSet], skipping the signer-specific cache key.
+func ValidateFinalizedProof(
+	msg []byte,
+	trustedKeys []PubKey,
+	pubKeyHash string,
+	signers *bitset.BitSet,
+	aggSig []byte,
+	cache *AggregatedKeyCache,
+) bool {
+	if signers == nil || signers.None() {
+		return false
+	}
+
+	if allSigned(len(trustedKeys), signers) {
+		return validateAllSigned(msg, trustedKeys, pubKeyHash, signers, aggSig, cache)
+	}
+
+	if cache != nil {
+		if key, ok := cache.Get(pubKeyHash, signers); ok {
+			return key.Verify(msg, aggSig)
+		}
+	}
+
+	key, ok := aggregateSignerKeys(trustedKeys, signers)
+	if !ok {
+		return false
+	}
+
+	if cache != nil {
+		cache.Add(pubKeyHash, signers, key)
+	}
+
+	return key.Verify(msg, aggSig)
+}
+
+// allSigned reports whether signers holds exactly the indices of all nKeys keys.
+func allSigned(nKeys int, signers *bitset.BitSet) bool {
+	if signers.Count() != uint(nKeys) {
+		return false
+	}
+
+	_, beyond := signers.NextSet(uint(nKeys))
+	return !beyond
+}
+
+// validateAllSigned is the fast path of [ValidateFinalizedProof]
+// for when every key in trustedKeys signed.
+func validateAllSigned(
+	msg []byte,
+	trustedKeys []PubKey,
+	pubKeyHash string,
+	signers *bitset.BitSet,
+	aggSig []byte,
+	cache *AggregatedKeyCache,
+) bool {
+	if cache != nil {
+		if key, ok := cache.GetFullSet(pubKeyHash); ok {
+			return key.Verify(msg, aggSig)
+		}
+	}
+
+	// Every index in signers is already known to be in range.
+	key, _ := aggregateSignerKeys(trustedKeys, signers)
+
+	if cache != nil {
+		cache.AddFullSet(pubKeyHash, key)
+	}
+
+	return key.Verify(msg, aggSig)
+}
+
+// aggregateSignerKeys returns the aggregate of the keys whose index is set in signers.
+// It reports false if signers refers to an index outside of keys.
+func aggregateSignerKeys(keys []PubKey, signers *bitset.BitSet) (PubKey, bool) {
+	agg := new(blst.P2)
+	for i, ok := signers.NextSet(0); ok; i, ok = signers.NextSet(i + 1) {
+		if i >= uint(len(keys)) {
+			return PubKey{}, false
+		}
+
+		k := blst.P2Affine(keys[i])
+		agg = agg.Add(&k)
+	}
+
+	return PubKey(*agg.ToAffine()), true
+}
@@ -0,0 +1,242 @@
+package gblsminsig_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/gcrypto/gblsminsig"
+	"github.com/stretchr/testify/require"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+// finalizedProofFixture returns the aggregated signature over msg
+// from every test signer whose index is set in signers.
+func finalizedProofFixture(t testing.TB, msg []byte, signers *bitset.BitSet) []byte {
+	t.Helper()
+
+	agg := new(blst.P1)
+	for i, ok := signers.NextSet(0); ok; i, ok = signers.NextSet(i + 1) {
+		sig, err := testSigners[i].Sign(context.Background(), msg)
+		require.NoError(t, err)
+
+		p1 := new(blst.P1Affine).Uncompress(sig)
+		agg = agg.Add(p1)
+	}
+
+	return agg.ToAffine().Compress()
+}
+
+func TestValidateFinalizedProof(t *testing.T) {
+	t.Parallel()
+
+	msg := []byte("hello")
+	const hash = "fake_hash"
+
+	signers := bitset.New(uint(len(testPubKeys)))
+	for _, i := range []uint{0, 1, 2, 5, 8, 13} {
+		signers.Set(i)
+	}
+	aggSig := finalizedProofFixture(t, msg, signers)
+
+	t.Run("without cache", func(t *testing.T) {
+		t.Parallel()
+
+		require.True(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:], hash, signers, aggSig, nil,
+		))
+		require.False(t, gblsminsig.ValidateFinalizedProof(
+			[]byte("goodbye"), testPubKeys[:], hash, signers, aggSig, nil,
+		))
+
+		// Claiming an extra signer must fail.
+		extra := signers.Clone()
+		extra.Set(3)
+		require.False(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:], hash, extra, aggSig, nil,
+		))
+
+		// Empty signers never validate.
+		require.False(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:], hash, bitset.New(uint(len(testPubKeys))), aggSig, nil,
+		))
+
+		// Signer index outside of the trusted keys.
+		require.False(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:4], hash, signers, aggSig, nil,
+		))
+	})
+
+	t.Run("with cache", func(t *testing.T) {
+		t.Parallel()
+
+		cache := gblsminsig.NewAggregatedKeyCache(4)
+
+		_, ok := cache.Get(hash, signers)
+		require.False(t, ok)
+
+		require.True(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:], hash, signers, aggSig, cache,
+		))
+
+		_, ok = cache.Get(hash, signers)
+		require.True(t, ok)
+
+		// Validating again uses the cached key, and still checks the signature.
+		require.True(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:], hash, signers, aggSig, cache,
+		))
+		require.False(t, gblsminsig.ValidateFinalizedProof(
+			[]byte("goodbye"), testPubKeys[:], hash, signers, aggSig, cache,
+		))
+
+		// A different key hash is a different cache entry.
+		_, ok = cache.Get("other_hash", signers)
+		require.False(t, ok)
+	})
+}
+
+func TestAggregatedKeyCache_eviction(t *testing.T) {
+	t.Parallel()
+
+	cache := gblsminsig.NewAggregatedKeyCache(2)
+
+	bs := make([]*bitset.BitSet, 3)
+	for i := range bs {
+		bs[i] = bitset.New(uint(len(testPubKeys)))
+		bs[i].Set(uint(i))
+		cache.Add("hash", bs[i], testPubKeys[i])
+	}
+
+	_, ok := cache.Get("hash", bs[0])
+	require.False(t, ok)
+
+	got, ok := cache.Get("hash", bs[2])
+	require.True(t, ok)
+	require.True(t, got.Equal(testPubKeys[2]))
+
+	// Equal signer sets with a different capacity share a cache entry.
+	wide := bitset.New(1024)
+	wide.Set(1)
+	got, ok = cache.Get("hash", wide)
+	require.True(t, ok)
+	require.True(t, got.Equal(testPubKeys[1]))
+}
+
+func TestValidateFinalizedProof_allSigned(t *testing.T) {
+	t.Parallel()
+
+	msg := []byte("hello")
+	const hash = "fake_hash"
+
+	signers := bitset.New(uint(len(testPubKeys)))
+	for i := range len(testPubKeys) {
+		signers.Set(uint(i))
+	}
+	aggSig := finalizedProofFixture(t, msg, signers)
+
+	t.Run("without cache", func(t *testing.T) {
+		t.Parallel()
+
+		require.True(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:], hash, signers, aggSig, nil,
+		))
+		require.False(t, gblsminsig.ValidateFinalizedProof(
+			[]byte("goodbye"), testPubKeys[:], hash, signers, aggSig, nil,
+		))
+
+		// Claiming every signer with a signature missing one signer must fail.
+		partial := signers.Clone()
+		partial.Clear(3)
+		partialSig := finalizedProofFixture(t, msg, partial)
+		require.False(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:], hash, signers, partialSig, nil,
+		))
+
+		// The right number of signers, but one outside of the trusted keys,
+		// is not the all-signed case and must fail.
+		shifted := bitset.New(uint(len(testPubKeys)))
+		for i := range 8 {
+			shifted.Set(uint(i))
+		}
+		shifted.Clear(7)
+		shifted.Set(9)
+		require.False(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:8], hash, shifted, aggSig, nil,
+		))
+	})
+
+	t.Run("with cache", func(t *testing.T) {
+		t.Parallel()
+
+		cache := gblsminsig.NewAggregatedKeyCache(4)
+
+		_, ok := cache.GetFullSet(hash)
+		require.False(t, ok)
+
+		require.True(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:], hash, signers, aggSig, cache,
+		))
+
+		// The full set aggregate is cached by key hash alone.
+		_, ok = cache.GetFullSet(hash)
+		require.True(t, ok)
+		_, ok = cache.Get(hash, signers)
+		require.False(t, ok)
+
+		// Validating again uses the cached key, and still checks the signature.
+		require.True(t, gblsminsig.ValidateFinalizedProof(
+			msg, testPubKeys[:], hash, signers, aggSig, cache,
+		))
+		require.False(t, gblsminsig.ValidateFinalizedProof(
+			[]byte("goodbye"), testPubKeys[:], hash, signers, aggSig, cache,
+		))
+	})
+}
+
+func BenchmarkValidateFinalizedProof(b *testing.B) {
+	msg := []byte("hello")
+	const hash = "fake_hash"
+
+	// All but one signer, so the aggregation is not a single subtree
+	// and validation takes the general path.
+	someSigners := bitset.New(uint(len(testPubKeys)))
+	for i := range len(testPubKeys) - 1 {
+		someSigners.Set(uint(i))
+	}
+	someSig := finalizedProofFixture(b, msg, someSigners)
+
+	// Every signer, so validation takes the all-signed path.
+	allSigners := someSigners.Clone()
+	allSigners.Set(uint(len(testPubKeys) - 1))
+	allSig := finalizedProofFixture(b, msg, allSigners)
+
+	for _, tc := range []struct {
+		name    string
+		signers *bitset.BitSet
+		aggSig  []byte
+	}{
+		{name: "general", signers: someSigners, aggSig: someSig},
+		{name: "all signed", signers: allSigners, aggSig: allSig},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.Run("without cache", func(b *testing.B) {
+				for range b.N {
+					if !gblsminsig.ValidateFinalizedProof(msg, testPubKeys[:], hash, tc.signers, tc.aggSig, nil) {
+						b.Fatal("proof failed validation")
+					}
+				}
+			})
+
+			b.Run("with cache", func(b *testing.B) {
+				cache := gblsminsig.NewAggregatedKeyCache(8)
+
+				for range b.N {
+					if !gblsminsig.ValidateFinalizedProof(msg, testPubKeys[:], hash, tc.signers, tc.aggSig, cache) {
+						b.Fatal("proof failed validation")
+					}
+				}
+			})
+		})
+	}
+}
@@ -107,6 +107,60 @@ func (t Tree) Index(k blst.P2Affine) int {
 	return -1
 }
 
+// IsValidIndex reports whether idx refers to a node
+// in a tree of nKeys unaggregated keys,
+// without requiring the tree to be constructed.
+//
+// Leaf indices are valid if they are less than nKeys.
+// Aggregated node indices are valid if their subtree
+// contains at least one unaggregated key;
+// nodes consisting entirely of padding are not valid.
+func IsValidIndex(nKeys, idx int) bool {
+	if nKeys < 1 || idx < 0 {
+		return false
+	}
+
+	var layerWidth int
+	if nKeys&(nKeys-1) == 0 {
+		// Already a power of two, so just use that value directly.
+		layerWidth = nKeys
+	} else {
+		layerWidth = 1 << (bits.Len16(uint16(nKeys)))
+	}
+
+	layerStart := 0
+	nLeaves := 1
+	for layerWidth > 0 {
+		if idx < layerStart+layerWidth {
+			// The node is valid if its leftmost leaf is a real key.
+			return (idx-layerStart)*nLeaves < nKeys
+		}
+
+		layerStart += layerWidth
+		layerWidth >>= 1
+		nLeaves <<= 1
+	}
+
+	// Index was beyond the root.
+	return false
+}
+
+// RootIndex returns the index of the root node
+// in a tree of nKeys unaggregated keys,
+// without requiring the tree to be constructed.
+// The root's key is the aggregate of every key in the tree.
+func RootIndex(nKeys int) int {
+	var leavesWidth int
+	if nKeys&(nKeys-1) == 0 {
+		// Already a power of two, so just use that value directly.
+		leavesWidth = nKeys
+	} else {
+		leavesWidth = 1 << (bits.Len16(uint16(nKeys)))
+	}
+
+	return 2*leavesWidth - 2
+}
+
 // Get returns the key and signature at the given index.
 // The ok value indicates whether the index was in bounds.
 // The key is guaranteed to be set if ok is true,
@@ -297,6 +297,57 @@ func TestTree_AddSignature_withPadding(t *testing.T) {
 	require.True(t, tree.SigBits.Test(2))
 }
 
+func TestIsValidIndex(t *testing.T) {
+	t.Parallel()
+
+	// Same layout as the withPadding test:
+	//   0 1 2 (3)
+	//    4   (5)
+	//      6
+	for idx, exp := range []bool{true, true, true, false, true, true, true} {
+		require.Equalf(t, exp, sigtree.IsValidIndex(3, idx), "index %d", idx)
+	}
+	require.False(t, sigtree.IsValidIndex(3, -1))
+	require.False(t, sigtree.IsValidIndex(3, 7))
+
+	// 5 keys, padded to 8 leaves:
+	//   0 1 2 3 4 (5) (6) (7)
+	//    8   9   10   (11)
+	//     12       13
+	//          14
+	for idx, exp := range []bool{
+		true, true, true, true, true, false, false, false,
+		true, true, true, false,
+		true, true,
+		true,
+	} {
+		require.Equalf(t, exp, sigtree.IsValidIndex(5, idx), "index %d", idx)
+	}
+	require.False(t, sigtree.IsValidIndex(5, 15))
+}
+
+func TestRootIndex(t *testing.T) {
+	t.Parallel()
+
+	for nKeys, exp := range map[int]int{
+		1: 0,
+		2: 2,
+		3: 6,
+		4: 6,
+		5: 14,
+		8: 14,
+		9: 30,
+	} {
+		require.Equalf(t, exp, sigtree.RootIndex(nKeys), "nKeys %d", nKeys)
+
+		tree := sigtree.New(keysSeq(nKeys), nKeys)
+		_, _, ok := tree.Get(exp)
+		require.True(t, ok)
+		_, _, ok = tree.Get(exp + 1)
+		require.False(t, ok)
+	}
+}
+
 func TestTree_SparseIndices(t *testing.T) {
 	t.Parallel()
 
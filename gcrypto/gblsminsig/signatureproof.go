@@ -5,6 +5,8 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math/bits"
+	"slices"
 
 	"github.com/bits-and-blooms/bitset"
 	"github.com/gordian-engine/gordian/gcrypto"
@@ -12,6 +14,42 @@ import (
 	blst "github.com/supranational/blst/bindings/go"
 )
 
+// SignatureProofSchemeID is the value returned by SignatureProofScheme.SchemeID.
+const SignatureProofSchemeID byte = 2
+
+// SignatureProofScheme is the [gcrypto.CommonMessageSignatureProofScheme]
+// for [SignatureProof].
+// Its SchemeID is [SignatureProofSchemeID].
+//
+// The scheme's KeyIDChecker accepts key IDs for aggregated nodes of the signature tree,
+// in addition to key IDs for individual keys.
+// This allows a peer to send a pre-aggregated sparse signature for a subtree,
+// which is verified against the corresponding aggregated key
+// when it is merged into a SignatureProof.
+var SignatureProofScheme gcrypto.CommonMessageSignatureProofScheme = gcrypto.LiteralCommonMessageSignatureProofSchemeWithID(
+	SignatureProofSchemeID,
+	func(msg []byte, candidateKeys []gcrypto.PubKey, pubKeyHash string) (SignatureProof, error) {
+		keys := make([]PubKey, len(candidateKeys))
+		for i, k := range candidateKeys {
+			pk, ok := k.(PubKey)
+			if !ok {
+				return SignatureProof{}, fmt.Errorf(
+					"expected type gblsminsig.PubKey for key at index %d, got %T", i, k,
+				)
+			}
+			keys[i] = pk
+		}
+		return NewSignatureProof(msg, keys, pubKeyHash)
+	},
+	func(keys []gcrypto.PubKey) gcrypto.KeyIDChecker {
+		return treeKeyIDChecker{nKeys: len(keys)}
+	},
+	func(keyID []byte) bool {
+		// Key IDs are big endian uint16 indices into the signature tree.
+		return len(keyID) == 2
+	},
+)
+
 // SignatureProof is an implementation of [gcrypto.CommonMessageSignatureProof]
 // for the BLS keys and signatures in this package.
 //
@@ -58,13 +96,14 @@ func NewSignatureProof(msg []byte, trustedKeys []PubKey, pubKeyHash string) (Sig
 		keyIdxs[string(k.PubKeyBytes())] = i
 	}
 
+	paddedKeys, nKeys := PadKeys(trustedKeys)
 	sigTree := sigtree.New(func(yield func(blst.P2Affine) bool) {
-		for _, key := range trustedKeys {
+		for _, key := range paddedKeys {
 			if !yield(blst.P2Affine(key)) {
 				return
 			}
 		}
-	}, len(trustedKeys))
+	}, nKeys)
 
 	return SignatureProof{
 		msg: msg,
@@ -75,6 +114,34 @@ func NewSignatureProof(msg []byte, trustedKeys []PubKey, pubKeyHash string) (Sig
 	}, nil
 }
 
+// PadKeys returns a copy of keys extended to the next power of two,
+// along with the number of real keys (that is, len(keys)).
+// If len(keys) is already a power of two, or if keys is empty,
+// the returned slice has the same length as keys.
+//
+// The padding entries are the zero PubKey,
+// which is the point at infinity in G2.
+// No private key corresponds to the point at infinity,
+// so a padding entry can never contribute a valid signature,
+// and every party derives identical padding from the same set of keys.
+// Aggregating a key with the point at infinity yields the key unchanged,
+// so a subtree whose right half is only padding
+// has the same aggregated key as its left half.
+//
+// [NewSignatureProof] pads its keys with PadKeys
+// to lay out the signature tree.
+func PadKeys(keys []PubKey) ([]PubKey, int) {
+	n := len(keys)
+	if n == 0 || n&(n-1) == 0 {
+		return slices.Clone(keys), n
+	}
+
+	padded := make([]PubKey, 1<<bits.Len(uint(n)))
+	copy(padded, keys)
+	// The remaining entries are already the zero PubKey.
+	return padded, n
+}
+
 func (p SignatureProof) Message() []byte {
 	return p.msg
 }
@@ -203,6 +270,16 @@ func (p SignatureProof) Merge(other gcrypto.CommonMessageSignatureProof) gcrypto
 }
 
 func (p SignatureProof) MergeSparse(s gcrypto.SparseSignatureProof) gcrypto.SignatureProofMergeResult {
+	return p.mergeSparse(s, true)
+}
+
+// MergeTrustedSparse implements [gcrypto.TrustedSparseMerger].
+// Signatures that cannot be decoded are still rejected.
+func (p SignatureProof) MergeTrustedSparse(s gcrypto.SparseSignatureProof) gcrypto.SignatureProofMergeResult {
+	return p.mergeSparse(s, false)
+}
+
+func (p SignatureProof) mergeSparse(s gcrypto.SparseSignatureProof, verify bool) gcrypto.SignatureProofMergeResult {
 	if s.PubKeyHash != p.keyHash {
 		// Unmergeable.
 		return gcrypto.SignatureProofMergeResult{}
@@ -219,13 +296,20 @@ func (p SignatureProof) MergeSparse(s gcrypto.SparseSignatureProof) gcrypto.Sign
 	countBefore := p.sigTree.SigBits.Count()
 
 	for _, ss := range s.Signatures {
-		if len(ss.KeyID) != 2 {
+		u, ok := gcrypto.DecodeKeyIDUint16(ss.KeyID)
+		if !ok {
 			// Maybe this should just return due to the input being malformed?
 			res.AllValidSignatures = false
 			continue
 		}
 
-		id := int(binary.BigEndian.Uint16(ss.KeyID))
+		// The key ID may refer to a leaf or to an aggregated subtree;
+		// either way, the signature is verified against the key at that node.
+		id := int(u)
+		if !sigtree.IsValidIndex(p.sigTree.NUnaggregatedKeys(), id) {
+			res.AllValidSignatures = false
+			continue
+		}
 		haveKey, haveSig, ok := p.sigTree.Get(id)
 		if !ok {
 			res.AllValidSignatures = false
@@ -233,16 +317,22 @@ func (p SignatureProof) MergeSparse(s gcrypto.SparseSignatureProof) gcrypto.Sign
 		}
 
 		if haveSig == (blst.P1Affine{}) {
-			// We didn't have this signature, so we need to verify it.
-			if !PubKey(haveKey).Verify(p.msg, ss.Sig) {
+			// We didn't have this signature, so we need to verify it,
+			// unless the caller trusts the sparse proof.
+			if verify && !PubKey(haveKey).Verify(p.msg, ss.Sig) {
 				res.AllValidSignatures = false
 				continue
 			}
 
-			// It verified, so add it to ours.
+			// It verified or is trusted, so add it to ours.
 			// Check the count before and after to determine whether this increased our signatures.
 			sig := new(blst.P1Affine)
 			sig = sig.Uncompress(ss.Sig)
+			if sig == nil {
+				// Only reachable without verification.
+				res.AllValidSignatures = false
+				continue
+			}
 			p.sigTree.AddSignature(id, *sig)
 			if p.sigTree.SigBits.Count() > countBefore {
 				res.IncreasedSignatures = true
@@ -267,10 +357,14 @@ func (p SignatureProof) MergeSparse(s gcrypto.SparseSignatureProof) gcrypto.Sign
 // If the key ID does not properly map into the set of trusted public keys,
 // the "valid" return parameter will be false.
 func (p SignatureProof) HasSparseKeyID(keyID []byte) (has, valid bool) {
-	if len(keyID) != 2 {
+	u, ok := gcrypto.DecodeKeyIDUint16(keyID)
+	if !ok {
+		return false, false
+	}
+	id := int(u)
+	if !sigtree.IsValidIndex(p.sigTree.NUnaggregatedKeys(), id) {
 		return false, false
 	}
-	id := int(binary.BigEndian.Uint16(keyID))
 	_, sig, ok := p.sigTree.Get(id)
 	if !ok {
 		return false, false
@@ -319,3 +413,37 @@ func (p SignatureProof) Derive() gcrypto.CommonMessageSignatureProof {
 func (p SignatureProof) SignatureBitSet(dst *bitset.BitSet) {
 	p.sigTree.SigBits.CopyFull(dst)
 }
+
+// treeKeyIDChecker is the [gcrypto.KeyIDChecker] for [SignatureProofScheme].
+// It accepts big endian uint16 key IDs
+// that refer to any non-padding node in the signature tree.
+type treeKeyIDChecker struct {
+	nKeys int
+}
+
+func (c treeKeyIDChecker) IsValid(keyID []byte) bool {
+	u, ok := gcrypto.DecodeKeyIDUint16(keyID)
+	return ok && sigtree.IsValidIndex(c.nKeys, int(u))
+}
+
+// ValidAggregateKeyIDs returns, in ascending order, every key ID
+// accepted by the [SignatureProofScheme] KeyIDChecker for nKeys keys,
+// as signature tree node indices.
+// That is the index of each individual key,
+// followed by the indices of the aggregated subtrees
+// that contain at least one key.
+// The byte form of each key ID is the index as a big endian uint16.
+func ValidAggregateKeyIDs(nKeys int) []uint16 {
+	if nKeys < 1 {
+		return nil
+	}
+
+	root := sigtree.RootIndex(nKeys)
+	ids := make([]uint16, 0, root+1)
+	for idx := range root + 1 {
+		if sigtree.IsValidIndex(nKeys, idx) {
+			ids = append(ids, uint16(idx))
+		}
+	}
+	return ids
+}
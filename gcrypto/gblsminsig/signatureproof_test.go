@@ -2,9 +2,12 @@ package gblsminsig_test
 
 import (
 	"context"
+	"encoding/binary"
+	"slices"
 	"testing"
 
 	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/gcrypto"
 	"github.com/gordian-engine/gordian/gcrypto/gblsminsig"
 	"github.com/stretchr/testify/require"
 	blst "github.com/supranational/blst/bindings/go"
@@ -142,6 +145,38 @@ func TestSignatureProof_MergeSparse_disjoint(t *testing.T) {
 	require.True(t, bs0.Test(2))
 }
 
+func TestSignatureProof_MergeTrustedSparse(t *testing.T) {
+	t.Parallel()
+
+	const hash = "fake_hash"
+	proof, err := gblsminsig.NewSignatureProof([]byte("hello"), testPubKeys[:], hash)
+	require.NoError(t, err)
+
+	// A well-formed signature over a different message.
+	otherProof, err := gblsminsig.NewSignatureProof([]byte("goodbye"), testPubKeys[:], hash)
+	require.NoError(t, err)
+
+	sig2, err := testSigners[2].Sign(context.Background(), []byte("goodbye"))
+	require.NoError(t, err)
+	require.NoError(t, otherProof.AddSignature(sig2, testPubKeys[2]))
+
+	sparse := otherProof.AsSparse()
+
+	// Verification rejects the signature.
+	res := proof.Clone().MergeSparse(sparse)
+	require.False(t, res.AllValidSignatures)
+
+	// But a trusted merge accepts it without verification.
+	res = proof.MergeTrustedSparse(sparse)
+	require.True(t, res.AllValidSignatures)
+	require.True(t, res.IncreasedSignatures)
+
+	var bs bitset.BitSet
+	proof.SignatureBitSet(&bs)
+	require.Equal(t, uint(1), bs.Count())
+	require.True(t, bs.Test(2))
+}
+
 func TestSignatureProof_HasSparseKeyID(t *testing.T) {
 	t.Parallel()
 
@@ -181,3 +216,219 @@ func TestSignatureProof_HasSparseKeyID(t *testing.T) {
 	require.True(t, valid)
 	require.True(t, has)
 }
+
+func TestPadKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pads to next power of two", func(t *testing.T) {
+		t.Parallel()
+
+		padded, n := gblsminsig.PadKeys(testPubKeys[:5])
+		require.Equal(t, 5, n)
+		require.Len(t, padded, 8)
+
+		require.Equal(t, testPubKeys[:5], padded[:5])
+
+		for _, p := range padded[5:] {
+			require.Equal(t, gblsminsig.PubKey{}, p)
+
+			// The padding is the point at infinity,
+			// which is rejected as a public key.
+			pb := p.PubKeyBytes()
+			require.Equal(t, byte(0xc0), pb[0])
+			_, err := gblsminsig.NewPubKey(pb)
+			require.Error(t, err)
+		}
+
+		// Padding the same keys again gives identical results.
+		again, _ := gblsminsig.PadKeys(testPubKeys[:5])
+		require.Equal(t, padded, again)
+	})
+
+	t.Run("power of two is not padded", func(t *testing.T) {
+		t.Parallel()
+
+		padded, n := gblsminsig.PadKeys(testPubKeys[:8])
+		require.Equal(t, 8, n)
+		require.Equal(t, testPubKeys[:8], padded)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		padded, n := gblsminsig.PadKeys(nil)
+		require.Zero(t, n)
+		require.Empty(t, padded)
+	})
+}
+
+func TestSignatureProofScheme_SchemeID(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, gblsminsig.SignatureProofSchemeID, gblsminsig.SignatureProofScheme.SchemeID())
+	require.Equal(t, byte(2), gblsminsig.SignatureProofScheme.SchemeID())
+
+	// Must not collide with the simple scheme.
+	require.NotEqual(
+		t,
+		gcrypto.SimpleCommonMessageSignatureProofScheme.SchemeID(),
+		gblsminsig.SignatureProofScheme.SchemeID(),
+	)
+}
+
+func TestSignatureProofScheme_ValidKeyIDWidth(t *testing.T) {
+	t.Parallel()
+
+	s := gblsminsig.SignatureProofScheme
+
+	require.True(t, s.ValidKeyIDWidth([]byte{0, 0}))
+	require.True(t, s.ValidKeyIDWidth([]byte{0xff, 0xff}))
+
+	require.False(t, s.ValidKeyIDWidth(nil))
+	require.False(t, s.ValidKeyIDWidth([]byte{}))
+	require.False(t, s.ValidKeyIDWidth([]byte{1}))
+	require.False(t, s.ValidKeyIDWidth([]byte{0, 0, 1}))
+	require.False(t, s.ValidKeyIDWidth(make([]byte, 32)))
+
+	// Every key ID in an actual sparse proof has a valid width.
+	msg := []byte("hello")
+	proof, err := gblsminsig.NewSignatureProof(msg, testPubKeys[:], "hash")
+	require.NoError(t, err)
+	for i := range 3 {
+		sig, err := testSigners[i].Sign(context.Background(), msg)
+		require.NoError(t, err)
+		require.NoError(t, proof.AddSignature(sig, testPubKeys[i]))
+	}
+	for _, ss := range proof.AsSparse().Signatures {
+		require.True(t, s.ValidKeyIDWidth(ss.KeyID))
+	}
+}
+
+func TestSignatureProofScheme_preAggregated(t *testing.T) {
+	t.Parallel()
+
+	msg := []byte("hello")
+
+	const hash = "fake_hash"
+
+	keys := make([]gcrypto.PubKey, len(testPubKeys))
+	for i, k := range testPubKeys {
+		keys[i] = k
+	}
+
+	ctx := context.Background()
+
+	sig0, err := testSigners[0].Sign(ctx, msg)
+	require.NoError(t, err)
+	sig1, err := testSigners[1].Sign(ctx, msg)
+	require.NoError(t, err)
+
+	// The peer's proof has signatures for both 0 and 1,
+	// so its sparse representation is the single A+B aggregate.
+	peerProof, err := gblsminsig.NewSignatureProof(msg, testPubKeys[:], hash)
+	require.NoError(t, err)
+	require.NoError(t, peerProof.AddSignature(sig0, testPubKeys[0]))
+	require.NoError(t, peerProof.AddSignature(sig1, testPubKeys[1]))
+
+	sp := peerProof.AsSparse()
+	require.Len(t, sp.Signatures, 1)
+	aggKeyID := sp.Signatures[0].KeyID
+	require.Equal(t, []byte{0, 16}, aggKeyID)
+
+	// The scheme accepts the aggregated key ID before any full proof exists.
+	checker := gblsminsig.SignatureProofScheme.KeyIDChecker(keys)
+	require.True(t, checker.IsValid(aggKeyID))
+
+	t.Run("valid aggregate is accepted", func(t *testing.T) {
+		t.Parallel()
+
+		proof, err := gblsminsig.SignatureProofScheme.New(msg, keys, hash)
+		require.NoError(t, err)
+
+		has, valid := proof.HasSparseKeyID(aggKeyID)
+		require.True(t, valid)
+		require.False(t, has)
+
+		res := proof.MergeSparse(sp)
+		require.True(t, res.AllValidSignatures)
+		require.True(t, res.IncreasedSignatures)
+
+		var bs bitset.BitSet
+		proof.SignatureBitSet(&bs)
+		require.Equal(t, uint(2), bs.Count())
+		require.True(t, bs.Test(0))
+		require.True(t, bs.Test(1))
+
+		has, valid = proof.HasSparseKeyID(aggKeyID)
+		require.True(t, valid)
+		require.True(t, has)
+	})
+
+	t.Run("aggregate with mismatched signature is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		proof, err := gblsminsig.SignatureProofScheme.New(msg, keys, hash)
+		require.NoError(t, err)
+
+		// Only signer 0's signature, claiming to be the 0+1 aggregate.
+		res := proof.MergeSparse(gcrypto.SparseSignatureProof{
+			PubKeyHash: hash,
+			Signatures: []gcrypto.SparseSignature{
+				{KeyID: aggKeyID, Sig: sig0},
+			},
+		})
+		require.False(t, res.AllValidSignatures)
+		require.False(t, res.IncreasedSignatures)
+
+		var bs bitset.BitSet
+		proof.SignatureBitSet(&bs)
+		require.Zero(t, bs.Count())
+	})
+
+	t.Run("padding nodes are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// 3 keys are padded to 4 leaves, so index 3 is padding.
+		checker := gblsminsig.SignatureProofScheme.KeyIDChecker(keys[:3])
+		require.True(t, checker.IsValid([]byte{0, 2}))
+		require.False(t, checker.IsValid([]byte{0, 3}))
+		require.True(t, checker.IsValid([]byte{0, 5}))
+		require.False(t, checker.IsValid([]byte{0, 7}))
+	})
+}
+
+func TestValidAggregateKeyIDs(t *testing.T) {
+	t.Parallel()
+
+	// Tree layout for 4 keys:
+	//   0 1 2 3
+	//    4   5
+	//      6
+	require.Equal(t, []uint16{0, 1, 2, 3, 4, 5, 6}, gblsminsig.ValidAggregateKeyIDs(4))
+
+	// Tree layout for 3 keys, where 3 is only padding:
+	//   0 1 2 (3)
+	//    4   5
+	//      6
+	require.Equal(t, []uint16{0, 1, 2, 4, 5, 6}, gblsminsig.ValidAggregateKeyIDs(3))
+
+	require.Equal(t, []uint16{0}, gblsminsig.ValidAggregateKeyIDs(1))
+	require.Empty(t, gblsminsig.ValidAggregateKeyIDs(0))
+
+	// Every returned ID is accepted by the scheme's key ID checker,
+	// and every other ID up to the root is rejected.
+	for _, nKeys := range []int{1, 2, 3, 4, 5, 11, 16} {
+		checker := gblsminsig.SignatureProofScheme.KeyIDChecker(
+			make([]gcrypto.PubKey, nKeys),
+		)
+
+		ids := gblsminsig.ValidAggregateKeyIDs(nKeys)
+		for id := range uint16(2 * nKeys * 2) {
+			keyID := binary.BigEndian.AppendUint16(nil, id)
+			require.Equalf(
+				t, slices.Contains(ids, id), checker.IsValid(keyID),
+				"nKeys %d, id %d", nKeys, id,
+			)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package gcryptotest
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
@@ -386,6 +387,23 @@ func TestCommonMessageSignatureProofCompliance_Ed25519(
 				require.Equal(t, orig, got)
 			}
 		})
+
+		t.Run("key IDs have valid width", func(t *testing.T) {
+			t.Parallel()
+
+			p, err := s.New(hello, []gcrypto.PubKey{edPubKey1, edPubKey2, edPubKey3, edPubKey4}, "myhash")
+			require.NoError(t, err)
+
+			require.NoError(t, p.AddSignature(helloSig1, edPubKey1))
+			require.NoError(t, p.AddSignature(helloSig3, edPubKey3))
+
+			for _, ss := range p.AsSparse().Signatures {
+				require.True(t, s.ValidKeyIDWidth(ss.KeyID))
+			}
+
+			// No scheme can identify keys with an empty key ID.
+			require.False(t, s.ValidKeyIDWidth(nil))
+		})
 	})
 
 	t.Run("MergeSparse", func(t *testing.T) {
@@ -580,4 +598,102 @@ func TestCommonMessageSignatureProofCompliance_Ed25519(
 			})
 		})
 	})
+
+	t.Run("MergeTrustedSparse", func(t *testing.T) {
+		t.Run("merges without verifying signatures", func(t *testing.T) {
+			t.Parallel()
+
+			p1, err := s.New(hello, []gcrypto.PubKey{edPubKey1, edPubKey2}, "myhash")
+			require.NoError(t, err)
+
+			require.NoError(t, p1.AddSignature(helloSig1, edPubKey1))
+			require.NoError(t, p1.AddSignature(helloSig2, edPubKey2))
+
+			sparse := p1.AsSparse()
+
+			// Corrupt every signature, which MergeSparse would reject.
+			for i := range sparse.Signatures {
+				sig := bytes.Clone(sparse.Signatures[i].Sig)
+				sig[0]++
+				sparse.Signatures[i].Sig = sig
+			}
+
+			p2, err := s.New(hello, []gcrypto.PubKey{edPubKey1, edPubKey2}, "myhash")
+			require.NoError(t, err)
+
+			tm, ok := p2.(gcrypto.TrustedSparseMerger)
+			if !ok {
+				t.Skipf("%T does not implement gcrypto.TrustedSparseMerger", p2)
+			}
+
+			res := tm.MergeTrustedSparse(sparse)
+			require.True(t, res.AllValidSignatures)
+			require.True(t, res.IncreasedSignatures)
+
+			var bs bitset.BitSet
+			p2.SignatureBitSet(&bs)
+			require.Equal(t, uint(2), bs.Count())
+			require.True(t, bs.Test(0))
+			require.True(t, bs.Test(1))
+		})
+
+		t.Run("key ID out of bounds is rejected", func(t *testing.T) {
+			t.Parallel()
+
+			// p1 has 3 keys.
+			p1, err := s.New(hello, []gcrypto.PubKey{edPubKey1, edPubKey2, edPubKey3}, "myhash")
+			require.NoError(t, err)
+
+			require.NoError(t, p1.AddSignature(helloSig1, edPubKey1))
+			require.NoError(t, p1.AddSignature(helloSig3, edPubKey3))
+
+			sparse := p1.AsSparse()
+
+			// p2 has only 2 keys.
+			p2, err := s.New(hello, []gcrypto.PubKey{edPubKey1, edPubKey2}, "myhash")
+			require.NoError(t, err)
+
+			tm, ok := p2.(gcrypto.TrustedSparseMerger)
+			if !ok {
+				t.Skipf("%T does not implement gcrypto.TrustedSparseMerger", p2)
+			}
+
+			sparse.PubKeyHash = string(p2.PubKeyHash())
+
+			res := tm.MergeTrustedSparse(sparse)
+			require.False(t, res.AllValidSignatures)
+
+			var bs bitset.BitSet
+			p2.SignatureBitSet(&bs)
+			require.Equal(t, uint(1), bs.Count())
+			require.True(t, bs.Test(0))
+		})
+
+		t.Run("wrong pub key hash is ignored", func(t *testing.T) {
+			t.Parallel()
+
+			p1, err := s.New(hello, []gcrypto.PubKey{edPubKey1, edPubKey2}, "myhash")
+			require.NoError(t, err)
+
+			require.NoError(t, p1.AddSignature(helloSig1, edPubKey1))
+
+			sparse := p1.AsSparse()
+
+			p2, err := s.New(hello, []gcrypto.PubKey{edPubKey1, edPubKey2}, "otherhash")
+			require.NoError(t, err)
+
+			tm, ok := p2.(gcrypto.TrustedSparseMerger)
+			if !ok {
+				t.Skipf("%T does not implement gcrypto.TrustedSparseMerger", p2)
+			}
+
+			res := tm.MergeTrustedSparse(sparse)
+			require.False(t, res.AllValidSignatures)
+			require.False(t, res.IncreasedSignatures)
+
+			var bs bitset.BitSet
+			p2.SignatureBitSet(&bs)
+			require.Zero(t, bs.Count())
+		})
+	})
 }
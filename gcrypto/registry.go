@@ -60,6 +60,9 @@ func (r *Registry) Marshal(pubKey PubKey) []byte {
 // therefore the slice must not be modified after calling Unmarshal.
 func (r *Registry) Unmarshal(b []byte) (PubKey, error) {
 	// TODO: more validation against b
+	if len(b) < prefixSize {
+		return nil, fmt.Errorf("input too short to contain public key type prefix (length %d)", len(b))
+	}
 	prefix := bytes.TrimRight(b[:prefixSize], "\x00")
 
 	fn := r.byPrefix[string(prefix)]
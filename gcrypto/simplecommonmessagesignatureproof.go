@@ -10,14 +10,23 @@ import (
 	"github.com/bits-and-blooms/bitset"
 )
 
+// SimpleCommonMessageSignatureProofSchemeID is the value returned by
+// SimpleCommonMessageSignatureProofScheme.SchemeID.
+const SimpleCommonMessageSignatureProofSchemeID byte = 1
+
 // SimpleCommonMessageSignatureProofScheme is the scheme for a SimpleCommonMessageSignatureProof.
-var SimpleCommonMessageSignatureProofScheme CommonMessageSignatureProofScheme = LiteralCommonMessageSignatureProofScheme(
+var SimpleCommonMessageSignatureProofScheme CommonMessageSignatureProofScheme = LiteralCommonMessageSignatureProofSchemeWithID(
+	SimpleCommonMessageSignatureProofSchemeID,
 	NewSimpleCommonMessageSignatureProof,
 	func(keys []PubKey) KeyIDChecker {
 		return beUint16KeyLenIDChecker{
 			nKeys: len(keys),
 		}
 	},
+	func(keyID []byte) bool {
+		// Key IDs are big endian uint16 indices into the candidate keys.
+		return len(keyID) == 2
+	},
 )
 
 // SimpleCommonMessageSignatureProof is the simplest signature proof,
@@ -210,6 +219,15 @@ func (p SimpleCommonMessageSignatureProof) AsSparse() SparseSignatureProof {
 }
 
 func (p SimpleCommonMessageSignatureProof) MergeSparse(s SparseSignatureProof) SignatureProofMergeResult {
+	return p.mergeSparse(s, true)
+}
+
+// MergeTrustedSparse implements [TrustedSparseMerger].
+func (p SimpleCommonMessageSignatureProof) MergeTrustedSparse(s SparseSignatureProof) SignatureProofMergeResult {
+	return p.mergeSparse(s, false)
+}
+
+func (p SimpleCommonMessageSignatureProof) mergeSparse(s SparseSignatureProof, verify bool) SignatureProofMergeResult {
 	if p.keyHash != s.PubKeyHash {
 		return SignatureProofMergeResult{}
 	}
@@ -228,17 +246,23 @@ func (p SimpleCommonMessageSignatureProof) MergeSparse(s SparseSignatureProof) S
 	for _, sparseSig := range s.Signatures {
 		// Assuming the index can be represented in a 16 bit integer.
 		// This type is certainly not intended to support 32k public keys.
-		n := int(binary.BigEndian.Uint16(sparseSig.KeyID))
+		u, ok := DecodeKeyIDUint16(sparseSig.KeyID)
+		n := int(u)
 
-		if n < 0 || n >= len(p.keys) {
+		if !ok || n >= len(p.keys) {
 			res.AllValidSignatures = false
 			continue
 		}
 		key := p.keys[n]
 
-		if err := p.AddSignature(sparseSig.Sig, key); err != nil {
-			res.AllValidSignatures = false
-			continue
+		if verify {
+			if err := p.AddSignature(sparseSig.Sig, key); err != nil {
+				res.AllValidSignatures = false
+				continue
+			}
+		} else {
+			p.sigs[string(sparseSig.Sig)] = key
+			p.bitset.Set(uint(n))
 		}
 
 		addedBS.Set(uint(n))
@@ -253,14 +277,14 @@ func (p SimpleCommonMessageSignatureProof) MergeSparse(s SparseSignatureProof) S
 }
 
 func (p SimpleCommonMessageSignatureProof) HasSparseKeyID(keyID []byte) (has, valid bool) {
-	if len(keyID) != 2 {
+	u, ok := DecodeKeyIDUint16(keyID)
+	if !ok {
 		// Invalid because the key IDs must be a big endian uint16.
 		return false, false
 	}
 
-	u := binary.BigEndian.Uint16(keyID)
 	idx := int(u)
-	if idx < 0 || idx >= len(p.keys) {
+	if idx >= len(p.keys) {
 		// Key ID must be in range to be valid.
 		return false, false
 	}
@@ -278,12 +302,6 @@ type beUint16KeyLenIDChecker struct {
 }
 
 func (c beUint16KeyLenIDChecker) IsValid(keyID []byte) bool {
-	if len(keyID) != 2 {
-		return false
-	}
-
-	u := binary.BigEndian.Uint16(keyID)
-	idx := int(u)
-
-	return idx >= 0 && idx < c.nKeys
+	u, ok := DecodeKeyIDUint16(keyID)
+	return ok && int(u) < c.nKeys
 }
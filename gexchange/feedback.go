@@ -36,4 +36,10 @@ const (
 	// The message will not be propagated,
 	// and no future messages will be sent to that peer.
 	FeedbackRejectAndDisconnect
+
+	// FeedbackTooLarge indicates that the message exceeded a configured size limit,
+	// and so it was not inspected.
+	// The message will not be propagated.
+	// The p2p layer should penalize the sender as it would for FeedbackRejected.
+	FeedbackTooLarge
 )
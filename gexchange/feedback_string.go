@@ -13,11 +13,12 @@ func _() {
 	_ = x[FeedbackRejected-2]
 	_ = x[FeedbackIgnored-3]
 	_ = x[FeedbackRejectAndDisconnect-4]
+	_ = x[FeedbackTooLarge-5]
 }
 
-const _Feedback_name = "UnspecifiedAcceptedRejectedIgnoredRejectAndDisconnect"
+const _Feedback_name = "UnspecifiedAcceptedRejectedIgnoredRejectAndDisconnectTooLarge"
 
-var _Feedback_index = [...]uint8{0, 11, 19, 27, 34, 53}
+var _Feedback_index = [...]uint8{0, 11, 19, 27, 34, 53, 61}
 
 func (i Feedback) String() string {
 	if i >= Feedback(len(_Feedback_index)-1) {
@@ -3,6 +3,7 @@ package tmcodectest
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 	"github.com/gordian-engine/gordian/tm/tmcodec"
@@ -114,6 +115,21 @@ func TestMarshalCodecCompliance(t *testing.T, mcf MarshalCodecFactory) {
 						}
 					})
 
+					t.Run("proposed header with timestamp", func(t *testing.T) {
+						ph, _ := getPH()
+						ph.Header.Timestamp = time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+						fx.RecalculateHash(&ph.Header)
+						fx.SignProposal(ctx, &ph, 0)
+
+						b, err := mc.MarshalProposedHeader(ph)
+						require.NoError(t, err)
+
+						var got tmconsensus.ProposedHeader
+						require.NoError(t, mc.UnmarshalProposedHeader(b, &got))
+
+						require.Equal(t, ph, got)
+					})
+
 					t.Run("replayed proposed header", func(t *testing.T) {
 						for _, ac := range tmconsensustest.AnnotationCombinations() {
 							ac := ac
@@ -2,6 +2,7 @@ package tmjson
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
@@ -126,6 +127,9 @@ type jsonHeader struct {
 	DataID           []byte
 	PrevAppStateHash []byte
 
+	// Zero value when the header has no timestamp.
+	Timestamp time.Time
+
 	UserAnnotation, DriverAnnotation []byte
 }
 
@@ -196,6 +200,8 @@ func (jh jsonHeader) ToHeader(
 		DataID:           jh.DataID,
 		PrevAppStateHash: jh.PrevAppStateHash,
 
+		Timestamp: jh.Timestamp,
+
 		Annotations: tmconsensus.Annotations{
 			User:   jh.UserAnnotation,
 			Driver: jh.DriverAnnotation,
@@ -225,6 +231,8 @@ func toJSONHeader(b tmconsensus.Header, reg *gcrypto.Registry) jsonHeader {
 		DataID:           b.DataID,
 		PrevAppStateHash: b.PrevAppStateHash,
 
+		Timestamp: b.Timestamp,
+
 		UserAnnotation:   b.Annotations.User,
 		DriverAnnotation: b.Annotations.Driver,
 	}
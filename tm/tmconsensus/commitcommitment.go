@@ -0,0 +1,80 @@
+package tmconsensus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+)
+
+// commitCommitmentDomain separates commit commitments
+// from any other content passed to [contentDigest].
+const commitCommitmentDomain = "gordian-commit-commitment-v1\x00"
+
+// CommitCommitment returns a deterministic hash
+// over the committed header ch and the proof that it was committed,
+// suitable for a verifier on another chain to record as a compact commitment.
+// The commitment is the SHA-256 digest described in [contentDigest],
+// over a canonical encoding of the header's height and block hash and of the proof.
+//
+// The header is represented by its block hash according to hs,
+// so every field that hs includes in the block hash is committed.
+// An error is returned if hs fails to hash the header,
+// or if ch.Header.Hash is set and does not match the calculated hash.
+//
+// The proof is encoded in a canonical form,
+// independent of the iteration order of the proof map
+// and of the order of signatures for each block hash.
+// Unlike [VersionedRoundView.ContentHash], the signatures themselves are included,
+// because a foreign verifier must be able to check them against the commitment.
+func CommitCommitment(ch CommittedHeader, hs HashScheme) ([]byte, error) {
+	blockHash, err := hs.Block(ch.Header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate block hash: %w", err)
+	}
+	if len(ch.Header.Hash) > 0 && !bytes.Equal(blockHash, ch.Header.Hash) {
+		return nil, fmt.Errorf(
+			"header hash %x does not match calculated block hash %x",
+			ch.Header.Hash, blockHash,
+		)
+	}
+
+	var h bytes.Buffer
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], ch.Header.Height)
+	_, _ = h.Write(buf[:])
+	writeLenPrefixed(&h, blockHash)
+
+	binary.BigEndian.PutUint32(buf[:4], ch.Proof.Round)
+	_, _ = h.Write(buf[:4])
+	writeLenPrefixed(&h, []byte(ch.Proof.PubKeyHash))
+
+	proofHashes := make([]string, 0, len(ch.Proof.Proofs))
+	for hash := range ch.Proof.Proofs {
+		proofHashes = append(proofHashes, hash)
+	}
+	slices.Sort(proofHashes)
+
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(proofHashes)))
+	_, _ = h.Write(buf[:4])
+	for _, hash := range proofHashes {
+		writeLenPrefixed(&h, []byte(hash))
+
+		sigs := slices.Clone(ch.Proof.Proofs[hash])
+		slices.SortFunc(sigs, func(a, b gcrypto.SparseSignature) int {
+			return bytes.Compare(a.KeyID, b.KeyID)
+		})
+
+		binary.BigEndian.PutUint32(buf[:4], uint32(len(sigs)))
+		_, _ = h.Write(buf[:4])
+		for _, sig := range sigs {
+			writeLenPrefixed(&h, sig.KeyID)
+			writeLenPrefixed(&h, sig.Sig)
+		}
+	}
+
+	return contentDigest(commitCommitmentDomain, h.Bytes()), nil
+}
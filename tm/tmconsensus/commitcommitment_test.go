@@ -0,0 +1,102 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"slices"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitCommitment(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fx := tmconsensustest.NewStandardFixture(4)
+
+	ph := fx.NextProposedHeader([]byte("app_data_1"), 0)
+	blockHash := string(ph.Header.Hash)
+	precommits := fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+		blockHash: {0, 1, 2},
+		"":        {3},
+	})
+	ch := tmconsensus.CommittedHeader{
+		Header: ph.Header,
+		Proof: tmconsensus.CommitProof{
+			Round:      0,
+			PubKeyHash: string(ph.Header.ValidatorSet.PubKeyHash),
+			Proofs:     tmconsensus.FullProofsToSparse(precommits).BlockSignatures,
+		},
+	}
+
+	want, err := tmconsensus.CommitCommitment(ch, fx.HashScheme)
+	require.NoError(t, err)
+	require.NotEmpty(t, want)
+
+	t.Run("stable across calls and signature order", func(t *testing.T) {
+		got, err := tmconsensus.CommitCommitment(ch, fx.HashScheme)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+
+		reordered := ch.Proof.Clone()
+		slices.Reverse(reordered.Proofs[blockHash])
+		got, err = tmconsensus.CommitCommitment(tmconsensus.CommittedHeader{
+			Header: ch.Header,
+			Proof:  reordered,
+		}, fx.HashScheme)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	})
+
+	t.Run("changes with the proof", func(t *testing.T) {
+		fewer := ch.Proof.Clone()
+		fewer.Proofs[blockHash] = fewer.Proofs[blockHash][1:]
+		got, err := tmconsensus.CommitCommitment(tmconsensus.CommittedHeader{
+			Header: ch.Header,
+			Proof:  fewer,
+		}, fx.HashScheme)
+		require.NoError(t, err)
+		require.NotEqual(t, want, got)
+
+		otherRound := ch.Proof.Clone()
+		otherRound.Round = 1
+		got, err = tmconsensus.CommitCommitment(tmconsensus.CommittedHeader{
+			Header: ch.Header,
+			Proof:  otherRound,
+		}, fx.HashScheme)
+		require.NoError(t, err)
+		require.NotEqual(t, want, got)
+	})
+
+	t.Run("fixed digest over the block hash from the given scheme", func(t *testing.T) {
+		require.Len(t, want, sha256.Size)
+
+		// The prefix scheme calculates a different block hash,
+		// so the header must not carry the simple scheme's hash.
+		unhashed := ch.Header
+		unhashed.Hash = nil
+		got, err := tmconsensus.CommitCommitment(tmconsensus.CommittedHeader{
+			Header: unhashed,
+			Proof:  ch.Proof,
+		}, prefixHashScheme{})
+		require.NoError(t, err)
+		require.Len(t, got, sha256.Size)
+		require.NotEqual(t, want, got)
+
+		_, err = tmconsensus.CommitCommitment(ch, failingHashScheme{})
+		require.Error(t, err)
+	})
+
+	t.Run("mismatched header hash", func(t *testing.T) {
+		badHeader := ch.Header
+		badHeader.Hash = []byte("not_the_block_hash")
+		_, err := tmconsensus.CommitCommitment(tmconsensus.CommittedHeader{
+			Header: badHeader,
+			Proof:  ch.Proof,
+		}, fx.HashScheme)
+		require.Error(t, err)
+	})
+}
@@ -3,6 +3,7 @@ package tmconsensus
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 // Proposal is the data an application needs to provide,
@@ -14,6 +15,10 @@ type Proposal struct {
 
 	// Respectively sets [ProposedBlock.Annotations] and [Block.Annotations].
 	ProposalAnnotations, BlockAnnotations Annotations
+
+	// Sets [Header.Timestamp].
+	// Leave as the zero value if the chain does not use header timestamps.
+	Timestamp time.Time
 }
 
 // ConsiderProposedBlocksReason is an argument in [ConsensusStrategy.ConsiderProposedBlocks].
@@ -28,7 +33,7 @@ type ConsiderProposedBlocksReason struct {
 	// Any data IDs that have been marked as updated
 	// since the previous call to ConsensusStrategy.
 	// The data IDs are provided in an arbitrary order.
-	UpdatedBlockDataIDs []string
+	UpdatedBlockDataIDs []DataID
 
 	// Indicates whether >2/3 of voting power is present,
 	// but does not necessarily indicate that the voting power
@@ -49,6 +54,9 @@ type ConsensusStrategy interface {
 	// If the application is going to propose a block for this round,
 	// it must publish the proposal information to the proposalOut channel;
 	// the state machine will compose that information into a proposed block.
+	//
+	// A strategy that wants the state machine to wait before acting on its proposal
+	// may implement [ProposalDelayer].
 	EnterRound(ctx context.Context, rv RoundView, proposalOut chan<- Proposal) error
 
 	// ConsiderProposedBlocks is called when new proposed headers arrive,
@@ -60,6 +68,10 @@ type ConsensusStrategy interface {
 	// the consensus strategy cannot decide whether to vote for the header
 	// until it has the actual block data to go with it.
 	//
+	// The phs slice is sorted by header hash,
+	// so that every node presents the same candidates in the same order
+	// regardless of the order in which the proposed headers arrived.
+	//
 	// The reason argument is a hint to the consensus strategy
 	// about which information is new in this call,
 	// compared to the previous call.
@@ -78,6 +90,7 @@ type ConsensusStrategy interface {
 
 	// ChooseProposedBlock is called when the state machine's proposal delay has elapsed.
 	// The phs slice may be empty.
+	// As with ConsiderProposedBlocks, phs is sorted by header hash.
 	//
 	// ChooseProposedBlock must return the hash of the block to vote for.
 	// Under certain circumstances (like Proof of Lock),
@@ -99,6 +112,72 @@ type ConsensusStrategy interface {
 	DecidePrecommit(ctx context.Context, vs VoteSummary) (string, error)
 }
 
+// RoundPointer identifies a single round at a particular height.
+type RoundPointer struct {
+	Height uint64
+	Round  uint32
+}
+
+// RoundJumpObserver is an optional interface that a [ConsensusStrategy] may implement
+// in order to be notified when the state machine jumps ahead to a later round.
+//
+// The state machine jumps ahead, without waiting for the current round to conclude,
+// when the network has clearly moved on to a later round.
+// A strategy may use this notification, for instance, to record why a round was skipped.
+type RoundJumpObserver interface {
+	// RoundJumped is called before the state machine enters the round indicated by to,
+	// and therefore before the corresponding call to EnterRound.
+	// The from value is the round the state machine was in when it jumped.
+	//
+	// The state machine calls this method synchronously,
+	// so the method should return promptly.
+	RoundJumped(ctx context.Context, from, to RoundPointer)
+}
+
+// ProposalDelayer is an optional interface that a [ConsensusStrategy] may implement
+// in order to have the state machine wait, after entering a round,
+// before acting on a proposal sent to the proposal channel.
+// A strategy may use the delay, for instance, to collect more transactions
+// while still proposing as soon as it has data.
+type ProposalDelayer interface {
+	// ProposalDelay is called after each successful call to EnterRound
+	// in which the strategy received a non-nil proposal channel.
+	// If the returned duration is positive,
+	// the state machine waits that long after entering the round
+	// before acting on a value sent to the proposal channel.
+	//
+	// The delay is bounded by the proposal timeout:
+	// the state machine stops waiting once the proposal timer elapses,
+	// even if the requested delay has not yet elapsed.
+	//
+	// The state machine calls this method synchronously,
+	// so the method should return promptly.
+	ProposalDelay(ctx context.Context, height uint64, round uint32) time.Duration
+}
+
+// PreviousRoundProposer is an optional interface that a [ConsensusStrategy] may implement
+// in order to re-propose data from an earlier round, after that round failed.
+//
+// When entering any round after round zero in which the local validator may propose,
+// the state machine consults PreviousRoundProposal before calling EnterRound.
+// If reuse is true, the state machine proposes a block with the given data ID
+// on behalf of the strategy, and the subsequent EnterRound call
+// receives a nil proposal channel.
+// If reuse is false, EnterRound is called as usual.
+type PreviousRoundProposer interface {
+	// PreviousRoundProposal reports whether the strategy wants to re-propose
+	// in the round following prevRound at the given height,
+	// and if so, the data ID to propose.
+	//
+	// Just like sending a value on the proposal channel in EnterRound,
+	// the strategy should only return reuse=true
+	// if the local validator is the proposer for the new round.
+	//
+	// The state machine calls this method synchronously,
+	// so the method should return promptly.
+	PreviousRoundProposal(ctx context.Context, height uint64, prevRound uint32) (reuse bool, dataID string)
+}
+
 // ErrProposedBlockChoiceNotReady is a sentinel error the [ConsensusStrategy] must return
 // from its ConsiderProposedBlocks method, if it is not ready to choose a proposed block.
 var ErrProposedBlockChoiceNotReady = errors.New("not ready to choose proposed block")
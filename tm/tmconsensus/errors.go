@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 )
@@ -65,6 +66,38 @@ func (e HeightMismatchError) Error() string {
 	)
 }
 
+// TimestampNotIncreasingError indicates a header
+// whose timestamp is not after its parent header's timestamp.
+type TimestampNotIncreasingError struct {
+	Parent, Got time.Time
+}
+
+func (e TimestampNotIncreasingError) Error() string {
+	if e.Got.IsZero() {
+		return fmt.Sprintf(
+			"timestamp missing, but parent timestamp is %s",
+			e.Parent.Format(time.RFC3339Nano),
+		)
+	}
+	return fmt.Sprintf(
+		"timestamp %s not after parent timestamp %s",
+		e.Got.Format(time.RFC3339Nano), e.Parent.Format(time.RFC3339Nano),
+	)
+}
+
+// TimestampTooFarInFutureError indicates a header
+// whose timestamp exceeds the allowed drift past the local clock.
+type TimestampTooFarInFutureError struct {
+	Limit, Got time.Time
+}
+
+func (e TimestampTooFarInFutureError) Error() string {
+	return fmt.Sprintf(
+		"timestamp %s too far in future (limit %s)",
+		e.Got.Format(time.RFC3339Nano), e.Limit.Format(time.RFC3339Nano),
+	)
+}
+
 // HeightUnknownError indicates a request for a height that is not known.
 type HeightUnknownError struct {
 	Want uint64
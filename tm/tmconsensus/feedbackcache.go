@@ -0,0 +1,129 @@
+package tmconsensus
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+)
+
+// feedbackCache is a bounded, concurrency-safe LRU set of message keys,
+// used by [DropDuplicateFeedbackMapper] to detect recently accepted messages
+// without consulting the wrapped handler.
+type feedbackCache struct {
+	mu sync.Mutex
+
+	maxEntries int
+
+	// Front of the list is the most recently seen key.
+	order *list.List
+	elems map[string]*list.Element
+}
+
+func newFeedbackCache(maxEntries int) *feedbackCache {
+	return &feedbackCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		elems:      make(map[string]*list.Element, maxEntries),
+	}
+}
+
+// Contains reports whether key is in the cache,
+// marking it as most recently used if so.
+func (c *feedbackCache) Contains(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.elems[key]
+	if ok {
+		c.order.MoveToFront(e)
+	}
+	return ok
+}
+
+// Add adds key to the cache as the most recently used entry,
+// evicting the least recently used entry if the cache is full.
+func (c *feedbackCache) Add(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.elems[key]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+
+	if c.order.Len() >= c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.elems, oldest.Value.(string))
+	}
+
+	c.elems[key] = c.order.PushFront(key)
+}
+
+// proposedHeaderCacheKey returns the cache key for ph,
+// made of the header's hash and the proposer's signature.
+// The signature alone is not enough, because the handler validates the hash,
+// so a copy of an accepted header with a different hash
+// must not be ignored as a duplicate.
+func proposedHeaderCacheKey(ph ProposedHeader) string {
+	var b bytes.Buffer
+	_ = b.WriteByte('h')
+	writeCacheKeyField(&b, ph.Header.Hash)
+	writeCacheKeyField(&b, ph.Signature)
+	return b.String()
+}
+
+// sparseVotesCacheKey returns a fixed-size cache key for a set of sparse vote proofs.
+// The key is independent of map iteration order and signature order.
+func sparseVotesCacheKey(
+	kind byte, height uint64, round uint32, pubKeyHash string, proofs map[string][]gcrypto.SparseSignature,
+) string {
+	h := sha256.New()
+
+	var buf [8]byte
+	_, _ = h.Write([]byte{kind})
+	binary.BigEndian.PutUint64(buf[:], height)
+	_, _ = h.Write(buf[:])
+	binary.BigEndian.PutUint32(buf[:4], round)
+	_, _ = h.Write(buf[:4])
+
+	writeCacheKeyField(h, []byte(pubKeyHash))
+
+	blockHashes := make([]string, 0, len(proofs))
+	for blockHash := range proofs {
+		blockHashes = append(blockHashes, blockHash)
+	}
+	slices.Sort(blockHashes)
+
+	for _, blockHash := range blockHashes {
+		writeCacheKeyField(h, []byte(blockHash))
+
+		sigs := slices.Clone(proofs[blockHash])
+		slices.SortFunc(sigs, func(a, b gcrypto.SparseSignature) int {
+			return bytes.Compare(a.KeyID, b.KeyID)
+		})
+
+		binary.BigEndian.PutUint32(buf[:4], uint32(len(sigs)))
+		_, _ = h.Write(buf[:4])
+		for _, sig := range sigs {
+			writeCacheKeyField(h, sig.KeyID)
+			writeCacheKeyField(h, sig.Sig)
+		}
+	}
+
+	return string([]byte{kind}) + string(h.Sum(nil))
+}
+
+// writeCacheKeyField writes a length-prefixed b to h.
+func writeCacheKeyField(h io.Writer, b []byte) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(b)))
+	_, _ = h.Write(buf[:])
+	_, _ = h.Write(b)
+}
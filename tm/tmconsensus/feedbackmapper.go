@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/gordian-engine/gordian/gcrypto"
 	"github.com/gordian-engine/gordian/gexchange"
 )
 
@@ -24,10 +25,15 @@ func (m AcceptAllValidFeedbackMapper) HandleProposedHeader(
 		return gexchange.FeedbackAccepted
 
 	case HandleProposedHeaderRoundTooOld,
+		HandleProposedHeaderPrefiltered,
 		HandleProposedHeaderInternalError:
 		return gexchange.FeedbackIgnored
 
 	case HandleProposedHeaderSignerUnrecognized,
+		HandleProposedHeaderUnexpectedProposer,
+		HandleProposedHeaderProposerQuotaExceeded,
+		HandleProposedHeaderBadTimestamp,
+		HandleProposedHeaderAnnotationsTooLarge,
 		HandleProposedHeaderBadSignature,
 		HandleProposedHeaderBadBlockHash,
 		HandleProposedHeaderBadPrevCommitProofPubKeyHash,
@@ -63,6 +69,8 @@ func (m AcceptAllValidFeedbackMapper) mapVoteResult(
 		return gexchange.FeedbackAccepted
 
 	case HandleVoteProofsRoundTooOld,
+		HandleVoteProofsTooFarInFuture,
+		HandleVoteProofsUnknownBlockHash,
 		HandleVoteProofsInternalError:
 		return gexchange.FeedbackIgnored
 
@@ -75,27 +83,160 @@ func (m AcceptAllValidFeedbackMapper) mapVoteResult(
 	}
 }
 
+// SizeLimitedFeedbackMapper is a variant of [AcceptAllValidFeedbackMapper]
+// that returns [gexchange.FeedbackTooLarge] for any message
+// whose approximate encoded size exceeds MaxSize bytes,
+// without passing the message to the wrapped Handler.
+// Messages within the limit are mapped exactly as AcceptAllValidFeedbackMapper does.
+//
+// The mapper only has access to decoded messages,
+// so the size is approximated as the sum of the lengths
+// of the message's variable-length fields (hashes, keys, signatures, and annotations).
+// The actual encoded size will be somewhat larger,
+// so MaxSize should leave some headroom below any hard network limit.
+//
+// A MaxSize of zero or less disables the size check.
+type SizeLimitedFeedbackMapper struct {
+	Handler FineGrainedConsensusHandler
+
+	MaxSize int
+}
+
+func (m SizeLimitedFeedbackMapper) HandleProposedHeader(
+	ctx context.Context, ph ProposedHeader,
+) gexchange.Feedback {
+	if m.MaxSize > 0 && proposedHeaderSize(ph) > m.MaxSize {
+		return gexchange.FeedbackTooLarge
+	}
+
+	return AcceptAllValidFeedbackMapper{Handler: m.Handler}.HandleProposedHeader(ctx, ph)
+}
+
+func (m SizeLimitedFeedbackMapper) HandlePrevoteProofs(
+	ctx context.Context, p PrevoteSparseProof,
+) gexchange.Feedback {
+	if m.MaxSize > 0 && len(p.PubKeyHash)+sparseSignatureMapSize(p.Proofs) > m.MaxSize {
+		return gexchange.FeedbackTooLarge
+	}
+
+	return AcceptAllValidFeedbackMapper{Handler: m.Handler}.HandlePrevoteProofs(ctx, p)
+}
+
+func (m SizeLimitedFeedbackMapper) HandlePrecommitProofs(
+	ctx context.Context, p PrecommitSparseProof,
+) gexchange.Feedback {
+	if m.MaxSize > 0 && len(p.PubKeyHash)+sparseSignatureMapSize(p.Proofs) > m.MaxSize {
+		return gexchange.FeedbackTooLarge
+	}
+
+	return AcceptAllValidFeedbackMapper{Handler: m.Handler}.HandlePrecommitProofs(ctx, p)
+}
+
+// proposedHeaderSize returns the approximate encoded size of ph,
+// as described on [SizeLimitedFeedbackMapper].
+func proposedHeaderSize(ph ProposedHeader) int {
+	h := ph.Header
+	n := len(h.Hash) + len(h.PrevBlockHash) + len(h.DataID) + len(h.PrevAppStateHash) +
+		len(h.Annotations.User) + len(h.Annotations.Driver) +
+		len(h.PrevCommitProof.PubKeyHash) + sparseSignatureMapSize(h.PrevCommitProof.Proofs) +
+		validatorSetSize(h.ValidatorSet) + validatorSetSize(h.NextValidatorSet)
+
+	n += len(ph.Annotations.User) + len(ph.Annotations.Driver) + len(ph.Signature)
+	if ph.ProposerPubKey != nil {
+		n += len(ph.ProposerPubKey.PubKeyBytes())
+	}
+
+	return n
+}
+
+func validatorSetSize(vs ValidatorSet) int {
+	n := len(vs.PubKeyHash) + len(vs.VotePowerHash)
+	for _, v := range vs.Validators {
+		if v.PubKey != nil {
+			n += len(v.PubKey.PubKeyBytes())
+		}
+	}
+	return n
+}
+
+func sparseSignatureMapSize(m map[string][]gcrypto.SparseSignature) int {
+	var n int
+	for hash, sigs := range m {
+		n += len(hash)
+		for _, s := range sigs {
+			n += len(s.KeyID) + len(s.Sig)
+		}
+	}
+	return n
+}
+
 // DropDuplicateFeedbackMapper is a [Handler] that wraps a FineGrainedConsensusHandler
 // that ignores proposed block messages if we already have the proposed block
 // and ignores vote messages if they do not increase existing vote knowledge.
+//
+// A DropDuplicateFeedbackMapper created with [NewDropDuplicateFeedbackMapper]
+// additionally remembers a bounded number of recently accepted messages,
+// so that exact duplicates are ignored without consulting the wrapped handler.
+// The zero value of the cache (i.e. a struct literal only setting Handler)
+// relies entirely on the wrapped handler to detect duplicates.
 type DropDuplicateFeedbackMapper struct {
 	Handler FineGrainedConsensusHandler
+
+	seen *feedbackCache
+}
+
+// NewDropDuplicateFeedbackMapper returns a DropDuplicateFeedbackMapper wrapping handler,
+// which remembers up to maxEntries recently accepted messages.
+// Once more than maxEntries messages have been accepted,
+// the least recently seen messages are evicted,
+// and a later copy of an evicted message is passed to the handler again.
+//
+// NewDropDuplicateFeedbackMapper panics if maxEntries is not positive.
+func NewDropDuplicateFeedbackMapper(
+	handler FineGrainedConsensusHandler, maxEntries int,
+) DropDuplicateFeedbackMapper {
+	if maxEntries <= 0 {
+		panic(fmt.Errorf(
+			"NewDropDuplicateFeedbackMapper: maxEntries must be positive (got %d)", maxEntries,
+		))
+	}
+
+	return DropDuplicateFeedbackMapper{
+		Handler: handler,
+		seen:    newFeedbackCache(maxEntries),
+	}
 }
 
 func (m DropDuplicateFeedbackMapper) HandleProposedHeader(
 	ctx context.Context, ph ProposedHeader,
 ) gexchange.Feedback {
+	var key string
+	if m.seen != nil {
+		key = proposedHeaderCacheKey(ph)
+		if m.seen.Contains(key) {
+			return gexchange.FeedbackIgnored
+		}
+	}
+
 	f := m.Handler.HandleProposedHeader(ctx, ph)
 	switch f {
 	case HandleProposedHeaderAccepted:
+		if m.seen != nil {
+			m.seen.Add(key)
+		}
 		return gexchange.FeedbackAccepted
 
 	case HandleProposedHeaderRoundTooOld,
+		HandleProposedHeaderPrefiltered,
 		HandleProposedHeaderInternalError,
 		HandleProposedHeaderAlreadyStored:
 		return gexchange.FeedbackIgnored
 
 	case HandleProposedHeaderSignerUnrecognized,
+		HandleProposedHeaderUnexpectedProposer,
+		HandleProposedHeaderProposerQuotaExceeded,
+		HandleProposedHeaderBadTimestamp,
+		HandleProposedHeaderAnnotationsTooLarge,
 		HandleProposedHeaderBadSignature,
 		HandleProposedHeaderBadBlockHash,
 		HandleProposedHeaderBadPrevCommitProofPubKeyHash,
@@ -111,26 +252,50 @@ func (m DropDuplicateFeedbackMapper) HandleProposedHeader(
 func (m DropDuplicateFeedbackMapper) HandlePrevoteProofs(
 	ctx context.Context, p PrevoteSparseProof,
 ) gexchange.Feedback {
+	var key string
+	if m.seen != nil {
+		key = sparseVotesCacheKey('p', p.Height, p.Round, p.PubKeyHash, p.Proofs)
+		if m.seen.Contains(key) {
+			return gexchange.FeedbackIgnored
+		}
+	}
+
 	f := m.Handler.HandlePrevoteProofs(ctx, p)
-	return m.mapVoteResult(f, "HandlePrevoteProofs")
+	return m.mapVoteResult(f, key, "HandlePrevoteProofs")
 }
 
 func (m DropDuplicateFeedbackMapper) HandlePrecommitProofs(
 	ctx context.Context, p PrecommitSparseProof,
 ) gexchange.Feedback {
+	var key string
+	if m.seen != nil {
+		key = sparseVotesCacheKey('c', p.Height, p.Round, p.PubKeyHash, p.Proofs)
+		if m.seen.Contains(key) {
+			return gexchange.FeedbackIgnored
+		}
+	}
+
 	f := m.Handler.HandlePrecommitProofs(ctx, p)
-	return m.mapVoteResult(f, "HandlePrecommitProofs")
+	return m.mapVoteResult(f, key, "HandlePrecommitProofs")
 }
 
+// mapVoteResult maps f to a feedback value.
+// If the result was accepted and m has a cache,
+// key is added to the cache.
 func (m DropDuplicateFeedbackMapper) mapVoteResult(
-	f HandleVoteProofsResult, name string,
+	f HandleVoteProofsResult, key, name string,
 ) gexchange.Feedback {
 	switch f {
 	case HandleVoteProofsAccepted:
+		if m.seen != nil {
+			m.seen.Add(key)
+		}
 		return gexchange.FeedbackAccepted
 
 	case HandleVoteProofsRoundTooOld,
+		HandleVoteProofsTooFarInFuture,
 		HandleVoteProofsNoNewSignatures,
+		HandleVoteProofsUnknownBlockHash,
 		HandleVoteProofsInternalError:
 		return gexchange.FeedbackIgnored
 
@@ -0,0 +1,228 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/gexchange"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/stretchr/testify/require"
+)
+
+// acceptAllHandler is a FineGrainedConsensusHandler
+// that accepts every message and counts how many times it was called.
+type acceptAllHandler struct {
+	phCalls, prevoteCalls, precommitCalls int
+}
+
+func (h *acceptAllHandler) HandleProposedHeader(
+	context.Context, tmconsensus.ProposedHeader,
+) tmconsensus.HandleProposedHeaderResult {
+	h.phCalls++
+	return tmconsensus.HandleProposedHeaderAccepted
+}
+
+func (h *acceptAllHandler) HandlePrevoteProofs(
+	context.Context, tmconsensus.PrevoteSparseProof,
+) tmconsensus.HandleVoteProofsResult {
+	h.prevoteCalls++
+	return tmconsensus.HandleVoteProofsAccepted
+}
+
+func (h *acceptAllHandler) HandlePrecommitProofs(
+	context.Context, tmconsensus.PrecommitSparseProof,
+) tmconsensus.HandleVoteProofsResult {
+	h.precommitCalls++
+	return tmconsensus.HandleVoteProofsAccepted
+}
+
+func TestNewDropDuplicateFeedbackMapper_eviction(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := new(acceptAllHandler)
+	m := tmconsensus.NewDropDuplicateFeedbackMapper(h, 2)
+
+	ph1 := tmconsensus.ProposedHeader{Signature: []byte("sig1")}
+	ph2 := tmconsensus.ProposedHeader{Signature: []byte("sig2")}
+	ph3 := tmconsensus.ProposedHeader{Signature: []byte("sig3")}
+
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandleProposedHeader(ctx, ph1))
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandleProposedHeader(ctx, ph2))
+
+	// Both are in the cache, so duplicates are ignored without reaching the handler.
+	require.Equal(t, gexchange.FeedbackIgnored, m.HandleProposedHeader(ctx, ph1))
+	require.Equal(t, gexchange.FeedbackIgnored, m.HandleProposedHeader(ctx, ph2))
+	require.Equal(t, 2, h.phCalls)
+
+	// The lookup of ph2 made it the most recently used entry,
+	// so adding ph3 evicts ph1.
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandleProposedHeader(ctx, ph3))
+	require.Equal(t, 3, h.phCalls)
+
+	// ph1 was evicted, so it reaches the handler again and is re-accepted.
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandleProposedHeader(ctx, ph1))
+	require.Equal(t, 4, h.phCalls)
+
+	// Re-adding ph1 evicted ph2, while ph3 is still cached.
+	require.Equal(t, gexchange.FeedbackIgnored, m.HandleProposedHeader(ctx, ph3))
+	require.Equal(t, 4, h.phCalls)
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandleProposedHeader(ctx, ph2))
+	require.Equal(t, 5, h.phCalls)
+}
+
+func TestNewDropDuplicateFeedbackMapper_proposedHeaderKey(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := new(acceptAllHandler)
+	m := tmconsensus.NewDropDuplicateFeedbackMapper(h, 4)
+
+	ph := tmconsensus.ProposedHeader{
+		Header:    tmconsensus.Header{Hash: []byte("hash1")},
+		Signature: []byte("sig"),
+	}
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandleProposedHeader(ctx, ph))
+	require.Equal(t, gexchange.FeedbackIgnored, m.HandleProposedHeader(ctx, ph))
+	require.Equal(t, 1, h.phCalls)
+
+	// Same signature with a different hash must still reach the handler.
+	otherHash := ph
+	otherHash.Header.Hash = []byte("hash2")
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandleProposedHeader(ctx, otherHash))
+	require.Equal(t, 2, h.phCalls)
+
+	// The fields are length-prefixed, so moving bytes between them is not a match.
+	shifted := tmconsensus.ProposedHeader{
+		Header:    tmconsensus.Header{Hash: []byte("hash1s")},
+		Signature: []byte("ig"),
+	}
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandleProposedHeader(ctx, shifted))
+	require.Equal(t, 3, h.phCalls)
+}
+
+func TestNewDropDuplicateFeedbackMapper_votes(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := new(acceptAllHandler)
+	m := tmconsensus.NewDropDuplicateFeedbackMapper(h, 1)
+
+	newPrevote := func() tmconsensus.PrevoteSparseProof {
+		return tmconsensus.PrevoteSparseProof{
+			Height: 1, Round: 0,
+			PubKeyHash: "pkh",
+			Proofs: map[string][]gcrypto.SparseSignature{
+				"block": {
+					{KeyID: []byte{0}, Sig: []byte("s0")},
+					{KeyID: []byte{1}, Sig: []byte("s1")},
+				},
+			},
+		}
+	}
+
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandlePrevoteProofs(ctx, newPrevote()))
+
+	// Same content with a different signature order is still a duplicate.
+	dup := newPrevote()
+	sigs := dup.Proofs["block"]
+	sigs[0], sigs[1] = sigs[1], sigs[0]
+	require.Equal(t, gexchange.FeedbackIgnored, m.HandlePrevoteProofs(ctx, dup))
+	require.Equal(t, 1, h.prevoteCalls)
+
+	// A precommit with identical fields is not confused with the prevote,
+	// and it evicts the prevote from the single-entry cache.
+	precommit := tmconsensus.PrecommitSparseProof(newPrevote())
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandlePrecommitProofs(ctx, precommit))
+	require.Equal(t, 1, h.precommitCalls)
+
+	require.Equal(t, gexchange.FeedbackAccepted, m.HandlePrevoteProofs(ctx, newPrevote()))
+	require.Equal(t, 2, h.prevoteCalls)
+}
+
+func TestSizeLimitedFeedbackMapper(t *testing.T) {
+	t.Parallel()
+
+	// The parallel subtests outlive this function,
+	// so they cannot share a context that is canceled on return.
+	ctx := context.Background()
+
+	prevote := tmconsensus.PrevoteSparseProof{
+		Height: 1, Round: 0,
+		PubKeyHash: "pkh",
+		Proofs: map[string][]gcrypto.SparseSignature{
+			"block": {
+				{KeyID: []byte{0}, Sig: []byte("s0")},
+				{KeyID: []byte{1}, Sig: []byte("s1")},
+			},
+		},
+	}
+	// 3 bytes of pub key hash, 5 bytes of block hash, and 2*(1+2) bytes of signatures.
+	const prevoteSize = 14
+
+	t.Run("votes within the limit are passed to the handler", func(t *testing.T) {
+		t.Parallel()
+
+		h := new(acceptAllHandler)
+		m := tmconsensus.SizeLimitedFeedbackMapper{Handler: h, MaxSize: prevoteSize}
+
+		require.Equal(t, gexchange.FeedbackAccepted, m.HandlePrevoteProofs(ctx, prevote))
+		require.Equal(t, 1, h.prevoteCalls)
+
+		precommit := tmconsensus.PrecommitSparseProof(prevote)
+		require.Equal(t, gexchange.FeedbackAccepted, m.HandlePrecommitProofs(ctx, precommit))
+		require.Equal(t, 1, h.precommitCalls)
+	})
+
+	t.Run("oversized votes are rejected before the handler", func(t *testing.T) {
+		t.Parallel()
+
+		h := new(acceptAllHandler)
+		m := tmconsensus.SizeLimitedFeedbackMapper{Handler: h, MaxSize: prevoteSize - 1}
+
+		require.Equal(t, gexchange.FeedbackTooLarge, m.HandlePrevoteProofs(ctx, prevote))
+		require.Zero(t, h.prevoteCalls)
+
+		precommit := tmconsensus.PrecommitSparseProof(prevote)
+		require.Equal(t, gexchange.FeedbackTooLarge, m.HandlePrecommitProofs(ctx, precommit))
+		require.Zero(t, h.precommitCalls)
+	})
+
+	t.Run("oversized proposed header is rejected before the handler", func(t *testing.T) {
+		t.Parallel()
+
+		h := new(acceptAllHandler)
+		m := tmconsensus.SizeLimitedFeedbackMapper{Handler: h, MaxSize: 64}
+
+		ph := tmconsensus.ProposedHeader{
+			Header: tmconsensus.Header{
+				DataID: []byte("small"),
+			},
+			Signature: []byte("sig"),
+		}
+		require.Equal(t, gexchange.FeedbackAccepted, m.HandleProposedHeader(ctx, ph))
+		require.Equal(t, 1, h.phCalls)
+
+		// The handler would accept this header too, but the annotation makes it too large.
+		ph.Annotations.User = make([]byte, 64)
+		require.Equal(t, gexchange.FeedbackTooLarge, m.HandleProposedHeader(ctx, ph))
+		require.Equal(t, 1, h.phCalls)
+	})
+
+	t.Run("zero MaxSize disables the check", func(t *testing.T) {
+		t.Parallel()
+
+		h := new(acceptAllHandler)
+		m := tmconsensus.SizeLimitedFeedbackMapper{Handler: h}
+
+		require.Equal(t, gexchange.FeedbackAccepted, m.HandlePrevoteProofs(ctx, prevote))
+		require.Equal(t, 1, h.prevoteCalls)
+	})
+}
@@ -3,6 +3,8 @@ package tmconsensus
 import (
 	"fmt"
 	"io"
+
+	"github.com/gordian-engine/gordian/gcrypto"
 )
 
 // Genesis is the value used to initialize a consensus store.
@@ -62,4 +64,24 @@ type ExternalGenesis struct {
 	// Validators according to the consensus engine's view.
 	// Can be overridden in the [tmdriver.InitChainResponse].
 	GenesisValidatorSet ValidatorSet
+
+	// Optional application-defined metadata for genesis validators,
+	// such as a moniker or network address.
+	// Keys are the validator public key hashes
+	// returned by [GenesisValidatorAnnotationKey].
+	// The consensus engine does not interpret the values;
+	// it stores them when initializing the chain,
+	// so they remain available after startup.
+	GenesisValidatorAnnotations map[string][]byte
+}
+
+// GenesisValidatorAnnotationKey returns the key for pubKey
+// in [ExternalGenesis.GenesisValidatorAnnotations].
+// The key is the hash, according to hs, of the set containing only pubKey.
+func GenesisValidatorAnnotationKey(hs HashScheme, pubKey gcrypto.PubKey) (string, error) {
+	h, err := hs.PubKeys([]gcrypto.PubKey{pubKey})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash public key: %w", err)
+	}
+	return string(h), nil
 }
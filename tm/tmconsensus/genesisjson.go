@@ -0,0 +1,117 @@
+package tmconsensus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+)
+
+// genesisJSON is the JSON form of an [ExternalGenesis].
+// See [MarshalGenesisJSON] for a description of the format.
+type genesisJSON struct {
+	ChainID         string                 `json:"chain_id"`
+	InitialHeight   uint64                 `json:"initial_height"`
+	InitialAppState []byte                 `json:"initial_app_state"`
+	Validators      []genesisValidatorJSON `json:"validators"`
+}
+
+type genesisValidatorJSON struct {
+	Type   string `json:"type"`
+	PubKey []byte `json:"pub_key"`
+	Power  uint64 `json:"power"`
+}
+
+// MarshalGenesisJSON returns the canonical JSON encoding of g,
+// intended for genesis files that operators write or review by hand.
+//
+// The format is a single object with the fields:
+//   - "chain_id": the chain ID, as a string
+//   - "initial_height": the initial height, as a number
+//   - "initial_app_state": the initial application state, base64-encoded
+//   - "validators": an array of objects, one per validator in order, with the fields
+//     "type", the public key type name as registered in reg (e.g. "ed25519" or "bls-minsig");
+//     "pub_key", the base64-encoded public key bytes;
+//     and "power", the validator's power as a number
+//
+// The validator set hashes are not encoded,
+// as they are recalculated in [UnmarshalGenesisJSON].
+//
+// MarshalGenesisJSON reads g.InitialAppState to completion, if it is not nil.
+// It returns an error if any validator's public key type was not registered with reg.
+func MarshalGenesisJSON(reg *gcrypto.Registry, g ExternalGenesis) ([]byte, error) {
+	j := genesisJSON{
+		ChainID:       g.ChainID,
+		InitialHeight: g.InitialHeight,
+		Validators:    make([]genesisValidatorJSON, len(g.GenesisValidatorSet.Validators)),
+	}
+
+	if g.InitialAppState != nil {
+		var err error
+		j.InitialAppState, err = io.ReadAll(g.InitialAppState)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read initial app state: %w", err)
+		}
+	}
+
+	for i, v := range g.GenesisValidatorSet.Validators {
+		typeName := v.PubKey.TypeName()
+		b := v.PubKey.PubKeyBytes()
+
+		// Ensure the key will be decodable on the way back in.
+		if _, err := reg.Decode(typeName, bytes.Clone(b)); err != nil {
+			return nil, fmt.Errorf("cannot encode public key for validator %d: %w", i, err)
+		}
+
+		j.Validators[i] = genesisValidatorJSON{
+			Type:   typeName,
+			PubKey: b,
+			Power:  v.Power,
+		}
+	}
+
+	return json.MarshalIndent(j, "", "  ")
+}
+
+// UnmarshalGenesisJSON decodes a genesis previously encoded with [MarshalGenesisJSON],
+// or written by hand in the same format.
+// Unknown fields are rejected, to catch misspelled field names.
+//
+// Public keys are decoded with reg,
+// and the hashes of the returned validator set are calculated with hs.
+// The returned genesis's InitialAppState is never nil.
+func UnmarshalGenesisJSON(reg *gcrypto.Registry, hs HashScheme, b []byte) (ExternalGenesis, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+
+	var j genesisJSON
+	if err := dec.Decode(&j); err != nil {
+		return ExternalGenesis{}, fmt.Errorf("failed to decode genesis JSON: %w", err)
+	}
+
+	vals := make([]Validator, len(j.Validators))
+	for i, v := range j.Validators {
+		pk, err := reg.Decode(v.Type, v.PubKey)
+		if err != nil {
+			return ExternalGenesis{}, fmt.Errorf(
+				"failed to decode public key for validator %d: %w", i, err,
+			)
+		}
+
+		vals[i] = Validator{PubKey: pk, Power: v.Power}
+	}
+
+	vs, err := NewValidatorSet(vals, hs)
+	if err != nil {
+		return ExternalGenesis{}, err
+	}
+
+	return ExternalGenesis{
+		ChainID:             j.ChainID,
+		InitialHeight:       j.InitialHeight,
+		InitialAppState:     bytes.NewReader(j.InitialAppState),
+		GenesisValidatorSet: vs,
+	}, nil
+}
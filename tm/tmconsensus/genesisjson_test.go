@@ -0,0 +1,72 @@
+package tmconsensus_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenesisJSON_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	var reg gcrypto.Registry
+	gcrypto.RegisterEd25519(&reg)
+
+	fx := tmconsensustest.NewStandardFixture(4)
+
+	orig := tmconsensus.ExternalGenesis{
+		ChainID:             "my-chain",
+		InitialHeight:       5,
+		InitialAppState:     strings.NewReader("app state"),
+		GenesisValidatorSet: fx.ValSet(),
+	}
+
+	b, err := tmconsensus.MarshalGenesisJSON(&reg, orig)
+	require.NoError(t, err)
+
+	got, err := tmconsensus.UnmarshalGenesisJSON(&reg, fx.HashScheme, b)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-chain", got.ChainID)
+	require.Equal(t, uint64(5), got.InitialHeight)
+	require.True(t, orig.GenesisValidatorSet.Equal(got.GenesisValidatorSet))
+
+	appState, err := io.ReadAll(got.InitialAppState)
+	require.NoError(t, err)
+	require.Equal(t, "app state", string(appState))
+}
+
+func TestGenesisJSON_unknownKeyType(t *testing.T) {
+	t.Parallel()
+
+	var reg gcrypto.Registry
+	gcrypto.RegisterEd25519(&reg)
+
+	fx := tmconsensustest.NewStandardFixture(2)
+
+	b, err := tmconsensus.MarshalGenesisJSON(&reg, tmconsensus.ExternalGenesis{
+		ChainID:             "my-chain",
+		InitialHeight:       1,
+		GenesisValidatorSet: fx.ValSet(),
+	})
+	require.NoError(t, err)
+
+	// Simulate a hand-written file with a key type the registry doesn't know.
+	j := strings.Replace(string(b), `"ed25519"`, `"ed448"`, 1)
+
+	_, err = tmconsensus.UnmarshalGenesisJSON(&reg, fx.HashScheme, []byte(j))
+	require.ErrorContains(t, err, "validator 0")
+	require.ErrorContains(t, err, `"ed448"`)
+
+	// Encoding a key type that is not in the registry fails too.
+	var emptyReg gcrypto.Registry
+	_, err = tmconsensus.MarshalGenesisJSON(&emptyReg, tmconsensus.ExternalGenesis{
+		GenesisValidatorSet: fx.ValSet(),
+	})
+	require.ErrorContains(t, err, `"ed25519"`)
+}
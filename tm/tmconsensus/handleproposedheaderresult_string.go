@@ -19,11 +19,16 @@ func _() {
 	_ = x[HandleProposedHeaderRoundTooOld-9]
 	_ = x[HandleProposedHeaderRoundTooFarInFuture-10]
 	_ = x[HandleProposedHeaderInternalError-11]
+	_ = x[HandleProposedHeaderUnexpectedProposer-12]
+	_ = x[HandleProposedHeaderProposerQuotaExceeded-13]
+	_ = x[HandleProposedHeaderBadTimestamp-14]
+	_ = x[HandleProposedHeaderAnnotationsTooLarge-15]
+	_ = x[HandleProposedHeaderPrefiltered-16]
 }
 
-const _HandleProposedHeaderResult_name = "AcceptedAlreadyStoredSignerUnrecognizedBadBlockHashBadSignatureBadPrevCommitProofPubKeyHashBadPrevCommitProofSignatureBadPrevCommitVoteCountRoundTooOldRoundTooFarInFutureInternalError"
+const _HandleProposedHeaderResult_name = "AcceptedAlreadyStoredSignerUnrecognizedBadBlockHashBadSignatureBadPrevCommitProofPubKeyHashBadPrevCommitProofSignatureBadPrevCommitVoteCountRoundTooOldRoundTooFarInFutureInternalErrorUnexpectedProposerProposerQuotaExceededBadTimestampAnnotationsTooLargePrefiltered"
 
-var _HandleProposedHeaderResult_index = [...]uint8{0, 8, 21, 39, 51, 63, 91, 118, 140, 151, 170, 183}
+var _HandleProposedHeaderResult_index = [...]uint16{0, 8, 21, 39, 51, 63, 91, 118, 140, 151, 170, 183, 201, 222, 234, 253, 264}
 
 func (i HandleProposedHeaderResult) String() string {
 	i -= 1
@@ -65,6 +65,31 @@ const (
 
 	// Internal error not necessarily correlated with the actual proposed block.
 	HandleProposedHeaderInternalError
+
+	// The signer of the proposed block is a known validator,
+	// but it is not the expected proposer for the block's height and round.
+	// This is only reported when the handler is configured to require the expected proposer.
+	HandleProposedHeaderUnexpectedProposer
+
+	// The signer of the proposed block already has the maximum number
+	// of proposed blocks in the block's height and round.
+	// This is only reported when the handler is configured with a per-proposer quota.
+	HandleProposedHeaderProposerQuotaExceeded
+
+	// The header's timestamp was not after its parent's timestamp,
+	// or it was too far ahead of the local clock.
+	// This is only reported when the handler is configured to validate timestamps.
+	HandleProposedHeaderBadTimestamp
+
+	// The combined size of the proposed header's annotations
+	// and its header's annotations exceeded the handler's limit.
+	// This is only reported when the handler is configured with an annotation size limit.
+	HandleProposedHeaderAnnotationsTooLarge
+
+	// The proposed header was dropped by the handler's prefilter,
+	// before any verification of its hash or signature.
+	// This is only reported when the handler is configured with a prefilter.
+	HandleProposedHeaderPrefiltered
 )
 
 // HandleVoteProofsResult is a set of constants
@@ -96,4 +121,10 @@ const (
 
 	// Internal error not necessarily correlated with the actual prevote proof.
 	HandleVoteProofsInternalError
+
+	// None of the proofs could be applied, because they were for block hashes
+	// that did not match a known proposed header (nor the nil block).
+	// This is only reported when the handler is configured to require known block hashes.
+	// It may happen when the sender has seen a proposed header that we have not yet seen.
+	HandleVoteProofsUnknownBlockHash
 )
@@ -15,11 +15,12 @@ func _() {
 	_ = x[HandleVoteProofsRoundTooOld-5]
 	_ = x[HandleVoteProofsTooFarInFuture-6]
 	_ = x[HandleVoteProofsInternalError-7]
+	_ = x[HandleVoteProofsUnknownBlockHash-8]
 }
 
-const _HandleVoteProofsResult_name = "AcceptedNoNewSignaturesEmptyBadPubKeyHashRoundTooOldTooFarInFutureInternalError"
+const _HandleVoteProofsResult_name = "AcceptedNoNewSignaturesEmptyBadPubKeyHashRoundTooOldTooFarInFutureInternalErrorUnknownBlockHash"
 
-var _HandleVoteProofsResult_index = [...]uint8{0, 8, 23, 28, 41, 52, 66, 79}
+var _HandleVoteProofsResult_index = [...]uint8{0, 8, 23, 28, 41, 52, 66, 79, 95}
 
 func (i HandleVoteProofsResult) String() string {
 	i -= 1
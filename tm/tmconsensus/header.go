@@ -2,6 +2,8 @@ package tmconsensus
 
 import (
 	"bytes"
+	"slices"
+	"time"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 )
@@ -35,12 +37,26 @@ type Header struct {
 	//
 	// The ID is typically, but not necessarily,
 	// a cryptographic hash of the application data for the block.
-	DataID []byte
+	DataID DataID
 
 	// The hash of the app state as a result of executing the previous block.
 	// Deriving this hash is an application-level concern.
+	//
+	// The engine's state machine ignores proposed headers
+	// whose PrevAppStateHash differs from the app state hash
+	// in its finalization of the previous height,
+	// so every committed header links to its parent's app state,
+	// and a light client can follow app state continuity through headers alone.
 	PrevAppStateHash []byte
 
+	// Optional time the block was proposed.
+	// The zero value indicates the header has no timestamp.
+	// When set, the value must be respected in the block's hash.
+	//
+	// Chains that rely on timestamps typically require them to increase
+	// from one block to the next; see [ValidateHeaderTimestamp].
+	Timestamp time.Time
+
 	// Arbitrary data to associate with the block.
 	// Unlike the annotations on a proposed block, these values are persisted to chain.
 	// The values must be respected in the block's hash.
@@ -56,6 +72,22 @@ type Header struct {
 	Annotations Annotations
 }
 
+// DataID is the ID of the application data for a block,
+// as set in [Header.DataID].
+//
+// The same type identifies block data arrivals reported by the driver,
+// and the entries of [ConsiderProposedBlocksReason.UpdatedBlockDataIDs].
+// Using a distinct type, rather than a plain byte slice,
+// makes it harder to accidentally compare a data ID against a different hash.
+// Format a DataID with %x, as with any other byte slice.
+type DataID []byte
+
+// Equal reports whether id and other contain exactly the same bytes.
+// A nil ID is equal to an empty ID.
+func (id DataID) Equal(other DataID) bool {
+	return bytes.Equal(id, other)
+}
+
 // CommitProof is the commit proof for a block.
 type CommitProof struct {
 	// Necessary to verify signature content.
@@ -73,24 +105,57 @@ type CommitProof struct {
 // Clone returns a new copy of CommitProof with identical values
 // but without any references to p.
 func (p CommitProof) Clone() CommitProof {
-	cloneProofs := make(map[string][]gcrypto.SparseSignature, len(p.Proofs))
-	for hash, sigs := range p.Proofs {
-		cloneSigs := make([]gcrypto.SparseSignature, len(sigs))
-		for i, sig := range sigs {
-			cloneSigs[i] = gcrypto.SparseSignature{
-				KeyID: bytes.Clone(sig.KeyID),
-				Sig:   bytes.Clone(sig.Sig),
-			}
+	return CommitProof{
+		Round:      p.Round,
+		PubKeyHash: p.PubKeyHash,
+		Proofs:     cloneSparseSignatureMap(p.Proofs),
+	}
+}
+
+// Equal reports whether p and other have the same round and public key hash,
+// and the same signatures for each block hash.
+// Unlike [PrecommitSparseProof.Equal], the order of the signatures is not significant,
+// so two proofs for the same commit compare equal
+// even if their signatures were collected in a different order,
+// such as a stored proof and one re-derived during backfill.
+func (p CommitProof) Equal(other CommitProof) bool {
+	return p.Round == other.Round &&
+		p.PubKeyHash == other.PubKeyHash &&
+		sparseSignatureMapsEquivalent(p.Proofs, other.Proofs)
+}
+
+// ValidateHeaderTimestamp checks h.Timestamp against
+// the timestamp of its parent header and the local clock.
+//
+// If parentTimestamp is set, h.Timestamp must be strictly after it.
+// If h.Timestamp is set, it must not be later than now plus maxFutureDrift.
+// When neither timestamp is set, as on chains that do not use header timestamps,
+// the header is trivially valid.
+//
+// The returned error is either a [TimestampNotIncreasingError]
+// or a [TimestampTooFarInFutureError].
+func ValidateHeaderTimestamp(
+	h Header, parentTimestamp, now time.Time, maxFutureDrift time.Duration,
+) error {
+	if !parentTimestamp.IsZero() && !h.Timestamp.After(parentTimestamp) {
+		return TimestampNotIncreasingError{
+			Parent: parentTimestamp,
+			Got:    h.Timestamp,
 		}
+	}
 
-		cloneProofs[hash] = cloneSigs
+	if h.Timestamp.IsZero() {
+		return nil
 	}
 
-	return CommitProof{
-		Round:      p.Round,
-		PubKeyHash: p.PubKeyHash,
-		Proofs:     cloneProofs,
+	if limit := now.Add(maxFutureDrift); h.Timestamp.After(limit) {
+		return TimestampTooFarInFutureError{
+			Limit: limit,
+			Got:   h.Timestamp,
+		}
 	}
+
+	return nil
 }
 
 // CommittedHeader is a header and the proof that it was committed.
@@ -99,6 +164,16 @@ type CommittedHeader struct {
 	Proof  CommitProof
 }
 
+// CommitRound returns the round in which ch's header was committed,
+// as recorded in ch's commit proof.
+//
+// The proof in ch is the same proof that a later header carries
+// as its PrevCommitProof, so this is also the PrevCommitProof.Round
+// of the header at the next height.
+func (ch CommittedHeader) CommitRound() uint32 {
+	return ch.Proof.Round
+}
+
 // ProposedBlock is the data sent by a proposer at the beginning of a round.
 // This is the logical representation within the engine,
 // not necessarily an exact representation of the data sent across the network.
@@ -136,6 +211,37 @@ type ProposedHeader struct {
 	Signature []byte
 }
 
+// SignBytes returns a new byte slice containing the proposal sign bytes for ph,
+// as defined by ss.
+// It is shorthand for calling [ProposalSignBytes]
+// with ph's Header, Round, and Annotations fields.
+func (ph ProposedHeader) SignBytes(ss SignatureScheme) ([]byte, error) {
+	return ProposalSignBytes(ph.Header, ph.Round, ph.Annotations, ss)
+}
+
+// ProposerIndex returns the index of ph.ProposerPubKey
+// within ph.Header.ValidatorSet.Validators.
+// The boolean result is false if ph has no proposer public key
+// or if the proposer is not in the header's validator set.
+func (ph ProposedHeader) ProposerIndex() (int, bool) {
+	if ph.ProposerPubKey == nil {
+		return -1, false
+	}
+
+	idx := slices.IndexFunc(ph.Header.ValidatorSet.Validators, func(v Validator) bool {
+		return v.PubKey.Equal(ph.ProposerPubKey)
+	})
+	return idx, idx >= 0
+}
+
+// AnnotationBytes returns the combined length of the annotations on ph
+// and the annotations on ph's header,
+// which together are the arbitrary data that a proposer adds to a gossiped header.
+func (ph ProposedHeader) AnnotationBytes() int {
+	return len(ph.Annotations.User) + len(ph.Annotations.Driver) +
+		len(ph.Header.Annotations.User) + len(ph.Header.Annotations.Driver)
+}
+
 // Annotations are arbitrary data to associate with a [Block] or [ProposedBlock].
 //
 // The Driver annotations are set by the driver
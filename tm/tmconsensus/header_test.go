@@ -0,0 +1,223 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposedHeader_SignBytes(t *testing.T) {
+	t.Parallel()
+
+	fx := tmconsensustest.NewStandardFixture(2)
+
+	ph := fx.NextProposedHeader([]byte("app_data"), 0)
+	ph.Round = 3
+	ph.Annotations = tmconsensus.Annotations{
+		User:   []byte("user"),
+		Driver: []byte("driver"),
+	}
+
+	got, err := ph.SignBytes(fx.SignatureScheme)
+	require.NoError(t, err)
+
+	want, err := tmconsensus.ProposalSignBytes(ph.Header, ph.Round, ph.Annotations, fx.SignatureScheme)
+	require.NoError(t, err)
+
+	require.Equal(t, want, got)
+	require.NotEmpty(t, got)
+}
+
+func TestDataID(t *testing.T) {
+	t.Parallel()
+
+	id := tmconsensus.DataID("app_data")
+
+	require.True(t, id.Equal(tmconsensus.DataID("app_data")))
+
+	// A trailing zero byte is easy to miss when printed as a string,
+	// but the IDs are not equal.
+	trailing := tmconsensus.DataID("app_data\x00")
+	require.False(t, id.Equal(trailing))
+
+	// A nil ID is equal to an empty ID.
+	require.True(t, tmconsensus.DataID(nil).Equal(tmconsensus.DataID{}))
+
+	// Formatting is the same as a plain byte slice.
+	require.Equal(t, "6170705f64617461", fmt.Sprintf("%x", id))
+	require.Equal(t, "app_data", fmt.Sprintf("%s", id))
+
+	fx := tmconsensustest.NewStandardFixture(2)
+	ph := fx.NextProposedHeader([]byte("app_data"), 0)
+	require.True(t, ph.Header.DataID.Equal(id))
+}
+
+func TestProposedHeader_ProposerIndex(t *testing.T) {
+	t.Parallel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+
+	ph := fx.NextProposedHeader([]byte("app_data"), 2)
+	fx.SignProposal(context.Background(), &ph, 2)
+	idx, ok := ph.ProposerIndex()
+	require.True(t, ok)
+	require.Equal(t, 2, idx)
+
+	// A proposer outside the header's own validator set is not found.
+	otherFx := tmconsensustest.NewStandardFixture(5)
+	ph.ProposerPubKey = otherFx.ValidatorPubKey(4)
+	_, ok = ph.ProposerIndex()
+	require.False(t, ok)
+
+	// Nor is a missing proposer.
+	ph.ProposerPubKey = nil
+	_, ok = ph.ProposerIndex()
+	require.False(t, ok)
+}
+
+func TestCommitProof_Equal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	ph := fx.NextProposedHeader([]byte("app_data"), 0)
+	blockHash := string(ph.Header.Hash)
+
+	precommits := fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+		blockHash: {0, 1, 2},
+		"":        {3},
+	})
+	orig := tmconsensus.CommitProof{
+		Round:      0,
+		PubKeyHash: string(ph.Header.ValidatorSet.PubKeyHash),
+		Proofs:     tmconsensus.FullProofsToSparse(precommits).BlockSignatures,
+	}
+	require.Len(t, orig.Proofs[blockHash], 3)
+
+	// The same signatures in a different order are equal.
+	reordered := orig.Clone()
+	slices.Reverse(reordered.Proofs[blockHash])
+	require.NotEqual(t, orig.Proofs[blockHash], reordered.Proofs[blockHash])
+	require.True(t, orig.Equal(reordered))
+	require.True(t, reordered.Equal(orig))
+
+	differentRound := orig.Clone()
+	differentRound.Round = 1
+	require.False(t, orig.Equal(differentRound))
+
+	differentPubKeyHash := orig.Clone()
+	differentPubKeyHash.PubKeyHash = "other"
+	require.False(t, orig.Equal(differentPubKeyHash))
+
+	missingSig := orig.Clone()
+	missingSig.Proofs[blockHash] = missingSig.Proofs[blockHash][1:]
+	require.False(t, orig.Equal(missingSig))
+
+	differentSig := orig.Clone()
+	differentSig.Proofs[blockHash][0].Sig[0]++
+	require.False(t, orig.Equal(differentSig))
+
+	missingHash := orig.Clone()
+	delete(missingHash.Proofs, "")
+	require.False(t, orig.Equal(missingHash))
+}
+
+func TestCommittedHeader_CommitRound(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	ph := fx.NextProposedHeader([]byte("app_data"), 0)
+	ph.Round = 2
+	blockHash := string(ph.Header.Hash)
+
+	precommits := fx.PrecommitProofMap(ctx, 1, 2, map[string][]int{
+		blockHash: {0, 1, 2, 3},
+	})
+	fx.CommitBlock(ph.Header, []byte("app_state_1"), 2, precommits)
+
+	ch := tmconsensus.CommittedHeader{
+		Header: ph.Header,
+		Proof: tmconsensus.CommitProof{
+			Round:      2,
+			PubKeyHash: string(ph.Header.ValidatorSet.PubKeyHash),
+			Proofs:     tmconsensus.FullProofsToSparse(precommits).BlockSignatures,
+		},
+	}
+	require.Equal(t, uint32(2), ch.CommitRound())
+
+	// The next header's previous commit proof agrees.
+	next := fx.NextProposedHeader([]byte("app_data_2"), 0)
+	require.Equal(t, ch.CommitRound(), next.Header.PrevCommitProof.Round)
+}
+
+func TestValidateHeaderTimestamp(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	parentTS := now.Add(-10 * time.Second)
+	const drift = 2 * time.Second
+
+	fx := tmconsensustest.NewStandardFixture(2)
+	h := fx.NextProposedHeader([]byte("app_data"), 0).Header
+
+	t.Run("no timestamps", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, tmconsensus.ValidateHeaderTimestamp(h, time.Time{}, now, drift))
+	})
+
+	t.Run("increasing and within drift", func(t *testing.T) {
+		t.Parallel()
+
+		h := h
+		h.Timestamp = now.Add(drift)
+		require.NoError(t, tmconsensus.ValidateHeaderTimestamp(h, parentTS, now, drift))
+	})
+
+	t.Run("backwards", func(t *testing.T) {
+		t.Parallel()
+
+		h := h
+		h.Timestamp = parentTS.Add(-time.Second)
+		err := tmconsensus.ValidateHeaderTimestamp(h, parentTS, now, drift)
+		require.ErrorIs(t, err, tmconsensus.TimestampNotIncreasingError{
+			Parent: parentTS,
+			Got:    h.Timestamp,
+		})
+
+		// Equal to the parent is also rejected.
+		h.Timestamp = parentTS
+		err = tmconsensus.ValidateHeaderTimestamp(h, parentTS, now, drift)
+		require.ErrorAs(t, err, new(tmconsensus.TimestampNotIncreasingError))
+
+		// And so is a missing timestamp, when the parent has one.
+		h.Timestamp = time.Time{}
+		err = tmconsensus.ValidateHeaderTimestamp(h, parentTS, now, drift)
+		require.ErrorAs(t, err, new(tmconsensus.TimestampNotIncreasingError))
+	})
+
+	t.Run("too far in future", func(t *testing.T) {
+		t.Parallel()
+
+		h := h
+		h.Timestamp = now.Add(drift + time.Nanosecond)
+		err := tmconsensus.ValidateHeaderTimestamp(h, parentTS, now, drift)
+		require.ErrorIs(t, err, tmconsensus.TimestampTooFarInFutureError{
+			Limit: now.Add(drift),
+			Got:   h.Timestamp,
+		})
+
+		// Without a parent timestamp, the drift is still enforced.
+		err = tmconsensus.ValidateHeaderTimestamp(h, time.Time{}, now, drift)
+		require.ErrorAs(t, err, new(tmconsensus.TimestampTooFarInFutureError))
+	})
+}
@@ -0,0 +1,159 @@
+package tmconsensus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/gcrypto"
+)
+
+// VerifyHeaderChain verifies that headers is a sequence of consecutive committed headers,
+// where the first header's validator set is trustedSet.
+//
+// For each header, VerifyHeaderChain confirms that:
+//   - the header's validator set matches the trusted set,
+//     which is trustedSet for the first header
+//     and the previous header's NextValidatorSet for every later header;
+//   - the header's commit proof contains valid precommits
+//     from a majority of the trusted set's voting power, for the header's hash;
+//   - for every header after the first, the height is one greater than the previous header,
+//     the PrevBlockHash matches the previous header's hash,
+//     and the PrevCommitProof is a valid commit proof for the previous header.
+//
+// Only the precommits for the committed block hash are checked;
+// precommits for other blocks or for nil, which a commit proof may also contain, are ignored.
+//
+// VerifyHeaderChain does not recalculate header hashes,
+// as that requires a [HashScheme].
+// Callers receiving headers from an untrusted source
+// must confirm each header's hash before relying on the other header fields.
+//
+// An empty headers slice is trivially valid.
+func VerifyHeaderChain(
+	headers []CommittedHeader,
+	trustedSet ValidatorSet,
+	ss SignatureScheme,
+	cmsp gcrypto.CommonMessageSignatureProofScheme,
+) error {
+	curSet := trustedSet
+	for i, ch := range headers {
+		h := ch.Header
+
+		if !h.ValidatorSet.Equal(curSet) {
+			return fmt.Errorf(
+				"header at index %d (height %d): validator set does not match trusted set",
+				i, h.Height,
+			)
+		}
+
+		if i > 0 {
+			prev := headers[i-1].Header
+			if h.Height != prev.Height+1 {
+				return fmt.Errorf("header at index %d: %w", i, HeightMismatchError{
+					Want: prev.Height + 1,
+					Got:  h.Height,
+				})
+			}
+
+			if !bytes.Equal(h.PrevBlockHash, prev.Hash) {
+				return fmt.Errorf("header at index %d (height %d): %w", i, h.Height, PreviousHashMismatchError{
+					Want: prev.Hash,
+					Got:  h.PrevBlockHash,
+				})
+			}
+
+			if err := verifyCommitProof(
+				prev.Height, string(prev.Hash), h.PrevCommitProof, prev.ValidatorSet, ss, cmsp,
+			); err != nil {
+				return fmt.Errorf(
+					"header at index %d (height %d): invalid previous commit proof: %w",
+					i, h.Height, err,
+				)
+			}
+		}
+
+		if err := verifyCommitProof(
+			h.Height, string(h.Hash), ch.Proof, curSet, ss, cmsp,
+		); err != nil {
+			return fmt.Errorf(
+				"header at index %d (height %d): invalid commit proof: %w",
+				i, h.Height, err,
+			)
+		}
+
+		curSet = h.NextValidatorSet
+	}
+
+	return nil
+}
+
+// verifyCommitProof confirms that cp contains valid precommits for blockHash
+// at the given height, from a majority of the voting power in vs.
+func verifyCommitProof(
+	height uint64,
+	blockHash string,
+	cp CommitProof,
+	vs ValidatorSet,
+	ss SignatureScheme,
+	cmsp gcrypto.CommonMessageSignatureProofScheme,
+) error {
+	if cp.PubKeyHash != string(vs.PubKeyHash) {
+		return fmt.Errorf(
+			"public key hash mismatch: expected %X, got %X",
+			vs.PubKeyHash, cp.PubKeyHash,
+		)
+	}
+
+	var availablePower uint64
+	for _, v := range vs.Validators {
+		availablePower += v.Power
+	}
+	if availablePower == 0 {
+		return errors.New("validator set has no voting power")
+	}
+
+	sigs := cp.Proofs[blockHash]
+	if len(sigs) == 0 {
+		return fmt.Errorf("no precommits for block %X", blockHash)
+	}
+
+	msg, err := PrecommitSignBytes(VoteTarget{
+		Height:    height,
+		Round:     cp.Round,
+		BlockHash: blockHash,
+	}, ss)
+	if err != nil {
+		return fmt.Errorf("failed to build precommit sign bytes: %w", err)
+	}
+
+	proof, err := cmsp.New(msg, ValidatorsToPubKeys(vs.Validators), string(vs.PubKeyHash))
+	if err != nil {
+		return fmt.Errorf("failed to build signature proof: %w", err)
+	}
+
+	res := proof.MergeSparse(gcrypto.SparseSignatureProof{
+		PubKeyHash: cp.PubKeyHash,
+		Signatures: sigs,
+	})
+	if !res.AllValidSignatures {
+		return fmt.Errorf("invalid precommit signature for block %X", blockHash)
+	}
+
+	var bs bitset.BitSet
+	proof.SignatureBitSet(&bs)
+	var blockPow uint64
+	for i, ok := bs.NextSet(0); ok && int(i) < len(vs.Validators); i, ok = bs.NextSet(i + 1) {
+		blockPow += vs.Validators[int(i)].Power
+	}
+
+	if maj := ByzantineMajority(availablePower); blockPow < maj {
+		return fmt.Errorf(
+			"insufficient precommit power for block %X: need %d, got %d",
+			blockHash, maj, blockPow,
+		)
+	}
+
+	return nil
+}
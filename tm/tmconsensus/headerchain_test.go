@@ -0,0 +1,95 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyHeaderChain(t *testing.T) {
+	t.Parallel()
+
+	// newChain returns three consecutive committed headers,
+	// each committed with precommits from the given validator indices.
+	newChain := func(t *testing.T, signers []int) (
+		*tmconsensustest.StandardFixture, []tmconsensus.CommittedHeader,
+	) {
+		t.Helper()
+
+		ctx := context.Background()
+		fx := tmconsensustest.NewStandardFixture(4)
+
+		var phs []tmconsensus.ProposedHeader
+		for h := uint64(1); h <= 4; h++ {
+			ph := fx.NextProposedHeader([]byte(fmt.Sprintf("app_data_%d", h)), 0)
+			phs = append(phs, ph)
+
+			voteMap := map[string][]int{string(ph.Header.Hash): signers}
+			fx.CommitBlock(
+				ph.Header, []byte(fmt.Sprintf("app_state_%d", h)), 0,
+				fx.PrecommitProofMap(ctx, h, 0, voteMap),
+			)
+		}
+
+		chs := make([]tmconsensus.CommittedHeader, 3)
+		for i := range chs {
+			chs[i] = tmconsensus.CommittedHeader{
+				Header: phs[i].Header,
+				Proof:  phs[i+1].Header.PrevCommitProof,
+			}
+		}
+		return fx, chs
+	}
+
+	t.Run("valid chain", func(t *testing.T) {
+		t.Parallel()
+
+		fx, chs := newChain(t, []int{0, 1, 2, 3})
+		require.NoError(t, tmconsensus.VerifyHeaderChain(
+			chs, fx.ValSet(), fx.SignatureScheme, fx.CommonMessageSignatureProofScheme,
+		))
+	})
+
+	t.Run("broken link", func(t *testing.T) {
+		t.Parallel()
+
+		fx, chs := newChain(t, []int{0, 1, 2, 3})
+		chs[2].Header.PrevBlockHash = []byte("not_the_previous_hash")
+
+		err := tmconsensus.VerifyHeaderChain(
+			chs, fx.ValSet(), fx.SignatureScheme, fx.CommonMessageSignatureProofScheme,
+		)
+		var mismatch tmconsensus.PreviousHashMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		require.Equal(t, chs[1].Header.Hash, mismatch.Want)
+		require.ErrorContains(t, err, "index 2")
+	})
+
+	t.Run("insufficient precommit power", func(t *testing.T) {
+		t.Parallel()
+
+		// Two of four equally weighted validators is not a majority.
+		fx, chs := newChain(t, []int{0, 1})
+		err := tmconsensus.VerifyHeaderChain(
+			chs, fx.ValSet(), fx.SignatureScheme, fx.CommonMessageSignatureProofScheme,
+		)
+		require.ErrorContains(t, err, "insufficient precommit power")
+	})
+
+	t.Run("untrusted validator set", func(t *testing.T) {
+		t.Parallel()
+
+		fx, chs := newChain(t, []int{0, 1, 2, 3})
+		otherSet, err := tmconsensus.NewValidatorSet(fx.Vals()[1:], fx.HashScheme)
+		require.NoError(t, err)
+
+		err = tmconsensus.VerifyHeaderChain(
+			chs, otherSet, fx.SignatureScheme, fx.CommonMessageSignatureProofScheme,
+		)
+		require.ErrorContains(t, err, "does not match trusted set")
+	})
+}
@@ -28,6 +28,10 @@ func ByzantineMajority(n uint64) uint64 {
 // unless both the for and against votes have reached the minority,
 // in which case it is impossible for one vote to reach the majority.
 //
+// The engine uses ByzantineMinority and [ByzantineMajority] for all of its voting thresholds,
+// so consensus strategies should use these functions as well
+// in order to agree with the engine on exact boundaries.
+//
 // ByzantineMinority(0) panics.
 func ByzantineMinority(n uint64) uint64 {
 	if n == 0 {
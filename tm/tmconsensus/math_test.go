@@ -59,3 +59,22 @@ func TestByzantineMinority(t *testing.T) {
 		_ = tmconsensus.ByzantineMinority(0)
 	})
 }
+
+func TestByzantineThresholds_boundaries(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []uint64{1, 2, 3, 4, 5, 6, 7, 9, 10, 11, 99, 100, 101, 1_000_000, 1_000_001} {
+		maj := tmconsensus.ByzantineMajority(n)
+		min := tmconsensus.ByzantineMinority(n)
+
+		// The majority is the smallest value strictly exceeding 2/3 of n,
+		// so one less than the majority must not exceed 2/3 of n.
+		require.Greaterf(t, 3*maj, 2*n, "majority for n=%d", n)
+		require.LessOrEqualf(t, 3*(maj-1), 2*n, "majority for n=%d", n)
+
+		// The minority is the smallest value reaching 1/3 of n,
+		// so one less than the minority must fall short of 1/3 of n.
+		require.GreaterOrEqualf(t, 3*min, n, "minority for n=%d", n)
+		require.Lessf(t, 3*(min-1), n, "minority for n=%d", n)
+	}
+}
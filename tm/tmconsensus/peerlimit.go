@@ -0,0 +1,143 @@
+package tmconsensus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gordian-engine/gordian/gexchange"
+)
+
+type peerIDContextKey struct{}
+
+// WithPeerID returns a child of ctx carrying the ID of the peer
+// that sent the message being handled.
+//
+// Network implementations should set the peer ID on the context
+// passed to a [ConsensusHandler],
+// so that wrappers such as [PeerConcurrencyLimitedHandler] can attribute work to a peer.
+func WithPeerID(ctx context.Context, peerID string) context.Context {
+	return context.WithValue(ctx, peerIDContextKey{}, peerID)
+}
+
+// PeerIDFromContext returns the peer ID set through [WithPeerID],
+// and whether a peer ID was set.
+func PeerIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(peerIDContextKey{}).(string)
+	return id, ok
+}
+
+// PeerConcurrencyLimitedHandler is a [ConsensusHandler]
+// that bounds how many vote proof messages from a single peer
+// may be handled concurrently by the wrapped Handler.
+//
+// Handling vote proofs involves verifying every signature in the message,
+// so without a bound, one peer flooding vote proofs
+// could occupy all available verification capacity.
+// Once a peer has the maximum number of vote proofs in flight,
+// further vote proofs from that peer are ignored, without consulting the wrapped Handler,
+// until one of the in-flight messages completes.
+// Messages from other peers are unaffected.
+//
+// Peers are identified through [PeerIDFromContext].
+// Messages whose context has no peer ID, and all proposed headers,
+// are passed directly to the wrapped Handler.
+//
+// Use [NewPeerConcurrencyLimitedHandler] to create a PeerConcurrencyLimitedHandler.
+type PeerConcurrencyLimitedHandler struct {
+	Handler ConsensusHandler
+
+	maxPerPeer int
+
+	mu sync.Mutex
+
+	// Number of in-flight vote proof messages, keyed by peer ID.
+	// Peers with no messages in flight are removed.
+	inFlight map[string]int
+}
+
+// NewPeerConcurrencyLimitedHandler returns a PeerConcurrencyLimitedHandler
+// wrapping handler, allowing up to maxPerPeer concurrent vote proof messages per peer.
+//
+// NewPeerConcurrencyLimitedHandler panics if maxPerPeer is not positive.
+func NewPeerConcurrencyLimitedHandler(
+	handler ConsensusHandler, maxPerPeer int,
+) *PeerConcurrencyLimitedHandler {
+	if maxPerPeer <= 0 {
+		panic(fmt.Errorf(
+			"NewPeerConcurrencyLimitedHandler: maxPerPeer must be positive (got %d)", maxPerPeer,
+		))
+	}
+
+	return &PeerConcurrencyLimitedHandler{
+		Handler: handler,
+
+		maxPerPeer: maxPerPeer,
+
+		inFlight: make(map[string]int),
+	}
+}
+
+func (h *PeerConcurrencyLimitedHandler) HandleProposedHeader(
+	ctx context.Context, ph ProposedHeader,
+) gexchange.Feedback {
+	return h.Handler.HandleProposedHeader(ctx, ph)
+}
+
+func (h *PeerConcurrencyLimitedHandler) HandlePrevoteProofs(
+	ctx context.Context, p PrevoteSparseProof,
+) gexchange.Feedback {
+	peerID, ok := PeerIDFromContext(ctx)
+	if !ok {
+		return h.Handler.HandlePrevoteProofs(ctx, p)
+	}
+
+	if !h.acquire(peerID) {
+		return gexchange.FeedbackIgnored
+	}
+	defer h.release(peerID)
+
+	return h.Handler.HandlePrevoteProofs(ctx, p)
+}
+
+func (h *PeerConcurrencyLimitedHandler) HandlePrecommitProofs(
+	ctx context.Context, p PrecommitSparseProof,
+) gexchange.Feedback {
+	peerID, ok := PeerIDFromContext(ctx)
+	if !ok {
+		return h.Handler.HandlePrecommitProofs(ctx, p)
+	}
+
+	if !h.acquire(peerID) {
+		return gexchange.FeedbackIgnored
+	}
+	defer h.release(peerID)
+
+	return h.Handler.HandlePrecommitProofs(ctx, p)
+}
+
+// acquire reserves an in-flight slot for peerID,
+// reporting false if the peer already has the maximum number of messages in flight.
+func (h *PeerConcurrencyLimitedHandler) acquire(peerID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := h.inFlight[peerID]
+	if n >= h.maxPerPeer {
+		return false
+	}
+	h.inFlight[peerID] = n + 1
+	return true
+}
+
+// release frees a slot previously reserved through acquire.
+func (h *PeerConcurrencyLimitedHandler) release(peerID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n := h.inFlight[peerID]; n <= 1 {
+		delete(h.inFlight, peerID)
+	} else {
+		h.inFlight[peerID] = n - 1
+	}
+}
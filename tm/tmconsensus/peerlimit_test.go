@@ -0,0 +1,95 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gordian-engine/gordian/gexchange"
+	"github.com/gordian-engine/gordian/internal/gtest"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingVoteHandler is a ConsensusHandler whose vote handling
+// reports the calling peer on Started and then blocks until Release is closed.
+type blockingVoteHandler struct {
+	Started chan string
+	Release chan struct{}
+}
+
+func (h blockingVoteHandler) HandleProposedHeader(
+	context.Context, tmconsensus.ProposedHeader,
+) gexchange.Feedback {
+	return gexchange.FeedbackAccepted
+}
+
+func (h blockingVoteHandler) HandlePrevoteProofs(
+	ctx context.Context, _ tmconsensus.PrevoteSparseProof,
+) gexchange.Feedback {
+	return h.block(ctx)
+}
+
+func (h blockingVoteHandler) HandlePrecommitProofs(
+	ctx context.Context, _ tmconsensus.PrecommitSparseProof,
+) gexchange.Feedback {
+	return h.block(ctx)
+}
+
+func (h blockingVoteHandler) block(ctx context.Context) gexchange.Feedback {
+	peerID, _ := tmconsensus.PeerIDFromContext(ctx)
+	h.Started <- peerID
+	<-h.Release
+	return gexchange.FeedbackAccepted
+}
+
+func TestPeerConcurrencyLimitedHandler(t *testing.T) {
+	t.Parallel()
+
+	inner := blockingVoteHandler{
+		Started: make(chan string, 8),
+		Release: make(chan struct{}),
+	}
+	h := tmconsensus.NewPeerConcurrencyLimitedHandler(inner, 2)
+
+	ctx := context.Background()
+	ctxA := tmconsensus.WithPeerID(ctx, "peer_a")
+	ctxB := tmconsensus.WithPeerID(ctx, "peer_b")
+
+	// Peer A fills its two slots with one prevote and one precommit.
+	results := make(chan gexchange.Feedback, 8)
+	go func() { results <- h.HandlePrevoteProofs(ctxA, tmconsensus.PrevoteSparseProof{}) }()
+	go func() { results <- h.HandlePrecommitProofs(ctxA, tmconsensus.PrecommitSparseProof{}) }()
+	require.Equal(t, "peer_a", gtest.ReceiveSoon(t, inner.Started))
+	require.Equal(t, "peer_a", gtest.ReceiveSoon(t, inner.Started))
+
+	// Further messages from peer A are throttled without reaching the inner handler.
+	for range 3 {
+		require.Equal(t, gexchange.FeedbackIgnored, h.HandlePrevoteProofs(ctxA, tmconsensus.PrevoteSparseProof{}))
+		require.Equal(t, gexchange.FeedbackIgnored, h.HandlePrecommitProofs(ctxA, tmconsensus.PrecommitSparseProof{}))
+	}
+
+	// But peer B still reaches the inner handler immediately.
+	go func() { results <- h.HandlePrevoteProofs(ctxB, tmconsensus.PrevoteSparseProof{}) }()
+	require.Equal(t, "peer_b", gtest.ReceiveSoon(t, inner.Started))
+
+	// As do messages without a peer ID.
+	go func() { results <- h.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{}) }()
+	require.Equal(t, "", gtest.ReceiveSoon(t, inner.Started))
+
+	// Once the in-flight messages complete, peer A is no longer throttled.
+	close(inner.Release)
+	for range 4 {
+		require.Equal(t, gexchange.FeedbackAccepted, gtest.ReceiveSoon(t, results))
+	}
+
+	require.Equal(t, gexchange.FeedbackAccepted, h.HandlePrevoteProofs(ctxA, tmconsensus.PrevoteSparseProof{}))
+	require.Equal(t, "peer_a", gtest.ReceiveSoon(t, inner.Started))
+}
+
+func TestNewPeerConcurrencyLimitedHandler_panicsOnNonPositiveLimit(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		tmconsensus.NewPeerConcurrencyLimitedHandler(blockingVoteHandler{}, 0)
+	})
+}
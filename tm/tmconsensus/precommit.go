@@ -2,7 +2,6 @@ package tmconsensus
 
 import (
 	"fmt"
-	"slices"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 )
@@ -52,6 +51,13 @@ type PrecommitSparseProof struct {
 	Proofs map[string][]gcrypto.SparseSignature
 }
 
+// PrecommitSparseProofFromFullProof extracts the sparse signatures
+// from each full precommit proof in fullProof, keyed by block hash,
+// producing the form that gossip strategies send across the network.
+// The result is suitable for a [ConsensusHandler]'s HandlePrecommitProofs method.
+//
+// An error is returned if the proofs in fullProof
+// do not all share the same public key hash.
 func PrecommitSparseProofFromFullProof(height uint64, round uint32, fullProof map[string]gcrypto.CommonMessageSignatureProof) (PrecommitSparseProof, error) {
 	p := PrecommitSparseProof{
 		Height: height,
@@ -78,21 +84,28 @@ func PrecommitSparseProofFromFullProof(height uint64, round uint32, fullProof ma
 	return p, nil
 }
 
+// Clone returns a deep copy of p,
+// sharing no maps, slices, or signature bytes with p.
 func (p PrecommitSparseProof) Clone() PrecommitSparseProof {
-	m := make(map[string][]gcrypto.SparseSignature, len(p.Proofs))
-	for k, v := range p.Proofs {
-		m[k] = slices.Clone(v)
-	}
 	return PrecommitSparseProof{
 		Height: p.Height,
 		Round:  p.Round,
 
 		PubKeyHash: p.PubKeyHash,
 
-		Proofs: m,
+		Proofs: cloneSparseSignatureMap(p.Proofs),
 	}
 }
 
+// Equal reports whether p and other have the same height, round, and public key hash,
+// and the same signatures for each block hash, in the same order.
+func (p PrecommitSparseProof) Equal(other PrecommitSparseProof) bool {
+	return p.Height == other.Height &&
+		p.Round == other.Round &&
+		p.PubKeyHash == other.PubKeyHash &&
+		sparseSignatureMapsEqual(p.Proofs, other.Proofs)
+}
+
 func (p PrecommitSparseProof) ToFull(
 	cmsps gcrypto.CommonMessageSignatureProofScheme,
 	sigScheme SignatureScheme,
@@ -0,0 +1,56 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrecommitSparseProof_Clone(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fx := tmconsensustest.NewStandardFixture(4)
+	keyHash, _ := fx.ValidatorHashes()
+
+	orig := tmconsensus.PrecommitSparseProof{
+		Height: 1,
+		Round:  2,
+
+		PubKeyHash: keyHash,
+
+		Proofs: fx.SparsePrecommitProofMap(ctx, 1, 2, map[string][]int{
+			"block": {0, 1},
+			"":      {2},
+		}),
+	}
+
+	clone := orig.Clone()
+	require.True(t, orig.Equal(clone))
+	require.True(t, clone.Equal(orig))
+
+	// Mutating the signature bytes of the clone does not affect the original.
+	clone.Proofs["block"][0].Sig[0]++
+	require.False(t, orig.Equal(clone))
+	clone = orig.Clone()
+
+	clone.Proofs["block"][0].KeyID = append(clone.Proofs["block"][0].KeyID, 0)
+	require.False(t, orig.Equal(clone))
+	clone = orig.Clone()
+
+	// Nor does replacing or removing entries.
+	clone.Proofs["block"] = clone.Proofs["block"][:1]
+	require.False(t, orig.Equal(clone))
+	require.Len(t, orig.Proofs["block"], 2)
+
+	delete(clone.Proofs, "")
+	require.Contains(t, orig.Proofs, "")
+
+	// Scalar fields participate in equality too.
+	clone = orig.Clone()
+	clone.Round++
+	require.False(t, orig.Equal(clone))
+}
@@ -2,7 +2,6 @@ package tmconsensus
 
 import (
 	"fmt"
-	"slices"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 )
@@ -51,6 +50,13 @@ type PrevoteSparseProof struct {
 	Proofs map[string][]gcrypto.SparseSignature
 }
 
+// PrevoteSparseProofFromFullProof extracts the sparse signatures
+// from each full prevote proof in fullProof, keyed by block hash,
+// producing the form that gossip strategies send across the network.
+// The result is suitable for a [ConsensusHandler]'s HandlePrevoteProofs method.
+//
+// An error is returned if the proofs in fullProof
+// do not all share the same public key hash.
 func PrevoteSparseProofFromFullProof(height uint64, round uint32, fullProof map[string]gcrypto.CommonMessageSignatureProof) (PrevoteSparseProof, error) {
 	p := PrevoteSparseProof{
 		Height: height,
@@ -77,21 +83,28 @@ func PrevoteSparseProofFromFullProof(height uint64, round uint32, fullProof map[
 	return p, nil
 }
 
+// Clone returns a deep copy of p,
+// sharing no maps, slices, or signature bytes with p.
 func (p PrevoteSparseProof) Clone() PrevoteSparseProof {
-	m := make(map[string][]gcrypto.SparseSignature, len(p.Proofs))
-	for k, v := range p.Proofs {
-		m[k] = slices.Clone(v)
-	}
 	return PrevoteSparseProof{
 		Height: p.Height,
 		Round:  p.Round,
 
 		PubKeyHash: p.PubKeyHash,
 
-		Proofs: m,
+		Proofs: cloneSparseSignatureMap(p.Proofs),
 	}
 }
 
+// Equal reports whether p and other have the same height, round, and public key hash,
+// and the same signatures for each block hash, in the same order.
+func (p PrevoteSparseProof) Equal(other PrevoteSparseProof) bool {
+	return p.Height == other.Height &&
+		p.Round == other.Round &&
+		p.PubKeyHash == other.PubKeyHash &&
+		sparseSignatureMapsEqual(p.Proofs, other.Proofs)
+}
+
 func (p PrevoteSparseProof) ToFull(
 	cmsps gcrypto.CommonMessageSignatureProofScheme,
 	sigScheme SignatureScheme,
@@ -99,6 +99,128 @@ func (v *RoundView) ResetForSameHeight() {
 	v.VoteSummary.ResetForSameHeight()
 }
 
+// CommitCandidate reports the block hash that has reached majority precommit power
+// in v, according to the validators in vs.
+//
+// The precommit power is calculated directly from v.PrecommitProofs,
+// so this method does not depend on v.VoteSummary having been populated.
+// This makes it suitable for external verifiers who only have the proofs.
+//
+// If no block has majority precommit power, or if the majority power is for nil,
+// CommitCandidate returns the empty string and false.
+func (v RoundView) CommitCandidate(vs ValidatorSet) (blockHash string, committed bool) {
+	var availablePower uint64
+	for _, val := range vs.Validators {
+		availablePower += val.Power
+	}
+	if availablePower == 0 {
+		return "", false
+	}
+
+	maj := ByzantineMajority(availablePower)
+
+	var bs bitset.BitSet
+	for hash, proof := range v.PrecommitProofs {
+		if hash == "" {
+			// A nil precommit majority is never a commit.
+			continue
+		}
+
+		proof.SignatureBitSet(&bs)
+		var blockPow uint64
+		for i, ok := bs.NextSet(0); ok && int(i) < len(vs.Validators); i, ok = bs.NextSet(i + 1) {
+			blockPow += vs.Validators[int(i)].Power
+		}
+
+		if blockPow >= maj {
+			return hash, true
+		}
+	}
+
+	return "", false
+}
+
+// PrevotePowerPresent returns the total voting power of the distinct validators
+// who have prevoted in v, across all block hashes including nil.
+//
+// Unlike v.VoteSummary.TotalPrevotePower, a validator with signatures
+// for more than one block hash only counts once.
+// The power is calculated from v.PrevoteProofs and v.ValidatorSet.
+func (v RoundView) PrevotePowerPresent() uint64 {
+	return distinctSignerPower(v.ValidatorSet.Validators, v.PrevoteProofs)
+}
+
+// PrecommitPowerPresent returns the total voting power of the distinct validators
+// who have precommitted in v, across all block hashes including nil.
+//
+// Unlike v.VoteSummary.TotalPrecommitPower, a validator with signatures
+// for more than one block hash only counts once.
+// The power is calculated from v.PrecommitProofs and v.ValidatorSet.
+func (v RoundView) PrecommitPowerPresent() uint64 {
+	return distinctSignerPower(v.ValidatorSet.Validators, v.PrecommitProofs)
+}
+
+// VotedBlockHashes returns the sorted, distinct block hashes
+// that have at least one prevote or precommit in v.
+// A vote for nil is reported as the empty string.
+//
+// Proofs without any signatures do not contribute a block hash.
+func (v RoundView) VotedBlockHashes() []string {
+	seen := make(map[string]struct{}, len(v.PrevoteProofs)+len(v.PrecommitProofs))
+	var bs bitset.BitSet
+	for _, proofs := range []map[string]gcrypto.CommonMessageSignatureProof{
+		v.PrevoteProofs, v.PrecommitProofs,
+	} {
+		for hash, proof := range proofs {
+			proof.SignatureBitSet(&bs)
+			if bs.Any() {
+				seen[hash] = struct{}{}
+			}
+		}
+	}
+
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// NonVoters returns the validators in vs who have no prevote in v,
+// for any block hash including nil, in the order they appear in vs.
+// The signature bits of v.PrevoteProofs are interpreted as indices into vs.Validators.
+//
+// A single view only reflects the votes that have arrived so far,
+// so operators monitoring liveness should look for validators
+// that are reported as non-voters across many heights.
+func (v RoundView) NonVoters(vs ValidatorSet) []Validator {
+	var voters, bs bitset.BitSet
+	for _, proof := range v.PrevoteProofs {
+		proof.SignatureBitSet(&bs)
+		voters.InPlaceUnion(&bs)
+	}
+
+	var nonVoters []Validator
+	for i, val := range vs.Validators {
+		if !voters.Test(uint(i)) {
+			nonVoters = append(nonVoters, val)
+		}
+	}
+	return nonVoters
+}
+
+// distinctSignerPower sums the power of each validator in vals
+// who has a signature in any of the given proofs.
+func distinctSignerPower(vals []Validator, proofs map[string]gcrypto.CommonMessageSignatureProof) uint64 {
+	var signers, bs bitset.BitSet
+	for _, proof := range proofs {
+		proof.SignatureBitSet(&bs)
+		signers.InPlaceUnion(&bs)
+	}
+
+	var pow uint64
+	for i, ok := signers.NextSet(0); ok && int(i) < len(vals); i, ok = signers.NextSet(i + 1) {
+		pow += vals[int(i)].Power
+	}
+	return pow
+}
+
 // LogValue converts v into an slog.Value.
 // This provides a highly detailed log, so it is only appropriate for infrequent log events,
 // such as responding to a watchdog termination signal.
@@ -245,6 +367,25 @@ func (v *VersionedRoundView) resetVersions() {
 	clear(v.PrecommitBlockVersions)
 }
 
+// RecomputeVoteSummary sets every field of v.VoteSummary
+// from v.PrevoteProofs and v.PrecommitProofs, according to the validators in vs.
+//
+// This is the same derivation the engine performs when it builds a view,
+// so it is useful for code outside the engine that constructs or modifies a view directly.
+// The version fields on v are not modified.
+func (v *VersionedRoundView) RecomputeVoteSummary(vs ValidatorSet) {
+	s := &v.VoteSummary
+	if s.PrevoteBlockPower == nil {
+		s.PrevoteBlockPower = make(map[string]uint64, len(v.PrevoteProofs))
+	}
+	if s.PrecommitBlockPower == nil {
+		s.PrecommitBlockPower = make(map[string]uint64, len(v.PrecommitProofs))
+	}
+
+	s.SetAvailablePower(vs.Validators)
+	s.SetVotePowers(vs.Validators, v.PrevoteProofs, v.PrecommitProofs)
+}
+
 // LogValue converts v into an slog.Value.
 // This provides a highly detailed log, so it is only appropriate for infrequent log events,
 // such as responding to a watchdog termination signal.
@@ -0,0 +1,343 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundView_CommitCandidate(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	vs := fx.ValSet()
+
+	t.Run("majority precommits for one block", func(t *testing.T) {
+		rv := tmconsensus.RoundView{
+			Height:       1,
+			ValidatorSet: vs,
+			PrecommitProofs: fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+				"":           {0},
+				"some_block": {1, 2, 3},
+			}),
+		}
+
+		hash, ok := rv.CommitCandidate(vs)
+		require.True(t, ok)
+		require.Equal(t, "some_block", hash)
+	})
+
+	t.Run("split precommits", func(t *testing.T) {
+		rv := tmconsensus.RoundView{
+			Height:       1,
+			ValidatorSet: vs,
+			PrecommitProofs: fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+				"block_a": {0, 1},
+				"block_b": {2, 3},
+			}),
+		}
+
+		hash, ok := rv.CommitCandidate(vs)
+		require.False(t, ok)
+		require.Empty(t, hash)
+	})
+
+	t.Run("majority nil precommits", func(t *testing.T) {
+		rv := tmconsensus.RoundView{
+			Height:       1,
+			ValidatorSet: vs,
+			PrecommitProofs: fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+				"": {0, 1, 2, 3},
+			}),
+		}
+
+		hash, ok := rv.CommitCandidate(vs)
+		require.False(t, ok)
+		require.Empty(t, hash)
+	})
+
+	t.Run("no precommits", func(t *testing.T) {
+		rv := tmconsensus.RoundView{
+			Height:       1,
+			ValidatorSet: vs,
+		}
+
+		hash, ok := rv.CommitCandidate(vs)
+		require.False(t, ok)
+		require.Empty(t, hash)
+	})
+}
+
+func TestRoundView_VotePowerPresent(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	vs := fx.ValSet()
+
+	var threePower uint64
+	for _, v := range vs.Validators[:3] {
+		threePower += v.Power
+	}
+
+	t.Run("prevotes across block and nil", func(t *testing.T) {
+		rv := tmconsensus.RoundView{
+			Height:       1,
+			ValidatorSet: vs,
+			PrevoteProofs: fx.PrevoteProofMap(ctx, 1, 0, map[string][]int{
+				"some_block": {0, 1},
+				"":           {2},
+			}),
+		}
+
+		require.Equal(t, threePower, rv.PrevotePowerPresent())
+		require.Zero(t, rv.PrecommitPowerPresent())
+	})
+
+	t.Run("validator precommitting for two blocks is only counted once", func(t *testing.T) {
+		rv := tmconsensus.RoundView{
+			Height:       1,
+			ValidatorSet: vs,
+			PrecommitProofs: fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+				"block_a": {0, 1},
+				"block_b": {1, 2},
+			}),
+		}
+
+		require.Equal(t, threePower, rv.PrecommitPowerPresent())
+		require.Zero(t, rv.PrevotePowerPresent())
+	})
+}
+
+func TestRoundView_VotedBlockHashes(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+
+	rv := tmconsensus.RoundView{
+		Height:       1,
+		ValidatorSet: fx.ValSet(),
+		PrevoteProofs: fx.PrevoteProofMap(ctx, 1, 0, map[string][]int{
+			"block_a": {0, 1},
+			"":        {2},
+		}),
+		PrecommitProofs: fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+			"block_a": {0},
+			"block_b": {3},
+		}),
+	}
+
+	require.Equal(t, []string{"", "block_a", "block_b"}, rv.VotedBlockHashes())
+
+	// A proof without any signatures does not count as a vote.
+	empty := fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+		"block_c": nil,
+	})
+	rv.PrecommitProofs["block_c"] = empty["block_c"]
+	require.Equal(t, []string{"", "block_a", "block_b"}, rv.VotedBlockHashes())
+
+	require.Empty(t, tmconsensus.RoundView{}.VotedBlockHashes())
+}
+
+func TestRoundView_NonVoters(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	vs := fx.ValSet()
+
+	rv := tmconsensus.RoundView{
+		Height:       1,
+		ValidatorSet: vs,
+		PrevoteProofs: fx.PrevoteProofMap(ctx, 1, 0, map[string][]int{
+			"block_a": {0, 3},
+			"":        {1},
+		}),
+	}
+
+	nonVoters := rv.NonVoters(vs)
+	require.Len(t, nonVoters, 1)
+	require.True(t, vs.Validators[2].PubKey.Equal(nonVoters[0].PubKey))
+
+	// Precommits do not count as prevotes.
+	rv.PrecommitProofs = fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+		"block_a": {2},
+	})
+	require.Len(t, rv.NonVoters(vs), 1)
+
+	// With no prevotes, every validator is a non-voter.
+	require.Len(t, tmconsensus.RoundView{}.NonVoters(vs), 4)
+}
+
+func TestVersionedRoundView_RecomputeVoteSummary(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	vs := fx.ValSet()
+	vals := fx.Vals()
+
+	// Start from a view with no vote summary maps at all.
+	vrv := tmconsensus.VersionedRoundView{
+		RoundView: tmconsensus.RoundView{
+			Height:       1,
+			ValidatorSet: vs,
+		},
+	}
+
+	vrv.RecomputeVoteSummary(vs)
+	require.Equal(
+		t,
+		vals[0].Power+vals[1].Power+vals[2].Power+vals[3].Power,
+		vrv.VoteSummary.AvailablePower,
+	)
+	require.Zero(t, vrv.VoteSummary.TotalPrevotePower)
+	require.Empty(t, vrv.VoteSummary.PrevoteBlockPower)
+
+	// Now add prevotes directly to the proof map.
+	vrv.PrevoteProofs = fx.PrevoteProofMap(ctx, 1, 0, map[string][]int{
+		"some_block": {0, 1, 2},
+		"":           {3},
+	})
+	vrv.PrecommitProofs = fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+		"some_block": {0},
+	})
+	vrv.RecomputeVoteSummary(vs)
+
+	s := vrv.VoteSummary
+	require.Equal(t, vals[0].Power+vals[1].Power+vals[2].Power, s.PrevoteBlockPower["some_block"])
+	require.Equal(t, vals[3].Power, s.PrevoteBlockPower[""])
+	require.Equal(t, s.AvailablePower, s.TotalPrevotePower)
+	require.Equal(t, "some_block", s.MostVotedPrevoteHash)
+
+	require.Equal(t, vals[0].Power, s.PrecommitBlockPower["some_block"])
+	require.Equal(t, vals[0].Power, s.TotalPrecommitPower)
+	require.Equal(t, "some_block", s.MostVotedPrecommitHash)
+
+	// The result matches the fixture's own derivation.
+	want := tmconsensus.NewVoteSummary()
+	want.SetAvailablePower(vals)
+	want.SetVotePowers(vals, vrv.PrevoteProofs, vrv.PrecommitProofs)
+	require.Equal(t, want, s)
+}
+
+func TestVersionedRoundView_ContentHash(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	vs := fx.ValSet()
+
+	ph1 := fx.NextProposedHeader([]byte("app_data_1"), 0)
+	fx.SignProposal(ctx, &ph1, 0)
+	ph2 := fx.NextProposedHeader([]byte("app_data_2"), 1)
+	fx.SignProposal(ctx, &ph2, 1)
+
+	voteMap := map[string][]int{
+		string(ph1.Header.Hash): {0, 1},
+		"":                      {3},
+	}
+
+	newView := func(phs []tmconsensus.ProposedHeader) tmconsensus.VersionedRoundView {
+		return tmconsensus.VersionedRoundView{
+			RoundView: tmconsensus.RoundView{
+				Height:          1,
+				Round:           0,
+				ValidatorSet:    vs,
+				ProposedHeaders: phs,
+				PrevoteProofs:   fx.PrevoteProofMap(ctx, 1, 0, voteMap),
+				PrecommitProofs: fx.PrecommitProofMap(ctx, 1, 0, voteMap),
+			},
+		}
+	}
+
+	a := newView([]tmconsensus.ProposedHeader{ph1, ph2})
+
+	// Same content, but with extra slice capacity, reversed header order,
+	// independently built proofs, and different versions.
+	phs := make([]tmconsensus.ProposedHeader, 0, 16)
+	phs = append(phs, ph2, ph1)
+	b := newView(phs)
+	b.Version = 5
+	b.PrevoteVersion = 3
+
+	hA := a.ContentHash(fx.HashScheme)
+	require.NotEmpty(t, hA)
+	require.Equal(t, hA, b.ContentHash(fx.HashScheme))
+
+	t.Run("different proposed headers", func(t *testing.T) {
+		c := newView([]tmconsensus.ProposedHeader{ph1})
+		require.NotEqual(t, hA, c.ContentHash(fx.HashScheme))
+	})
+
+	t.Run("additional vote", func(t *testing.T) {
+		c := newView([]tmconsensus.ProposedHeader{ph1, ph2})
+		c.PrecommitProofs = fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 2},
+			"":                      {3},
+		})
+		require.NotEqual(t, hA, c.ContentHash(fx.HashScheme))
+	})
+
+	t.Run("fixed digest over block hashes from the given scheme", func(t *testing.T) {
+		require.Len(t, hA, sha256.Size)
+
+		// The prefix scheme calculates different block hashes for the headers.
+		h := a.ContentHash(prefixHashScheme{})
+		require.Len(t, h, sha256.Size)
+		require.NotEqual(t, hA, h)
+
+		// A failing scheme falls back to the headers' existing hashes.
+		require.Equal(t, hA, a.ContentHash(failingHashScheme{}))
+	})
+
+	t.Run("prevotes and precommits are distinct", func(t *testing.T) {
+		onlyPrevotes := newView(nil)
+		onlyPrevotes.PrecommitProofs = nil
+		onlyPrecommits := newView(nil)
+		onlyPrecommits.PrevoteProofs = nil
+		require.NotEqual(t, onlyPrevotes.ContentHash(fx.HashScheme), onlyPrecommits.ContentHash(fx.HashScheme))
+	})
+}
+
+// prefixHashScheme prefixes every block hash from the SimpleHashScheme,
+// to confirm that a hash was calculated through the scheme.
+type prefixHashScheme struct {
+	tmconsensustest.SimpleHashScheme
+}
+
+func (s prefixHashScheme) Block(h tmconsensus.Header) ([]byte, error) {
+	hash, err := s.SimpleHashScheme.Block(h)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("prefix:"), hash...), nil
+}
+
+// failingHashScheme fails to hash any block.
+type failingHashScheme struct {
+	tmconsensustest.SimpleHashScheme
+}
+
+func (failingHashScheme) Block(tmconsensus.Header) ([]byte, error) {
+	return nil, errors.New("failing hash scheme")
+}
@@ -0,0 +1,139 @@
+package tmconsensus
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"slices"
+
+	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/gcrypto"
+)
+
+// roundViewContentDomain separates round view content hashes
+// from any other content passed to [contentDigest].
+const roundViewContentDomain = "gordian-round-view-content-v1\x00"
+
+// ContentHash returns a deterministic hash of the canonical content of rv:
+// its height and round, its proposed headers, and which validators
+// have prevoted and precommitted for each block hash.
+//
+// Gossip strategies may compare content hashes
+// to avoid re-sending a view that a peer has already received.
+//
+// The hash ignores everything that does not affect the content,
+// such as version numbers, the order of proposed headers,
+// the iteration order of the proof maps,
+// and the length or capacity of any underlying slices.
+// Vote signatures are not hashed, only the set of signers per block hash,
+// so two views with the same voters hash equally
+// even if one holds a differently aggregated signature.
+//
+// Each proposed header is identified by its block hash according to hs;
+// if hs fails to hash a header, the header's existing Hash field is used instead.
+// The content hash itself is calculated as described in [contentDigest],
+// independent of hs.
+func (rv VersionedRoundView) ContentHash(hs HashScheme) []byte {
+	var h bytes.Buffer
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], rv.Height)
+	_, _ = h.Write(buf[:])
+	binary.BigEndian.PutUint32(buf[:4], rv.Round)
+	_, _ = h.Write(buf[:4])
+
+	// Encode every proposed header independently,
+	// then sort the encodings so the slice order does not matter.
+	phs := make([][]byte, len(rv.ProposedHeaders))
+	for i, ph := range rv.ProposedHeaders {
+		blockHash, err := hs.Block(ph.Header)
+		if err != nil {
+			blockHash = ph.Header.Hash
+		}
+
+		var b bytes.Buffer
+		writeLenPrefixed(&b, blockHash)
+		binary.BigEndian.PutUint32(buf[:4], ph.Round)
+		_, _ = b.Write(buf[:4])
+		var pubKey []byte
+		if ph.ProposerPubKey != nil {
+			pubKey = ph.ProposerPubKey.PubKeyBytes()
+		}
+		writeLenPrefixed(&b, pubKey)
+		writeLenPrefixed(&b, ph.Annotations.User)
+		writeLenPrefixed(&b, ph.Annotations.Driver)
+		writeLenPrefixed(&b, ph.Signature)
+
+		phs[i] = b.Bytes()
+	}
+	slices.SortFunc(phs, bytes.Compare)
+
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(phs)))
+	_, _ = h.Write(buf[:4])
+	for _, ph := range phs {
+		writeLenPrefixed(&h, ph)
+	}
+
+	writeProofMapContent(&h, rv.PrevoteProofs)
+	writeProofMapContent(&h, rv.PrecommitProofs)
+
+	return contentDigest(roundViewContentDomain, h.Bytes())
+}
+
+// contentDigest returns the SHA-256 digest of domain followed by content,
+// where content is a canonical encoding built by the caller.
+//
+// The digest is fixed rather than calculated through a [HashScheme],
+// so that it can be reproduced without knowledge of the chain's hash scheme.
+// Each caller uses its own domain, ending in a zero byte,
+// to keep its digests distinct from digests of other content.
+func contentDigest(domain string, content []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte(domain))
+	_, _ = h.Write(content)
+	return h.Sum(nil)
+}
+
+// writeProofMapContent writes the block hashes in proofs, in sorted order,
+// each followed by the indices of the validators who signed for that block hash.
+//
+// Writing set indices, rather than the bitset's backing words,
+// keeps the output independent of the bitset's length.
+// Block hashes without any signers are skipped.
+func writeProofMapContent(h io.Writer, proofs map[string]gcrypto.CommonMessageSignatureProof) {
+	hashes := make([]string, 0, len(proofs))
+	var bs bitset.BitSet
+	for blockHash, proof := range proofs {
+		proof.SignatureBitSet(&bs)
+		if bs.Any() {
+			hashes = append(hashes, blockHash)
+		}
+	}
+	slices.Sort(hashes)
+
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(hashes)))
+	_, _ = h.Write(buf[:])
+
+	for _, blockHash := range hashes {
+		writeLenPrefixed(h, []byte(blockHash))
+
+		proofs[blockHash].SignatureBitSet(&bs)
+		binary.BigEndian.PutUint32(buf[:], uint32(bs.Count()))
+		_, _ = h.Write(buf[:])
+		for i, ok := bs.NextSet(0); ok; i, ok = bs.NextSet(i + 1) {
+			binary.BigEndian.PutUint32(buf[:], uint32(i))
+			_, _ = h.Write(buf[:])
+		}
+	}
+}
+
+// writeLenPrefixed writes the length of b followed by b to w,
+// so that adjacent variable-length fields cannot be confused.
+func writeLenPrefixed(w io.Writer, b []byte) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(b)))
+	_, _ = w.Write(buf[:])
+	_, _ = w.Write(b)
+}
@@ -28,6 +28,18 @@ type SignatureScheme interface {
 	WritePrevoteSigningContent(io.Writer, VoteTarget) (int, error)
 
 	WritePrecommitSigningContent(io.Writer, VoteTarget) (int, error)
+
+	// SignatureLen reports the length in bytes of every signature
+	// produced by the validators using this scheme.
+	// The boolean result is false if signatures may vary in length,
+	// in which case the returned length is meaningless.
+	//
+	// This is intended as an inexpensive filter for malformed messages,
+	// before any signature verification is attempted.
+	// Sparse signatures in vote proofs are expected to have the same length,
+	// so aggregating signature schemes must aggregate to a same-length signature
+	// in order to report a fixed length.
+	SignatureLen() (n int, fixed bool)
 }
 
 var sigBufPool = sync.Pool{
@@ -89,3 +101,34 @@ func PrecommitSignBytes(vt VoteTarget, s SignatureScheme) ([]byte, error) {
 
 	return bytes.Clone(buf.Bytes()), nil
 }
+
+// CommitProofSignContents returns the precommit sign content
+// for every block hash in proof at the given height, as defined by s.
+// The returned map is keyed by sign content,
+// and each value is the corresponding block hash
+// (the empty string for a precommit for nil).
+//
+// Every sparse signature in proof is a signature over one of the returned sign contents,
+// so callers verifying the proof can use the map
+// to associate each sign content with the block hash it commits to.
+func CommitProofSignContents(height uint64, proof CommitProof, s SignatureScheme) (map[string]string, error) {
+	buf := sigBufPool.Get().(*bytes.Buffer)
+	defer sigBufPool.Put(buf)
+
+	out := make(map[string]string, len(proof.Proofs))
+	for blockHash := range proof.Proofs {
+		buf.Reset()
+		_, err := s.WritePrecommitSigningContent(buf, VoteTarget{
+			Height:    height,
+			Round:     proof.Round,
+			BlockHash: blockHash,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		out[buf.String()] = blockHash
+	}
+
+	return out, nil
+}
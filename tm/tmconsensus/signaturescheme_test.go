@@ -0,0 +1,41 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitProofSignContents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fx := tmconsensustest.NewStandardFixture(4)
+
+	// Precommits split across two blocks and nil.
+	cp := tmconsensus.CommitProof{
+		Round:      2,
+		PubKeyHash: string(fx.ValSet().PubKeyHash),
+		Proofs: fx.SparsePrecommitProofMap(ctx, 1, 2, map[string][]int{
+			"block_a": {0, 1},
+			"block_b": {2},
+			"":        {3},
+		}),
+	}
+
+	got, err := tmconsensus.CommitProofSignContents(1, cp, fx.SignatureScheme)
+	require.NoError(t, err)
+
+	want := make(map[string]string, 3)
+	for _, hash := range []string{"block_a", "block_b", ""} {
+		b, err := tmconsensus.PrecommitSignBytes(tmconsensus.VoteTarget{
+			Height: 1, Round: 2, BlockHash: hash,
+		}, fx.SignatureScheme)
+		require.NoError(t, err)
+		want[string(b)] = hash
+	}
+	require.Equal(t, want, got)
+}
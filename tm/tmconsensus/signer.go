@@ -3,6 +3,7 @@ package tmconsensus
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 )
@@ -72,7 +73,7 @@ func (s PassthroughSigner) Precommit(ctx context.Context, vt VoteTarget) (
 }
 
 func (s PassthroughSigner) SignProposedHeader(ctx context.Context, ph *ProposedHeader) error {
-	signContent, err := ProposalSignBytes(ph.Header, ph.Round, ph.Annotations, s.SignatureScheme)
+	signContent, err := ph.SignBytes(s.SignatureScheme)
 	if err != nil {
 		return fmt.Errorf("PassthroughSigner.SignProposedHeader failed to generate sign bytes: %w", err)
 	}
@@ -88,3 +89,85 @@ func (s PassthroughSigner) SignProposedHeader(ctx context.Context, ph *ProposedH
 func (s PassthroughSigner) PubKey() gcrypto.PubKey {
 	return s.Signer.PubKey()
 }
+
+// MultiSigner holds signers for several validator identities,
+// so that a single process can sign on behalf of multiple validators.
+//
+// Each identity is addressed by its public key hash,
+// which is the result of [HashScheme.PubKeys] on a slice containing only that key.
+//
+// A MultiSigner is not a [Signer] itself;
+// use SignerFor to obtain the [Signer] for a particular identity.
+type MultiSigner struct {
+	signers map[string]PassthroughSigner
+
+	// Public key hashes in the order the signers were provided.
+	hashes []string
+}
+
+// NewMultiSigner returns a new MultiSigner for the given signers,
+// all of which produce signatures with sigScheme.
+// It returns an error if two signers share a public key.
+func NewMultiSigner(
+	hs HashScheme, sigScheme SignatureScheme, signers []gcrypto.Signer,
+) (*MultiSigner, error) {
+	m := &MultiSigner{
+		signers: make(map[string]PassthroughSigner, len(signers)),
+		hashes:  make([]string, 0, len(signers)),
+	}
+
+	for i, s := range signers {
+		h, err := hs.PubKeys([]gcrypto.PubKey{s.PubKey()})
+		if err != nil {
+			return nil, fmt.Errorf(
+				"NewMultiSigner: failed to hash public key of signer at index %d: %w", i, err,
+			)
+		}
+
+		if _, ok := m.signers[string(h)]; ok {
+			return nil, fmt.Errorf(
+				"NewMultiSigner: duplicate public key for signer at index %d", i,
+			)
+		}
+
+		m.signers[string(h)] = PassthroughSigner{
+			Signer:          s,
+			SignatureScheme: sigScheme,
+		}
+		m.hashes = append(m.hashes, string(h))
+	}
+
+	return m, nil
+}
+
+// PubKeyHashes returns the public key hashes of the signers in m,
+// in the order they were provided to [NewMultiSigner].
+func (m *MultiSigner) PubKeyHashes() []string {
+	return slices.Clone(m.hashes)
+}
+
+// SignerFor returns the [Signer] for the identity with the given public key hash.
+// The returned Signer may be used as the signer for the engine
+// acting on behalf of that identity.
+func (m *MultiSigner) SignerFor(pubKeyHash string) (Signer, bool) {
+	s, ok := m.signers[pubKeyHash]
+	if !ok {
+		return nil, false
+	}
+	return s, true
+}
+
+// SignFor signs msg with the signer for the identity with the given public key hash.
+// It returns an error if m has no signer for that identity.
+func (m *MultiSigner) SignFor(ctx context.Context, pubKeyHash string, msg []byte) ([]byte, error) {
+	s, ok := m.signers[pubKeyHash]
+	if !ok {
+		return nil, fmt.Errorf("MultiSigner.SignFor: no signer for public key hash %x", pubKeyHash)
+	}
+
+	sig, err := s.Signer.Sign(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("MultiSigner.SignFor: failed to sign: %w", err)
+	}
+	return sig, nil
+}
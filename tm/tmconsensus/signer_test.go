@@ -0,0 +1,67 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/gcrypto/gcryptotest"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiSigner(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	edSigners := gcryptotest.DeterministicEd25519Signers(2)
+	signers := []gcrypto.Signer{edSigners[0], edSigners[1]}
+
+	var hs tmconsensustest.SimpleHashScheme
+	ms, err := tmconsensus.NewMultiSigner(hs, tmconsensustest.SimpleSignatureScheme{}, signers)
+	require.NoError(t, err)
+
+	hashes := ms.PubKeyHashes()
+	require.Len(t, hashes, 2)
+	require.NotEqual(t, hashes[0], hashes[1])
+
+	msg := []byte("hello")
+	for i, h := range hashes {
+		h0, err := hs.PubKeys([]gcrypto.PubKey{signers[i].PubKey()})
+		require.NoError(t, err)
+		require.Equal(t, string(h0), h)
+
+		sig, err := ms.SignFor(ctx, h, msg)
+		require.NoError(t, err)
+
+		// Each signature is only valid for the addressed key.
+		require.True(t, signers[i].PubKey().Verify(msg, sig))
+		require.False(t, signers[1-i].PubKey().Verify(msg, sig))
+
+		s, ok := ms.SignerFor(h)
+		require.True(t, ok)
+		require.True(t, signers[i].PubKey().Equal(s.PubKey()))
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ms.SignFor(ctx, "not_a_hash", msg)
+		require.Error(t, err)
+
+		_, ok := ms.SignerFor("not_a_hash")
+		require.False(t, ok)
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := tmconsensus.NewMultiSigner(
+			hs, tmconsensustest.SimpleSignatureScheme{},
+			[]gcrypto.Signer{signers[0], signers[1], signers[0]},
+		)
+		require.ErrorContains(t, err, "index 2")
+	})
+}
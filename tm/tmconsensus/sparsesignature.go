@@ -0,0 +1,62 @@
+package tmconsensus
+
+import (
+	"slices"
+
+	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/gcrypto"
+)
+
+// VerifySparseSignature reports whether sig is a valid signature over msg
+// that includes a signature from key.
+//
+// The key ID in sig is opaque and specific to the signature proof scheme,
+// so it is resolved against the validators in vs
+// by building a proof with cmsp and merging sig into it.
+// For schemes that aggregate signatures, sig may represent several validators;
+// VerifySparseSignature reports true as long as the aggregated signature is valid
+// and key is one of the represented validators.
+//
+// This is intended for debugging tools and tests
+// that need to check one sparse signature in isolation.
+// Consensus code should merge sparse signatures into a full proof instead.
+func VerifySparseSignature(
+	cmsp gcrypto.CommonMessageSignatureProofScheme,
+	msg []byte,
+	vs ValidatorSet,
+	key gcrypto.PubKey,
+	sig gcrypto.SparseSignature,
+) bool {
+	keyIdx := slices.IndexFunc(vs.Validators, func(v Validator) bool {
+		return v.PubKey.Equal(key)
+	})
+	if keyIdx < 0 {
+		return false
+	}
+
+	if !cmsp.ValidKeyIDWidth(sig.KeyID) {
+		return false
+	}
+
+	keys := ValidatorsToPubKeys(vs.Validators)
+	if !cmsp.KeyIDChecker(keys).IsValid(sig.KeyID) {
+		return false
+	}
+
+	proof, err := cmsp.New(msg, keys, string(vs.PubKeyHash))
+	if err != nil {
+		return false
+	}
+
+	res := proof.MergeSparse(gcrypto.SparseSignatureProof{
+		PubKeyHash: string(vs.PubKeyHash),
+		Signatures: []gcrypto.SparseSignature{sig},
+	})
+	if !res.AllValidSignatures {
+		return false
+	}
+
+	var bs bitset.BitSet
+	proof.SignatureBitSet(&bs)
+	return bs.Test(uint(keyIdx))
+}
@@ -0,0 +1,49 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySparseSignature(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fx := tmconsensustest.NewStandardFixture(4)
+	vs := fx.ValSet()
+
+	vt := tmconsensus.VoteTarget{Height: 1, Round: 0, BlockHash: "block_hash"}
+	msg, err := tmconsensus.PrevoteSignBytes(vt, fx.SignatureScheme)
+	require.NoError(t, err)
+
+	// A proof with only validator 2's signature yields a single sparse signature.
+	sparse := fx.PrevoteSignatureProof(ctx, vt, nil, []int{2}).AsSparse()
+	require.Len(t, sparse.Signatures, 1)
+	sig := sparse.Signatures[0]
+
+	require.True(t, tmconsensus.VerifySparseSignature(
+		fx.CommonMessageSignatureProofScheme, msg, vs, fx.ValidatorPubKey(2), sig,
+	))
+
+	// The signature does not belong to a different validator.
+	require.False(t, tmconsensus.VerifySparseSignature(
+		fx.CommonMessageSignatureProofScheme, msg, vs, fx.ValidatorPubKey(1), sig,
+	))
+
+	// Nor is it valid for a different message.
+	otherMsg, err := tmconsensus.PrecommitSignBytes(vt, fx.SignatureScheme)
+	require.NoError(t, err)
+	require.False(t, tmconsensus.VerifySparseSignature(
+		fx.CommonMessageSignatureProofScheme, otherMsg, vs, fx.ValidatorPubKey(2), sig,
+	))
+
+	// And a key outside the validator set is never verified.
+	otherFx := tmconsensustest.NewStandardFixture(5)
+	require.False(t, tmconsensus.VerifySparseSignature(
+		fx.CommonMessageSignatureProofScheme, msg, vs, otherFx.ValidatorPubKey(4), sig,
+	))
+}
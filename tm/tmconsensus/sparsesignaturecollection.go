@@ -1,7 +1,10 @@
 package tmconsensus
 
 import (
+	"bytes"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 )
@@ -123,3 +126,101 @@ func (c SparseSignatureCollection) toFullProofMap(
 
 	return out, nil
 }
+
+// FullProofsToSparse converts a map of block hashes to full proofs
+// into a SparseSignatureCollection.
+// It is the inverse of [SparseSignatureCollection.ToFullPrevoteProofMap]
+// and [SparseSignatureCollection.ToFullPrecommitProofMap].
+//
+// Proofs without any signatures are omitted from the collection,
+// as the sparse-to-full conversion requires at least one signature per block hash.
+//
+// Every proof must have the same public key hash;
+// FullProofsToSparse panics otherwise.
+func FullProofsToSparse(proofs map[string]gcrypto.CommonMessageSignatureProof) SparseSignatureCollection {
+	out := SparseSignatureCollection{
+		BlockSignatures: make(map[string][]gcrypto.SparseSignature, len(proofs)),
+	}
+
+	isFirst := true
+	for hash, proof := range proofs {
+		if isFirst {
+			out.PubKeyHash = proof.PubKeyHash()
+			isFirst = false
+		} else {
+			if !bytes.Equal(out.PubKeyHash, proof.PubKeyHash()) {
+				panic(fmt.Errorf(
+					"public key hash mismatch in signature proofs: %x and %x",
+					out.PubKeyHash, proof.PubKeyHash(),
+				))
+			}
+		}
+
+		sp := proof.AsSparse()
+		if len(sp.Signatures) == 0 {
+			continue
+		}
+		out.BlockSignatures[hash] = append(out.BlockSignatures[hash], sp.Signatures...)
+	}
+
+	return out
+}
+
+// cloneSparseSignatureMap returns a deep copy of m,
+// including the key ID and signature bytes of each sparse signature.
+func cloneSparseSignatureMap(m map[string][]gcrypto.SparseSignature) map[string][]gcrypto.SparseSignature {
+	out := make(map[string][]gcrypto.SparseSignature, len(m))
+	for hash, sigs := range m {
+		cloneSigs := make([]gcrypto.SparseSignature, len(sigs))
+		for i, sig := range sigs {
+			cloneSigs[i] = gcrypto.SparseSignature{
+				KeyID: bytes.Clone(sig.KeyID),
+				Sig:   bytes.Clone(sig.Sig),
+			}
+		}
+
+		out[hash] = cloneSigs
+	}
+	return out
+}
+
+// sparseSignatureMapsEqual reports whether a and b have the same keys
+// and the same sparse signatures, in the same order, for each key.
+// A nil map is equal to an empty map.
+func sparseSignatureMapsEqual(a, b map[string][]gcrypto.SparseSignature) bool {
+	return maps.EqualFunc(a, b, func(x, y []gcrypto.SparseSignature) bool {
+		return slices.EqualFunc(x, y, func(s, t gcrypto.SparseSignature) bool {
+			return bytes.Equal(s.KeyID, t.KeyID) && bytes.Equal(s.Sig, t.Sig)
+		})
+	})
+}
+
+// sparseSignatureMapsEquivalent reports whether a and b have the same keys
+// and the same set of sparse signatures for each key,
+// regardless of the order of the signatures.
+// A nil map is equal to an empty map.
+func sparseSignatureMapsEquivalent(a, b map[string][]gcrypto.SparseSignature) bool {
+	return maps.EqualFunc(a, b, func(x, y []gcrypto.SparseSignature) bool {
+		if len(x) != len(y) {
+			return false
+		}
+
+		return slices.EqualFunc(
+			sortedSparseSignatures(x), sortedSparseSignatures(y),
+			func(s, t gcrypto.SparseSignature) bool {
+				return bytes.Equal(s.KeyID, t.KeyID) && bytes.Equal(s.Sig, t.Sig)
+			},
+		)
+	})
+}
+
+// sortedSparseSignatures returns a copy of sigs
+// sorted by key ID and then by signature.
+func sortedSparseSignatures(sigs []gcrypto.SparseSignature) []gcrypto.SparseSignature {
+	return slices.SortedFunc(slices.Values(sigs), func(s, t gcrypto.SparseSignature) int {
+		if c := bytes.Compare(s.KeyID, t.KeyID); c != 0 {
+			return c
+		}
+		return bytes.Compare(s.Sig, t.Sig)
+	})
+}
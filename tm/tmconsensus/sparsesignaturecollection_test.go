@@ -0,0 +1,84 @@
+package tmconsensus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullProofsToSparse_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fx := tmconsensustest.NewStandardFixture(4)
+	vs := fx.ValSet()
+
+	voteMap := map[string][]int{
+		"block_a": {0, 1},
+		"block_b": {2},
+		"":        {3},
+	}
+
+	requireSameProofs := func(t *testing.T, want, got map[string]gcrypto.CommonMessageSignatureProof) {
+		t.Helper()
+
+		require.Len(t, got, len(want))
+		for hash, wantProof := range want {
+			gotProof, ok := got[hash]
+			require.Truef(t, ok, "missing proof for hash %q", hash)
+
+			var wantBS, gotBS bitset.BitSet
+			wantProof.SignatureBitSet(&wantBS)
+			gotProof.SignatureBitSet(&gotBS)
+			require.Truef(t, wantBS.Equal(&gotBS), "signature bits differ for hash %q", hash)
+
+			require.Equal(t, wantProof.AsSparse(), gotProof.AsSparse())
+		}
+	}
+
+	t.Run("prevotes", func(t *testing.T) {
+		t.Parallel()
+
+		full := fx.PrevoteProofMap(ctx, 1, 0, voteMap)
+
+		sparse := tmconsensus.FullProofsToSparse(full)
+		require.Equal(t, vs.PubKeyHash, sparse.PubKeyHash)
+		require.Len(t, sparse.BlockSignatures, len(voteMap))
+
+		got, err := sparse.ToFullPrevoteProofMap(1, 0, vs, fx.SignatureScheme, fx.CommonMessageSignatureProofScheme)
+		require.NoError(t, err)
+		requireSameProofs(t, full, got)
+	})
+
+	t.Run("precommits", func(t *testing.T) {
+		t.Parallel()
+
+		full := fx.PrecommitProofMap(ctx, 1, 0, voteMap)
+
+		sparse := tmconsensus.FullProofsToSparse(full)
+		require.Equal(t, vs.PubKeyHash, sparse.PubKeyHash)
+		require.Len(t, sparse.BlockSignatures, len(voteMap))
+
+		got, err := sparse.ToFullPrecommitProofMap(1, 0, vs, fx.SignatureScheme, fx.CommonMessageSignatureProofScheme)
+		require.NoError(t, err)
+		requireSameProofs(t, full, got)
+	})
+
+	t.Run("empty proofs are omitted", func(t *testing.T) {
+		t.Parallel()
+
+		full := fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+			"block_a": {0, 1},
+			"block_b": {},
+		})
+
+		sparse := tmconsensus.FullProofsToSparse(full)
+		require.Len(t, sparse.BlockSignatures, 1)
+		require.Contains(t, sparse.BlockSignatures, "block_a")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
@@ -127,6 +128,13 @@ func TestHashSchemeCompliance(
 					},
 				},
 
+				{
+					name: "Timestamp",
+					fn: func(h *tmconsensus.Header) {
+						h.Timestamp = time.Unix(1_700_000_000, 0)
+					},
+				},
+
 				// TODO: manipulate PrevCommitProof.
 
 				{
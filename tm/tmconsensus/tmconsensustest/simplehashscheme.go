@@ -82,6 +82,10 @@ PrevAppStateHash: %x
 		h.PrevAppStateHash,
 	)
 
+	if !h.Timestamp.IsZero() {
+		fmt.Fprintf(hasher, "Timestamp: %d\n", h.Timestamp.UnixNano())
+	}
+
 	if h.Annotations.User != nil {
 		fmt.Fprintf(hasher, "UserAnnotation: %x\n", h.Annotations.User)
 	}
@@ -174,7 +174,7 @@ func (f *StandardFixture) NextProposedHeader(appDataID []byte, valIdx int) tmcon
 func (f *StandardFixture) SignProposal(ctx context.Context, ph *tmconsensus.ProposedHeader, valIdx int) {
 	v := f.PrivVals[valIdx]
 
-	b, err := tmconsensus.ProposalSignBytes(ph.Header, ph.Round, ph.Annotations, f.SignatureScheme)
+	b, err := ph.SignBytes(f.SignatureScheme)
 	if err != nil {
 		panic(fmt.Errorf("failed to get sign bytes for proposal %#v: %w", ph, err))
 	}
@@ -2,10 +2,14 @@ package tmconsensus
 
 import (
 	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"slices"
 	"sort"
 
+	"github.com/bits-and-blooms/bitset"
 	"github.com/gordian-engine/gordian/gcrypto"
 )
 
@@ -34,6 +38,45 @@ func (v ValidatorSet) Equal(other ValidatorSet) bool {
 		ValidatorSlicesEqual(v.Validators, other.Validators)
 }
 
+// Diff reports the membership changes from v to other,
+// where v is treated as the earlier set, such as the validators at one height,
+// and other is treated as the later set.
+// Validators are matched by public key.
+//
+// The added validators are those in other but not in v,
+// and removed validators are those in v but not in other.
+// The powerChanged validators are present in both sets with a different power,
+// and they are reported with their power in other.
+// Added and powerChanged validators are in the order they appear in other;
+// removed validators are in the order they appear in v.
+func (v ValidatorSet) Diff(other ValidatorSet) (added, removed, powerChanged []Validator) {
+	prev := make(map[string]uint64, len(v.Validators))
+	for _, val := range v.Validators {
+		prev[string(val.PubKey.PubKeyBytes())] = val.Power
+	}
+
+	next := make(map[string]struct{}, len(other.Validators))
+	for _, val := range other.Validators {
+		k := string(val.PubKey.PubKeyBytes())
+		next[k] = struct{}{}
+
+		pow, ok := prev[k]
+		if !ok {
+			added = append(added, val)
+		} else if pow != val.Power {
+			powerChanged = append(powerChanged, val)
+		}
+	}
+
+	for _, val := range v.Validators {
+		if _, ok := next[string(val.PubKey.PubKeyBytes())]; !ok {
+			removed = append(removed, val)
+		}
+	}
+
+	return added, removed, powerChanged
+}
+
 // NewValidatorSet returns a ValidatorSet based on vs,
 // with hashes calculated using hs.
 //
@@ -56,6 +99,79 @@ func NewValidatorSet(vs []Validator, hs HashScheme) (ValidatorSet, error) {
 	return s, nil
 }
 
+// Hash returns a single value identifying both the public keys and the vote powers of v,
+// derived from v.PubKeyHash and v.VotePowerHash.
+// Two sets with equal hashes have equal PubKeyHash and VotePowerHash fields.
+//
+// The returned value is the length of the public key hash as a uvarint,
+// followed by the public key hash and then the vote power hash.
+func (v ValidatorSet) Hash() string {
+	b := make([]byte, 0, binary.MaxVarintLen64+len(v.PubKeyHash)+len(v.VotePowerHash))
+	b = binary.AppendUvarint(b, uint64(len(v.PubKeyHash)))
+	b = append(b, v.PubKeyHash...)
+	b = append(b, v.VotePowerHash...)
+	return string(b)
+}
+
+// VerifyPubKeyHash reports whether v.PubKeyHash matches
+// the hash of v's validators' public keys, as calculated by hs.
+// This is useful to detect a corrupted or tampered validator set
+// that was loaded from storage or received from the network.
+//
+// ValidatorSet does not record which HashScheme produced its hashes,
+// so the caller must supply the same scheme that was passed to [NewValidatorSet].
+// If hs fails to hash the public keys, VerifyPubKeyHash returns false.
+func (v ValidatorSet) VerifyPubKeyHash(hs HashScheme) bool {
+	h, err := hs.PubKeys(ValidatorsToPubKeys(v.Validators))
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(h, v.PubKeyHash)
+}
+
+// PrecomputeNextValidatorSetHash returns the value that [ValidatorSet.Hash] would return
+// for the set produced by NewValidatorSet(vals, hs).
+//
+// A proposer may use this to confirm that the NextValidatorSet it puts in a header
+// agrees with the validators the application will report when finalizing the block,
+// without constructing a full ValidatorSet from the application's response.
+// The order of vals is significant, just as it is for NewValidatorSet.
+//
+// An error is returned if vals would not be usable as a validator set,
+// per [ValidateValidators], or if hs fails to hash the validators.
+func PrecomputeNextValidatorSetHash(vals []Validator, hs HashScheme) (string, error) {
+	if err := ValidateValidators(vals); err != nil {
+		return "", fmt.Errorf("invalid next validators: %w", err)
+	}
+
+	vs, err := NewValidatorSet(vals, hs)
+	if err != nil {
+		return "", err
+	}
+
+	return vs.Hash(), nil
+}
+
+// ValidateValidators reports an error if vs cannot be used
+// as the validators for a height:
+// that is, if vs is empty or if the validators' total power is zero.
+// No validators would be able to reach a majority vote in either case,
+// so consensus would halt permanently.
+func ValidateValidators(vs []Validator) error {
+	if len(vs) == 0 {
+		return errors.New("validator set must not be empty")
+	}
+
+	for _, v := range vs {
+		if v.Power > 0 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("total power of %d validators must be positive", len(vs))
+}
+
 // SortValidators sorts vs in-place, by power descending,
 // and then by public key ascending.
 func SortValidators(vs []Validator) {
@@ -92,6 +208,31 @@ func ValidatorSlicesEqual(vs1, vs2 []Validator) bool {
 	})
 }
 
+// ExpectedProposer returns the validator expected to propose a block
+// at the given height and round, using a simple round robin
+// over the ordered validators in vs.
+//
+// The engine does not otherwise dictate proposer selection,
+// so this is only meaningful for chains that follow the same selection strategy.
+// ExpectedProposer panics if vs has no validators.
+func ExpectedProposer(vs ValidatorSet, height uint64, round uint32) Validator {
+	n := uint64(len(vs.Validators))
+	if n == 0 {
+		panic(errors.New("BUG: ExpectedProposer called with empty validator set"))
+	}
+	return vs.Validators[(height+uint64(round))%n]
+}
+
+// IsExpectedProposer reports whether ph was proposed by
+// the validator that [ExpectedProposer] reports for ph's height and round.
+// The validator set vs must be the one for ph's height.
+func IsExpectedProposer(vs ValidatorSet, ph ProposedHeader) bool {
+	if ph.ProposerPubKey == nil || len(vs.Validators) == 0 {
+		return false
+	}
+	return ExpectedProposer(vs, ph.Header.Height, ph.Round).PubKey.Equal(ph.ProposerPubKey)
+}
+
 // CanTrustValidators reports whether the validator set vs contains at least 1/3 voting power
 // represented by the passed-in set of trusted public keys.
 func CanTrustValidators(vs []Validator, pubKeys []gcrypto.PubKey) bool {
@@ -110,3 +251,47 @@ func CanTrustValidators(vs []Validator, pubKeys []gcrypto.PubKey) bool {
 
 	return trustedPower > ByzantineMinority(totalPower)+1
 }
+
+// MinimalMajoritySigners returns the smallest subset of the signed validators
+// whose combined power reaches the [ByzantineMajority] of the total power of vals.
+// The bits in signed and in the result correspond to indices in vals.
+//
+// Signers are chosen greedily in descending order of power,
+// which yields the fewest possible signers;
+// validators with equal power are chosen in order of lowest index,
+// so the result is deterministic for a given input.
+//
+// The returned boolean is false, and the bit set is nil,
+// if the signed validators do not reach a majority.
+// Bits in signed beyond the length of vals are ignored.
+func MinimalMajoritySigners(vals []Validator, signed *bitset.BitSet) (*bitset.BitSet, bool) {
+	var totalPower uint64
+	for _, v := range vals {
+		totalPower += v.Power
+	}
+	if totalPower == 0 || signed == nil {
+		return nil, false
+	}
+	majority := ByzantineMajority(totalPower)
+
+	signers := make([]int, 0, signed.Count())
+	for u, ok := signed.NextSet(0); ok && int(u) < len(vals); u, ok = signed.NextSet(u + 1) {
+		signers = append(signers, int(u))
+	}
+	slices.SortStableFunc(signers, func(a, b int) int {
+		// Descending by power.
+		return cmp.Compare(vals[b].Power, vals[a].Power)
+	})
+
+	out := bitset.New(uint(len(vals)))
+	var power uint64
+	for _, idx := range signers {
+		out.Set(uint(idx))
+		power += vals[idx].Power
+		if power >= majority {
+			return out, true
+		}
+	}
+
+	return nil, false
+}
@@ -1,8 +1,12 @@
 package tmconsensus_test
 
 import (
+	"bytes"
+	"context"
+	"slices"
 	"testing"
 
+	"github.com/bits-and-blooms/bitset"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
 	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
 	"github.com/stretchr/testify/require"
@@ -96,3 +100,225 @@ func TestCanTrustValidators(t *testing.T) {
 		require.True(t, tmconsensus.CanTrustValidators(newVals, pubKeys))
 	})
 }
+
+func TestMinimalMajoritySigners(t *testing.T) {
+	t.Parallel()
+
+	vals := tmconsensustest.NewStandardFixture(5).Vals()
+	for i, p := range []uint64{10, 40, 5, 35, 10} {
+		vals[i].Power = p
+	}
+
+	t.Run("two of five suffice", func(t *testing.T) {
+		t.Parallel()
+
+		// Total power is 100, so the majority is 67,
+		// which the two largest validators exceed together.
+		signed := bitset.New(5)
+		for i := range uint(5) {
+			signed.Set(i)
+		}
+
+		got, ok := tmconsensus.MinimalMajoritySigners(vals, signed)
+		require.True(t, ok)
+		require.Equal(t, []uint{1, 3}, setBits(got))
+
+		// The input is unmodified.
+		require.Equal(t, uint(5), signed.Count())
+	})
+
+	t.Run("smaller validators fill in for a missing one", func(t *testing.T) {
+		t.Parallel()
+
+		signed := bitset.New(5).Set(0).Set(1).Set(2).Set(4)
+		_, ok := tmconsensus.MinimalMajoritySigners(vals, signed)
+
+		// 40+10+10+5 = 65, short of 67.
+		require.False(t, ok)
+
+		signed.Clear(2).Set(3)
+		got, ok := tmconsensus.MinimalMajoritySigners(vals, signed)
+		require.True(t, ok)
+		require.Equal(t, []uint{1, 3}, setBits(got))
+	})
+
+	t.Run("ties choose the lowest index", func(t *testing.T) {
+		t.Parallel()
+
+		tied := slices.Clone(vals)
+		for i, p := range []uint64{20, 30, 20, 10, 20} {
+			tied[i].Power = p
+		}
+
+		// 30 plus two of the 20-power validators reaches 70.
+		signed := bitset.New(5)
+		for i := range uint(5) {
+			signed.Set(i)
+		}
+		got, ok := tmconsensus.MinimalMajoritySigners(tied, signed)
+		require.True(t, ok)
+		require.Equal(t, []uint{0, 1, 2}, setBits(got))
+	})
+
+	t.Run("nothing signed", func(t *testing.T) {
+		t.Parallel()
+
+		got, ok := tmconsensus.MinimalMajoritySigners(vals, bitset.New(5))
+		require.False(t, ok)
+		require.Nil(t, got)
+	})
+}
+
+func setBits(bs *bitset.BitSet) []uint {
+	out := make([]uint, 0, bs.Count())
+	for u, ok := bs.NextSet(0); ok; u, ok = bs.NextSet(u + 1) {
+		out = append(out, u)
+	}
+	return out
+}
+
+func TestValidateValidators(t *testing.T) {
+	t.Parallel()
+
+	fx := tmconsensustest.NewStandardFixture(2)
+
+	require.NoError(t, tmconsensus.ValidateValidators(fx.Vals()))
+
+	require.Error(t, tmconsensus.ValidateValidators(nil))
+	require.Error(t, tmconsensus.ValidateValidators([]tmconsensus.Validator{}))
+
+	zeroPower := fx.Vals()
+	for i := range zeroPower {
+		zeroPower[i].Power = 0
+	}
+	require.Error(t, tmconsensus.ValidateValidators(zeroPower))
+
+	// A single validator with power is sufficient.
+	zeroPower[1].Power = 1
+	require.NoError(t, tmconsensus.ValidateValidators(zeroPower))
+}
+
+func TestIsExpectedProposer(t *testing.T) {
+	t.Parallel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	vs := fx.ValSet()
+
+	// Round robin over the validators, offset by height and round.
+	require.True(t, tmconsensus.ExpectedProposer(vs, 1, 0).PubKey.Equal(vs.Validators[1].PubKey))
+	require.True(t, tmconsensus.ExpectedProposer(vs, 1, 3).PubKey.Equal(vs.Validators[0].PubKey))
+
+	ctx := context.Background()
+
+	ph := fx.NextProposedHeader([]byte("app_data_1"), 1)
+	fx.SignProposal(ctx, &ph, 1)
+	require.Equal(t, uint64(1), ph.Header.Height)
+	require.Zero(t, ph.Round)
+	require.True(t, tmconsensus.IsExpectedProposer(vs, ph))
+
+	ph = fx.NextProposedHeader([]byte("app_data_1"), 2)
+	fx.SignProposal(ctx, &ph, 2)
+	require.False(t, tmconsensus.IsExpectedProposer(vs, ph))
+
+	// Validator 2 is expected in the next round.
+	ph.Round = 1
+	require.True(t, tmconsensus.IsExpectedProposer(vs, ph))
+}
+
+func TestValidatorSet_Diff(t *testing.T) {
+	t.Parallel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	before := fx.ValSet()
+
+	// Remove validator 3, raise the power of validator 1,
+	// and add the new validator 4.
+	fx5 := tmconsensustest.NewStandardFixture(5)
+	afterVals := fx5.Vals()
+	afterVals = append(afterVals[:3], afterVals[4])
+	afterVals[1].Power += 5
+	after, err := tmconsensus.NewValidatorSet(afterVals, fx.HashScheme)
+	require.NoError(t, err)
+
+	added, removed, powerChanged := before.Diff(after)
+	require.True(t, tmconsensus.ValidatorSlicesEqual([]tmconsensus.Validator{fx5.Vals()[4]}, added))
+	require.True(t, tmconsensus.ValidatorSlicesEqual([]tmconsensus.Validator{before.Validators[3]}, removed))
+	require.True(t, tmconsensus.ValidatorSlicesEqual([]tmconsensus.Validator{afterVals[1]}, powerChanged))
+
+	// Reversing the direction swaps added and removed,
+	// and reports the original power.
+	added, removed, powerChanged = after.Diff(before)
+	require.True(t, tmconsensus.ValidatorSlicesEqual([]tmconsensus.Validator{before.Validators[3]}, added))
+	require.True(t, tmconsensus.ValidatorSlicesEqual([]tmconsensus.Validator{fx5.Vals()[4]}, removed))
+	require.True(t, tmconsensus.ValidatorSlicesEqual([]tmconsensus.Validator{before.Validators[1]}, powerChanged))
+
+	// Identical sets have no differences.
+	added, removed, powerChanged = before.Diff(before)
+	require.Empty(t, added)
+	require.Empty(t, removed)
+	require.Empty(t, powerChanged)
+}
+
+func TestValidatorSet_VerifyPubKeyHash(t *testing.T) {
+	t.Parallel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+
+	t.Run("well-formed set", func(t *testing.T) {
+		t.Parallel()
+
+		require.True(t, fx.ValSet().VerifyPubKeyHash(fx.HashScheme))
+	})
+
+	t.Run("tampered hash", func(t *testing.T) {
+		t.Parallel()
+
+		vs := fx.ValSet()
+		vs.PubKeyHash = bytes.Clone(vs.PubKeyHash)
+		vs.PubKeyHash[0]++
+
+		require.False(t, vs.VerifyPubKeyHash(fx.HashScheme))
+	})
+
+	t.Run("tampered validators", func(t *testing.T) {
+		t.Parallel()
+
+		vs := fx.ValSet()
+		vs.Validators = slices.Clone(vs.Validators)
+		vs.Validators[0], vs.Validators[1] = vs.Validators[1], vs.Validators[0]
+
+		require.False(t, vs.VerifyPubKeyHash(fx.HashScheme))
+	})
+}
+
+func TestPrecomputeNextValidatorSetHash(t *testing.T) {
+	t.Parallel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+
+	h, err := tmconsensus.PrecomputeNextValidatorSetHash(fx.Vals(), fx.HashScheme)
+	require.NoError(t, err)
+	require.Equal(t, fx.ValSet().Hash(), h)
+
+	t.Run("differs when powers change", func(t *testing.T) {
+		t.Parallel()
+
+		vals := fx.Vals()
+		vals[0].Power++
+
+		changed, err := tmconsensus.PrecomputeNextValidatorSetHash(vals, fx.HashScheme)
+		require.NoError(t, err)
+		require.NotEqual(t, h, changed)
+
+		vs, err := tmconsensus.NewValidatorSet(vals, fx.HashScheme)
+		require.NoError(t, err)
+		require.Equal(t, vs.Hash(), changed)
+	})
+
+	t.Run("invalid validators", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := tmconsensus.PrecomputeNextValidatorSetHash(nil, fx.HashScheme)
+		require.Error(t, err)
+	})
+}
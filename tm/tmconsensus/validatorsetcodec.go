@@ -0,0 +1,123 @@
+package tmconsensus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+)
+
+// MarshalValidatorSet returns the canonical binary encoding of vs.
+//
+// The encoding is intended for light clients and other consumers
+// that need to verify validator set transitions between headers,
+// and so it is deterministic for a given validator set.
+//
+// The format is:
+//   - the number of validators, as a uvarint
+//   - for each validator, in order: the length of its public key as marshaled by reg,
+//     as a uvarint; the marshaled public key;
+//     and the validator's power, as a fixed-width 8-byte big endian integer
+//   - the length of vs.PubKeyHash, as a uvarint
+//   - vs.PubKeyHash
+//
+// The vote power hash is not encoded,
+// as it is recalculated from the powers in [UnmarshalValidatorSet].
+//
+// MarshalValidatorSet panics if any validator's public key type
+// was not registered with reg.
+func MarshalValidatorSet(reg *gcrypto.Registry, vs ValidatorSet) []byte {
+	b := binary.AppendUvarint(nil, uint64(len(vs.Validators)))
+
+	for _, v := range vs.Validators {
+		pk := reg.Marshal(v.PubKey)
+		b = binary.AppendUvarint(b, uint64(len(pk)))
+		b = append(b, pk...)
+		b = binary.BigEndian.AppendUint64(b, v.Power)
+	}
+
+	b = binary.AppendUvarint(b, uint64(len(vs.PubKeyHash)))
+	return append(b, vs.PubKeyHash...)
+}
+
+// UnmarshalValidatorSet decodes a validator set previously encoded with [MarshalValidatorSet].
+//
+// The hashes of the returned validator set are recalculated with hs,
+// and an error is returned if the recalculated public key hash
+// does not match the encoded public key hash.
+//
+// The returned validator set does not retain a reference to b.
+func UnmarshalValidatorSet(reg *gcrypto.Registry, hs HashScheme, b []byte) (ValidatorSet, error) {
+	n, sz := binary.Uvarint(b)
+	if sz <= 0 {
+		return ValidatorSet{}, errors.New("failed to decode validator count")
+	}
+	b = b[sz:]
+
+	// Every validator requires at least a one-byte key length and an 8-byte power,
+	// so reject counts that could not possibly fit in the remaining input
+	// before allocating.
+	if n > uint64(len(b)/9) {
+		return ValidatorSet{}, fmt.Errorf("validator count %d exceeds input size", n)
+	}
+
+	vals := make([]Validator, n)
+	for i := range vals {
+		keyLen, sz := binary.Uvarint(b)
+		if sz <= 0 {
+			return ValidatorSet{}, fmt.Errorf("failed to decode public key length for validator %d", i)
+		}
+		b = b[sz:]
+
+		if keyLen > uint64(len(b)) {
+			return ValidatorSet{}, fmt.Errorf(
+				"public key length %d for validator %d exceeds remaining input", keyLen, i,
+			)
+		}
+
+		// The registry may retain a reference to the slice,
+		// so give it a copy that is not shared with the caller's input.
+		pk, err := reg.Unmarshal(bytes.Clone(b[:keyLen]))
+		if err != nil {
+			return ValidatorSet{}, fmt.Errorf("failed to decode public key for validator %d: %w", i, err)
+		}
+		b = b[keyLen:]
+
+		if len(b) < 8 {
+			return ValidatorSet{}, fmt.Errorf("input too short to decode power for validator %d", i)
+		}
+		vals[i] = Validator{
+			PubKey: pk,
+			Power:  binary.BigEndian.Uint64(b[:8]),
+		}
+		b = b[8:]
+	}
+
+	hashLen, sz := binary.Uvarint(b)
+	if sz <= 0 {
+		return ValidatorSet{}, errors.New("failed to decode public key hash length")
+	}
+	b = b[sz:]
+	if hashLen != uint64(len(b)) {
+		return ValidatorSet{}, fmt.Errorf(
+			"public key hash length %d does not match remaining input length %d", hashLen, len(b),
+		)
+	}
+	encodedHash := b
+
+	vs, err := NewValidatorSet(vals, hs)
+	if err != nil {
+		return ValidatorSet{}, err
+	}
+
+	if !bytes.Equal(vs.PubKeyHash, encodedHash) {
+		return ValidatorSet{}, fmt.Errorf(
+			"encoded public key hash %x does not match calculated hash %x",
+			encodedHash, vs.PubKeyHash,
+		)
+	}
+
+	return vs, nil
+}
@@ -0,0 +1,72 @@
+package tmconsensus_test
+
+import (
+	"testing"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorSetCodec_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	var reg gcrypto.Registry
+	gcrypto.RegisterEd25519(&reg)
+
+	fx := tmconsensustest.NewStandardFixture(4)
+
+	vals := fx.Vals()
+	// Distinct powers, including one exceeding 32 bits,
+	// to ensure each power is encoded independently and in full.
+	for i := range vals {
+		vals[i].Power = uint64(i+1) << 33
+	}
+	orig, err := tmconsensus.NewValidatorSet(vals, fx.HashScheme)
+	require.NoError(t, err)
+
+	b := tmconsensus.MarshalValidatorSet(&reg, orig)
+
+	// Encoding is deterministic.
+	require.Equal(t, b, tmconsensus.MarshalValidatorSet(&reg, orig))
+
+	got, err := tmconsensus.UnmarshalValidatorSet(&reg, fx.HashScheme, b)
+	require.NoError(t, err)
+
+	require.True(t, orig.Equal(got))
+	require.Equal(t, orig.PubKeyHash, got.PubKeyHash)
+	require.Equal(t, orig.VotePowerHash, got.VotePowerHash)
+}
+
+func TestValidatorSetCodec_hashMismatch(t *testing.T) {
+	t.Parallel()
+
+	var reg gcrypto.Registry
+	gcrypto.RegisterEd25519(&reg)
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	vs := fx.ValSet()
+
+	// Claim a different public key hash than the validators produce.
+	vs.PubKeyHash = []byte("not the real hash")
+	b := tmconsensus.MarshalValidatorSet(&reg, vs)
+
+	_, err := tmconsensus.UnmarshalValidatorSet(&reg, fx.HashScheme, b)
+	require.ErrorContains(t, err, "does not match calculated hash")
+}
+
+func TestValidatorSetCodec_truncated(t *testing.T) {
+	t.Parallel()
+
+	var reg gcrypto.Registry
+	gcrypto.RegisterEd25519(&reg)
+
+	fx := tmconsensustest.NewStandardFixture(2)
+	b := tmconsensus.MarshalValidatorSet(&reg, fx.ValSet())
+
+	for i := range len(b) {
+		_, err := tmconsensus.UnmarshalValidatorSet(&reg, fx.HashScheme, b[:i])
+		require.Errorf(t, err, "expected error for input truncated to %d bytes", i)
+	}
+}
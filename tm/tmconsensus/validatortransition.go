@@ -0,0 +1,93 @@
+package tmconsensus
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// ValidatorSetTransitionValidator decides whether the validator set
+// may change from cur to next, where next takes effect at the given height.
+//
+// Chains that restrict which validator set changes are legal,
+// for example by limiting how much voting power may move in a single height,
+// can provide a ValidatorSetTransitionValidator to the engine,
+// which checks the validators the application returns when finalizing a block
+// and halts if the policy is violated.
+//
+// ValidateValidatorSetTransition returns nil if the transition is allowed.
+// It must be safe for concurrent use.
+type ValidatorSetTransitionValidator interface {
+	ValidateValidatorSetTransition(height uint64, cur, next ValidatorSet) error
+}
+
+// PowerChangeLimit is a [ValidatorSetTransitionValidator]
+// that limits the total change in voting power between two validator sets.
+//
+// The total change is the sum of the absolute power differences
+// of every validator in either set, matched by public key,
+// where a validator missing from one set is treated as having zero power there.
+// The transition is rejected with a [PowerChangeTooLargeError]
+// if the total change exceeds MaxChangePercent of the total power of cur.
+type PowerChangeLimit struct {
+	MaxChangePercent uint64
+}
+
+func (l PowerChangeLimit) ValidateValidatorSetTransition(height uint64, cur, next ValidatorSet) error {
+	prev := make(map[string]uint64, len(cur.Validators))
+	var curTotal uint64
+	for _, v := range cur.Validators {
+		prev[string(v.PubKey.PubKeyBytes())] = v.Power
+		curTotal += v.Power
+	}
+
+	var change uint64
+	for _, v := range next.Validators {
+		k := string(v.PubKey.PubKeyBytes())
+		pow := prev[k]
+		delete(prev, k)
+
+		if v.Power > pow {
+			change += v.Power - pow
+		} else {
+			change += pow - v.Power
+		}
+	}
+
+	// Anything left in prev was removed in next.
+	for _, pow := range prev {
+		change += pow
+	}
+
+	// Calculate the limit with 128-bit intermediates
+	// so that a large total power and percentage cannot overflow.
+	hi, lo := bits.Mul64(curTotal, l.MaxChangePercent)
+	if hi >= 100 {
+		// The limit exceeds the uint64 range, so no change can exceed it.
+		return nil
+	}
+	limit, _ := bits.Div64(hi, lo, 100)
+
+	if change > limit {
+		return PowerChangeTooLargeError{
+			Height: height,
+			Change: change,
+			Limit:  limit,
+		}
+	}
+
+	return nil
+}
+
+// PowerChangeTooLargeError is returned from [PowerChangeLimit]
+// when the voting power changes by more than the configured limit.
+type PowerChangeTooLargeError struct {
+	Height        uint64
+	Change, Limit uint64
+}
+
+func (e PowerChangeTooLargeError) Error() string {
+	return fmt.Sprintf(
+		"validator set transition at height %d changes power by %d, exceeding limit of %d",
+		e.Height, e.Change, e.Limit,
+	)
+}
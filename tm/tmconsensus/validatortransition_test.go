@@ -0,0 +1,77 @@
+package tmconsensus_test
+
+import (
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowerChangeLimit(t *testing.T) {
+	t.Parallel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	cur := fx.ValSet()
+
+	// The fixture's powers total 399_994, so 10% allows a change of 39_999.
+	l := tmconsensus.PowerChangeLimit{MaxChangePercent: 10}
+
+	nextWith := func(vals []tmconsensus.Validator) tmconsensus.ValidatorSet {
+		t.Helper()
+		vs, err := tmconsensus.NewValidatorSet(vals, fx.HashScheme)
+		require.NoError(t, err)
+		return vs
+	}
+
+	t.Run("unchanged set", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, l.ValidateValidatorSetTransition(1, cur, cur))
+	})
+
+	t.Run("power change at limit", func(t *testing.T) {
+		t.Parallel()
+
+		vals := fx.Vals()
+		vals[0].Power += 20_000
+		vals[1].Power -= 19_999
+		require.NoError(t, l.ValidateValidatorSetTransition(1, cur, nextWith(vals)))
+	})
+
+	t.Run("power change over limit", func(t *testing.T) {
+		t.Parallel()
+
+		vals := fx.Vals()
+		vals[0].Power += 40_000
+		err := l.ValidateValidatorSetTransition(5, cur, nextWith(vals))
+		require.Equal(t, tmconsensus.PowerChangeTooLargeError{
+			Height: 5,
+			Change: 40_000,
+			Limit:  39_999,
+		}, err)
+	})
+
+	t.Run("added and removed validators count their full power", func(t *testing.T) {
+		t.Parallel()
+
+		fx5 := tmconsensustest.NewStandardFixture(5)
+		added := append(fx.Vals(), fx5.Vals()[4])
+		require.ErrorAs(
+			t,
+			l.ValidateValidatorSetTransition(1, cur, nextWith(added)),
+			new(tmconsensus.PowerChangeTooLargeError),
+		)
+
+		removed := fx.Vals()[:3]
+		require.ErrorAs(
+			t,
+			l.ValidateValidatorSetTransition(1, cur, nextWith(removed)),
+			new(tmconsensus.PowerChangeTooLargeError),
+		)
+
+		// With a looser limit, removing one of four validators is allowed.
+		loose := tmconsensus.PowerChangeLimit{MaxChangePercent: 30}
+		require.NoError(t, loose.ValidateValidatorSetTransition(1, cur, nextWith(removed)))
+	})
+}
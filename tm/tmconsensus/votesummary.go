@@ -121,6 +121,91 @@ func (vs *VoteSummary) SetPrecommitPowers(vals []Validator, precommits map[strin
 	vs.MostVotedPrecommitHash = maxHash
 }
 
+// ConsensusKind indicates what, if anything, a set of votes has reached consensus on.
+type ConsensusKind uint8
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type ConsensusKind -trimprefix=Consensus .
+
+const (
+	// No single value has a majority of the voting power.
+	ConsensusNone ConsensusKind = iota
+
+	// A majority of the voting power voted for nil.
+	ConsensusNil
+
+	// A majority of the voting power voted for a particular block.
+	ConsensusBlock
+)
+
+// PrecommitConsensus reports whether the precommits in vs
+// represent a Byzantine majority of totalPower for a single value.
+//
+// If a majority precommitted for a block, the block's hash and [ConsensusBlock] are returned.
+// If a majority precommitted for nil, the empty string and [ConsensusNil] are returned.
+// Otherwise, including when totalPower is zero,
+// the empty string and [ConsensusNone] are returned.
+//
+// The state machine advances to the next round upon nil consensus,
+// and it finalizes the block upon block consensus.
+func (vs VoteSummary) PrecommitConsensus(totalPower uint64) (hash string, kind ConsensusKind) {
+	if totalPower == 0 {
+		return "", ConsensusNone
+	}
+
+	maj := ByzantineMajority(totalPower)
+	for h, pow := range vs.PrecommitBlockPower {
+		if pow < maj {
+			continue
+		}
+
+		// At most one value can hold a majority.
+		if h == "" {
+			return "", ConsensusNil
+		}
+		return h, ConsensusBlock
+	}
+
+	return "", ConsensusNone
+}
+
+// CanStillCommit reports whether the precommits in vs
+// could still reach a Byzantine majority of totalPower for any single block,
+// assuming all the power that has not yet precommitted were to precommit for that block.
+//
+// Once CanStillCommit returns false, no block can be committed in this round,
+// for instance because more than one third of the power has precommitted nil,
+// so the round can only end with a nil commit or a timeout.
+// CanStillCommit returns false when totalPower is zero.
+func (vs VoteSummary) CanStillCommit(totalPower uint64) bool {
+	if totalPower == 0 {
+		return false
+	}
+
+	var remaining uint64
+	if vs.TotalPrecommitPower < totalPower {
+		remaining = totalPower - vs.TotalPrecommitPower
+	}
+
+	maj := ByzantineMajority(totalPower)
+
+	// A block that has no precommits yet could receive all the remaining power.
+	if remaining >= maj {
+		return true
+	}
+
+	for h, pow := range vs.PrecommitBlockPower {
+		if h == "" {
+			continue
+		}
+
+		if pow+remaining >= maj {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (vs *VoteSummary) Reset() {
 	vs.AvailablePower = 0
 	vs.ResetForSameHeight()
@@ -56,3 +56,155 @@ func TestVoteSummary_powers(t *testing.T) {
 		}, vs.PrecommitBlockPower)
 	})
 }
+
+func TestVoteSummary_PrecommitConsensus(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	vals := fx.Vals()
+
+	for _, tc := range []struct {
+		name     string
+		voteMap  map[string][]int
+		wantHash string
+		wantKind tmconsensus.ConsensusKind
+	}{
+		{
+			name:     "no votes",
+			voteMap:  map[string][]int{},
+			wantKind: tmconsensus.ConsensusNone,
+		},
+		{
+			name: "majority for block",
+			voteMap: map[string][]int{
+				"some_block": {0, 1, 2},
+				"":           {3},
+			},
+			wantHash: "some_block",
+			wantKind: tmconsensus.ConsensusBlock,
+		},
+		{
+			name: "majority for nil",
+			voteMap: map[string][]int{
+				"":           {0, 1, 2},
+				"some_block": {3},
+			},
+			wantKind: tmconsensus.ConsensusNil,
+		},
+		{
+			name: "split between block and nil",
+			voteMap: map[string][]int{
+				"some_block": {0, 1},
+				"":           {2, 3},
+			},
+			wantKind: tmconsensus.ConsensusNone,
+		},
+		{
+			name: "split between two blocks",
+			voteMap: map[string][]int{
+				"block_a": {0, 1},
+				"block_b": {2, 3},
+			},
+			wantKind: tmconsensus.ConsensusNone,
+		},
+		{
+			name: "minority for block with votes missing",
+			voteMap: map[string][]int{
+				"some_block": {0, 1},
+			},
+			wantKind: tmconsensus.ConsensusNone,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			vs := tmconsensus.NewVoteSummary()
+			vs.SetAvailablePower(vals)
+			vs.SetPrecommitPowers(vals, fx.PrecommitProofMap(ctx, 1, 0, tc.voteMap))
+
+			hash, kind := vs.PrecommitConsensus(vs.AvailablePower)
+			require.Equal(t, tc.wantHash, hash)
+			require.Equal(t, tc.wantKind, kind)
+		})
+	}
+
+	t.Run("zero total power", func(t *testing.T) {
+		vs := tmconsensus.NewVoteSummary()
+		hash, kind := vs.PrecommitConsensus(0)
+		require.Empty(t, hash)
+		require.Equal(t, tmconsensus.ConsensusNone, kind)
+	})
+}
+
+func TestVoteSummary_CanStillCommit(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	vals := fx.Vals()
+
+	for _, tc := range []struct {
+		name    string
+		voteMap map[string][]int
+		want    bool
+	}{
+		{
+			name:    "no votes",
+			voteMap: map[string][]int{},
+			want:    true,
+		},
+		{
+			name: "one nil vote",
+			voteMap: map[string][]int{
+				"": {3},
+			},
+			want: true,
+		},
+		{
+			name: "minority for nil and a block",
+			voteMap: map[string][]int{
+				"":           {0},
+				"some_block": {1},
+			},
+			want: true,
+		},
+		{
+			name: "more than one third for nil",
+			voteMap: map[string][]int{
+				"": {0, 1},
+			},
+			want: false,
+		},
+		{
+			name: "split between two blocks",
+			voteMap: map[string][]int{
+				"block_a": {0, 1},
+				"block_b": {2, 3},
+			},
+			want: false,
+		},
+		{
+			name: "majority for block",
+			voteMap: map[string][]int{
+				"some_block": {0, 1, 2},
+			},
+			want: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			vs := tmconsensus.NewVoteSummary()
+			vs.SetAvailablePower(vals)
+			vs.SetPrecommitPowers(vals, fx.PrecommitProofMap(ctx, 1, 0, tc.voteMap))
+
+			require.Equal(t, tc.want, vs.CanStillCommit(vs.AvailablePower))
+		})
+	}
+
+	t.Run("zero total power", func(t *testing.T) {
+		vs := tmconsensus.NewVoteSummary()
+		require.False(t, vs.CanStillCommit(0))
+	})
+}
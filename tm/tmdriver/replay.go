@@ -0,0 +1,90 @@
+package tmdriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmstore"
+)
+
+// ReplayFinalizations is shorthand for [ReplayFinalizationsFrom]
+// with a start height of the genesis's initial height.
+func ReplayFinalizations(
+	ctx context.Context,
+	fs tmstore.FinalizationStore,
+	chs tmstore.CommittedHeaderStore,
+	g tmconsensus.Genesis,
+	handler func(FinalizeBlockRequest) error,
+) error {
+	return ReplayFinalizationsFrom(ctx, fs, chs, g.InitialHeight, handler)
+}
+
+// ReplayFinalizationsFrom calls handler with a [FinalizeBlockRequest]
+// for each stored height beginning at startHeight, in increasing order,
+// so that a driver can rebuild its application state from scratch
+// by passing each request through its usual finalization handling.
+//
+// Each request is reconstructed from the committed header in chs
+// and the finalized round in fs.
+// Replayed requests have IsCatchup set,
+// and their Resp channel is 1-buffered so the handler may respond as usual;
+// any response is discarded.
+//
+// The replay ends without error at the first height after startHeight
+// that is missing from either store.
+// An error is returned if startHeight itself is missing from either store,
+// wrapping the store's [tmconsensus.HeightUnknownError],
+// so that a mistaken start height is not mistaken for an empty replay.
+// An error is also returned if handler returns an error,
+// if ctx is canceled,
+// or if the stores disagree on the block hash at a height.
+func ReplayFinalizationsFrom(
+	ctx context.Context,
+	fs tmstore.FinalizationStore,
+	chs tmstore.CommittedHeaderStore,
+	startHeight uint64,
+	handler func(FinalizeBlockRequest) error,
+) error {
+	for h := startHeight; ; h++ {
+		if err := context.Cause(ctx); err != nil {
+			return fmt.Errorf("replay interrupted before height %d: %w", h, err)
+		}
+
+		ch, err := chs.LoadCommittedHeader(ctx, h)
+		if err != nil {
+			if h > startHeight && errors.As(err, new(tmconsensus.HeightUnknownError)) {
+				return nil
+			}
+			return fmt.Errorf("failed to load committed header at height %d: %w", h, err)
+		}
+
+		round, blockHash, _, _, err := fs.LoadFinalizationByHeight(ctx, h)
+		if err != nil {
+			if h > startHeight && errors.As(err, new(tmconsensus.HeightUnknownError)) {
+				return nil
+			}
+			return fmt.Errorf("failed to load finalization at height %d: %w", h, err)
+		}
+
+		if blockHash != string(ch.Header.Hash) {
+			return fmt.Errorf(
+				"finalization at height %d has block hash %x, but committed header has hash %x",
+				h, blockHash, ch.Header.Hash,
+			)
+		}
+
+		req := FinalizeBlockRequest{
+			Header: ch.Header,
+			Round:  round,
+
+			IsCatchup: true,
+
+			Resp: make(chan FinalizeBlockResponse, 1),
+		}
+		if err := handler(req); err != nil {
+			return fmt.Errorf("handler failed at height %d: %w", h, err)
+		}
+	}
+}
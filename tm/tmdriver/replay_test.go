@@ -0,0 +1,132 @@
+package tmdriver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/gordian-engine/gordian/tm/tmdriver"
+	"github.com/gordian-engine/gordian/tm/tmstore/tmmemstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayFinalizations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	fx := tmconsensustest.NewStandardFixture(4)
+	fs := tmmemstore.NewFinalizationStore()
+	chs := tmmemstore.NewCommittedHeaderStore()
+	g := fx.DefaultGenesis()
+
+	// Store three committed heights, each finalized in a different round.
+	var want []tmconsensus.Header
+	for h := uint64(1); h <= 3; h++ {
+		round := uint32(h - 1)
+
+		ph := fx.NextProposedHeader([]byte(fmt.Sprintf("app_data_%d", h)), 0)
+		ph.Round = round
+		blockHash := string(ph.Header.Hash)
+
+		precommits := fx.PrecommitProofMap(ctx, h, round, map[string][]int{
+			blockHash: {0, 1, 2, 3},
+		})
+		appStateHash := []byte(fmt.Sprintf("app_state_%d", h))
+		fx.CommitBlock(ph.Header, appStateHash, round, precommits)
+
+		require.NoError(t, chs.SaveCommittedHeader(ctx, tmconsensus.CommittedHeader{
+			Header: ph.Header,
+			Proof: tmconsensus.CommitProof{
+				Round:      round,
+				PubKeyHash: string(ph.Header.ValidatorSet.PubKeyHash),
+				Proofs:     tmconsensus.FullProofsToSparse(precommits).BlockSignatures,
+			},
+		}))
+		require.NoError(t, fs.SaveFinalization(
+			ctx, h, round, blockHash, fx.ValSet(), string(appStateHash),
+		))
+
+		want = append(want, ph.Header)
+	}
+
+	t.Run("replays stored heights in order", func(t *testing.T) {
+		t.Parallel()
+
+		var reqs []tmdriver.FinalizeBlockRequest
+		require.NoError(t, tmdriver.ReplayFinalizations(ctx, fs, chs, g, func(req tmdriver.FinalizeBlockRequest) error {
+			reqs = append(reqs, req)
+
+			// Responding as a driver normally would must not block.
+			req.Resp <- tmdriver.FinalizeBlockResponse{
+				Height:    req.Header.Height,
+				Round:     req.Round,
+				BlockHash: req.Header.Hash,
+			}
+			return nil
+		}))
+
+		require.Len(t, reqs, 3)
+		for i, req := range reqs {
+			require.Equal(t, want[i], req.Header)
+			require.Equal(t, uint32(i), req.Round)
+			require.True(t, req.IsCatchup)
+		}
+	})
+
+	t.Run("handler error stops replay", func(t *testing.T) {
+		t.Parallel()
+
+		errStop := errors.New("stop")
+		var heights []uint64
+		err := tmdriver.ReplayFinalizations(ctx, fs, chs, g, func(req tmdriver.FinalizeBlockRequest) error {
+			heights = append(heights, req.Header.Height)
+			if req.Header.Height == 2 {
+				return errStop
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, errStop)
+		require.Equal(t, []uint64{1, 2}, heights)
+	})
+
+	t.Run("later start height", func(t *testing.T) {
+		t.Parallel()
+
+		var heights []uint64
+		require.NoError(t, tmdriver.ReplayFinalizationsFrom(ctx, fs, chs, 2, func(req tmdriver.FinalizeBlockRequest) error {
+			heights = append(heights, req.Header.Height)
+			return nil
+		}))
+		require.Equal(t, []uint64{2, 3}, heights)
+	})
+
+	t.Run("genesis initial height", func(t *testing.T) {
+		t.Parallel()
+
+		// Heights below the initial height are not replayed.
+		laterGenesis := g
+		laterGenesis.InitialHeight = 3
+
+		var heights []uint64
+		require.NoError(t, tmdriver.ReplayFinalizations(ctx, fs, chs, laterGenesis, func(req tmdriver.FinalizeBlockRequest) error {
+			heights = append(heights, req.Header.Height)
+			return nil
+		}))
+		require.Equal(t, []uint64{3}, heights)
+	})
+
+	t.Run("missing start height", func(t *testing.T) {
+		t.Parallel()
+
+		err := tmdriver.ReplayFinalizationsFrom(ctx, fs, chs, 4, func(tmdriver.FinalizeBlockRequest) error {
+			t.Fatal("handler must not be called")
+			return nil
+		})
+		require.Error(t, err)
+		require.ErrorAs(t, err, new(tmconsensus.HeightUnknownError))
+	})
+}
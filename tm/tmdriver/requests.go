@@ -38,6 +38,16 @@ type FinalizeBlockRequest struct {
 	Header tmconsensus.Header
 	Round  uint32
 
+	// IsCatchup indicates that the engine is finalizing this block
+	// as part of catching up to the network,
+	// by replaying a header that the network already committed,
+	// rather than having observed the votes for the block live.
+	//
+	// The driver must still evaluate the block and respond as usual,
+	// but it may choose to skip expensive side effects, such as emitting events,
+	// that are only useful for recent blocks.
+	IsCatchup bool
+
 	Resp chan FinalizeBlockResponse
 }
 
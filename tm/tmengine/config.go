@@ -0,0 +1,518 @@
+package tmengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/gordian-engine/gordian/gassert"
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/gwatchdog"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmdriver"
+	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmeil"
+	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmmirror"
+	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmstate"
+	"github.com/gordian-engine/gordian/tm/tmengine/tmelink"
+	"github.com/gordian-engine/gordian/tm/tmgossip"
+	"github.com/gordian-engine/gordian/tm/tmstore"
+)
+
+// EngineConfig holds every setting of an [Engine].
+// It is an alternative to passing [Opt] values to [New],
+// for callers that build their configuration programmatically,
+// such as from a configuration file.
+// Pass the populated config to [NewFromConfig].
+//
+// Each field corresponds to a With* option,
+// whose documentation describes the setting in detail;
+// every option is implemented by setting fields of an EngineConfig.
+// The fields that are required by [NewFromConfig] are the same
+// as the options that are required by [New].
+// The zero value of any other field leaves the corresponding feature disabled
+// or at its documented default.
+type EngineConfig struct {
+	// Required fields.
+
+	Genesis *tmconsensus.ExternalGenesis // See [WithGenesis].
+
+	HashScheme                        tmconsensus.HashScheme                    // See [WithHashScheme].
+	SignatureScheme                   tmconsensus.SignatureScheme               // See [WithSignatureScheme].
+	CommonMessageSignatureProofScheme gcrypto.CommonMessageSignatureProofScheme // See [WithCommonMessageSignatureProofScheme].
+
+	ConsensusStrategy tmconsensus.ConsensusStrategy // See [WithConsensusStrategy].
+	GossipStrategy    tmgossip.Strategy             // See [WithGossipStrategy].
+
+	CommittedHeaderStore tmstore.CommittedHeaderStore // See [WithCommittedHeaderStore].
+	FinalizationStore    tmstore.FinalizationStore    // See [WithFinalizationStore].
+	MirrorStore          tmstore.MirrorStore          // See [WithMirrorStore].
+	RoundStore           tmstore.RoundStore           // See [WithRoundStore].
+	StateMachineStore    tmstore.StateMachineStore    // See [WithStateMachineStore].
+	ValidatorStore       tmstore.ValidatorStore       // See [WithValidatorStore].
+
+	BlockFinalizationChannel chan<- tmdriver.FinalizeBlockRequest // See [WithBlockFinalizationChannel].
+
+	// The round timer is created from TimeoutStrategy
+	// with the context passed to [NewFromConfig],
+	// unless it was set by [WithTimeoutStrategy], which uses that option's context.
+	// See [WithTimeoutStrategy].
+	TimeoutStrategy TimeoutStrategy
+
+	Watchdog *gwatchdog.Watchdog // See [WithWatchdog].
+
+	// Conditionally required fields.
+
+	Signer      tmconsensus.Signer  // See [WithSigner].
+	ActionStore tmstore.ActionStore // Required if Signer is set. See [WithActionStore].
+
+	InitChainChannel chan<- tmdriver.InitChainRequest // See [WithInitChainChannel].
+
+	// Required if Genesis has GenesisValidatorAnnotations.
+	// See [WithGenesisAnnotationStore].
+	GenesisAnnotationStore tmstore.GenesisAnnotationStore
+
+	AssertEnv gassert.Env // Required in debug builds. See [WithAssertEnv].
+
+	// Optional fields.
+
+	StrategyResponseTimeout time.Duration // See [WithStrategyResponseTimeout].
+
+	// DataAvailabilityTimeout must be positive if DataAvailabilityChecker is set.
+	// See [WithDataAvailabilityChecker].
+	DataAvailabilityChecker func(ctx context.Context, dataID string) (available bool, err error)
+	DataAvailabilityTimeout time.Duration
+
+	BlockDataAvailabilityFunc func(ctx context.Context, height uint64, round uint32, dataID string) (available bool) // See [WithBlockDataAvailabilityFunc].
+
+	BlockDataArrivalChannel <-chan tmelink.BlockDataArrival // See [WithBlockDataArrivalChannel].
+	BlockDataArrivalBuffer  int                             // See [WithBlockDataArrivalBuffer].
+
+	// MinPeersTimeout must be positive if MinPeersBeforeVoting is positive.
+	// See [WithMinPeersBeforeVoting].
+	MinPeersBeforeVoting int
+	MinPeersTimeout      time.Duration
+
+	// ResourceGuardPollInterval must be positive if ResourceGuard is set.
+	// See [WithResourceGuard].
+	ResourceGuard             func() (ok bool, reason string)
+	ResourceGuardPollInterval time.Duration
+
+	ResourceGuardAlertOutput chan<- tmelink.ResourceGuardAlert // See [WithResourceGuardAlertOutput].
+
+	LagStateChannel              chan<- tmelink.LagState              // See [WithLagStateChannel].
+	BeaconOutput                 chan<- tmelink.Beacon                // See [WithBeaconOutput].
+	SafetyViolationOutput        chan<- tmelink.SafetyViolation       // See [WithSafetyViolationOutput].
+	ReplayedHeaderRequestChannel <-chan tmelink.ReplayedHeaderRequest // See [WithReplayedHeaderRequestChannel].
+	ProposalPrepareNotifier      chan<- tmelink.ProposalPrepare       // See [WithProposalPrepareNotifier].
+	CommitWaitNotifier           chan<- tmelink.CommitWaitEntered     // See [WithCommitWaitNotifier].
+
+	FetchedHeaderBufferLimit      int    // See [WithFetchedHeaderBufferLimit].
+	RequireExpectedProposer       bool   // See [WithRequireExpectedProposer].
+	MaxHeadersPerProposerPerRound int    // See [WithMaxHeadersPerProposerPerRound].
+	MaxFutureRoundForHeader       uint32 // See [WithMaxFutureRoundForHeader].
+	MaxAnnotationBytes            int    // See [WithMaxAnnotationBytes].
+
+	// See [WithHeaderTimestampValidation].
+	ValidateHeaderTimestamps      bool
+	HeaderTimestampMaxFutureDrift time.Duration
+
+	IncomingHeaderPrefilter func(tmconsensus.ProposedHeader) bool // See [WithIncomingHeaderPrefilter].
+
+	AllowTrustedHeaderIngestion bool // See [WithTrustedHeaderIngestion].
+
+	RequireKnownVoteBlockHash bool          // See [WithRequireKnownVoteBlockHash].
+	MinimizeCommitProof       bool          // See [WithMinimizeCommitProof].
+	MinVotePowerToGossip      uint64        // See [WithMinVotePowerToGossip].
+	LateVoteGracePeriod       time.Duration // See [WithLateVoteGracePeriod].
+
+	EndCommitWaitOnFullPrecommits    bool          // See [WithEndCommitWaitOnFullPrecommits].
+	SkipSingleValidatorCommitWait    bool          // See [WithSkipSingleValidatorCommitWait].
+	WatchdogFinalizationFlushTimeout time.Duration // See [WithWatchdogFinalizationFlushTimeout].
+	MaxRoundsPerHeight               uint32        // See [WithMaxRoundsPerHeight].
+	SelfEquivocationGuard            bool          // See [WithSelfEquivocationGuard].
+
+	ValidatorSetTransitionValidator tmconsensus.ValidatorSetTransitionValidator // See [WithValidatorSetTransitionValidator].
+
+	ProposalAnnotator           func(height uint64, round uint32) (proposalAnn, blockAnn []byte, err error) // See [WithProposalAnnotator].
+	EnterRoundObserver          func(tmconsensus.RoundView)                                                 // See [WithEnterRoundObserver].
+	InitialValidatorSetProvider func(context.Context) (tmconsensus.ValidatorSet, uint64, error)             // See [WithInitialValidatorSetProvider].
+	ActionObserver              func(tmelink.StateMachineRoundAction)                                       // See [WithActionObserver].
+
+	// The heartbeat durations must be either all zero, to use the defaults,
+	// or all positive.
+	// See [WithWatchdogHeartbeat].
+	WatchdogHeartbeatInterval        time.Duration
+	WatchdogHeartbeatJitter          time.Duration
+	WatchdogHeartbeatResponseTimeout time.Duration
+
+	MetricsChannel    chan<- Metrics        // See [WithMetricsChannel].
+	PeerScoringOutput chan<- PeerScoreEvent // See [WithPeerScoringOutput].
+
+	// Alerts are only sent if DivergenceAlertChannel is set.
+	// See [WithDivergenceAlert].
+	DivergenceAlertThreshold uint64
+	DivergenceAlertChannel   chan<- DivergenceAlert
+
+	// Set by WithInternalRoundTimer,
+	// taking precedence over TimeoutStrategy.
+	roundTimer roundTimer
+
+	// Set by WithTimeoutStrategy,
+	// to create the round timer from TimeoutStrategy
+	// with the context passed to that option.
+	timeoutCtx context.Context
+}
+
+// validateValues returns an error if any of c's fields hold invalid values.
+// It does not check for missing required fields.
+func (c EngineConfig) validateValues() error {
+	var err error
+
+	if c.StrategyResponseTimeout < 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"StrategyResponseTimeout must not be negative (got %s)", c.StrategyResponseTimeout,
+		))
+	}
+
+	if c.DataAvailabilityChecker != nil && c.DataAvailabilityTimeout <= 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"DataAvailabilityTimeout must be positive (got %s)", c.DataAvailabilityTimeout,
+		))
+	}
+
+	if c.BlockDataArrivalBuffer < 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"BlockDataArrivalBuffer must not be negative (got %d)", c.BlockDataArrivalBuffer,
+		))
+	}
+
+	if c.MinPeersBeforeVoting < 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"MinPeersBeforeVoting must not be negative (got %d)", c.MinPeersBeforeVoting,
+		))
+	}
+
+	if c.MinPeersBeforeVoting > 0 && c.MinPeersTimeout <= 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"MinPeersTimeout must be positive (got %s)", c.MinPeersTimeout,
+		))
+	}
+
+	if c.ResourceGuard != nil && c.ResourceGuardPollInterval <= 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"ResourceGuardPollInterval must be positive (got %s)", c.ResourceGuardPollInterval,
+		))
+	}
+
+	if c.ResourceGuardAlertOutput != nil && cap(c.ResourceGuardAlertOutput) == 0 {
+		err = errors.Join(err, errors.New("ResourceGuardAlertOutput must be buffered"))
+	}
+
+	if cap(c.LagStateChannel) != 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"LagStateChannel capacity must be zero (got %d)", cap(c.LagStateChannel),
+		))
+	}
+
+	if c.ProposalPrepareNotifier != nil && cap(c.ProposalPrepareNotifier) == 0 {
+		err = errors.Join(err, errors.New("ProposalPrepareNotifier must be buffered"))
+	}
+
+	if c.CommitWaitNotifier != nil && cap(c.CommitWaitNotifier) == 0 {
+		err = errors.Join(err, errors.New("CommitWaitNotifier must be buffered"))
+	}
+
+	if c.FetchedHeaderBufferLimit < 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"FetchedHeaderBufferLimit must not be negative (got %d)", c.FetchedHeaderBufferLimit,
+		))
+	}
+
+	if c.MaxHeadersPerProposerPerRound < 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"MaxHeadersPerProposerPerRound must not be negative (got %d)", c.MaxHeadersPerProposerPerRound,
+		))
+	}
+
+	if c.MaxAnnotationBytes < 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"MaxAnnotationBytes must not be negative (got %d)", c.MaxAnnotationBytes,
+		))
+	}
+
+	if c.HeaderTimestampMaxFutureDrift < 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"HeaderTimestampMaxFutureDrift must not be negative (got %s)", c.HeaderTimestampMaxFutureDrift,
+		))
+	}
+
+	if c.LateVoteGracePeriod < 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"LateVoteGracePeriod must not be negative (got %s)", c.LateVoteGracePeriod,
+		))
+	}
+
+	if c.WatchdogFinalizationFlushTimeout < 0 {
+		err = errors.Join(err, fmt.Errorf(
+			"WatchdogFinalizationFlushTimeout must not be negative (got %s)", c.WatchdogFinalizationFlushTimeout,
+		))
+	}
+
+	if c.WatchdogHeartbeatInterval != 0 || c.WatchdogHeartbeatJitter != 0 || c.WatchdogHeartbeatResponseTimeout != 0 {
+		if c.WatchdogHeartbeatInterval <= 0 || c.WatchdogHeartbeatJitter <= 0 || c.WatchdogHeartbeatResponseTimeout <= 0 {
+			err = errors.Join(err, fmt.Errorf(
+				"watchdog heartbeat durations must be positive (got interval=%s, jitter=%s, response timeout=%s)",
+				c.WatchdogHeartbeatInterval, c.WatchdogHeartbeatJitter, c.WatchdogHeartbeatResponseTimeout,
+			))
+		} else if c.WatchdogHeartbeatJitter > c.WatchdogHeartbeatInterval {
+			err = errors.Join(err, fmt.Errorf(
+				"watchdog heartbeat jitter must not exceed interval (got interval=%s, jitter=%s)",
+				c.WatchdogHeartbeatInterval, c.WatchdogHeartbeatJitter,
+			))
+		}
+	}
+
+	if len(c.MetricsChannel) != 0 {
+		err = errors.Join(err, errors.New("MetricsChannel must be unbuffered"))
+	}
+
+	if c.Genesis != nil && len(c.Genesis.GenesisValidatorAnnotations) > 0 && c.HashScheme != nil {
+		err = errors.Join(err, c.validateGenesisValidatorAnnotations())
+	}
+
+	return err
+}
+
+// validateGenesisValidatorAnnotations returns an error for each key
+// in c's GenesisValidatorAnnotations that is not the annotation key
+// of a validator in c's GenesisValidatorSet.
+// c.Genesis and c.HashScheme must be set.
+func (c EngineConfig) validateGenesisValidatorAnnotations() error {
+	vals := c.Genesis.GenesisValidatorSet.Validators
+	valKeys := make(map[string]struct{}, len(vals))
+	for _, v := range vals {
+		key, err := tmconsensus.GenesisValidatorAnnotationKey(c.HashScheme, v.PubKey)
+		if err != nil {
+			return fmt.Errorf("failed to calculate genesis validator annotation key: %w", err)
+		}
+		valKeys[key] = struct{}{}
+	}
+
+	var err error
+	for _, key := range slices.Sorted(maps.Keys(c.Genesis.GenesisValidatorAnnotations)) {
+		if _, ok := valKeys[key]; !ok {
+			err = errors.Join(err, fmt.Errorf(
+				"GenesisValidatorAnnotations key %x does not belong to a genesis validator", key,
+			))
+		}
+	}
+	return err
+}
+
+// validateRequired returns an error if any of the fields
+// required to run a full engine are missing.
+func (c EngineConfig) validateRequired() error {
+	var err error
+
+	if c.Genesis == nil {
+		err = errors.Join(err, errors.New("no genesis set (use tmengine.WithGenesis)"))
+	}
+
+	if c.HashScheme == nil {
+		err = errors.Join(err, errors.New("no hash scheme set (use tmengine.WithHashScheme)"))
+	}
+	if c.SignatureScheme == nil {
+		err = errors.Join(err, errors.New("no signature scheme set (use tmengine.WithSignatureScheme)"))
+	}
+	if c.CommonMessageSignatureProofScheme == nil {
+		err = errors.Join(err, errors.New("no common message signature proof scheme set (use tmengine.WithCommonMessageSignatureProofScheme)"))
+	}
+
+	if c.GossipStrategy == nil {
+		err = errors.Join(err, errors.New("no gossip strategy set (use tmengine.WithGossipStrategy)"))
+	}
+
+	if c.ActionStore == nil && c.Signer != nil {
+		err = errors.Join(err, errors.New("no action store set (use tmengine.WithActionStore)"))
+	}
+
+	if c.FinalizationStore == nil {
+		err = errors.Join(err, errors.New("no finalization store set (use tmengine.WithFinalizationStore)"))
+	}
+
+	if c.GenesisAnnotationStore == nil && c.Genesis != nil && len(c.Genesis.GenesisValidatorAnnotations) > 0 {
+		err = errors.Join(err, errors.New(
+			"genesis has validator annotations but no genesis annotation store set (use tmengine.WithGenesisAnnotationStore)",
+		))
+	}
+
+	if c.MirrorStore == nil {
+		err = errors.Join(err, errors.New("no mirror store set (use tmengine.WithMirrorStore)"))
+	}
+
+	if c.RoundStore == nil {
+		err = errors.Join(err, errors.New("no round store set (use tmengine.WithRoundStore)"))
+	}
+
+	if c.StateMachineStore == nil {
+		err = errors.Join(err, errors.New("no state machine store set (use tmengine.WithStateMachineStore)"))
+	}
+
+	if c.ValidatorStore == nil {
+		err = errors.Join(err, errors.New("no validator store set (use tmengine.WithValidatorStore)"))
+	}
+
+	if c.Watchdog == nil {
+		err = errors.Join(err, errors.New("no watchdog set (use tmengine.WithWatchdog)"))
+	}
+
+	if c.MinPeersBeforeVoting > 0 && c.GossipStrategy != nil {
+		if _, ok := c.GossipStrategy.(tmgossip.PeerCounter); !ok {
+			err = errors.Join(err, errors.New(
+				"gossip strategy must implement tmgossip.PeerCounter when using tmengine.WithMinPeersBeforeVoting",
+			))
+		}
+	}
+
+	if c.ConsensusStrategy == nil {
+		err = errors.Join(err, errors.New("no consensus strategy set (use tmengine.WithConsensusStrategy)"))
+	}
+
+	if c.BlockFinalizationChannel == nil {
+		err = errors.Join(err, errors.New("no block finalization channel set (use tmengine.WithBlockFinalizationChannel)"))
+	}
+
+	// TODO: we are currently not validating the presence of the AppDataArrival channel.
+	// Add a WithoutAppDataArrival() option so that the rare case
+	// of not needing to separately retrieve app data is explicitly opt-in.
+	// Fail validation if both or neither of the option pair is provided.
+
+	// Tests use WithInternalRoundTimer to set a tmstate.MockRoundTimer,
+	// to avoid reliance on the wall clock.
+	// But, external callers are expected to only provide a TimeoutStrategy,
+	// so that is the API named in the error.
+	if c.roundTimer == nil && c.TimeoutStrategy == nil {
+		err = errors.Join(err, errors.New("no timeout strategy set (use tmengine.WithTimeoutStrategy)"))
+	}
+
+	return err
+}
+
+// mirrorConfig returns the mirror settings from c.
+// The caller is responsible for setting the mirror's channels,
+// initial height and validator set, and metrics collector.
+func (c EngineConfig) mirrorConfig() tmmirror.MirrorConfig {
+	return tmmirror.MirrorConfig{
+		Store:                c.MirrorStore,
+		CommittedHeaderStore: c.CommittedHeaderStore,
+		RoundStore:           c.RoundStore,
+		ValidatorStore:       c.ValidatorStore,
+
+		HashScheme:                        c.HashScheme,
+		SignatureScheme:                   c.SignatureScheme,
+		CommonMessageSignatureProofScheme: c.CommonMessageSignatureProofScheme,
+
+		LagStateOut:        c.LagStateChannel,
+		BeaconOut:          c.BeaconOutput,
+		SafetyViolationOut: c.SafetyViolationOutput,
+		ReplayedHeadersIn:  c.ReplayedHeaderRequestChannel,
+
+		FetchedHeaderBufferLimit:      c.FetchedHeaderBufferLimit,
+		RequireExpectedProposer:       c.RequireExpectedProposer,
+		MaxHeadersPerProposerPerRound: c.MaxHeadersPerProposerPerRound,
+		ValidateHeaderTimestamps:      c.ValidateHeaderTimestamps,
+		HeaderTimestampMaxFutureDrift: c.HeaderTimestampMaxFutureDrift,
+		MaxFutureRoundForHeader:       c.MaxFutureRoundForHeader,
+		MaxAnnotationBytes:            c.MaxAnnotationBytes,
+		IncomingHeaderPrefilter:       c.IncomingHeaderPrefilter,
+		RequireKnownVoteBlockHash:     c.RequireKnownVoteBlockHash,
+		MinimizeCommitProof:           c.MinimizeCommitProof,
+		MinVotePowerToGossip:          c.MinVotePowerToGossip,
+		LateVoteGracePeriod:           c.LateVoteGracePeriod,
+		AllowTrustedHeaderIngestion:   c.AllowTrustedHeaderIngestion,
+
+		Watchdog:          c.Watchdog,
+		WatchdogHeartbeat: c.watchdogHeartbeat(),
+
+		AssertEnv: c.AssertEnv,
+	}
+}
+
+// stateMachineConfig returns the state machine settings from c,
+// creating the round timer from c.TimeoutStrategy if necessary.
+// The caller is responsible for setting the state machine's channels,
+// genesis, and metrics collector.
+func (c EngineConfig) stateMachineConfig(ctx context.Context) tmstate.StateMachineConfig {
+	smc := tmstate.StateMachineConfig{
+		Signer:            c.Signer,
+		HashScheme:        c.HashScheme,
+		ConsensusStrategy: c.ConsensusStrategy,
+
+		ActionStore:       c.ActionStore,
+		FinalizationStore: c.FinalizationStore,
+		StateMachineStore: c.StateMachineStore,
+
+		RoundTimer: c.roundTimer,
+
+		FinalizeBlockRequestCh: c.BlockFinalizationChannel,
+		BlockDataArrivalCh:     c.BlockDataArrivalChannel,
+		BlockDataArrivalBuffer: c.BlockDataArrivalBuffer,
+
+		StrategyResponseTimeout:   c.StrategyResponseTimeout,
+		DataAvailabilityChecker:   c.DataAvailabilityChecker,
+		DataAvailabilityTimeout:   c.DataAvailabilityTimeout,
+		BlockDataAvailabilityFunc: c.BlockDataAvailabilityFunc,
+
+		MinPeersBeforeVoting: c.MinPeersBeforeVoting,
+		MinPeersTimeout:      c.MinPeersTimeout,
+
+		ResourceGuard:             c.ResourceGuard,
+		ResourceGuardPollInterval: c.ResourceGuardPollInterval,
+		ResourceGuardAlertOut:     c.ResourceGuardAlertOutput,
+
+		MaxAnnotationBytes:               c.MaxAnnotationBytes,
+		EndCommitWaitOnFullPrecommits:    c.EndCommitWaitOnFullPrecommits,
+		SkipSingleValidatorCommitWait:    c.SkipSingleValidatorCommitWait,
+		WatchdogFinalizationFlushTimeout: c.WatchdogFinalizationFlushTimeout,
+		MaxRoundsPerHeight:               c.MaxRoundsPerHeight,
+		HaltOnSelfEquivocation:           c.SelfEquivocationGuard,
+
+		ValidatorSetTransitionValidator: c.ValidatorSetTransitionValidator,
+
+		ProposalAnnotator:           c.ProposalAnnotator,
+		EnterRoundObserver:          c.EnterRoundObserver,
+		InitialValidatorSetProvider: c.InitialValidatorSetProvider,
+		ActionObserver:              c.ActionObserver,
+
+		ProposalPrepareOut:   c.ProposalPrepareNotifier,
+		CommitWaitEnteredOut: c.CommitWaitNotifier,
+
+		Watchdog:          c.Watchdog,
+		WatchdogHeartbeat: c.watchdogHeartbeat(),
+
+		AssertEnv: c.AssertEnv,
+	}
+
+	if smc.RoundTimer == nil && c.TimeoutStrategy != nil {
+		timeoutCtx := c.timeoutCtx
+		if timeoutCtx == nil {
+			timeoutCtx = ctx
+		}
+		smc.RoundTimer = tmstate.NewStandardRoundTimer(timeoutCtx, c.TimeoutStrategy)
+	}
+
+	return smc
+}
+
+// watchdogHeartbeat returns the heartbeat settings from c,
+// or the zero value to use the subsystems' defaults.
+func (c EngineConfig) watchdogHeartbeat() tmeil.WatchdogHeartbeat {
+	return tmeil.WatchdogHeartbeat{
+		Interval:        c.WatchdogHeartbeatInterval,
+		Jitter:          c.WatchdogHeartbeatJitter,
+		ResponseTimeout: c.WatchdogHeartbeatResponseTimeout,
+	}
+}
@@ -40,13 +40,44 @@ type Engine struct {
 
 	sm *tmstate.StateMachine
 
-	initChainCh chan<- tmdriver.InitChainRequest
-	metricsCh   chan<- Metrics
+	initChainCh  chan<- tmdriver.InitChainRequest
+	metricsCh    chan<- Metrics
+	peerScoreOut chan<- PeerScoreEvent
+
+	gaStore tmstore.GenesisAnnotationStore
+
+	divergenceAlert tmemetrics.DivergenceAlertConfig
+
+	valSetUpdates chan tmelink.ValidatorSetUpdate
 
 	watchdog *gwatchdog.Watchdog
 }
 
+// New returns a new Engine configured by opts.
+// It is equivalent to applying opts to a zero [EngineConfig]
+// and passing the result to [NewFromConfig].
 func New(ctx context.Context, log *slog.Logger, opts ...Opt) (*Engine, error) {
+	var cfg EngineConfig
+
+	var err error
+	for _, opt := range opts {
+		err = errors.Join(err, opt(&cfg))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromConfig(ctx, log, cfg)
+}
+
+// NewFromConfig returns a new Engine configured by cfg.
+// Every field of cfg is validated before any subsystem is started,
+// and all validation failures are reported together.
+func NewFromConfig(ctx context.Context, log *slog.Logger, cfg EngineConfig) (*Engine, error) {
+	if err := errors.Join(cfg.validateValues(), cfg.validateRequired()); err != nil {
+		return nil, err
+	}
+
 	// These channels must be unbuffered so that all communication is synchronized.
 	smViewCh := make(chan tmeil.StateMachineRoundView)
 	gsCh := make(chan tmelink.NetworkViewUpdate)
@@ -54,30 +85,68 @@ func New(ctx context.Context, log *slog.Logger, opts ...Opt) (*Engine, error) {
 	e := &Engine{
 		log: log,
 
-		mCfg: tmmirror.MirrorConfig{
-			GossipStrategyOut:        gsCh,
-			StateMachineRoundViewOut: smViewCh,
-		},
-	}
+		genesis: cfg.Genesis,
 
-	smCfg := tmstate.StateMachineConfig{
-		RoundViewInCh: smViewCh,
-	}
+		gs: cfg.GossipStrategy,
 
-	var err error
-	for _, opt := range opts {
-		err = errors.Join(opt(e, &smCfg))
-	}
-	if err != nil {
-		return nil, err
+		hashScheme: cfg.HashScheme,
+		sigScheme:  cfg.SignatureScheme,
+		cmspScheme: cfg.CommonMessageSignatureProofScheme,
+
+		mCfg: cfg.mirrorConfig(),
+
+		initChainCh:  cfg.InitChainChannel,
+		metricsCh:    cfg.MetricsChannel,
+		peerScoreOut: cfg.PeerScoringOutput,
+
+		gaStore: cfg.GenesisAnnotationStore,
+
+		watchdog: cfg.Watchdog,
 	}
+	e.mCfg.GossipStrategyOut = gsCh
+	e.mCfg.StateMachineRoundViewOut = smViewCh
 
-	if err := e.validateSettings(smCfg); err != nil {
-		return nil, err
+	if cfg.DivergenceAlertChannel != nil {
+		e.divergenceAlert = tmemetrics.DivergenceAlertConfig{
+			Threshold: cfg.DivergenceAlertThreshold,
+			Out:       cfg.DivergenceAlertChannel,
+		}
 	}
 
-	if e.metricsCh != nil {
-		mc := tmemetrics.NewCollector(ctx, 4, e.metricsCh)
+	// Buffered so that a briefly slow reader does not miss updates;
+	// the state machine discards the oldest update if the buffer is full.
+	valSetUpdates := make(chan tmelink.ValidatorSetUpdate, 8)
+	e.valSetUpdates = valSetUpdates
+
+	smCfg := cfg.stateMachineConfig(ctx)
+	smCfg.RoundViewInCh = smViewCh
+	smCfg.ValidatorSetUpdatesOut = valSetUpdates
+
+	var err error
+
+	if smCfg.MinPeersBeforeVoting > 0 {
+		// Only the latest count matters,
+		// so the callback replaces any count the state machine has not yet read.
+		peerCounts := make(chan int, 1)
+		smCfg.PeerCountCh = peerCounts
+		e.gs.(tmgossip.PeerCounter).SetPeerCountCallback(func(n int) {
+			for {
+				select {
+				case peerCounts <- n:
+					return
+				default:
+				}
+
+				select {
+				case <-peerCounts:
+				default:
+				}
+			}
+		})
+	}
+
+	if e.metricsCh != nil || e.divergenceAlert.Out != nil {
+		mc := tmemetrics.NewCollectorWithDivergenceAlert(ctx, 4, e.metricsCh, e.divergenceAlert)
 		smCfg.MetricsCollector = mc
 		e.mCfg.MetricsCollector = mc
 	}
@@ -95,6 +164,11 @@ func New(ctx context.Context, log *slog.Logger, opts ...Opt) (*Engine, error) {
 
 	e.mCfg.InitialHeight = e.genesis.InitialHeight
 
+	// The mirror only skips previous commit proof verification at the chain's genesis height.
+	// That is the same as the initial height unless the node is started above genesis,
+	// through [WithInitialValidatorSetProvider].
+	e.mCfg.GenesisHeight = e.genesis.InitialHeight
+
 	// The mirror needs its initial validator set too.
 	// We only set the state machine genesis if we did initialize the chain.
 	// So if that is empty, populate the mirror's initial validator set
@@ -111,6 +185,27 @@ func New(ctx context.Context, log *slog.Logger, opts ...Opt) (*Engine, error) {
 		}
 	}
 
+	if cfg.InitialValidatorSetProvider != nil {
+		// The mirror and the state machine must agree on the height this node starts at,
+		// so call the provider once here and share its result with both.
+		valSet, h, err := cfg.InitialValidatorSetProvider(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get initial validator set from provider: %w", err)
+		}
+		if h < e.genesis.InitialHeight {
+			return nil, fmt.Errorf(
+				"initial validator set provider returned height %d before genesis initial height %d",
+				h, e.genesis.InitialHeight,
+			)
+		}
+
+		e.mCfg.InitialHeight = h
+		e.mCfg.InitialValidatorSet = valSet
+		smCfg.InitialValidatorSetProvider = func(context.Context) (tmconsensus.ValidatorSet, uint64, error) {
+			return valSet, h, nil
+		}
+	}
+
 	// Set up a cancelable context in case any of the subsystems fail to create.
 	// We cancel the context in any error path to stop the subsystems,
 	// although we don't wait for them at that point.
@@ -158,80 +253,6 @@ func (e *Engine) Wait() {
 	}
 }
 
-func (e *Engine) validateSettings(smc tmstate.StateMachineConfig) error {
-	var err error
-
-	if e.genesis == nil {
-		err = errors.Join(err, errors.New("no genesis set (use tmengine.WithGenesis)"))
-	}
-
-	if e.hashScheme == nil {
-		err = errors.Join(err, errors.New("no hash scheme set (use tmengine.WithHashScheme)"))
-	}
-	if e.sigScheme == nil {
-		err = errors.Join(err, errors.New("no signature scheme set (use tmengine.WithSignatureScheme)"))
-	}
-	if e.cmspScheme == nil {
-		err = errors.Join(err, errors.New("no common message signature proof scheme set (use tmengine.WithCommonMessageSignatureProofScheme)"))
-	}
-
-	if e.gs == nil {
-		err = errors.Join(err, errors.New("no gossip strategy set (use tmengine.WithGossipStrategy)"))
-	}
-
-	if smc.ActionStore == nil && smc.Signer != nil {
-		err = errors.Join(err, errors.New("no action store set (use tmengine.WithActionStore)"))
-	}
-
-	if smc.FinalizationStore == nil {
-		err = errors.Join(err, errors.New("no finalization store set (use tmengine.WithFinalizationStore)"))
-	}
-
-	if e.mCfg.Store == nil {
-		err = errors.Join(err, errors.New("no mirror store set (use tmengine.WithMirrorStore)"))
-	}
-
-	if e.mCfg.RoundStore == nil {
-		err = errors.Join(err, errors.New("no round store set (use tmengine.WithRoundStore)"))
-	}
-
-	if smc.StateMachineStore == nil {
-		err = errors.Join(err, errors.New("no state machine store set (use tmengine.WithStateMachineStore)"))
-	}
-
-	if e.mCfg.ValidatorStore == nil {
-		err = errors.Join(err, errors.New("no validator store set (use tmengine.WithValidatorStore)"))
-	}
-
-	if e.watchdog == nil {
-		err = errors.Join(err, errors.New("no watchdog set (use tmengine.WithWatchdog)"))
-	}
-
-	if smc.ConsensusStrategy == nil {
-		err = errors.Join(err, errors.New("no consensus strategy set (use tmengine.WithConsensusStrategy)"))
-	}
-
-	if smc.FinalizeBlockRequestCh == nil {
-		err = errors.Join(err, errors.New("no block finalization channel set (use tmengine.WithBlockFinalizationChannel)"))
-	}
-
-	// TODO: we are currently not validating the presence of the AppDataArrival channel.
-	// Add a WithoutAppDataArrival() option so that the rare case
-	// of not needing to separately retrieve app data is explicitly opt-in.
-	// Fail validation if both or neither of the option pair is provided.
-
-	// This is one special case.
-	// Tests instantiate a tmstate.MockRoundTimer to avoid reliance on the wall clock.
-	// But, external callers are expected to only provide a TimeoutStrategy.
-	// So even though we are inspecting the lower-level RoundTimer value,
-	// we return an error about the API external callers are expected to use.
-	if smc.RoundTimer == nil {
-		err = errors.Join(err, errors.New("no timeout strategy set (use tmengine.WithTimeoutStrategy)"))
-	}
-
-	return err
-}
-
 // maybeInitializeChain checks if we need to call into the app for InitChain, and calls it if required.
 //
 // The Genesis value returned is only populated if InitChain was called.
@@ -328,6 +349,19 @@ func (e *Engine) maybeInitializeChain(
 		return tmconsensus.Genesis{}, fmt.Errorf("failure building genesis header: %w", err)
 	}
 
+	// Save the annotations before the finalization,
+	// as the stored finalization indicates that the chain is initialized,
+	// and we would not reach this point again after a restart.
+	if len(e.genesis.GenesisValidatorAnnotations) > 0 {
+		if err := e.gaStore.SaveGenesisValidatorAnnotations(
+			ctx, e.genesis.GenesisValidatorAnnotations,
+		); err != nil {
+			return tmconsensus.Genesis{}, fmt.Errorf(
+				"failure saving genesis validator annotations: %w", err,
+			)
+		}
+	}
+
 	// Now we have the finalization; we have to store it.
 	if err := fStore.SaveFinalization(
 		ctx,
@@ -348,14 +382,64 @@ func (e *Engine) maybeInitializeChain(
 	return updatedGenesis, nil
 }
 
+// ChainID returns the chain ID of the genesis provided through [WithGenesis].
+func (e *Engine) ChainID() string {
+	return e.genesis.ChainID
+}
+
+// InitialHeight returns the initial height of the genesis provided through [WithGenesis].
+func (e *Engine) InitialHeight() uint64 {
+	return e.genesis.InitialHeight
+}
+
+// ValidatorSetUpdate is the value sent on the channel returned by [*Engine.ValidatorSetUpdates].
+type ValidatorSetUpdate = tmelink.ValidatorSetUpdate
+
+// ValidatorSetUpdates returns a channel that receives an update
+// each time a finalized block changes the validator set.
+// Each update carries the new set
+// and the first height at which that set is the current validator set.
+//
+// The engine never blocks on this channel.
+// It is buffered, and if the reader falls behind far enough to fill the buffer,
+// the oldest unread updates are discarded in favor of newer ones.
+// Every call returns the same channel,
+// so there should be only one reader.
+func (e *Engine) ValidatorSetUpdates() <-chan ValidatorSetUpdate {
+	return e.valSetUpdates
+}
+
+// IngestTrustedHeaders applies the given committed headers, in ascending height order,
+// without fetching them from the network.
+// Headers below the current voting height are ignored.
+// The engine must have been created with [WithTrustedHeaderIngestion],
+// otherwise an error is returned.
+func (e *Engine) IngestTrustedHeaders(ctx context.Context, chs []tmconsensus.CommittedHeader) error {
+	return e.m.IngestTrustedHeaders(ctx, chs)
+}
+
 func (e *Engine) HandleProposedHeader(ctx context.Context, ph tmconsensus.ProposedHeader) tmconsensus.HandleProposedHeaderResult {
-	return e.m.HandleProposedHeader(ctx, ph)
+	r := e.m.HandleProposedHeader(ctx, ph)
+	if c, ok := proposedHeaderPeerScoreClass(r); ok {
+		e.sendPeerScore(ctx, c)
+	}
+	return r
 }
 
 func (e *Engine) HandlePrevoteProofs(ctx context.Context, p tmconsensus.PrevoteSparseProof) tmconsensus.HandleVoteProofsResult {
-	return e.m.HandlePrevoteProofs(ctx, p)
+	var rep tmmirror.VoteProofsReport
+	r := e.m.HandlePrevoteProofsWithReport(ctx, p, &rep)
+	if c, ok := voteProofsPeerScoreClass(r, rep); ok {
+		e.sendPeerScore(ctx, c)
+	}
+	return r
 }
 
 func (e *Engine) HandlePrecommitProofs(ctx context.Context, p tmconsensus.PrecommitSparseProof) tmconsensus.HandleVoteProofsResult {
-	return e.m.HandlePrecommitProofs(ctx, p)
+	var rep tmmirror.VoteProofsReport
+	r := e.m.HandlePrecommitProofsWithReport(ctx, p, &rep)
+	if c, ok := voteProofsPeerScoreClass(r, rep); ok {
+		e.sendPeerScore(ctx, c)
+	}
+	return r
 }
@@ -3,11 +3,18 @@ package tmengine_test
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/gassert/gasserttest"
+	"github.com/gordian-engine/gordian/gcrypto"
 	"github.com/gordian-engine/gordian/gwatchdog"
 	"github.com/gordian-engine/gordian/internal/gtest"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
@@ -18,6 +25,7 @@ import (
 	"github.com/gordian-engine/gordian/tm/tmengine/tmelink"
 	"github.com/gordian-engine/gordian/tm/tmengine/tmenginetest"
 	"github.com/gordian-engine/gordian/tm/tmgossip/tmgossiptest"
+	"github.com/gordian-engine/gordian/tm/tmstore"
 	"github.com/gordian-engine/gordian/tm/tmstore/tmmemstore"
 	"github.com/stretchr/testify/require"
 )
@@ -873,6 +881,70 @@ func TestEngine_plumbing_ReplayedHeaders(t *testing.T) {
 	})
 }
 
+func TestEngine_IngestTrustedHeaders(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "enabled", enabled: true},
+		{name: "disabled", enabled: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			efx := tmenginetest.NewFixture(ctx, t, 4)
+
+			var engine *tmengine.Engine
+			eReady := make(chan struct{})
+			go func() {
+				defer close(eReady)
+				om := efx.BaseOptionMap()
+				om["WithTrustedHeaderIngestion"] = tmengine.WithTrustedHeaderIngestion(tc.enabled)
+				engine = efx.MustNewEngine(om.ToSlice()...)
+			}()
+
+			defer func() {
+				cancel()
+				<-eReady
+				engine.Wait()
+			}()
+
+			_ = efx.ConsensusStrategy.ExpectEnterRound(1, 0, nil)
+
+			icReq := gtest.ReceiveSoon(t, efx.InitChainCh)
+			gtest.SendSoon(t, icReq.Resp, tmdriver.InitChainResponse{
+				AppStateHash: []byte("whatever"),
+			})
+			_ = gtest.ReceiveSoon(t, eReady)
+
+			ph1 := efx.Fx.NextProposedHeader([]byte("app_state_1"), 0)
+			precommitProofsMap := efx.Fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+				string(ph1.Header.Hash): {0, 1, 2, 3},
+			})
+			efx.Fx.CommitBlock(ph1.Header, []byte("app_state_height_1"), 0, precommitProofsMap)
+			ph2 := efx.Fx.NextProposedHeader([]byte("app_state_2"), 0)
+
+			err := engine.IngestTrustedHeaders(ctx, []tmconsensus.CommittedHeader{
+				{Header: ph1.Header, Proof: ph2.Header.PrevCommitProof},
+			})
+			if !tc.enabled {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// The header was applied without being fetched.
+			phs, _, _, err := efx.RoundStore.LoadRoundState(ctx, 1, 0)
+			require.NoError(t, err)
+			require.Len(t, phs, 1)
+			require.Equal(t, ph1.Header, phs[0].Header)
+		})
+	}
+}
+
 func TestEngine_wiring_validatorChanges(t *testing.T) {
 	t.Parallel()
 
@@ -1246,6 +1318,108 @@ func TestEngine_initChain(t *testing.T) {
 		require.Equal(t, "app_state_0", appStateHash)
 	})
 
+	t.Run("genesis validator annotations are saved during initialization", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		efx := tmenginetest.NewFixture(ctx, t, 2)
+
+		annotations := make(map[string][]byte, 2)
+		for i, v := range efx.Fx.Vals() {
+			key, err := tmconsensus.GenesisValidatorAnnotationKey(efx.Fx.HashScheme, v.PubKey)
+			require.NoError(t, err)
+			annotations[key] = []byte(fmt.Sprintf("moniker=val%d", i))
+		}
+		gaStore := tmmemstore.NewGenesisAnnotationStore()
+
+		var engine *tmengine.Engine
+		eReady := make(chan struct{})
+		go func() {
+			defer close(eReady)
+			optMap := efx.SigningOptionMap()
+			optMap["WithGenesis"] = tmengine.WithGenesis(&tmconsensus.ExternalGenesis{
+				ChainID:             "my-chain",
+				InitialHeight:       1,
+				InitialAppState:     new(bytes.Buffer),
+				GenesisValidatorSet: efx.Fx.ValSet(),
+
+				GenesisValidatorAnnotations: annotations,
+			})
+			optMap["WithGenesisAnnotationStore"] = tmengine.WithGenesisAnnotationStore(gaStore)
+			engine = efx.MustNewEngine(optMap.ToSlice()...)
+		}()
+
+		defer func() {
+			cancel()
+			<-eReady
+			engine.Wait()
+		}()
+
+		_ = efx.ConsensusStrategy.ExpectEnterRound(1, 0, nil)
+
+		// The app sees the annotations in the init chain request.
+		icReq := gtest.ReceiveSoon(t, efx.InitChainCh)
+		require.Equal(t, annotations, icReq.Genesis.GenesisValidatorAnnotations)
+
+		// Nothing is saved until the chain is initialized.
+		_, err := gaStore.LoadGenesisValidatorAnnotations(ctx)
+		require.ErrorIs(t, err, tmstore.ErrStoreUninitialized)
+
+		gtest.SendSoon(t, icReq.Resp, tmdriver.InitChainResponse{
+			AppStateHash: []byte("app_state_0"),
+		})
+		_ = gtest.ReceiveSoon(t, eReady)
+
+		got, err := gaStore.LoadGenesisValidatorAnnotations(ctx)
+		require.NoError(t, err)
+		require.Equal(t, annotations, got)
+	})
+
+	t.Run("genesis validator annotations for unknown validators are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		efx := tmenginetest.NewFixture(ctx, t, 2)
+
+		// A key for a validator outside the genesis validator set.
+		otherFx := tmconsensustest.NewStandardFixture(3)
+		otherKey, err := tmconsensus.GenesisValidatorAnnotationKey(efx.Fx.HashScheme, otherFx.Vals()[2].PubKey)
+		require.NoError(t, err)
+
+		annotations := map[string][]byte{
+			otherKey: []byte("moniker=stranger"),
+		}
+		for i, v := range efx.Fx.Vals() {
+			key, err := tmconsensus.GenesisValidatorAnnotationKey(efx.Fx.HashScheme, v.PubKey)
+			require.NoError(t, err)
+			annotations[key] = []byte(fmt.Sprintf("moniker=val%d", i))
+		}
+
+		optMap := efx.SigningOptionMap()
+		optMap["WithGenesis"] = tmengine.WithGenesis(&tmconsensus.ExternalGenesis{
+			ChainID:             "my-chain",
+			InitialHeight:       1,
+			InitialAppState:     new(bytes.Buffer),
+			GenesisValidatorSet: efx.Fx.ValSet(),
+
+			GenesisValidatorAnnotations: annotations,
+		})
+		optMap["WithGenesisAnnotationStore"] = tmengine.WithGenesisAnnotationStore(
+			tmmemstore.NewGenesisAnnotationStore(),
+		)
+
+		e, err := tmengine.New(ctx, gtest.NewLogger(t), optMap.ToSlice()...)
+		require.Nil(t, e)
+		require.ErrorContains(t, err, fmt.Sprintf("%x", otherKey))
+
+		// Only the unknown key is reported.
+		require.Equal(t, 1, strings.Count(err.Error(), "does not belong to a genesis validator"))
+	})
+
 	t.Run("no init chain call when finalization already exists", func(t *testing.T) {
 		t.Parallel()
 
@@ -1515,6 +1689,365 @@ func TestEngine_configuration(t *testing.T) {
 	}
 }
 
+func TestNewFromConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("options set the corresponding config fields", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fx := tmconsensustest.NewStandardFixture(2)
+		wd, _ := gwatchdog.NewNopWatchdog(ctx, gtest.NewLogger(t))
+		defer wd.Wait()
+		defer cancel()
+
+		eg := &tmconsensus.ExternalGenesis{
+			ChainID:             "my-chain",
+			InitialHeight:       1,
+			InitialAppState:     new(bytes.Buffer),
+			GenesisValidatorSet: fx.ValSet(),
+		}
+		signer := tmconsensus.PassthroughSigner{
+			Signer:          fx.PrivVals[0].Signer,
+			SignatureScheme: fx.SignatureScheme,
+		}
+		ts := tmengine.LinearTimeoutStrategy{ProposalBase: time.Second}
+
+		dataAvailable := func(context.Context, string) (bool, error) { return true, nil }
+		blockDataAvailable := func(context.Context, uint64, uint32, string) bool { return true }
+		resourceGuard := func() (bool, string) { return true, "" }
+		annotator := func(uint64, uint32) ([]byte, []byte, error) { return nil, nil, nil }
+		enterRoundObserver := func(tmconsensus.RoundView) {}
+		valSetProvider := func(context.Context) (tmconsensus.ValidatorSet, uint64, error) {
+			return fx.ValSet(), 1, nil
+		}
+		actionObserver := func(tmelink.StateMachineRoundAction) {}
+		prefilter := func(tmconsensus.ProposedHeader) bool { return true }
+
+		// Every exported field is set.
+		cfg := tmengine.EngineConfig{
+			Genesis: eg,
+
+			HashScheme:                        fx.HashScheme,
+			SignatureScheme:                   fx.SignatureScheme,
+			CommonMessageSignatureProofScheme: fx.CommonMessageSignatureProofScheme,
+
+			ConsensusStrategy: tmconsensustest.NewMockConsensusStrategy(),
+			GossipStrategy:    tmgossiptest.NopStrategy{},
+
+			CommittedHeaderStore: tmmemstore.NewCommittedHeaderStore(),
+			FinalizationStore:    tmmemstore.NewFinalizationStore(),
+			MirrorStore:          tmmemstore.NewMirrorStore(),
+			RoundStore:           tmmemstore.NewRoundStore(),
+			StateMachineStore:    tmmemstore.NewStateMachineStore(),
+			ValidatorStore:       fx.NewMemValidatorStore(),
+
+			BlockFinalizationChannel: make(chan tmdriver.FinalizeBlockRequest),
+
+			TimeoutStrategy: ts,
+
+			Watchdog: wd,
+
+			Signer:      signer,
+			ActionStore: fx.NewMemActionStore(),
+
+			InitChainChannel: make(chan tmdriver.InitChainRequest),
+
+			GenesisAnnotationStore: tmmemstore.NewGenesisAnnotationStore(),
+
+			AssertEnv: gasserttest.DefaultEnv(),
+
+			StrategyResponseTimeout: time.Second,
+
+			DataAvailabilityChecker: dataAvailable,
+			DataAvailabilityTimeout: time.Second,
+
+			BlockDataAvailabilityFunc: blockDataAvailable,
+
+			BlockDataArrivalChannel: make(chan tmelink.BlockDataArrival),
+			BlockDataArrivalBuffer:  4,
+
+			MinPeersBeforeVoting: 2,
+			MinPeersTimeout:      30 * time.Second,
+
+			ResourceGuard:             resourceGuard,
+			ResourceGuardPollInterval: time.Second,
+
+			ResourceGuardAlertOutput: make(chan tmelink.ResourceGuardAlert, 1),
+
+			LagStateChannel:              make(chan tmelink.LagState),
+			BeaconOutput:                 make(chan tmelink.Beacon),
+			SafetyViolationOutput:        make(chan tmelink.SafetyViolation),
+			ReplayedHeaderRequestChannel: make(chan tmelink.ReplayedHeaderRequest),
+			ProposalPrepareNotifier:      make(chan tmelink.ProposalPrepare, 1),
+			CommitWaitNotifier:           make(chan tmelink.CommitWaitEntered, 1),
+
+			FetchedHeaderBufferLimit:      8,
+			RequireExpectedProposer:       true,
+			MaxHeadersPerProposerPerRound: 2,
+			MaxFutureRoundForHeader:       3,
+			MaxAnnotationBytes:            1024,
+
+			ValidateHeaderTimestamps:      true,
+			HeaderTimestampMaxFutureDrift: time.Second,
+
+			ValidatorSetTransitionValidator: tmconsensus.PowerChangeLimit{MaxChangePercent: 10},
+			IncomingHeaderPrefilter:         prefilter,
+
+			AllowTrustedHeaderIngestion: true,
+
+			RequireKnownVoteBlockHash: true,
+			MinimizeCommitProof:       true,
+			MinVotePowerToGossip:      5,
+			LateVoteGracePeriod:       time.Second,
+
+			EndCommitWaitOnFullPrecommits:    true,
+			SkipSingleValidatorCommitWait:    true,
+			WatchdogFinalizationFlushTimeout: time.Second,
+			MaxRoundsPerHeight:               10,
+			SelfEquivocationGuard:            true,
+
+			ProposalAnnotator:           annotator,
+			EnterRoundObserver:          enterRoundObserver,
+			InitialValidatorSetProvider: valSetProvider,
+			ActionObserver:              actionObserver,
+
+			WatchdogHeartbeatInterval:        10 * time.Second,
+			WatchdogHeartbeatJitter:          time.Second,
+			WatchdogHeartbeatResponseTimeout: time.Second,
+
+			MetricsChannel:    make(chan tmengine.Metrics),
+			PeerScoringOutput: make(chan tmengine.PeerScoreEvent),
+
+			DivergenceAlertThreshold: 2,
+			DivergenceAlertChannel:   make(chan tmengine.DivergenceAlert),
+		}
+
+		opts := []tmengine.Opt{
+			tmengine.WithGenesis(cfg.Genesis),
+
+			tmengine.WithHashScheme(cfg.HashScheme),
+			tmengine.WithSignatureScheme(cfg.SignatureScheme),
+			tmengine.WithCommonMessageSignatureProofScheme(cfg.CommonMessageSignatureProofScheme),
+
+			tmengine.WithConsensusStrategy(cfg.ConsensusStrategy),
+			tmengine.WithGossipStrategy(cfg.GossipStrategy),
+
+			tmengine.WithCommittedHeaderStore(cfg.CommittedHeaderStore),
+			tmengine.WithFinalizationStore(cfg.FinalizationStore),
+			tmengine.WithMirrorStore(cfg.MirrorStore),
+			tmengine.WithRoundStore(cfg.RoundStore),
+			tmengine.WithStateMachineStore(cfg.StateMachineStore),
+			tmengine.WithValidatorStore(cfg.ValidatorStore),
+
+			tmengine.WithBlockFinalizationChannel(cfg.BlockFinalizationChannel),
+			tmengine.WithTimeoutStrategy(ctx, ts),
+			tmengine.WithWatchdog(wd),
+
+			tmengine.WithSigner(signer),
+			tmengine.WithActionStore(cfg.ActionStore),
+			tmengine.WithInitChainChannel(cfg.InitChainChannel),
+			tmengine.WithGenesisAnnotationStore(cfg.GenesisAnnotationStore),
+			tmengine.WithAssertEnv(cfg.AssertEnv),
+
+			tmengine.WithStrategyResponseTimeout(cfg.StrategyResponseTimeout),
+			tmengine.WithDataAvailabilityChecker(dataAvailable, cfg.DataAvailabilityTimeout),
+			tmengine.WithBlockDataAvailabilityFunc(blockDataAvailable),
+			tmengine.WithBlockDataArrivalChannel(cfg.BlockDataArrivalChannel),
+			tmengine.WithBlockDataArrivalBuffer(cfg.BlockDataArrivalBuffer),
+			tmengine.WithMinPeersBeforeVoting(cfg.MinPeersBeforeVoting, cfg.MinPeersTimeout),
+			tmengine.WithResourceGuard(resourceGuard, cfg.ResourceGuardPollInterval),
+			tmengine.WithResourceGuardAlertOutput(cfg.ResourceGuardAlertOutput),
+
+			tmengine.WithLagStateChannel(cfg.LagStateChannel),
+			tmengine.WithBeaconOutput(cfg.BeaconOutput),
+			tmengine.WithSafetyViolationOutput(cfg.SafetyViolationOutput),
+			tmengine.WithReplayedHeaderRequestChannel(cfg.ReplayedHeaderRequestChannel),
+			tmengine.WithProposalPrepareNotifier(cfg.ProposalPrepareNotifier),
+			tmengine.WithCommitWaitNotifier(cfg.CommitWaitNotifier),
+
+			tmengine.WithFetchedHeaderBufferLimit(cfg.FetchedHeaderBufferLimit),
+			tmengine.WithRequireExpectedProposer(true),
+			tmengine.WithMaxHeadersPerProposerPerRound(cfg.MaxHeadersPerProposerPerRound),
+			tmengine.WithMaxFutureRoundForHeader(cfg.MaxFutureRoundForHeader),
+			tmengine.WithMaxAnnotationBytes(cfg.MaxAnnotationBytes),
+			tmengine.WithHeaderTimestampValidation(cfg.HeaderTimestampMaxFutureDrift),
+			tmengine.WithValidatorSetTransitionValidator(cfg.ValidatorSetTransitionValidator),
+			tmengine.WithIncomingHeaderPrefilter(prefilter),
+			tmengine.WithRequireKnownVoteBlockHash(true),
+			tmengine.WithMinimizeCommitProof(true),
+			tmengine.WithMinVotePowerToGossip(cfg.MinVotePowerToGossip),
+			tmengine.WithLateVoteGracePeriod(cfg.LateVoteGracePeriod),
+			tmengine.WithTrustedHeaderIngestion(true),
+
+			tmengine.WithEndCommitWaitOnFullPrecommits(true),
+			tmengine.WithSkipSingleValidatorCommitWait(true),
+			tmengine.WithWatchdogFinalizationFlushTimeout(cfg.WatchdogFinalizationFlushTimeout),
+			tmengine.WithMaxRoundsPerHeight(cfg.MaxRoundsPerHeight),
+			tmengine.WithSelfEquivocationGuard(true),
+
+			tmengine.WithProposalAnnotator(annotator),
+			tmengine.WithEnterRoundObserver(enterRoundObserver),
+			tmengine.WithInitialValidatorSetProvider(valSetProvider),
+			tmengine.WithActionObserver(actionObserver),
+
+			tmengine.WithWatchdogHeartbeat(
+				cfg.WatchdogHeartbeatInterval, cfg.WatchdogHeartbeatJitter, cfg.WatchdogHeartbeatResponseTimeout,
+			),
+			tmengine.WithMetricsChannel(cfg.MetricsChannel),
+			tmengine.WithPeerScoringOutput(cfg.PeerScoringOutput),
+			tmengine.WithDivergenceAlert(cfg.DivergenceAlertThreshold, cfg.DivergenceAlertChannel),
+		}
+
+		var optCfg tmengine.EngineConfig
+		for _, opt := range opts {
+			require.NoError(t, opt(&optCfg))
+		}
+
+		for _, f := range reflect.VisibleFields(reflect.TypeFor[tmengine.EngineConfig]()) {
+			if !f.IsExported() {
+				continue
+			}
+
+			want := reflect.ValueOf(cfg).FieldByIndex(f.Index)
+			got := reflect.ValueOf(optCfg).FieldByIndex(f.Index)
+
+			if f.Type.Size() > 0 {
+				// The assert env is an empty struct in non-debug builds.
+				require.Falsef(t, want.IsZero(), "field %s not populated in test config", f.Name)
+			}
+
+			// Funcs, including those inside interface values, are never deeply equal,
+			// but their Go-syntax representations include their addresses.
+			require.Equalf(
+				t,
+				fmt.Sprintf("%#v", want.Interface()), fmt.Sprintf("%#v", got.Interface()),
+				"field %s", f.Name,
+			)
+		}
+	})
+
+	t.Run("engine from config behaves like engine from options", func(t *testing.T) {
+		t.Parallel()
+
+		annotator := func(h uint64, r uint32) ([]byte, []byte, error) {
+			return []byte(fmt.Sprintf("proposal_%d_%d", h, r)), []byte(fmt.Sprintf("block_%d_%d", h, r)), nil
+		}
+
+		// proposeFirstHeader starts an engine from newEngine,
+		// initializes the chain, and returns the engine's first proposed header.
+		proposeFirstHeader := func(
+			t *testing.T,
+			newEngine func(efx *tmenginetest.Fixture) (*tmengine.Engine, error),
+		) tmconsensus.ProposedHeader {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			efx := tmenginetest.NewFixture(ctx, t, 2)
+
+			var engine *tmengine.Engine
+			eReady := make(chan struct{})
+			go func() {
+				defer close(eReady)
+				var err error
+				engine, err = newEngine(efx)
+				if err != nil {
+					panic(err)
+				}
+			}()
+
+			defer func() {
+				cancel()
+				<-eReady
+				engine.Wait()
+			}()
+
+			ercCh := efx.ConsensusStrategy.ExpectEnterRound(1, 0, nil)
+
+			icReq := gtest.ReceiveSoon(t, efx.InitChainCh)
+			gtest.SendSoon(t, icReq.Resp, tmdriver.InitChainResponse{
+				AppStateHash: []byte("app_state_0"),
+			})
+			_ = gtest.ReceiveSoon(t, eReady)
+
+			erc := gtest.ReceiveSoon(t, ercCh)
+
+			// Drain the initial voting view before proposing.
+			_ = gtest.ReceiveSoon(t, efx.GossipStrategy.Updates)
+
+			erc.ProposalOut <- tmconsensus.Proposal{DataID: "app_data_1"}
+
+			vrv := gtest.ReceiveSoon(t, efx.GossipStrategy.Updates).Voting
+			require.Len(t, vrv.ProposedHeaders, 1)
+			ph := vrv.ProposedHeaders[0]
+
+			// The state machine must consider its own proposed header
+			// before the engine can be stopped.
+			cReq := gtest.ReceiveSoon(t, efx.ConsensusStrategy.ConsiderProposedBlocksRequests)
+			require.Equal(t, []tmconsensus.ProposedHeader{ph}, cReq.PHs)
+
+			return ph
+		}
+
+		fromOpts := proposeFirstHeader(t, func(efx *tmenginetest.Fixture) (*tmengine.Engine, error) {
+			opts := efx.SigningOptionMap()
+			opts["WithProposalAnnotator"] = tmengine.WithProposalAnnotator(annotator)
+			opts["WithMaxAnnotationBytes"] = tmengine.WithMaxAnnotationBytes(1024)
+			return tmengine.New(efx.WatchdogCtx, efx.Log, opts.ToSlice()...)
+		})
+
+		fromCfg := proposeFirstHeader(t, func(efx *tmenginetest.Fixture) (*tmengine.Engine, error) {
+			cfg := efx.SigningConfig()
+			cfg.ProposalAnnotator = annotator
+			cfg.MaxAnnotationBytes = 1024
+			return tmengine.NewFromConfig(efx.WatchdogCtx, efx.Log, cfg)
+		})
+
+		require.Equal(t, "proposal_1_0", string(fromCfg.Annotations.Driver))
+		require.Equal(t, fromOpts, fromCfg)
+	})
+
+	t.Run("invalid values are reported together", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		efx := tmenginetest.NewFixture(ctx, t, 2)
+
+		cfg := efx.SigningConfig()
+		cfg.StrategyResponseTimeout = -time.Second
+		cfg.LagStateChannel = make(chan tmelink.LagState, 1)
+		cfg.WatchdogHeartbeatInterval = time.Second
+
+		e, err := tmengine.NewFromConfig(ctx, efx.Log, cfg)
+		require.Nil(t, e)
+		require.ErrorContains(t, err, "StrategyResponseTimeout")
+		require.ErrorContains(t, err, "LagStateChannel")
+		require.ErrorContains(t, err, "watchdog heartbeat")
+	})
+
+	t.Run("invalid option arguments are reported by option name", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		efx := tmenginetest.NewFixture(ctx, t, 2)
+
+		optMap := efx.SigningOptionMap()
+		optMap["WithStrategyResponseTimeout"] = tmengine.WithStrategyResponseTimeout(-time.Second)
+		optMap["WithWatchdogHeartbeat"] = tmengine.WithWatchdogHeartbeat(0, 0, 0)
+
+		e, err := tmengine.New(ctx, efx.Log, optMap.ToSlice()...)
+		require.Nil(t, e)
+		require.ErrorContains(t, err, "WithStrategyResponseTimeout: ")
+		require.ErrorContains(t, err, "WithWatchdogHeartbeat: ")
+	})
+}
+
 func TestEngine_mirrorSkipsAhead(t *testing.T) {
 	t.Run("skip to next round due to minority prevote", func(t *testing.T) {
 		t.Parallel()
@@ -1659,3 +2192,458 @@ func TestEngine_metrics(t *testing.T) {
 	require.Equal(t, uint64(1), m.StateMachineHeight)
 	require.Zero(t, m.StateMachineRound)
 }
+
+func TestEngine_divergenceAlert(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	efx := tmenginetest.NewFixture(ctx, t, 4)
+
+	alertCh := make(chan tmengine.DivergenceAlert)
+	var engine *tmengine.Engine
+	eReady := make(chan struct{})
+	go func() {
+		defer close(eReady)
+		opts := efx.BaseOptionMap().ToSlice()
+		opts = append(opts, tmengine.WithDivergenceAlert(2, alertCh))
+		engine = efx.MustNewEngine(opts...)
+	}()
+
+	defer func() {
+		cancel()
+		<-eReady
+		engine.Wait()
+	}()
+
+	// The state machine enters 1/0, but it never advances past height 1,
+	// because nothing in this test responds to the consensus strategy
+	// or to block finalization requests.
+	ercCh := efx.ConsensusStrategy.ExpectEnterRound(1, 0, nil)
+
+	icReq := gtest.ReceiveSoon(t, efx.InitChainCh)
+	gtest.SendSoon(t, icReq.Resp, tmdriver.InitChainResponse{
+		AppStateHash: []byte("app_state_0"),
+	})
+	_ = gtest.ReceiveSoon(t, eReady)
+
+	keyHash, _ := efx.Fx.ValidatorHashes()
+
+	// commitHeight has the mirror see a proposed header and full precommits at height h,
+	// moving the mirror's voting height to h+1.
+	commitHeight := func(h uint64) {
+		ph := efx.Fx.NextProposedHeader([]byte(fmt.Sprintf("app_data_%d", h)), 0)
+		efx.Fx.SignProposal(ctx, &ph, 0)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, engine.HandleProposedHeader(ctx, ph))
+
+		voteMap := map[string][]int{string(ph.Header.Hash): {0, 1, 2, 3}}
+		require.Equal(t, tmconsensus.HandleVoteProofsAccepted, engine.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+			Height: h, Round: 0,
+			PubKeyHash: keyHash,
+			Proofs:     efx.Fx.SparsePrecommitProofMap(ctx, h, 0, voteMap),
+		}))
+
+		efx.Fx.CommitBlock(
+			ph.Header, []byte(fmt.Sprintf("app_state_%d", h)), 0,
+			efx.Fx.PrecommitProofMap(ctx, h, 0, voteMap),
+		)
+	}
+
+	commitHeight(1)
+	_ = gtest.ReceiveSoon(t, ercCh)
+
+	// Mirror at voting height 3 is only a gap of 2, which does not exceed the threshold.
+	commitHeight(2)
+	gtest.NotSendingSoon(t, alertCh)
+
+	// But voting height 4 does.
+	commitHeight(3)
+	alert := gtest.ReceiveSoon(t, alertCh)
+	require.Equal(t, tmengine.DivergenceAlert{
+		MirrorVotingHeight: 4,
+		StateMachineHeight: 1,
+	}, alert)
+}
+
+func TestEngine_initialValidatorSetProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	efx := tmenginetest.NewFixture(ctx, t, 4)
+
+	// The chain was already initialized.
+	require.NoError(t, efx.FinalizationStore.SaveFinalization(
+		ctx, 0, 0, "some_block_hash", efx.Fx.ValSet(), "app_state_height_0",
+	))
+
+	// Build a chain through height 4 in the fixture,
+	// as though a state sync had brought us to that height.
+	ph := efx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+	for h := uint64(1); h <= 4; h++ {
+		precommitProofs := efx.Fx.PrecommitProofMap(ctx, h, 0, map[string][]int{
+			string(ph.Header.Hash): {0, 1, 2, 3},
+		})
+		appStateHash := fmt.Sprintf("app_state_height_%d", h)
+		efx.Fx.CommitBlock(ph.Header, []byte(appStateHash), 0, precommitProofs)
+
+		next := efx.Fx.NextProposedHeader([]byte(fmt.Sprintf("app_data_%d", h+1)), 0)
+		if h == 4 {
+			require.NoError(t, efx.CommittedHeaderStore.SaveCommittedHeader(ctx, tmconsensus.CommittedHeader{
+				Header: ph.Header,
+				Proof:  next.Header.PrevCommitProof,
+			}))
+			require.NoError(t, efx.FinalizationStore.SaveFinalization(
+				ctx, 4, 0, string(ph.Header.Hash), efx.Fx.ValSet(), appStateHash,
+			))
+		}
+		ph = next
+	}
+
+	ercCh := efx.ConsensusStrategy.ExpectEnterRound(5, 0, nil)
+
+	var engine *tmengine.Engine
+	eReady := make(chan struct{})
+	go func() {
+		defer close(eReady)
+		opts := efx.BaseOptionMap().ToSlice()
+		opts = append(opts, tmengine.WithInitialValidatorSetProvider(
+			func(context.Context) (tmconsensus.ValidatorSet, uint64, error) {
+				return efx.Fx.ValSet(), 5, nil
+			},
+		))
+		engine = efx.MustNewEngine(opts...)
+	}()
+
+	defer func() {
+		cancel()
+		<-eReady
+		engine.Wait()
+	}()
+
+	_ = gtest.ReceiveSoon(t, eReady)
+
+	// Both the mirror and the state machine begin at the provided height.
+	erc := gtest.ReceiveSoon(t, ercCh)
+	require.Equal(t, uint64(5), erc.RV.Height)
+	require.Zero(t, erc.RV.Round)
+	require.True(t, efx.Fx.ValSet().Equal(erc.RV.ValidatorSet))
+
+	// The network completes the round.
+	efx.Fx.SignProposal(ctx, &ph, 1)
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, engine.HandleProposedHeader(ctx, ph))
+
+	cReq := gtest.ReceiveSoon(t, efx.ConsensusStrategy.ConsiderProposedBlocksRequests)
+	require.Equal(t, []tmconsensus.ProposedHeader{ph}, cReq.PHs)
+	gtest.SendSoon(t, cReq.ChoiceHash, string(ph.Header.Hash))
+
+	// Depending on how the mirror batches the vote updates,
+	// the state machine may or may not ask the strategy to choose a block or decide a precommit;
+	// vote for the proposed block whenever it does.
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case req := <-efx.ConsensusStrategy.ChooseProposedBlockRequests:
+				req.ChoiceHash <- string(ph.Header.Hash)
+			case req := <-efx.ConsensusStrategy.DecidePrecommitRequests:
+				req.ChoiceHash <- string(ph.Header.Hash)
+			}
+		}
+	}()
+
+	keyHash, _ := efx.Fx.ValidatorHashes()
+	voteMap := map[string][]int{
+		string(ph.Header.Hash): {0, 1, 2, 3},
+	}
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, engine.HandlePrevoteProofs(ctx, tmconsensus.PrevoteSparseProof{
+		Height: 5, Round: 0,
+		PubKeyHash: keyHash,
+		Proofs:     efx.Fx.SparsePrevoteProofMap(ctx, 5, 0, voteMap),
+	}))
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, engine.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height: 5, Round: 0,
+		PubKeyHash: keyHash,
+		Proofs:     efx.Fx.SparsePrecommitProofMap(ctx, 5, 0, voteMap),
+	}))
+
+	// So the state machine finalizes the block at the provided height.
+	finReq := gtest.ReceiveSoon(t, efx.FinalizeBlockRequests)
+	require.Equal(t, ph.Header, finReq.Header)
+	require.Zero(t, finReq.Round)
+
+	gtest.SendSoon(t, finReq.Resp, tmdriver.FinalizeBlockResponse{
+		Height: 5, Round: 0,
+		BlockHash:    ph.Header.Hash,
+		Validators:   efx.Fx.Vals(),
+		AppStateHash: []byte("app_state_height_5"),
+	})
+
+	// And it advances to the next height after commit wait.
+	ercCh = efx.ConsensusStrategy.ExpectEnterRound(6, 0, nil)
+	efx.RoundTimer.RequireActiveCommitWaitTimer(t, 5, 0)
+	require.NoError(t, efx.RoundTimer.ElapseCommitWaitTimer(5, 0))
+
+	erc = gtest.ReceiveSoon(t, ercCh)
+	require.Equal(t, uint64(6), erc.RV.Height)
+}
+
+func TestEngine_peerScoring(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	efx := tmenginetest.NewFixture(ctx, t, 4)
+
+	scoreCh := make(chan tmengine.PeerScoreEvent, 1)
+	var engine *tmengine.Engine
+	eReady := make(chan struct{})
+	go func() {
+		defer close(eReady)
+		om := efx.BaseOptionMap()
+		// Report the fixture's ed25519 signature length,
+		// so that wrongly sized signatures are discarded before verification.
+		om["WithSignatureScheme"] = tmengine.WithSignatureScheme(fixedLenSignatureScheme{
+			SignatureScheme: efx.Fx.SignatureScheme,
+			N:               ed25519.SignatureSize,
+		})
+		opts := om.ToSlice()
+		opts = append(opts, tmengine.WithPeerScoringOutput(scoreCh))
+		engine = efx.MustNewEngine(opts...)
+	}()
+
+	defer func() {
+		cancel()
+		<-eReady
+		engine.Wait()
+	}()
+
+	ercCh := efx.ConsensusStrategy.ExpectEnterRound(1, 0, nil)
+
+	icReq := gtest.ReceiveSoon(t, efx.InitChainCh)
+	gtest.SendSoon(t, icReq.Resp, tmdriver.InitChainResponse{
+		AppStateHash: []byte("app_state_0"),
+	})
+	_ = gtest.ReceiveSoon(t, eReady)
+
+	peerCtx := tmconsensus.WithPeerID(ctx, "peer1")
+
+	ph := efx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+	efx.Fx.SignProposal(ctx, &ph, 0)
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, engine.HandleProposedHeader(peerCtx, ph))
+	_ = gtest.ReceiveSoon(t, ercCh)
+
+	ev := gtest.ReceiveSoon(t, scoreCh)
+	require.Equal(t, tmengine.PeerScoreEvent{PeerID: "peer1", Class: tmengine.PeerScoreValidNew}, ev)
+	require.Positive(t, ev.Class.Delta())
+
+	// The same header again is a duplicate.
+	require.Equal(t, tmconsensus.HandleProposedHeaderAlreadyStored, engine.HandleProposedHeader(peerCtx, ph))
+	ev = gtest.ReceiveSoon(t, scoreCh)
+	require.Equal(t, tmengine.PeerScoreEvent{PeerID: "peer1", Class: tmengine.PeerScoreDuplicate}, ev)
+	require.Zero(t, ev.Class.Delta())
+
+	// A prevote proof with a corrupted signature is penalized.
+	keyHash, _ := efx.Fx.ValidatorHashes()
+	proofs := efx.Fx.SparsePrevoteProofMap(ctx, 1, 0, map[string][]int{
+		string(ph.Header.Hash): {1},
+	})
+	proofs[string(ph.Header.Hash)][0].Sig[0]++
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, engine.HandlePrevoteProofs(
+		tmconsensus.WithPeerID(ctx, "peer2"),
+		tmconsensus.PrevoteSparseProof{
+			Height: 1, Round: 0,
+			PubKeyHash: keyHash,
+			Proofs:     proofs,
+		},
+	))
+	ev = gtest.ReceiveSoon(t, scoreCh)
+	require.Equal(t, tmengine.PeerScoreEvent{PeerID: "peer2", Class: tmengine.PeerScoreInvalidSignature}, ev)
+	require.Negative(t, ev.Class.Delta())
+
+	// A truncated signature is discarded before verification,
+	// which is malformed rather than a duplicate.
+	proofs = efx.Fx.SparsePrevoteProofMap(ctx, 1, 0, map[string][]int{
+		string(ph.Header.Hash): {2},
+	})
+	proofs[string(ph.Header.Hash)][0].Sig = proofs[string(ph.Header.Hash)][0].Sig[:10]
+	require.Equal(t, tmconsensus.HandleVoteProofsNoNewSignatures, engine.HandlePrevoteProofs(
+		tmconsensus.WithPeerID(ctx, "peer3"),
+		tmconsensus.PrevoteSparseProof{
+			Height: 1, Round: 0,
+			PubKeyHash: keyHash,
+			Proofs:     proofs,
+		},
+	))
+	ev = gtest.ReceiveSoon(t, scoreCh)
+	require.Equal(t, tmengine.PeerScoreEvent{PeerID: "peer3", Class: tmengine.PeerScoreMalformed}, ev)
+	require.Negative(t, ev.Class.Delta())
+
+	// A header signed by an unknown validator reflects on the proposer,
+	// not on the peer relaying it, so the peer is not scored.
+	otherFx := tmconsensustest.NewStandardFixture(5)
+	unknownPH := efx.Fx.NextProposedHeader([]byte("app_data_1_unknown"), 0)
+	otherFx.SignProposal(ctx, &unknownPH, 4)
+	require.Equal(t, tmconsensus.HandleProposedHeaderSignerUnrecognized, engine.HandleProposedHeader(
+		tmconsensus.WithPeerID(ctx, "peer4"), unknownPH,
+	))
+	gtest.NotSendingSoon(t, scoreCh)
+
+	// Without a peer ID, there is nobody to score.
+	require.Equal(t, tmconsensus.HandleProposedHeaderAlreadyStored, engine.HandleProposedHeader(ctx, ph))
+	gtest.NotSendingSoon(t, scoreCh)
+}
+
+func TestEngine_genesisAccessors(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	efx := tmenginetest.NewFixture(ctx, t, 4)
+
+	var engine *tmengine.Engine
+	eReady := make(chan struct{})
+	go func() {
+		defer close(eReady)
+		om := efx.BaseOptionMap()
+		om["WithGenesis"] = tmengine.WithGenesis(&tmconsensus.ExternalGenesis{
+			ChainID:             "accessor-chain",
+			InitialHeight:       5,
+			InitialAppState:     new(bytes.Buffer),
+			GenesisValidatorSet: efx.Fx.ValSet(),
+		})
+		engine = efx.MustNewEngine(om.ToSlice()...)
+	}()
+
+	defer func() {
+		cancel()
+		<-eReady
+		engine.Wait()
+	}()
+
+	_ = efx.ConsensusStrategy.ExpectEnterRound(5, 0, nil)
+
+	icReq := gtest.ReceiveSoon(t, efx.InitChainCh)
+	gtest.SendSoon(t, icReq.Resp, tmdriver.InitChainResponse{
+		AppStateHash: []byte("whatever"),
+	})
+
+	_ = gtest.ReceiveSoon(t, eReady)
+
+	require.Equal(t, "accessor-chain", engine.ChainID())
+	require.Equal(t, uint64(5), engine.InitialHeight())
+}
+
+func TestEngine_SchemeFingerprint(t *testing.T) {
+	t.Parallel()
+
+	// fingerprint starts an engine with the base options,
+	// optionally overridden by overrideFn,
+	// and returns the engine's scheme fingerprint.
+	fingerprint := func(t *testing.T, overrideFn func(*tmenginetest.Fixture, tmenginetest.OptionMap)) string {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		efx := tmenginetest.NewFixture(ctx, t, 4)
+
+		var engine *tmengine.Engine
+		eReady := make(chan struct{})
+		go func() {
+			defer close(eReady)
+			om := efx.BaseOptionMap()
+			if overrideFn != nil {
+				overrideFn(efx, om)
+			}
+			engine = efx.MustNewEngine(om.ToSlice()...)
+		}()
+
+		defer func() {
+			cancel()
+			<-eReady
+			engine.Wait()
+		}()
+
+		_ = efx.ConsensusStrategy.ExpectEnterRound(1, 0, nil)
+
+		icReq := gtest.ReceiveSoon(t, efx.InitChainCh)
+		gtest.SendSoon(t, icReq.Resp, tmdriver.InitChainResponse{
+			AppStateHash: []byte("whatever"),
+		})
+
+		_ = gtest.ReceiveSoon(t, eReady)
+
+		return engine.SchemeFingerprint()
+	}
+
+	base := fingerprint(t, nil)
+	require.NotEmpty(t, base)
+
+	// Separate fixtures have separate scheme instances,
+	// but the schemes are identical.
+	require.Equal(t, base, fingerprint(t, nil))
+
+	// A different signature scheme produces a different fingerprint.
+	differentSig := fingerprint(t, func(efx *tmenginetest.Fixture, om tmenginetest.OptionMap) {
+		om["WithSignatureScheme"] = tmengine.WithSignatureScheme(prefixedSignatureScheme{
+			SignatureScheme: efx.Fx.SignatureScheme,
+		})
+	})
+	require.NotEqual(t, base, differentSig)
+
+	// A different proof scheme ID produces a different fingerprint.
+	differentProofID := fingerprint(t, func(efx *tmenginetest.Fixture, om tmenginetest.OptionMap) {
+		om["WithCommonMessageSignatureProofScheme"] = tmengine.WithCommonMessageSignatureProofScheme(
+			renumberedProofScheme{
+				CommonMessageSignatureProofScheme: efx.Fx.CommonMessageSignatureProofScheme,
+			},
+		)
+	})
+	require.NotEqual(t, base, differentProofID)
+}
+
+// renumberedProofScheme wraps a CommonMessageSignatureProofScheme
+// and reports a different scheme ID,
+// in order to test scheme fingerprints.
+type renumberedProofScheme struct {
+	gcrypto.CommonMessageSignatureProofScheme
+}
+
+func (s renumberedProofScheme) SchemeID() byte {
+	return s.CommonMessageSignatureProofScheme.SchemeID() + 1
+}
+
+// prefixedSignatureScheme wraps a SignatureScheme
+// and produces different prevote signing content,
+// in order to test scheme fingerprints.
+type prefixedSignatureScheme struct {
+	tmconsensus.SignatureScheme
+}
+
+func (s prefixedSignatureScheme) WritePrevoteSigningContent(w io.Writer, vt tmconsensus.VoteTarget) (int, error) {
+	n, err := w.Write([]byte("prefix:"))
+	if err != nil {
+		return n, err
+	}
+
+	m, err := s.SignatureScheme.WritePrevoteSigningContent(w, vt)
+	return n + m, err
+}
+
+// fixedLenSignatureScheme wraps a SignatureScheme
+// and reports a fixed signature length.
+type fixedLenSignatureScheme struct {
+	tmconsensus.SignatureScheme
+
+	N int
+}
+
+func (s fixedLenSignatureScheme) SignatureLen() (int, bool) {
+	return s.N, true
+}
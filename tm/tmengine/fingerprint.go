@@ -0,0 +1,81 @@
+package tmengine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+)
+
+// SchemeFingerprint returns a stable, hex-encoded hash
+// identifying the engine's configured hash scheme, signature scheme,
+// and common message signature proof scheme.
+//
+// Nodes with mismatched schemes fail to reach consensus,
+// usually without any clear error.
+// Operators can compare fingerprints across nodes out-of-band
+// to detect that kind of misconfiguration.
+//
+// The fingerprint covers the concrete type of each scheme,
+// and, for the hash and signature schemes,
+// the output of each scheme on a fixed set of inputs.
+// The proof scheme is only identified by its type and its scheme ID,
+// as constructing a proof requires real public keys.
+// Therefore, identical fingerprints are a strong indication,
+// but not a guarantee, that the schemes are compatible.
+func (e *Engine) SchemeFingerprint() string {
+	h := sha256.New()
+
+	writeFingerprintField(h, []byte("gordian-scheme-fingerprint-v1"))
+
+	writeFingerprintField(h, fmt.Appendf(nil, "%T", e.hashScheme))
+	writeFingerprintField(h, fmt.Appendf(nil, "%T", e.sigScheme))
+	writeFingerprintField(h, fmt.Appendf(nil, "%T", e.cmspScheme))
+	writeFingerprintField(h, []byte{e.cmspScheme.SchemeID()})
+
+	// Hash scheme probe.
+	// Only vote powers are probed, as hashing public keys or headers
+	// requires inputs that schemes may not accept in synthetic form.
+	// Errors are included in the fingerprint rather than returned,
+	// as a scheme consistently rejecting an input is still part of its identity.
+	b, err := e.hashScheme.VotePowers([]uint64{1, 2, 3})
+	writeFingerprintResult(h, b, err)
+
+	// Signature scheme probes.
+	vt := tmconsensus.VoteTarget{
+		Height:    1,
+		Round:     2,
+		BlockHash: "fingerprint",
+	}
+	var buf bytes.Buffer
+	_, err = e.sigScheme.WritePrevoteSigningContent(&buf, vt)
+	writeFingerprintResult(h, buf.Bytes(), err)
+
+	buf.Reset()
+	_, err = e.sigScheme.WritePrecommitSigningContent(&buf, vt)
+	writeFingerprintResult(h, buf.Bytes(), err)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeFingerprintResult writes the outcome of a scheme probe to w.
+func writeFingerprintResult(w io.Writer, b []byte, err error) {
+	if err != nil {
+		_, _ = w.Write([]byte{0})
+		writeFingerprintField(w, []byte(err.Error()))
+		return
+	}
+
+	_, _ = w.Write([]byte{1})
+	writeFingerprintField(w, b)
+}
+
+// writeFingerprintField writes b to w, prefixed with its length,
+// so that adjacent fields cannot be confused with one another.
+func writeFingerprintField(w io.Writer, b []byte) {
+	_, _ = fmt.Fprintf(w, "%d:", len(b))
+	_, _ = w.Write(b)
+}
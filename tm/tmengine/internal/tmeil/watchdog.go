@@ -0,0 +1,47 @@
+package tmeil
+
+import (
+	"time"
+
+	"github.com/gordian-engine/gordian/gwatchdog"
+)
+
+// WatchdogHeartbeat configures how often the watchdog checks
+// each engine subsystem's main loop, and how long the subsystem has to respond.
+//
+// The zero value uses the default settings,
+// and any individual zero field falls back to its default.
+type WatchdogHeartbeat struct {
+	Interval, Jitter, ResponseTimeout time.Duration
+}
+
+// Default watchdog heartbeat settings,
+// used when the corresponding [WatchdogHeartbeat] field is zero.
+const (
+	DefaultWatchdogInterval        = 10 * time.Second
+	DefaultWatchdogJitter          = time.Second
+	DefaultWatchdogResponseTimeout = time.Second
+)
+
+// MonitorConfig returns the [gwatchdog.MonitorConfig] for the subsystem with the given name,
+// applying defaults to any unset fields in h.
+func (h WatchdogHeartbeat) MonitorConfig(name string) gwatchdog.MonitorConfig {
+	cfg := gwatchdog.MonitorConfig{
+		Name:            name,
+		Interval:        h.Interval,
+		Jitter:          h.Jitter,
+		ResponseTimeout: h.ResponseTimeout,
+	}
+
+	if cfg.Interval == 0 {
+		cfg.Interval = DefaultWatchdogInterval
+	}
+	if cfg.Jitter == 0 {
+		cfg.Jitter = min(DefaultWatchdogJitter, cfg.Interval)
+	}
+	if cfg.ResponseTimeout == 0 {
+		cfg.ResponseTimeout = DefaultWatchdogResponseTimeout
+	}
+
+	return cfg
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync/atomic"
 )
 
 // Metrics is the set of metrics for an engine.
@@ -17,6 +18,18 @@ type Metrics struct {
 
 	StateMachineHeight uint64
 	StateMachineRound  uint32
+
+	// The cumulative number of consensus strategy calls
+	// that did not respond within the configured strategy response timeout.
+	StateMachineStrategyTimeouts uint64
+
+	// The cumulative number of proposed headers that the state machine
+	// withheld from the consensus strategy, by reason.
+	StateMachineFilteredProposedHeaders FilteredProposedHeaderCounts
+
+	// The cumulative number of block data arrivals that the state machine discarded
+	// because its block data arrival buffer was full.
+	StateMachineDroppedBlockDataArrivals uint64
 }
 
 func (m Metrics) LogValue() slog.Value {
@@ -26,9 +39,68 @@ func (m Metrics) LogValue() slog.Value {
 		slog.String("mirror_voting_hr", fmt.Sprintf("%d/%d", m.MirrorVotingHeight, m.MirrorVotingRound)),
 
 		slog.String("state_machine_hr", fmt.Sprintf("%d/%d", m.StateMachineHeight, m.StateMachineRound)),
+
+		slog.Uint64("state_machine_strategy_timeouts", m.StateMachineStrategyTimeouts),
+
+		slog.Any("state_machine_filtered_proposed_headers", m.StateMachineFilteredProposedHeaders),
+
+		slog.Uint64("state_machine_dropped_block_data_arrivals", m.StateMachineDroppedBlockDataArrivals),
+	)
+}
+
+// ProposedHeaderFilterReason indicates why the state machine
+// withheld a proposed header from the consensus strategy.
+type ProposedHeaderFilterReason uint8
+
+const (
+	// The header's PrevAppStateHash did not match the previous finalization.
+	FilterPrevAppStateHashMismatch ProposedHeaderFilterReason = iota
+
+	// The header's ValidatorSet did not match the current validator set.
+	FilterValidatorSetMismatch
+
+	// The header's NextValidatorSet did not match the previous finalization's next validator set.
+	FilterNextValidatorSetMismatch
+
+	nFilterReasons
+)
+
+// FilteredProposedHeaderCounts holds cumulative counts of filtered proposed headers,
+// with one field per [ProposedHeaderFilterReason].
+// This type is declared here, but aliased in [tmengine].
+type FilteredProposedHeaderCounts struct {
+	PrevAppStateHashMismatch uint64
+	ValidatorSetMismatch     uint64
+	NextValidatorSetMismatch uint64
+}
+
+func (c FilteredProposedHeaderCounts) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Uint64("prev_app_state_hash_mismatch", c.PrevAppStateHashMismatch),
+		slog.Uint64("validator_set_mismatch", c.ValidatorSetMismatch),
+		slog.Uint64("next_validator_set_mismatch", c.NextValidatorSetMismatch),
 	)
 }
 
+// DivergenceAlert is emitted when the mirror's voting height
+// is further ahead of the state machine's height than a configured threshold.
+// This type is declared here, but aliased in [tmengine].
+type DivergenceAlert struct {
+	MirrorVotingHeight uint64
+	StateMachineHeight uint64
+}
+
+// DivergenceAlertConfig configures a [Collector] to emit [DivergenceAlert] values.
+type DivergenceAlertConfig struct {
+	// The alert fires when the mirror's voting height
+	// exceeds the state machine's height by more than Threshold.
+	Threshold uint64
+
+	// Channel to send alerts on.
+	// If nil, no alerts are emitted.
+	Out chan<- DivergenceAlert
+}
+
 type MirrorMetrics struct {
 	// Voting.
 	VH uint64
@@ -48,18 +120,40 @@ type Collector struct {
 	mCh chan MirrorMetrics
 	sCh chan StateMachineMetrics
 
+	// Counters are accumulated atomically,
+	// and the wake channel signals the background goroutine to publish them.
+	strategyTimeouts atomic.Uint64
+	filteredPHs      [nFilterReasons]atomic.Uint64
+	droppedArrivals  atomic.Uint64
+	counterWake      chan struct{}
+
 	outCh chan<- Metrics
 
+	dac DivergenceAlertConfig
+
 	done chan struct{}
 }
 
 func NewCollector(ctx context.Context, bufSize int, outCh chan<- Metrics) *Collector {
+	return NewCollectorWithDivergenceAlert(ctx, bufSize, outCh, DivergenceAlertConfig{})
+}
+
+// NewCollectorWithDivergenceAlert returns a Collector like [NewCollector],
+// that additionally emits alerts according to dac.
+// The outCh argument may be nil if only alerts are desired.
+func NewCollectorWithDivergenceAlert(
+	ctx context.Context, bufSize int, outCh chan<- Metrics, dac DivergenceAlertConfig,
+) *Collector {
 	c := &Collector{
 		mCh: make(chan MirrorMetrics, bufSize),
 		sCh: make(chan StateMachineMetrics, bufSize),
 
+		counterWake: make(chan struct{}, 1),
+
 		outCh: outCh,
 
+		dac: dac,
+
 		done: make(chan struct{}),
 	}
 	go c.background(ctx)
@@ -80,6 +174,47 @@ func (c *Collector) UpdateStateMachine(m StateMachineMetrics) {
 	}
 }
 
+// IncrementStrategyTimeouts records that a consensus strategy call
+// did not respond within the configured timeout.
+func (c *Collector) IncrementStrategyTimeouts() {
+	c.strategyTimeouts.Add(1)
+
+	// The wake channel is 1-buffered,
+	// so a dropped send means a wake is already pending.
+	select {
+	case c.counterWake <- struct{}{}:
+	default:
+	}
+}
+
+// IncrementFilteredProposedHeaders records that the state machine
+// withheld a proposed header from the consensus strategy for the given reason.
+func (c *Collector) IncrementFilteredProposedHeaders(reason ProposedHeaderFilterReason) {
+	if reason >= nFilterReasons {
+		panic(fmt.Errorf("BUG: invalid proposed header filter reason %d", reason))
+	}
+
+	c.filteredPHs[reason].Add(1)
+
+	// Same wake handling as IncrementStrategyTimeouts.
+	select {
+	case c.counterWake <- struct{}{}:
+	default:
+	}
+}
+
+// IncrementDroppedBlockDataArrivals records that the state machine
+// discarded a block data arrival because its arrival buffer was full.
+func (c *Collector) IncrementDroppedBlockDataArrivals() {
+	c.droppedArrivals.Add(1)
+
+	// Same wake handling as IncrementStrategyTimeouts.
+	select {
+	case c.counterWake <- struct{}{}:
+	default:
+	}
+}
+
 func (c *Collector) Wait() {
 	<-c.done
 }
@@ -90,6 +225,12 @@ func (c *Collector) background(ctx context.Context) {
 	var cur Metrics
 
 	var gotM, gotS, outdated bool
+
+	// An alert is only sent once per divergence;
+	// the heights must come back within the threshold before another alert is sent.
+	var diverged, alertPending bool
+	var alert DivergenceAlert
+
 	for {
 		// Don't attempt to send the output until
 		// we've written both mirror and state machine metrics.
@@ -98,6 +239,34 @@ func (c *Collector) background(ctx context.Context) {
 			outCh = c.outCh
 		}
 
+		if gotM && gotS && c.dac.Out != nil {
+			var gap uint64
+			if cur.MirrorVotingHeight > cur.StateMachineHeight {
+				gap = cur.MirrorVotingHeight - cur.StateMachineHeight
+			}
+
+			if gap > c.dac.Threshold {
+				if !diverged || alertPending {
+					// Keep a pending alert up to date with the latest heights.
+					alert = DivergenceAlert{
+						MirrorVotingHeight: cur.MirrorVotingHeight,
+						StateMachineHeight: cur.StateMachineHeight,
+					}
+					alertPending = true
+				}
+				diverged = true
+			} else {
+				// Drop any unsent alert, as it no longer applies.
+				diverged = false
+				alertPending = false
+			}
+		}
+
+		var alertCh chan<- DivergenceAlert
+		if alertPending {
+			alertCh = c.dac.Out
+		}
+
 		select {
 		case <-ctx.Done():
 			return
@@ -118,9 +287,22 @@ func (c *Collector) background(ctx context.Context) {
 			gotS = true
 			outdated = true
 
+		case <-c.counterWake:
+			cur.StateMachineStrategyTimeouts = c.strategyTimeouts.Load()
+			cur.StateMachineFilteredProposedHeaders = FilteredProposedHeaderCounts{
+				PrevAppStateHashMismatch: c.filteredPHs[FilterPrevAppStateHashMismatch].Load(),
+				ValidatorSetMismatch:     c.filteredPHs[FilterValidatorSetMismatch].Load(),
+				NextValidatorSetMismatch: c.filteredPHs[FilterNextValidatorSetMismatch].Load(),
+			}
+			cur.StateMachineDroppedBlockDataArrivals = c.droppedArrivals.Load()
+			outdated = true
+
 		case outCh <- cur:
 			// Okay.
 			outdated = false
+
+		case alertCh <- alert:
+			alertPending = false
 		}
 	}
 }
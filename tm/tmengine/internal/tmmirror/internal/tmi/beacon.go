@@ -0,0 +1,60 @@
+package tmi
+
+import (
+	"github.com/gordian-engine/gordian/tm/tmengine/tmelink"
+)
+
+// beaconManager holds the beacons that have not yet been sent to the driver.
+//
+// Unlike the lag manager, which only needs the latest value,
+// every beacon must be delivered in order,
+// so pending beacons are queued until the driver reads them.
+type beaconManager struct {
+	outCh chan<- tmelink.Beacon
+
+	pending []tmelink.Beacon
+}
+
+func newBeaconManager(out chan<- tmelink.Beacon) beaconManager {
+	return beaconManager{outCh: out}
+}
+
+// Add queues b to be sent to the output channel.
+// If no output channel was configured, Add is a no-op.
+func (m *beaconManager) Add(b tmelink.Beacon) {
+	if m.outCh == nil {
+		return
+	}
+
+	m.pending = append(m.pending, b)
+}
+
+// Output returns a BeaconOutput,
+// containing a destination channel and the oldest pending Beacon to send.
+//
+// If there are no pending beacons,
+// the output channel is nil, so the send will block forever.
+func (m *beaconManager) Output() BeaconOutput {
+	if m.outCh == nil || len(m.pending) == 0 {
+		return BeaconOutput{}
+	}
+
+	return BeaconOutput{
+		m:   m,
+		Ch:  m.outCh,
+		Val: m.pending[0],
+	}
+}
+
+// MarkSent must be called after a successful send of o.Val to o.Ch.
+func (o BeaconOutput) MarkSent() {
+	o.m.pending[0] = tmelink.Beacon{}
+	o.m.pending = o.m.pending[1:]
+}
+
+// BeaconOutput is the value returned by [*beaconManager.Output].
+type BeaconOutput struct {
+	m   *beaconManager
+	Ch  chan<- tmelink.Beacon
+	Val tmelink.Beacon
+}
@@ -0,0 +1,81 @@
+package tmi
+
+import (
+	"fmt"
+
+	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+)
+
+// minimizedCommitProof returns a copy of cp containing only the precommits for blockHash,
+// reduced to the shortest prefix of full's sparse signatures
+// whose signers hold a majority of the voting power in vals.
+//
+// Sparse signatures are ordered by key ID,
+// so the result is deterministic and favors the lowest-index signers.
+// Depending on the signature proof scheme, a single sparse signature
+// may represent several signers; such signatures are kept or dropped as a unit.
+func minimizedCommitProof(
+	cp tmconsensus.CommitProof,
+	full gcrypto.CommonMessageSignatureProof,
+	blockHash string,
+	vals []tmconsensus.Validator,
+) (tmconsensus.CommitProof, error) {
+	if full == nil {
+		return tmconsensus.CommitProof{}, fmt.Errorf(
+			"no precommit proof for committed block %x", blockHash,
+		)
+	}
+
+	var totalPower uint64
+	for _, v := range vals {
+		totalPower += v.Power
+	}
+	majority := tmconsensus.ByzantineMajority(totalPower)
+
+	sparse := full.AsSparse()
+
+	// Merge each sparse signature into an empty proof,
+	// to learn which signers it represents.
+	p := full.Derive()
+	var bs bitset.BitSet
+	n := len(sparse.Signatures)
+	for i := range sparse.Signatures {
+		res := p.MergeSparse(gcrypto.SparseSignatureProof{
+			PubKeyHash: sparse.PubKeyHash,
+			Signatures: sparse.Signatures[i : i+1],
+		})
+		if !res.AllValidSignatures {
+			return tmconsensus.CommitProof{}, fmt.Errorf(
+				"invalid precommit signature for committed block %x", blockHash,
+			)
+		}
+
+		p.SignatureBitSet(&bs)
+		var power uint64
+		for j, ok := bs.NextSet(0); ok; j, ok = bs.NextSet(j + 1) {
+			power += vals[j].Power
+		}
+		if power >= majority {
+			n = i + 1
+			break
+		}
+	}
+
+	sigs := make([]gcrypto.SparseSignature, n)
+	for i, s := range sparse.Signatures[:n] {
+		sigs[i] = gcrypto.SparseSignature{
+			KeyID: append([]byte(nil), s.KeyID...),
+			Sig:   append([]byte(nil), s.Sig...),
+		}
+	}
+
+	return tmconsensus.CommitProof{
+		Round:      cp.Round,
+		PubKeyHash: cp.PubKeyHash,
+		Proofs: map[string][]gcrypto.SparseSignature{
+			blockHash: sigs,
+		},
+	}, nil
+}
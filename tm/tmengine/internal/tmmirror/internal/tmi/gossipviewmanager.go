@@ -1,11 +1,18 @@
 package tmi
 
 import (
+	"log/slog"
+
+	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/internal/glog"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
 	"github.com/gordian-engine/gordian/tm/tmengine/tmelink"
 )
 
 type gossipViewManager struct {
+	log *slog.Logger
+
 	out chan<- tmelink.NetworkViewUpdate
 
 	// When the kernel transitions from one voting round to another,
@@ -14,10 +21,16 @@ type gossipViewManager struct {
 	NilVotedRound *tmconsensus.VersionedRoundView
 
 	Committing, Voting, NextRound OutgoingView
+
+	// If positive, votes from validators with less power than this
+	// are removed from the views sent to the gossip strategy.
+	minVotePower uint64
 }
 
-func newGossipViewManager(out chan<- tmelink.NetworkViewUpdate) gossipViewManager {
-	return gossipViewManager{out: out}
+func newGossipViewManager(
+	log *slog.Logger, out chan<- tmelink.NetworkViewUpdate, minVotePower uint64,
+) gossipViewManager {
+	return gossipViewManager{log: log, out: out, minVotePower: minVotePower}
 }
 
 func (m *gossipViewManager) Output() gossipStrategyOutput {
@@ -35,21 +48,21 @@ func (m *gossipViewManager) Output() gossipStrategyOutput {
 	if !m.Committing.HasBeenSent() {
 		o.Ch = m.out
 
-		val := m.Committing.VRV.Clone()
+		val := m.outgoingClone(&m.Committing.VRV)
 		o.Val.Committing = &val
 	}
 
 	if !m.Voting.HasBeenSent() {
 		o.Ch = m.out
 
-		val := m.Voting.VRV.Clone()
+		val := m.outgoingClone(&m.Voting.VRV)
 		o.Val.Voting = &val
 	}
 
 	if !m.NextRound.HasBeenSent() {
 		o.Ch = m.out
 
-		val := m.NextRound.VRV.Clone()
+		val := m.outgoingClone(&m.NextRound.VRV)
 		o.Val.NextRound = &val
 	}
 
@@ -59,7 +72,12 @@ func (m *gossipViewManager) Output() gossipStrategyOutput {
 	if m.NilVotedRound != nil {
 		o.Ch = m.out
 
-		o.Val.NilVotedRound = m.NilVotedRound
+		if m.minVotePower > 0 {
+			val := m.outgoingClone(m.NilVotedRound)
+			o.Val.NilVotedRound = &val
+		} else {
+			o.Val.NilVotedRound = m.NilVotedRound
+		}
 	}
 
 	return o
@@ -90,3 +108,105 @@ func (o gossipStrategyOutput) MarkSent() {
 	// Always clear the NilVotedRound; no version tracking involved there.
 	o.m.NilVotedRound = nil
 }
+
+// outgoingClone returns a clone of vrv to send to the gossip strategy.
+//
+// If m has a minimum vote power, signatures that only represent
+// validators below that power are removed from the clone's prevote and precommit proofs.
+// Aggregated signatures that include at least one validator at or above the minimum
+// cannot be split, so they are retained.
+// The clone's vote summary is not modified,
+// as it reflects the mirror's own accounting rather than what is gossiped.
+func (m *gossipViewManager) outgoingClone(vrv *tmconsensus.VersionedRoundView) tmconsensus.VersionedRoundView {
+	c := vrv.Clone()
+	if m.minVotePower == 0 {
+		return c
+	}
+
+	var low bitset.BitSet
+	for i, v := range vrv.ValidatorSet.Validators {
+		if v.Power < m.minVotePower {
+			low.Set(uint(i))
+		}
+	}
+	if low.None() {
+		return c
+	}
+
+	m.filterOutgoingProofs(c.PrevoteProofs, &low)
+	m.filterOutgoingProofs(c.PrecommitProofs, &low)
+	return c
+}
+
+// filterOutgoingProofs replaces each proof in proofs
+// that includes a signature from a validator in low
+// with its filtered equivalent,
+// deleting the entry if no signatures remain.
+// If a proof cannot be filtered, it is left unfiltered.
+func (m *gossipViewManager) filterOutgoingProofs(proofs map[string]gcrypto.CommonMessageSignatureProof, low *bitset.BitSet) {
+	var bs bitset.BitSet
+	for hash, proof := range proofs {
+		proof.SignatureBitSet(&bs)
+		if !bs.Intersection(low).Any() {
+			// No low-power signers, so the existing clone is fine.
+			continue
+		}
+
+		filtered, ok := filteredProof(proof, low)
+		if !ok {
+			// The proof in the view was already validated,
+			// so this should never happen;
+			// but gossiping too many signatures is better than dropping votes.
+			m.log.Warn(
+				"Failed to filter low power votes from outgoing proof; sending unfiltered proof",
+				"block_hash", glog.Hex(hash),
+			)
+			continue
+		}
+
+		if filtered == nil {
+			delete(proofs, hash)
+		} else {
+			proofs[hash] = filtered
+		}
+	}
+}
+
+// filteredProof returns a proof containing only the signatures in proof
+// that represent at least one validator whose bit is not set in low,
+// or nil if there are no such signatures.
+// The ok result is false if any signature failed to merge,
+// in which case the returned proof must not be used.
+//
+// The sparse signatures in proof are opaque,
+// so each one is merged into an otherwise empty proof
+// to determine which validators it represents.
+func filteredProof(
+	proof gcrypto.CommonMessageSignatureProof, low *bitset.BitSet,
+) (out gcrypto.CommonMessageSignatureProof, ok bool) {
+	sparse := proof.AsSparse()
+
+	var bs bitset.BitSet
+	for _, sig := range sparse.Signatures {
+		single := proof.Derive()
+		res := single.MergeSparse(gcrypto.SparseSignatureProof{
+			PubKeyHash: sparse.PubKeyHash,
+			Signatures: []gcrypto.SparseSignature{sig},
+		})
+		if !res.AllValidSignatures {
+			return nil, false
+		}
+		single.SignatureBitSet(&bs)
+		if bs.Difference(low).None() {
+			continue
+		}
+
+		if out == nil {
+			out = single
+		} else if res := out.Merge(single); !res.AllValidSignatures {
+			return nil, false
+		}
+	}
+
+	return out, true
+}
@@ -9,6 +9,7 @@ import (
 	"maps"
 	"runtime/trace"
 	"slices"
+	"sync/atomic"
 	"time"
 
 	"github.com/bits-and-blooms/bitset"
@@ -42,9 +43,41 @@ type Kernel struct {
 	initialHeight uint64
 	initialValSet tmconsensus.ValidatorSet
 
+	// The chain's genesis height, which may be below initialHeight
+	// if the mirror was started from a state sync.
+	// Only proposed headers at the genesis height skip prev-commit verification.
+	genesisHeight uint64
+
+	// When initialHeight is above genesisHeight,
+	// the committed header at initialHeight-1,
+	// used to verify the previous commit of proposed headers at initialHeight.
+	initialPrevHeader tmconsensus.CommittedHeader
+
 	phf tmelink.ProposedHeaderFetcher
 	mc  *tmemetrics.Collector
 
+	// Maximum number of outstanding proposed header fetches.
+	// Zero means unlimited.
+	fetchedPHLimit int
+
+	// Whether proposed headers must come from [tmconsensus.ExpectedProposer].
+	requireExpectedProposer bool
+
+	// Maximum number of proposed headers accepted from a single proposer in one round.
+	// Zero means unlimited.
+	maxPHsPerProposer int
+
+	// How many rounds beyond the voting round to accept proposed headers for.
+	// Values below 1 behave as 1, as the kernel always has a view for the next round.
+	maxFutureRound uint32
+
+	// Whether to trim commit proofs to a majority subset upon commit.
+	minimizeCommitProof bool
+
+	// How long to keep an abandoned voting round's view for late votes.
+	// Zero disables keeping the view.
+	lateVoteGracePeriod time.Duration
+
 	replayedHeadersIn <-chan tmelink.ReplayedHeaderRequest
 	gossipOutCh       chan<- tmelink.NetworkViewUpdate
 
@@ -54,10 +87,20 @@ type Kernel struct {
 	viewLookupRequests <-chan ViewLookupRequest
 	phCheckRequests    <-chan PHCheckRequest
 
+	// If non-nil, the kernel stores a fresh copy of its views here
+	// whenever they change.
+	publishedSnapshot *atomic.Pointer[Snapshot]
+
 	addPHRequests        <-chan tmconsensus.ProposedHeader
 	addPrevoteRequests   <-chan AddPrevoteRequest
 	addPrecommitRequests <-chan AddPrecommitRequest
 
+	// Nil unless trusted header ingestion is enabled.
+	trustedHeadersRequests <-chan TrustedHeadersRequest
+
+	// Nil unless a safety violation output is configured.
+	safetyViolationReports <-chan tmelink.SafetyViolation
+
 	assertEnv gassert.Env
 
 	done chan struct{}
@@ -76,11 +119,70 @@ type KernelConfig struct {
 	InitialHeight       uint64
 	InitialValidatorSet tmconsensus.ValidatorSet
 
+	// The height of the chain's first block.
+	// Zero means the same as InitialHeight.
+	// If greater than zero and less than InitialHeight,
+	// the CommittedHeaderStore must contain the header at InitialHeight-1,
+	// which is used to verify the previous commit proof of proposed headers at InitialHeight.
+	GenesisHeight uint64
+
 	ProposedHeaderFetcher tmelink.ProposedHeaderFetcher
 
+	// If positive, the maximum number of proposed headers
+	// that may be requested from the ProposedHeaderFetcher
+	// without yet having been added to the kernel.
+	FetchedHeaderBufferLimit int
+
+	// If set, proposed headers whose signer is a known validator
+	// but not the [tmconsensus.ExpectedProposer] for the header's height and round
+	// are reported as PHCheckUnexpectedProposer.
+	RequireExpectedProposer bool
+
+	// If positive, the maximum number of proposed headers
+	// from a single proposer to accept in a single round.
+	// Further headers from that proposer are reported as PHCheckProposerQuotaExceeded.
+	MaxHeadersPerProposerPerRound int
+
+	// The number of rounds beyond the voting round, at the voting height,
+	// for which proposed headers are accepted.
+	// Headers further ahead are reported as PHCheckRoundTooFarInFuture.
+	// Headers for the round after the voting round are always accepted,
+	// so values of zero and one are equivalent.
+	// Headers for rounds beyond the next round are held in the kernel
+	// until the voting round advances enough for them to enter the next round view.
+	MaxFutureRoundForHeader uint32
+
+	// If set, the commit proof for a newly committed block
+	// is reduced to a deterministic majority-power subset of its precommits.
+	// See [minimizedCommitProof].
+	MinimizeCommitProof bool
+
+	// If positive, votes from validators with less power than this
+	// are withheld from the views sent to the gossip strategy,
+	// but they are still counted by the kernel.
+	MinVotePowerToGossip uint64
+
+	// If positive, how long to keep the view of an abandoned voting round
+	// after advancing the voting round,
+	// so that late votes for the abandoned round are still recorded.
+	LateVoteGracePeriod time.Duration
+
 	ReplayedHeadersIn <-chan tmelink.ReplayedHeaderRequest
 	GossipStrategyOut chan<- tmelink.NetworkViewUpdate
 	LagStateOut       chan<- tmelink.LagState
+	BeaconOut         chan<- tmelink.Beacon
+
+	// If set, the kernel sends a value on this channel
+	// when it observes majority precommits at the committing height
+	// for a block other than the one being committed,
+	// or when a violation is reported on SafetyViolationReports.
+	SafetyViolationOut chan<- tmelink.SafetyViolation
+
+	// Safety violations detected outside the kernel,
+	// such as conflicting commits for heights the kernel has already committed.
+	// The kernel deduplicates, logs, and forwards these to SafetyViolationOut.
+	// May be nil.
+	SafetyViolationReports <-chan tmelink.SafetyViolation
 
 	StateMachineRoundEntranceIn <-chan tmeil.StateMachineRoundEntrance
 
@@ -96,13 +198,23 @@ type KernelConfig struct {
 	ViewLookupRequests <-chan ViewLookupRequest
 	PHCheckRequests    <-chan PHCheckRequest
 
+	// If set, the kernel publishes a copy of its voting and committing views
+	// to this pointer after any change to either view.
+	// The published Snapshot and its views must be treated as read-only.
+	PublishedSnapshot *atomic.Pointer[Snapshot]
+
 	AddPHRequests        <-chan tmconsensus.ProposedHeader
 	AddPrevoteRequests   <-chan AddPrevoteRequest
 	AddPrecommitRequests <-chan AddPrecommitRequest
 
+	// Requests to apply headers from a trusted source.
+	// May be nil, to disable trusted header ingestion.
+	TrustedHeadersRequests <-chan TrustedHeadersRequest
+
 	MetricsCollector *tmemetrics.Collector
 
-	Watchdog *gwatchdog.Watchdog
+	Watchdog          *gwatchdog.Watchdog
+	WatchdogHeartbeat tmeil.WatchdogHeartbeat
 
 	AssertEnv gassert.Env
 }
@@ -133,6 +245,25 @@ func NewKernel(ctx context.Context, log *slog.Logger, cfg KernelConfig) (*Kernel
 		}
 	}
 
+	genesisHeight := cfg.GenesisHeight
+	if genesisHeight == 0 {
+		genesisHeight = cfg.InitialHeight
+	}
+
+	// If we are starting above the genesis height,
+	// we need the previous committed header in order to verify
+	// the previous commit proof of any proposed header at the initial height.
+	var initialPrevHeader tmconsensus.CommittedHeader
+	if cfg.InitialHeight > genesisHeight {
+		initialPrevHeader, err = cfg.CommittedHeaderStore.LoadCommittedHeader(ctx, cfg.InitialHeight-1)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot initialize mirror kernel: failed to load committed header before initial height %d: %w",
+				cfg.InitialHeight, err,
+			)
+		}
+	}
+
 	// Load the round state for the committing round,
 	// in order to populate the initial previous commit proof
 	// on the voting view.
@@ -150,9 +281,13 @@ func NewKernel(ctx context.Context, log *slog.Logger, cfg KernelConfig) (*Kernel
 		}
 	} else if errors.As(err, new(tmconsensus.RoundUnknownError)) {
 		// Assuming that means we are voting at initial height.
-		committingProof = tmconsensus.CommitProof{
-			// Proofs must be non-nil in the special case of initial height.
-			Proofs: map[string][]gcrypto.SparseSignature{},
+		if cfg.InitialHeight > genesisHeight {
+			committingProof = initialPrevHeader.Proof
+		} else {
+			committingProof = tmconsensus.CommitProof{
+				// Proofs must be non-nil in the special case of initial height.
+				Proofs: map[string][]gcrypto.SparseSignature{},
+			}
 		}
 	} else {
 		return nil, fmt.Errorf(
@@ -175,9 +310,24 @@ func NewKernel(ctx context.Context, log *slog.Logger, cfg KernelConfig) (*Kernel
 		initialHeight: cfg.InitialHeight,
 		initialValSet: cfg.InitialValidatorSet,
 
+		genesisHeight:     genesisHeight,
+		initialPrevHeader: initialPrevHeader,
+
 		phf: cfg.ProposedHeaderFetcher,
 		mc:  cfg.MetricsCollector,
 
+		fetchedPHLimit: cfg.FetchedHeaderBufferLimit,
+
+		requireExpectedProposer: cfg.RequireExpectedProposer,
+
+		maxPHsPerProposer: cfg.MaxHeadersPerProposerPerRound,
+
+		maxFutureRound: cfg.MaxFutureRoundForHeader,
+
+		minimizeCommitProof: cfg.MinimizeCommitProof,
+
+		lateVoteGracePeriod: cfg.LateVoteGracePeriod,
+
 		// Channels provided through the config,
 		// i.e. channels coordinated by the Engine or Mirror.
 		replayedHeadersIn: cfg.ReplayedHeadersIn,
@@ -189,10 +339,16 @@ func NewKernel(ctx context.Context, log *slog.Logger, cfg KernelConfig) (*Kernel
 		viewLookupRequests: cfg.ViewLookupRequests,
 		phCheckRequests:    cfg.PHCheckRequests,
 
+		publishedSnapshot: cfg.PublishedSnapshot,
+
 		addPHRequests:        cfg.AddPHRequests,
 		addPrevoteRequests:   cfg.AddPrevoteRequests,
 		addPrecommitRequests: cfg.AddPrecommitRequests,
 
+		trustedHeadersRequests: cfg.TrustedHeadersRequests,
+
+		safetyViolationReports: cfg.SafetyViolationReports,
+
 		assertEnv: cfg.AssertEnv,
 
 		done: make(chan struct{}),
@@ -220,9 +376,13 @@ func NewKernel(ctx context.Context, log *slog.Logger, cfg KernelConfig) (*Kernel
 
 		StateMachineViewManager: newStateMachineViewManager(cfg.StateMachineRoundViewOut),
 
-		GossipViewManager: newGossipViewManager(cfg.GossipStrategyOut),
+		GossipViewManager: newGossipViewManager(log, cfg.GossipStrategyOut, cfg.MinVotePowerToGossip),
 
 		LagManager: newLagManager(cfg.LagStateOut),
+
+		BeaconManager: newBeaconManager(cfg.BeaconOut),
+
+		SafetyViolationManager: newSafetyViolationManager(cfg.SafetyViolationOut),
 	}
 
 	// Have to load the committing view first,
@@ -247,7 +407,7 @@ func NewKernel(ctx context.Context, log *slog.Logger, cfg KernelConfig) (*Kernel
 		return nil, err
 	}
 
-	go k.mainLoop(ctx, &initState, cfg.Watchdog)
+	go k.mainLoop(ctx, &initState, cfg.Watchdog, cfg.WatchdogHeartbeat)
 
 	return k, nil
 }
@@ -256,7 +416,9 @@ func (k *Kernel) Wait() {
 	<-k.done
 }
 
-func (k *Kernel) mainLoop(ctx context.Context, s *kState, wd *gwatchdog.Watchdog) {
+func (k *Kernel) mainLoop(
+	ctx context.Context, s *kState, wd *gwatchdog.Watchdog, hb tmeil.WatchdogHeartbeat,
+) {
 	ctx, task := trace.NewTask(ctx, "Mirror.kernel.mainLoop")
 	defer task.End()
 
@@ -283,19 +445,30 @@ func (k *Kernel) mainLoop(ctx context.Context, s *kState, wd *gwatchdog.Watchdog
 		)
 	}()
 
-	wSig := wd.Monitor(ctx, gwatchdog.MonitorConfig{
-		Name:     "Mirror kernel",
-		Interval: 10 * time.Second, Jitter: time.Second,
-		ResponseTimeout: time.Second,
-	})
+	wSig := wd.Monitor(ctx, hb.MonitorConfig("Mirror kernel"))
+
+	// The gossip output is monitored separately,
+	// so that a gossip strategy that stops reading updates is reported by name.
+	gsSig := wd.Monitor(ctx, hb.MonitorConfig("Mirror gossip output"))
+
+	// When a gossip output signal arrives while an update is pending,
+	// its alive channel is held here until the gossip strategy accepts the update,
+	// so that the kernel keeps serving other requests in the meantime.
+	var gsAlive chan<- struct{}
 
 	for {
+		k.publishSnapshot(ctx, s)
+
 		smOut := s.StateMachineViewManager.Output(s)
 
 		gsOut := s.GossipViewManager.Output()
 
 		lagOut := s.LagManager.Output()
 
+		beaconOut := s.BeaconManager.Output()
+
+		svOut := s.SafetyViolationManager.Output()
+
 		select {
 		case <-ctx.Done():
 			k.log.Info(
@@ -329,15 +502,32 @@ func (k *Kernel) mainLoop(ctx context.Context, s *kState, wd *gwatchdog.Watchdog
 		case req := <-k.addPrecommitRequests:
 			k.addPrecommit(ctx, s, req)
 
+		case req := <-k.trustedHeadersRequests:
+			req.Resp <- k.handleTrustedHeaders(ctx, s, req.Headers)
+
+		case v := <-k.safetyViolationReports:
+			k.reportSafetyViolation(s, v)
+
 		case gsOut.Ch <- gsOut.Val:
 			gsOut.MarkSent()
 
+			if gsAlive != nil {
+				close(gsAlive)
+				gsAlive = nil
+			}
+
 		case smOut.Ch <- smOut.Val:
 			smOut.MarkSent()
 
 		case lagOut.Ch <- lagOut.Val:
 			lagOut.MarkSent()
 
+		case beaconOut.Ch <- beaconOut.Val:
+			beaconOut.MarkSent()
+
+		case svOut.Ch <- svOut.Val:
+			svOut.MarkSent()
+
 		case ph := <-k.phf.FetchedProposedHeaders:
 			k.addProposedHeader(ctx, s, ph)
 
@@ -366,8 +556,20 @@ func (k *Kernel) mainLoop(ctx context.Context, s *kState, wd *gwatchdog.Watchdog
 				panic(fmt.Errorf("TODO: handle internal error from handling replayed block: %w", err))
 			}
 
+		case <-s.OrphanedTimerC():
+			s.ClearOrphaned()
+
 		case sig := <-wSig:
 			close(sig.Alive)
+
+		case sig := <-gsSig:
+			// Only report alive once the gossip strategy has accepted any pending update.
+			// If there is nothing pending, the gossip strategy cannot be stuck on us.
+			if gsOut.Ch == nil {
+				close(sig.Alive)
+			} else {
+				gsAlive = sig.Alive
+			}
 		}
 	}
 }
@@ -383,9 +585,21 @@ func (k *Kernel) addProposedHeader(ctx context.Context, s *kState, ph tmconsensu
 	if cancel, ok := s.InFlightFetchPHs[string(ph.Header.Hash)]; ok {
 		cancel()
 		delete(s.InFlightFetchPHs, string(ph.Header.Hash))
+
+		if s.DeferredFetchPHs {
+			// A fetch slot was just freed, so retry any fetch
+			// that was skipped due to the buffer limit.
+			// This is deferred so that ph is already part of the view
+			// and will not be requested again.
+			defer k.resumeDeferredFetches(ctx, s)
+		}
 	}
 
-	vrv, viewID, _ := s.FindView(ph.Header.Height, ph.Round, "(*Kernel).addProposedHeader")
+	vrv, viewID, status := s.FindView(ph.Header.Height, ph.Round, "(*Kernel).addProposedHeader")
+	if status == ViewLaterVotingRound && ph.Round-s.Voting.Round <= k.maxFutureRound {
+		k.addFutureRoundProposedHeader(ctx, s, ph)
+		return
+	}
 	if vrv == nil {
 		k.log.Info(
 			"Dropping proposed block that did not match a view (may have been received immediately before a view shift)",
@@ -410,7 +624,18 @@ func (k *Kernel) addProposedHeader(ctx context.Context, s *kState, ph tmconsensu
 		}
 	}
 
-	// On the right height/round, no duplicate detected,
+	// Concurrent calls to HandleProposedHeader may have each passed the quota check
+	// against the same original view, so check the quota again.
+	if k.proposerQuotaExceeded(vrv.ProposedHeaders, ph.ProposerPubKey) {
+		k.log.Debug(
+			"Dropping proposed header beyond proposer quota",
+			"height", ph.Header.Height, "round", ph.Round,
+			"limit", k.maxPHsPerProposer,
+		)
+		return
+	}
+
+	// On the right height/round, no duplicate or excess header detected,
 	// so we can add the proposed header.
 	vrv.ProposedHeaders = append(vrv.ProposedHeaders, ph)
 
@@ -450,8 +675,15 @@ func (k *Kernel) addProposedHeader(ctx context.Context, s *kState, ph tmconsensu
 	// which is guaranteed by the prior guard clause.
 	backfillVRV := &s.Committing
 
+	// There is no committing view for the height before the initial height.
+	// If we started above the genesis height, the previous commit proof
+	// was already verified against the committed header loaded at startup.
+	var commitProofs map[string][]gcrypto.SparseSignature
+	if ph.Header.Height > k.initialHeight {
+		commitProofs = ph.Header.PrevCommitProof.Proofs
+	}
+
 	// TODO: this merging code should probably move to a function in gcrypto.
-	commitProofs := ph.Header.PrevCommitProof.Proofs
 	mergedAny := false
 	for blockHash, laterSigs := range commitProofs {
 		target := backfillVRV.PrecommitProofs[blockHash]
@@ -473,7 +705,7 @@ func (k *Kernel) addProposedHeader(ctx context.Context, s *kState, ph tmconsensu
 		if err := k.rStore.OverwriteRoundPrecommitProofs(
 			ctx,
 			ph.Header.Height-1, ph.Header.PrevCommitProof.Round, // TODO: Don't assume this matches the committing view.
-			mapToSparseSignatureCollection(backfillVRV.PrecommitProofs),
+			tmconsensus.FullProofsToSparse(backfillVRV.PrecommitProofs),
 		); err != nil {
 			glog.HRE(k.log, ph.Header.Height, ph.Round, err).Warn(
 				"Failed to save backfilled commit info to round store; this may cause issues upon restart",
@@ -501,38 +733,6 @@ func (k *Kernel) addProposedHeader(ctx context.Context, s *kState, ph tmconsensu
 	}
 }
 
-// mapToSparseSignatureCollection converts a mapped full proof
-// to a SparseSignatureCollection.
-// TODO: we should extract a type for the full map
-// and add this as a method there.
-func mapToSparseSignatureCollection(
-	proofs map[string]gcrypto.CommonMessageSignatureProof,
-) tmconsensus.SparseSignatureCollection {
-	out := tmconsensus.SparseSignatureCollection{
-		BlockSignatures: make(map[string][]gcrypto.SparseSignature, len(proofs)),
-	}
-
-	isFirst := true
-	for hash, proof := range proofs {
-		if isFirst {
-			out.PubKeyHash = proof.PubKeyHash()
-			isFirst = false
-		} else {
-			if !bytes.Equal(out.PubKeyHash, proof.PubKeyHash()) {
-				panic(fmt.Errorf(
-					"public key hash mismatch in signature proofs: %x and %x",
-					out.PubKeyHash, proof.PubKeyHash(),
-				))
-			}
-		}
-
-		sp := proof.AsSparse()
-		out.BlockSignatures[hash] = append(out.BlockSignatures[hash], sp.Signatures...)
-	}
-
-	return out
-}
-
 // addPrevote is the kernel method to add prevotes to the current state.
 // The non-kernel HandlePrevoteProofs method takes a snapshot of the then-current kernel state,
 // and eagerly updates that copy with the new prevotes from the network.
@@ -546,7 +746,7 @@ func (k *Kernel) addPrevote(ctx context.Context, s *kState, req AddPrevoteReques
 
 	// NOTE: keep changes to this method synchronized with addPrecommit.
 
-	vrv, vID, vStatus := s.FindView(req.H, req.R, "(*Kernel).addPrevote")
+	vrv, vID, vStatus := s.FindVoteView(req.H, req.R, "(*Kernel).addPrevote")
 	if vStatus != ViewFound {
 		switch vStatus {
 		case ViewBeforeCommitting, ViewOrphaned:
@@ -567,7 +767,7 @@ func (k *Kernel) addPrevote(ctx context.Context, s *kState, req AddPrevoteReques
 			))
 		}
 	}
-	if vID != ViewIDCommitting && vID != ViewIDVoting && vID != ViewIDNextRound {
+	if vID != ViewIDCommitting && vID != ViewIDVoting && vID != ViewIDNextRound && vID != ViewIDOrphaned {
 		panic(fmt.Errorf(
 			"TODO: handle adding prevotes to %s view", vID,
 		))
@@ -598,7 +798,7 @@ func (k *Kernel) addPrevote(ctx context.Context, s *kState, req AddPrevoteReques
 		if err := k.rStore.OverwriteRoundPrevoteProofs(
 			ctx,
 			req.H, req.R,
-			mapToSparseSignatureCollection(vrv.PrevoteProofs),
+			tmconsensus.FullProofsToSparse(vrv.PrevoteProofs),
 		); err != nil {
 			glog.HRE(k.log, req.H, req.R, err).Warn(
 				"Failed to save prevotes to round store; this may cause issues upon restart",
@@ -620,7 +820,11 @@ func (k *Kernel) addPrevote(ctx context.Context, s *kState, req AddPrevoteReques
 	}
 
 	// See if we need to make a request for a proposed block.
-	k.checkMissingPHs(ctx, s, vrv.PrevoteProofs)
+	// Late votes in the orphaned view are only kept for the record,
+	// so there is no need to fetch their blocks.
+	if vID != ViewIDOrphaned {
+		k.checkMissingPHs(ctx, s, vrv.PrevoteProofs)
+	}
 
 	// END OF addPrecommit SYNCHRONIZATION.
 
@@ -647,7 +851,7 @@ func (k *Kernel) addPrecommit(ctx context.Context, s *kState, req AddPrecommitRe
 
 	// NOTE: keep changes to this method synchronized with addPrevote.
 
-	vrv, vID, vStatus := s.FindView(req.H, req.R, "(*Kernel).addPrecommit")
+	vrv, vID, vStatus := s.FindVoteView(req.H, req.R, "(*Kernel).addPrecommit")
 	if vStatus != ViewFound {
 		switch vStatus {
 		case ViewBeforeCommitting, ViewOrphaned:
@@ -668,7 +872,7 @@ func (k *Kernel) addPrecommit(ctx context.Context, s *kState, req AddPrecommitRe
 			))
 		}
 	}
-	if vID != ViewIDCommitting && vID != ViewIDVoting && vID != ViewIDNextRound {
+	if vID != ViewIDCommitting && vID != ViewIDVoting && vID != ViewIDNextRound && vID != ViewIDOrphaned {
 		panic(fmt.Errorf(
 			"TODO: handle adding precommits to %s view", vID,
 		))
@@ -699,7 +903,7 @@ func (k *Kernel) addPrecommit(ctx context.Context, s *kState, req AddPrecommitRe
 		if err := k.rStore.OverwriteRoundPrecommitProofs(
 			ctx,
 			req.H, req.R,
-			mapToSparseSignatureCollection(vrv.PrecommitProofs),
+			tmconsensus.FullProofsToSparse(vrv.PrecommitProofs),
 		); err != nil {
 			glog.HRE(k.log, req.H, req.R, err).Warn(
 				"Failed to save precommits to round store; this may cause issues upon restart",
@@ -721,7 +925,11 @@ func (k *Kernel) addPrecommit(ctx context.Context, s *kState, req AddPrecommitRe
 	}
 
 	// See if we need to make a request for a proposed block.
-	k.checkMissingPHs(ctx, s, vrv.PrecommitProofs)
+	// Late votes in the orphaned view are only kept for the record,
+	// so there is no need to fetch their blocks.
+	if vID != ViewIDOrphaned {
+		k.checkMissingPHs(ctx, s, vrv.PrecommitProofs)
+	}
 
 	// END OF addPrevote SYNCHRONIZATION.
 
@@ -739,12 +947,79 @@ func (k *Kernel) addPrecommit(ctx context.Context, s *kState, req AddPrecommitRe
 			k.log.Warn("Error while checking view shift for precommit in next round; kernel may be in bad state", "err", err)
 		}
 	case ViewIDCommitting:
-		// No view shift possible here.
+		// No view shift possible here,
+		// but a majority for a different block indicates a safety violation.
+		k.checkCommittingSafetyViolation(s)
+	case ViewIDOrphaned:
+		// Late precommits cannot change the outcome of an abandoned round.
 	default:
 		panic(fmt.Errorf("BUG: unhandled view ID %s in addPrecommit", vID))
 	}
 }
 
+// checkCommittingSafetyViolation checks whether any block other than the committing header
+// has reached majority precommit power in the committing view.
+// Such a majority means that a conflicting commit exists for the committing height,
+// which is only possible if validators with more than one third of the voting power
+// signed conflicting precommits.
+//
+// The precommits in the committing view have already had their signatures verified
+// before reaching the kernel, so the conflicting proof is valid.
+func (k *Kernel) checkCommittingSafetyViolation(s *kState) {
+	vrv := &s.Committing
+	vs := vrv.VoteSummary
+	maj := tmconsensus.ByzantineMajority(vs.AvailablePower)
+	committedHash := string(s.CommittingHeader.Hash)
+
+	for blockHash, pow := range vs.PrecommitBlockPower {
+		if blockHash == "" || blockHash == committedHash || pow < maj {
+			continue
+		}
+
+		proof, ok := vrv.PrecommitProofs[blockHash]
+		if !ok {
+			// Should be impossible, since the power was derived from the proofs.
+			continue
+		}
+		sparse := proof.AsSparse()
+
+		k.reportSafetyViolation(s, tmelink.SafetyViolation{
+			Height: vrv.Height,
+
+			CommittedRound:     vrv.Round,
+			CommittedBlockHash: []byte(committedHash),
+
+			ConflictingRound:     vrv.Round,
+			ConflictingBlockHash: []byte(blockHash),
+
+			Evidence: tmconsensus.CommitProof{
+				Round:      vrv.Round,
+				PubKeyHash: sparse.PubKeyHash,
+				Proofs: map[string][]gcrypto.SparseSignature{
+					blockHash: sparse.Signatures,
+				},
+			},
+		})
+	}
+}
+
+// reportSafetyViolation logs v and queues it for the safety violation output,
+// unless the same conflict was already reported.
+func (k *Kernel) reportSafetyViolation(s *kState, v tmelink.SafetyViolation) {
+	if !s.SafetyViolationManager.Add(v) {
+		return
+	}
+
+	k.log.Error(
+		"SAFETY VIOLATION: observed majority precommits for a block conflicting with the committed block",
+		"height", v.Height,
+		"committed_round", v.CommittedRound,
+		"committed_hash", glog.Hex(v.CommittedBlockHash),
+		"conflicting_round", v.ConflictingRound,
+		"conflicting_hash", glog.Hex(v.ConflictingBlockHash),
+	)
+}
+
 // checkVotingPrecommitViewShift checks if precommit consensus
 // has been reached on the voting round, and if so,
 // updates the voting round accordingly.
@@ -827,11 +1102,33 @@ func (k *Kernel) checkVotingPrecommitViewShift(ctx context.Context, s *kState) e
 		))
 	}
 
+	// Minimize the commit proof before shifting,
+	// so that the views marked updated during the shift
+	// already carry the minimized proof.
+	var commitProof tmconsensus.CommitProof
+	if k.minimizeCommitProof {
+		minProof, err := minimizedCommitProof(
+			tmconsensus.CommitProof{
+				Round:      vrv.Round,
+				PubKeyHash: string(vrv.ValidatorSet.PubKeyHash),
+			},
+			vrv.PrecommitProofs[committingHash],
+			committingHash,
+			vrv.ValidatorSet.Validators,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to minimize commit proof: %w", err)
+		}
+
+		commitProof = minProof
+	}
+
 	// TODO: gassert: verify incoming validator set's hashes.
 	nextValSet := votedHeader.NextValidatorSet
 	s.ShiftVotingToCommitting(nextHeightDetails{
 		ValidatorSet: nextValSet,
 		VotedHeader:  votedHeader,
+		CommitProof:  commitProof,
 	})
 
 	// Since we have a new committing header,
@@ -868,6 +1165,17 @@ func (k *Kernel) saveCurrentCommittingHeader(ctx context.Context, s *kState) err
 		return fmt.Errorf("failed to save newly committed header: %w", err)
 	}
 
+	// The locally observed proof differs between nodes,
+	// so the beacon is derived from the canonical proof for the previous height instead,
+	// which is now fixed in the new committing header.
+	if s.CommittingHeader.Height > k.initialHeight {
+		s.BeaconManager.Add(tmelink.NewBeacon(
+			s.CommittingHeader.Height-1,
+			s.CommittingHeader.PrevBlockHash,
+			s.CommittingHeader.PrevCommitProof,
+		))
+	}
+
 	return nil
 }
 
@@ -1034,6 +1342,20 @@ func (k *Kernel) checkMissingPHs(ctx context.Context, s *kState, proofs map[stri
 			continue
 		}
 
+		if k.fetchedPHLimit > 0 && len(s.InFlightFetchPHs) >= k.fetchedPHLimit {
+			// Too many fetched headers are outstanding.
+			// Hold off on the request until one of them is added,
+			// at which point resumeDeferredFetches retries.
+			k.log.Debug(
+				"Deferring fetch request due to fetched header buffer limit",
+				"height", s.Voting.Height, "round", s.Voting.Round,
+				"missing_hash", glog.Hex(missingHash),
+				"limit", k.fetchedPHLimit,
+			)
+			s.DeferredFetchPHs = true
+			return
+		}
+
 		// This hash has met or exceeded the minimum threshold,
 		// so we need to make a fetch request.
 
@@ -1064,9 +1386,25 @@ func (k *Kernel) checkMissingPHs(ctx context.Context, s *kState, proofs map[stri
 	}
 }
 
+// resumeDeferredFetches retries fetch requests for the voting view
+// that were previously skipped due to the fetched header buffer limit.
+func (k *Kernel) resumeDeferredFetches(ctx context.Context, s *kState) {
+	s.DeferredFetchPHs = false
+
+	if len(s.Voting.PrevoteProofs) > 0 {
+		k.checkMissingPHs(ctx, s, s.Voting.PrevoteProofs)
+	}
+	if len(s.Voting.PrecommitProofs) > 0 {
+		k.checkMissingPHs(ctx, s, s.Voting.PrecommitProofs)
+	}
+}
+
 // advanceVotingRound is called when the kernel needs to increase the voting round by one,
 // and when we have sufficient information for the voting round to treat it as a nil commit.
 func (k *Kernel) advanceVotingRound(ctx context.Context, s *kState) error {
+	if k.lateVoteGracePeriod > 0 {
+		s.OrphanVotingView(k.lateVoteGracePeriod)
+	}
 	s.AdvanceVotingRound()
 	if err := k.updateObservers(ctx, s); err != nil {
 		return fmt.Errorf(
@@ -1082,6 +1420,9 @@ func (k *Kernel) advanceVotingRound(ctx context.Context, s *kState) error {
 // Compared to [*Kernel.advanceVotingRound], this sends more information to the state machine
 // indicating the kernel's intent to skip the round.
 func (k *Kernel) jumpVotingRound(ctx context.Context, s *kState, newRound uint32) error {
+	if k.lateVoteGracePeriod > 0 {
+		s.OrphanVotingView(k.lateVoteGracePeriod)
+	}
 	s.JumpVotingRound()
 	if err := k.updateObservers(ctx, s); err != nil {
 		return fmt.Errorf(
@@ -1099,15 +1440,47 @@ func (k *Kernel) sendSnapshotResponse(ctx context.Context, s *kState, req Snapsh
 	defer close(req.Ready)
 
 	if req.Snapshot.Voting != nil {
-		k.copySnapshotView(s.Voting, req.Snapshot.Voting, req.Fields)
+		CopySnapshotView(s.Voting, req.Snapshot.Voting, req.Fields)
 	}
 	if req.Snapshot.Committing != nil {
-		k.copySnapshotView(s.Committing, req.Snapshot.Committing, req.Fields)
+		CopySnapshotView(s.Committing, req.Snapshot.Committing, req.Fields)
 	}
 }
 
-// copySnapshotView copies an individual view from kernel state to a snapshot request.
-func (k *Kernel) copySnapshotView(src tmconsensus.VersionedRoundView, dst *tmconsensus.VersionedRoundView, fields RVFieldFlags) {
+// publishSnapshot stores a copy of s's voting and committing views
+// in k.publishedSnapshot, if publishing is enabled
+// and either view has changed since the last published snapshot.
+func (k *Kernel) publishSnapshot(ctx context.Context, s *kState) {
+	if k.publishedSnapshot == nil {
+		return
+	}
+
+	if prev := k.publishedSnapshot.Load(); prev != nil &&
+		sameViewVersion(*prev.Voting, s.Voting) &&
+		sameViewVersion(*prev.Committing, s.Committing) {
+		return
+	}
+
+	defer trace.StartRegion(ctx, "publishSnapshot").End()
+
+	voting := s.Voting.Clone()
+	committing := s.Committing.Clone()
+	k.publishedSnapshot.Store(&Snapshot{
+		Voting:     &voting,
+		Committing: &committing,
+	})
+}
+
+// sameViewVersion reports whether a and b have the same height, round, and version.
+func sameViewVersion(a, b tmconsensus.VersionedRoundView) bool {
+	return a.Height == b.Height && a.Round == b.Round && a.Version == b.Version
+}
+
+// CopySnapshotView copies the requested fields of an individual view from src to dst,
+// reusing dst's existing slices and maps where possible.
+// It is used when responding to snapshot requests,
+// and by readers of a published snapshot.
+func CopySnapshotView(src tmconsensus.VersionedRoundView, dst *tmconsensus.VersionedRoundView, fields RVFieldFlags) {
 	dst.Height = src.Height
 	dst.Round = src.Round
 	dst.Version = src.Version
@@ -1221,9 +1594,11 @@ func (k *Kernel) sendViewLookupResponse(ctx context.Context, s *kState, req View
 
 	var resp ViewLookupResponse
 
-	srcVRV, vID, vStatus := s.FindView(req.H, req.R, req.Reason)
+	// View lookups are only made to handle incoming votes,
+	// so late votes may match the orphaned view.
+	srcVRV, vID, vStatus := s.FindVoteView(req.H, req.R, req.Reason)
 	if srcVRV != nil {
-		k.copySnapshotView(*srcVRV, req.VRV, req.Fields)
+		CopySnapshotView(*srcVRV, req.VRV, req.Fields)
 	}
 	resp.ID = vID
 	resp.Status = vStatus
@@ -1274,11 +1649,12 @@ func (k *Kernel) sendPHCheckResponse(ctx context.Context, s *kState, req PHCheck
 			k.setPHCheckStatus(s, req, &resp, s.Voting, ViewIDVoting)
 		} else if pbRound == votingRound+1 {
 			k.setPHCheckStatus(s, req, &resp, s.NextRound, ViewIDNextRound)
+		} else if pbRound-votingRound <= k.maxFutureRound {
+			// The future round has the same height as the next round,
+			// so it has the same previous block details as the next round.
+			k.setPHCheckStatus(s, req, &resp, s.FutureRoundView(pbRound), ViewIDNextRound)
 		} else {
-			panic(fmt.Errorf(
-				"TODO: handle proposed block with round (%d) beyond voting round (%d)",
-				pbRound, votingRound,
-			))
+			resp.Status = PHCheckRoundTooFarInFuture
 		}
 	} else if pbHeight == votingHeight+1 {
 		// Special case of the proposed block being for the next height.
@@ -1333,6 +1709,10 @@ func (k *Kernel) setPHCheckStatus(
 
 		if proposerPubKey == nil {
 			resp.Status = PHCheckSignerUnrecognized
+		} else if k.requireExpectedProposer && !tmconsensus.IsExpectedProposer(vrv.ValidatorSet, req.PH) {
+			resp.Status = PHCheckUnexpectedProposer
+		} else if k.proposerQuotaExceeded(vrv.ProposedHeaders, proposerPubKey) {
+			resp.Status = PHCheckProposerQuotaExceeded
 		} else {
 			resp.Status = PHCheckAcceptable
 			resp.ProposerPubKey = proposerPubKey
@@ -1344,9 +1724,18 @@ func (k *Kernel) setPHCheckStatus(
 		return
 	}
 
-	if req.PH.Header.Height == k.initialHeight {
+	if req.PH.Header.Height == k.genesisHeight {
 		// Explicitly leave the previous block hash and previous validator set empty
-		// if this is a proposed header for the initial height.
+		// if this is a proposed header for the genesis height.
+		return
+	}
+
+	if req.PH.Header.Height == k.initialHeight {
+		// We started above the genesis height, so there is no committing view
+		// for the previous height; use the committed header loaded at startup.
+		resp.PrevBlockHash = k.initialPrevHeader.Header.Hash
+		resp.PrevValidatorSet = k.initialPrevHeader.Header.ValidatorSet
+		resp.PrevTimestamp = k.initialPrevHeader.Header.Timestamp
 		return
 	}
 
@@ -1360,11 +1749,70 @@ func (k *Kernel) setPHCheckStatus(
 	case ViewIDVoting, ViewIDNextRound:
 		resp.PrevBlockHash = s.CommittingHeader.Hash
 		resp.PrevValidatorSet = s.CommittingHeader.ValidatorSet
+		resp.PrevTimestamp = s.CommittingHeader.Timestamp
 	default:
 		panic(fmt.Errorf("BUG: setPHCheckStatus called with invalid view ID %s", vID))
 	}
 }
 
+// addFutureRoundProposedHeader adds ph to s.FutureRoundPHs,
+// where it is held until the voting round advances enough
+// for ph's round to become the next round.
+// It is called from [*Kernel.addProposedHeader]
+// for headers that were checked against [*kState.FutureRoundView].
+func (k *Kernel) addFutureRoundProposedHeader(ctx context.Context, s *kState, ph tmconsensus.ProposedHeader) {
+	phs := s.FutureRoundPHs[ph.Round]
+
+	// Same duplicate and quota checks as addProposedHeader,
+	// as there may have been concurrent calls to HandleProposedHeader.
+	if slices.ContainsFunc(phs, func(have tmconsensus.ProposedHeader) bool {
+		return bytes.Equal(have.Signature, ph.Signature)
+	}) {
+		return
+	}
+	if k.proposerQuotaExceeded(phs, ph.ProposerPubKey) {
+		k.log.Debug(
+			"Dropping future round proposed header beyond proposer quota",
+			"height", ph.Header.Height, "round", ph.Round,
+			"limit", k.maxPHsPerProposer,
+		)
+		return
+	}
+
+	if s.FutureRoundPHs == nil {
+		s.FutureRoundPHs = make(map[uint32][]tmconsensus.ProposedHeader)
+	}
+	s.FutureRoundPHs[ph.Round] = append(phs, ph)
+
+	if err := k.rStore.SaveRoundProposedHeader(ctx, ph); err != nil {
+		glog.HRE(k.log, ph.Header.Height, ph.Round, err).Warn(
+			"Failed to save future round proposed header to round store; this may cause issues upon restart",
+		)
+	}
+}
+
+// proposerQuotaExceeded reports whether phs already contains
+// the maximum number of proposed headers from proposerPubKey.
+// It always returns false if the kernel has no per-proposer limit,
+// or if proposerPubKey is nil.
+//
+// Replayed headers are stored without a proposer public key,
+// so entries of phs may have a nil ProposerPubKey;
+// those never count towards a quota.
+func (k *Kernel) proposerQuotaExceeded(phs []tmconsensus.ProposedHeader, proposerPubKey gcrypto.PubKey) bool {
+	if k.maxPHsPerProposer <= 0 || proposerPubKey == nil {
+		return false
+	}
+
+	n := 0
+	for _, ph := range phs {
+		if ph.ProposerPubKey != nil && proposerPubKey.Equal(ph.ProposerPubKey) {
+			n++
+		}
+	}
+	return n >= k.maxPHsPerProposer
+}
+
 func (k *Kernel) handleStateMachineRoundEntrance(ctx context.Context, s *kState, re tmeil.StateMachineRoundEntrance) {
 	defer trace.StartRegion(ctx, "handleStateMachineRoundEntrance").End()
 
@@ -1574,6 +2022,25 @@ func (k *Kernel) handleReplayedHeader(
 ) error {
 	defer trace.StartRegion(ctx, "handleReplayedHeader").End()
 
+	return k.applyCommittedHeader(ctx, s, header, proof, false)
+}
+
+// applyCommittedHeader applies header and its commit proof to the voting view,
+// shifting the voting view to committing.
+// The header's hash is always checked,
+// and the commit proof must always carry majority voting power for the header.
+//
+// If trusted is set and the signature proof scheme supports [gcrypto.TrustedSparseMerger],
+// the signatures in the commit proof are accepted without verification.
+// Otherwise every signature is verified.
+func (k *Kernel) applyCommittedHeader(
+	ctx context.Context,
+	s *kState,
+	header tmconsensus.Header,
+	proof tmconsensus.CommitProof,
+	trusted bool,
+) error {
+
 	if header.Height != s.Voting.Height {
 		return tmelink.ReplayedHeaderOutOfSyncError{
 			WantHeight: s.Voting.Height,
@@ -1691,10 +2158,16 @@ func (k *Kernel) handleReplayedHeader(
 		tempProofs[hash] = haveProof
 
 		// Now merge the incoming proof with the local copy.
-		mergeRes := haveProof.MergeSparse(gcrypto.SparseSignatureProof{
+		sparse := gcrypto.SparseSignatureProof{
 			PubKeyHash: string(header.ValidatorSet.PubKeyHash),
 			Signatures: sparseSigs,
-		})
+		}
+		var mergeRes gcrypto.SignatureProofMergeResult
+		if tm, ok := haveProof.(gcrypto.TrustedSparseMerger); ok && trusted {
+			mergeRes = tm.MergeTrustedSparse(sparse)
+		} else {
+			mergeRes = haveProof.MergeSparse(sparse)
+		}
 
 		// There are three fields on the merge result.
 		//
@@ -1783,7 +2256,7 @@ func (k *Kernel) handleReplayedHeader(
 	if err := k.rStore.OverwriteRoundPrecommitProofs(
 		ctx,
 		h, r,
-		mapToSparseSignatureCollection(s.Voting.PrecommitProofs),
+		tmconsensus.FullProofsToSparse(s.Voting.PrecommitProofs),
 	); err != nil {
 		return tmelink.ReplayedHeaderInternalError{
 			Err: fmt.Errorf(
@@ -1806,6 +2279,40 @@ func (k *Kernel) handleReplayedHeader(
 	return nil
 }
 
+// handleTrustedHeaders applies each of the given committed headers
+// through the same path as a replayed header, except that commit proof signatures
+// are not verified if the signature proof scheme supports trusted merges,
+// stopping at the first header that fails to apply.
+// Header hashes and commit proof voting power are still checked.
+//
+// Headers below the voting height are assumed to have already been committed
+// and are skipped, so that callers may pass overlapping ranges.
+// Because each header is accompanied by its commit proof,
+// applying it shifts the voting view to committing directly;
+// the proposed header fetcher is never consulted.
+func (k *Kernel) handleTrustedHeaders(
+	ctx context.Context,
+	s *kState,
+	chs []tmconsensus.CommittedHeader,
+) error {
+	defer trace.StartRegion(ctx, "handleTrustedHeaders").End()
+
+	for _, ch := range chs {
+		if ch.Header.Height < s.Voting.Height {
+			continue
+		}
+
+		if err := k.applyCommittedHeader(ctx, s, ch.Header, ch.Proof, true); err != nil {
+			return fmt.Errorf(
+				"failed to apply trusted header at height %d: %w",
+				ch.Header.Height, err,
+			)
+		}
+	}
+
+	return nil
+}
+
 // loadInitialView loads the committing or voting RoundView
 // at the given height and round from the RoundStore, inside NewKernel.
 func (k *Kernel) loadInitialView(
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/gwatchdog"
 	"github.com/gordian-engine/gordian/internal/gtest"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
 	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmeil"
@@ -361,6 +363,15 @@ func TestKernel_closeRoundEntranceHeightCommitted(t *testing.T) {
 
 		// This puts height 2 in committing, which means height 1 is now committed.
 		_ = gtest.ReceiveSoon(t, height1Committed)
+
+		// The mirror's committing view is now ahead of the state machine's height.
+		// The state machine can only jump ahead within its current height,
+		// so the kernel must not offer it a jump to height 2;
+		// the state machine catches up through its next round entrance instead.
+		vrv := gtest.ReceiveSoon(t, kfx.StateMachineRoundViewOut)
+		require.Equal(t, uint64(1), vrv.VRV.Height)
+		require.Nil(t, vrv.JumpAheadRoundView)
+		gtest.NotSendingSoon(t, kfx.StateMachineRoundViewOut)
 	})
 
 	// TODO: another subtest, with non-committed headers.
@@ -583,3 +594,78 @@ func TestKernel_initialViewLoadsPrevCommitProof(t *testing.T) {
 		require.Equal(t, rer.VRV.VoteSummary.AvailablePower, rer.VRV.VoteSummary.PrecommitBlockPower[string(ph2.Header.Hash)])
 	})
 }
+
+func TestKernel_gossipOutputHeartbeat(t *testing.T) {
+	newFixture := func(ctx context.Context, t *testing.T) *KernelFixture {
+		kfx := NewKernelFixture(ctx, t, 4)
+
+		// Use a real watchdog with a short heartbeat,
+		// so the gossip output is checked several times during the test.
+		wd, wCtx := gwatchdog.NewWatchdog(ctx, kfx.Log.With("sys", "watchdog"))
+		t.Cleanup(wd.Wait)
+		kfx.Cfg.Watchdog = wd
+		kfx.WatchdogCtx = wCtx
+		kfx.Cfg.WatchdogHeartbeat = tmeil.WatchdogHeartbeat{
+			Interval:        10 * time.Millisecond,
+			Jitter:          time.Millisecond,
+			ResponseTimeout: time.Duration(gtest.ScaleMs(250)),
+		}
+
+		return kfx
+	}
+
+	t.Run("kernel stays responsive while update is pending", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		kfx := newFixture(ctx, t)
+
+		k := kfx.NewKernel()
+		defer k.Wait()
+		defer cancel()
+
+		// Don't read the initial gossip update yet,
+		// and allow a few heartbeats to arrive while it is pending.
+		gtest.Sleep(gtest.ScaleMs(50))
+
+		// The kernel still serves other requests.
+		s := tmi.Snapshot{
+			Voting: new(tmconsensus.VersionedRoundView),
+		}
+		req := tmi.SnapshotRequest{
+			Snapshot: &s,
+			Ready:    make(chan struct{}),
+			Fields:   tmi.RVValidators,
+		}
+		gtest.SendSoon(t, kfx.SnapshotRequests, req)
+		_ = gtest.ReceiveSoon(t, req.Ready)
+
+		// Reading the pending update satisfies the heartbeat,
+		// so the watchdog does not terminate.
+		_ = gtest.ReceiveSoon(t, kfx.GossipStrategyOut)
+		gtest.Sleep(gtest.ScaleMs(300))
+		require.NoError(t, kfx.WatchdogCtx.Err())
+	})
+
+	t.Run("watchdog terminates when gossip strategy stops reading", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		kfx := newFixture(ctx, t)
+
+		k := kfx.NewKernel()
+		defer k.Wait()
+		defer cancel()
+
+		// The initial gossip update is never read.
+		_ = gtest.ReceiveOrTimeout(t, kfx.WatchdogCtx.Done(), gtest.ScaleMs(500))
+
+		var fe gwatchdog.FailureToRespondError
+		require.ErrorAs(t, context.Cause(kfx.WatchdogCtx), &fe)
+		require.Equal(t, "Mirror gossip output", fe.SubsystemName)
+	})
+}
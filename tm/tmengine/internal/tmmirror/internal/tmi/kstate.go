@@ -3,6 +3,7 @@ package tmi
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gordian-engine/gordian/gcrypto"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
@@ -18,12 +19,24 @@ type kState struct {
 	// before we orphan the current voting view.
 	NextRound tmconsensus.VersionedRoundView
 
+	// Proposed headers at the voting height for rounds beyond NextRound,
+	// keyed by round.
+	// These are only held when the kernel is configured
+	// with a MaxFutureRoundForHeader greater than one.
+	// When the voting round increments,
+	// any headers for the new NextRound are moved into that view.
+	FutureRoundPHs map[uint32][]tmconsensus.ProposedHeader
+
 	// The kernel makes a fetch request if a block reaches >1/3
 	// prevotes or precommits, and we don't have the actual proposed header.
 	// If a request is outstanding and we switch views,
 	// we need to cancel those outstanding requests.
 	InFlightFetchPHs map[string]context.CancelFunc
 
+	// Set when a fetch request was skipped
+	// because InFlightFetchPHs had reached the configured limit.
+	DeferredFetchPHs bool
+
 	// Certain operations on the Voting view require knowledge
 	// of which header in the Committing view, is being committed.
 	// The header will be the zero value if the mirror does not yet have a Committing view.
@@ -43,6 +56,22 @@ type kState struct {
 	// Manager for lag state, to inform the driver
 	// when we believe we are lagging the network.
 	LagManager lagManager
+
+	// Manager for beacons derived from commit proofs,
+	// to inform the driver after each commit.
+	BeaconManager beaconManager
+
+	// Manager for safety violations,
+	// to inform the driver of conflicting commits at the committing height.
+	SafetyViolationManager safetyViolationManager
+
+	// If the kernel has a late vote grace period,
+	// the voting view most recently abandoned by a round advance,
+	// so that votes for that round arriving shortly after the advance
+	// are still recorded.
+	// Orphaned is only meaningful while OrphanedTimer is non-nil.
+	Orphaned      tmconsensus.VersionedRoundView
+	OrphanedTimer *time.Timer
 }
 
 // FindView finds the view in s matching the given height and round,
@@ -75,6 +104,8 @@ func (s *kState) FindView(h uint64, r uint32, reason string) (*tmconsensus.Versi
 		if r < cr {
 			return nil, 0, ViewBeforeCommitting
 		}
+
+		return nil, 0, ViewWrongCommit
 	}
 
 	if h < s.Committing.Height {
@@ -92,6 +123,72 @@ func (s *kState) FindView(h uint64, r uint32, reason string) (*tmconsensus.Versi
 	))
 }
 
+// FutureRoundView returns a view for round r at the voting height,
+// holding the proposed headers in s.FutureRoundPHs for that round.
+// The view only has the fields needed to check an incoming proposed header;
+// it is not suitable to share outside the kernel.
+func (s *kState) FutureRoundView(r uint32) tmconsensus.VersionedRoundView {
+	return tmconsensus.VersionedRoundView{
+		RoundView: tmconsensus.RoundView{
+			Height: s.Voting.Height,
+			Round:  r,
+
+			ValidatorSet: s.Voting.ValidatorSet,
+
+			ProposedHeaders: s.FutureRoundPHs[r],
+		},
+	}
+}
+
+// FindVoteView is like FindView,
+// but if the requested round was orphaned by a recent round advance
+// and s still holds that orphaned view,
+// it returns the orphaned view.
+// It must only be used when handling incoming votes.
+func (s *kState) FindVoteView(h uint64, r uint32, reason string) (*tmconsensus.VersionedRoundView, ViewID, ViewLookupStatus) {
+	vrv, id, status := s.FindView(h, r, reason)
+	if status == ViewOrphaned && s.OrphanedTimer != nil &&
+		s.Orphaned.Height == h && s.Orphaned.Round == r {
+		return &s.Orphaned, ViewIDOrphaned, ViewFound
+	}
+	return vrv, id, status
+}
+
+// OrphanVotingView keeps a copy of s's voting view as the orphaned view
+// for the given duration.
+// It must be called immediately before advancing the voting round.
+// Any previously orphaned view is replaced.
+func (s *kState) OrphanVotingView(d time.Duration) {
+	s.ClearOrphaned()
+
+	s.Orphaned = s.Voting.Clone()
+	if s.Orphaned.PrevoteProofs == nil {
+		s.Orphaned.PrevoteProofs = make(map[string]gcrypto.CommonMessageSignatureProof)
+	}
+	if s.Orphaned.PrecommitProofs == nil {
+		s.Orphaned.PrecommitProofs = make(map[string]gcrypto.CommonMessageSignatureProof)
+	}
+	s.OrphanedTimer = time.NewTimer(d)
+}
+
+// OrphanedTimerC returns the channel of s.OrphanedTimer,
+// or nil if there is no orphaned view.
+func (s *kState) OrphanedTimerC() <-chan time.Time {
+	if s.OrphanedTimer == nil {
+		return nil
+	}
+	return s.OrphanedTimer.C
+}
+
+// ClearOrphaned discards s's orphaned view, if any.
+func (s *kState) ClearOrphaned() {
+	if s.OrphanedTimer != nil {
+		s.OrphanedTimer.Stop()
+		s.OrphanedTimer = nil
+	}
+	s.Orphaned = tmconsensus.VersionedRoundView{}
+}
+
 // MarkCommittingViewUpdated increments the version of s's committing view,
 // and informs s's view managers that the Voting view
 // has updates that need to be propagated.
@@ -106,8 +203,11 @@ func (s *kState) MarkCommittingViewUpdated() {
 	// The state machine view only needs updated if synchronized with the voting view.
 	if smh == s.Committing.Height && smr == s.Committing.Round {
 		s.StateMachineViewManager.SetView(s.Committing)
-	} else if (smh < s.Committing.Height) ||
-		(smh == s.Committing.Height && smr < s.Committing.Round) {
+	} else if smh == s.Committing.Height && smr < s.Committing.Round {
+		// The state machine can only jump ahead within its current height;
+		// it treats a jump to a different height as a bug.
+		// If it is on an earlier height, it catches up through its next round entrance instead,
+		// which reports the height as already committed.
 		s.StateMachineViewManager.JumpToRound(s.Committing)
 	}
 }
@@ -149,6 +249,9 @@ func (s *kState) MarkViewUpdated(id ViewID) {
 		s.MarkVotingViewUpdated()
 	case ViewIDNextRound:
 		s.MarkNextRoundViewUpdated()
+	case ViewIDOrphaned:
+		// The orphaned view is not shared with the state machine or gossip strategy.
+		s.Orphaned.Version++
 	default:
 		panic(fmt.Errorf("TODO: MarkViewUpdated: handle id %s", id))
 	}
@@ -159,6 +262,10 @@ type nextHeightDetails struct {
 
 	VotedHeader tmconsensus.Header
 
+	// If Proofs is non-nil, CommitProof is used as the next height's previous commit proof,
+	// instead of a proof built from every precommit in the committing view.
+	CommitProof tmconsensus.CommitProof
+
 	Round0NilPrevote, Round0NilPrecommit,
 	Round1NilPrevote, Round1NilPrecommit gcrypto.CommonMessageSignatureProof
 }
@@ -179,9 +286,17 @@ func (s *kState) ShiftVotingToCommitting(nhd nextHeightDetails) {
 
 	newHeight := s.Voting.Height + 1
 
-	commitProofs := make(map[string][]gcrypto.SparseSignature, len(s.Committing.PrecommitProofs))
-	for hash, proof := range s.Committing.PrecommitProofs {
-		commitProofs[hash] = proof.AsSparse().Signatures
+	commitProof := nhd.CommitProof
+	if commitProof.Proofs == nil {
+		commitProofs := make(map[string][]gcrypto.SparseSignature, len(s.Committing.PrecommitProofs))
+		for hash, proof := range s.Committing.PrecommitProofs {
+			commitProofs[hash] = proof.AsSparse().Signatures
+		}
+		commitProof = tmconsensus.CommitProof{
+			Round:      s.Committing.Round,
+			PubKeyHash: string(s.Committing.ValidatorSet.PubKeyHash),
+			Proofs:     commitProofs,
+		}
 	}
 
 	// If we had NextHeight, we might use that here.
@@ -193,11 +308,7 @@ func (s *kState) ShiftVotingToCommitting(nhd nextHeightDetails) {
 
 			ValidatorSet: nhd.ValidatorSet,
 
-			PrevCommitProof: tmconsensus.CommitProof{
-				Round:      s.Committing.Round,
-				PubKeyHash: string(s.Committing.ValidatorSet.PubKeyHash),
-				Proofs:     commitProofs,
-			},
+			PrevCommitProof: commitProof,
 
 			// Empty but not nil maps.
 			PrevoteProofs:   map[string]gcrypto.CommonMessageSignatureProof{},
@@ -225,6 +336,9 @@ func (s *kState) ShiftVotingToCommitting(nhd nextHeightDetails) {
 
 	s.MarkNextRoundViewUpdated()
 
+	// Any held headers were for rounds at the height that was just committed.
+	clear(s.FutureRoundPHs)
+
 	s.CommittingHeader = nhd.VotedHeader
 
 	// As mentioned at the top,
@@ -275,5 +389,10 @@ func (s *kState) incrementVotingRound() {
 	s.NextRound.ResetForSameHeight()
 	s.NextRound.Round = s.Voting.Round + 1
 
+	if phs, ok := s.FutureRoundPHs[s.NextRound.Round]; ok {
+		s.NextRound.ProposedHeaders = phs
+		delete(s.FutureRoundPHs, s.NextRound.Round)
+	}
+
 	s.MarkNextRoundViewUpdated()
 }
@@ -1,6 +1,8 @@
 package tmi
 
 import (
+	"time"
+
 	"github.com/gordian-engine/gordian/gcrypto"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
 )
@@ -30,6 +32,11 @@ type PHCheckResponse struct {
 	// This value must also not be modified.
 	PrevValidatorSet tmconsensus.ValidatorSet
 
+	// The timestamp of the header matching PrevBlockHash.
+	// Zero if that header has no timestamp,
+	// or if the proposed header is for the committing height.
+	PrevTimestamp time.Time
+
 	// If the status is PHCheckNextHeight, this is a clone of the voting view.
 	VotingRoundView *tmconsensus.RoundView
 }
@@ -60,6 +67,15 @@ const (
 	// but the reported proposer public key did not match the known validators for that height.
 	PHCheckSignerUnrecognized
 
+	// The proposer public key matched a known validator,
+	// but not the expected proposer for the header's height and round.
+	// Only reported when the kernel is configured with RequireExpectedProposer.
+	PHCheckUnexpectedProposer
+
+	// The proposer already has the maximum number of proposed headers in this round.
+	// Only reported when the kernel is configured with MaxHeadersPerProposerPerRound.
+	PHCheckProposerQuotaExceeded
+
 	// The proposed header references an out-of-bounds round that is too old.
 	PHCheckRoundTooOld
 
@@ -13,13 +13,15 @@ func _() {
 	_ = x[PHCheckNextHeight-2]
 	_ = x[PHCheckAlreadyHaveSignature-3]
 	_ = x[PHCheckSignerUnrecognized-4]
-	_ = x[PHCheckRoundTooOld-5]
-	_ = x[PHCheckRoundTooFarInFuture-6]
+	_ = x[PHCheckUnexpectedProposer-5]
+	_ = x[PHCheckProposerQuotaExceeded-6]
+	_ = x[PHCheckRoundTooOld-7]
+	_ = x[PHCheckRoundTooFarInFuture-8]
 }
 
-const _PHCheckStatus_name = "InvalidAcceptableNextHeightAlreadyHaveSignatureSignerUnrecognizedRoundTooOldRoundTooFarInFuture"
+const _PHCheckStatus_name = "InvalidAcceptableNextHeightAlreadyHaveSignatureSignerUnrecognizedUnexpectedProposerProposerQuotaExceededRoundTooOldRoundTooFarInFuture"
 
-var _PHCheckStatus_index = [...]uint8{0, 7, 17, 27, 47, 65, 76, 95}
+var _PHCheckStatus_index = [...]uint8{0, 7, 17, 27, 47, 65, 83, 104, 115, 134}
 
 func (i PHCheckStatus) String() string {
 	if i >= PHCheckStatus(len(_PHCheckStatus_index)-1) {
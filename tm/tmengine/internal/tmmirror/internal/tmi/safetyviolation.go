@@ -0,0 +1,91 @@
+package tmi
+
+import (
+	"github.com/gordian-engine/gordian/tm/tmengine/tmelink"
+)
+
+// safetyViolationManager holds the safety violations
+// that have not yet been sent to the driver.
+//
+// Like the beacon manager, every violation must be delivered,
+// so pending violations are queued until the driver reads them.
+// The manager also remembers which conflicts have been reported,
+// so that additional precommits for an already-reported block
+// do not cause the same violation to be reported repeatedly.
+// Each remembered conflict requires validators with more than one third of the voting power
+// to have signed conflicting precommits, so the set of reported conflicts stays small.
+type safetyViolationManager struct {
+	outCh chan<- tmelink.SafetyViolation
+
+	pending []tmelink.SafetyViolation
+
+	reported map[safetyViolationKey]struct{}
+}
+
+// safetyViolationKey identifies a conflicting commit,
+// for deduplicating reports in the safetyViolationManager.
+type safetyViolationKey struct {
+	Height           uint64
+	ConflictingRound uint32
+	ConflictingHash  string
+}
+
+func newSafetyViolationManager(out chan<- tmelink.SafetyViolation) safetyViolationManager {
+	return safetyViolationManager{outCh: out}
+}
+
+// Add queues v to be sent to the output channel,
+// unless a violation for the same height, conflicting round, and conflicting block hash
+// was already added.
+// It reports whether v was new.
+// If no output channel was configured, v is tracked but not queued.
+func (m *safetyViolationManager) Add(v tmelink.SafetyViolation) (added bool) {
+	key := safetyViolationKey{
+		Height:           v.Height,
+		ConflictingRound: v.ConflictingRound,
+		ConflictingHash:  string(v.ConflictingBlockHash),
+	}
+	if _, ok := m.reported[key]; ok {
+		return false
+	}
+
+	if m.reported == nil {
+		m.reported = make(map[safetyViolationKey]struct{})
+	}
+	m.reported[key] = struct{}{}
+
+	if m.outCh != nil {
+		m.pending = append(m.pending, v)
+	}
+	return true
+}
+
+// Output returns a SafetyViolationOutput,
+// containing a destination channel and the oldest pending SafetyViolation to send.
+//
+// If there are no pending violations,
+// the output channel is nil, so the send will block forever.
+func (m *safetyViolationManager) Output() SafetyViolationOutput {
+	if m.outCh == nil || len(m.pending) == 0 {
+		return SafetyViolationOutput{}
+	}
+
+	return SafetyViolationOutput{
+		m:   m,
+		Ch:  m.outCh,
+		Val: m.pending[0],
+	}
+}
+
+// MarkSent must be called after a successful send of o.Val to o.Ch.
+func (o SafetyViolationOutput) MarkSent() {
+	o.m.pending[0] = tmelink.SafetyViolation{}
+	o.m.pending = o.m.pending[1:]
+}
+
+// SafetyViolationOutput is the value returned by [*safetyViolationManager.Output].
+type SafetyViolationOutput struct {
+	m   *safetyViolationManager
+	Ch  chan<- tmelink.SafetyViolation
+	Val tmelink.SafetyViolation
+}
@@ -0,0 +1,18 @@
+package tmi
+
+import "github.com/gordian-engine/gordian/tm/tmconsensus"
+
+// TrustedHeadersRequest is a request for the kernel to apply
+// a sequence of committed headers obtained from a trusted source,
+// such as a state sync snapshot,
+// advancing the voting and committing views without fetching proposed headers.
+type TrustedHeadersRequest struct {
+	// Headers in ascending height order.
+	// Headers below the current voting height are ignored.
+	Headers []tmconsensus.CommittedHeader
+
+	// Must be 1-buffered.
+	// Receives nil if every header was applied,
+	// otherwise the first error encountered.
+	Resp chan error
+}
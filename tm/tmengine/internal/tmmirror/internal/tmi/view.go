@@ -15,6 +15,10 @@ const (
 	ViewIDCommitting
 	ViewIDNextRound
 	ViewIDNextHeight
+
+	// A voting round that was recently abandoned,
+	// kept only to record late votes.
+	ViewIDOrphaned
 )
 
 // View holds a maintained round view and associated metadata.
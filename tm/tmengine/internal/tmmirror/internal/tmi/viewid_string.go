@@ -13,11 +13,12 @@ func _() {
 	_ = x[ViewIDCommitting-2]
 	_ = x[ViewIDNextRound-3]
 	_ = x[ViewIDNextHeight-4]
+	_ = x[ViewIDOrphaned-5]
 }
 
-const _ViewID_name = "NotFoundVotingCommittingNextRoundNextHeight"
+const _ViewID_name = "NotFoundVotingCommittingNextRoundNextHeightOrphaned"
 
-var _ViewID_index = [...]uint8{0, 8, 14, 24, 33, 43}
+var _ViewID_index = [...]uint8{0, 8, 14, 24, 33, 43, 51}
 
 func (i ViewID) String() string {
 	if i >= ViewID(len(_ViewID_index)-1) {
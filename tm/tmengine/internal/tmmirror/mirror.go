@@ -3,10 +3,15 @@ package tmmirror
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"runtime/trace"
+	"slices"
+	"sync/atomic"
+	"time"
 
+	"github.com/bits-and-blooms/bitset"
 	"github.com/gordian-engine/gordian/gassert"
 	"github.com/gordian-engine/gordian/gcrypto"
 	"github.com/gordian-engine/gordian/gwatchdog"
@@ -33,15 +38,49 @@ type Mirror struct {
 	sigScheme  tmconsensus.SignatureScheme
 	cmspScheme gcrypto.CommonMessageSignatureProofScheme
 
+	// The signature length reported by the signature scheme,
+	// only meaningful when fixedSigLen is true.
+	sigLen      int
+	fixedSigLen bool
+
 	snapshotRequests   chan<- tmi.SnapshotRequest
 	viewLookupRequests chan<- tmi.ViewLookupRequest
 
 	phCheckRequests chan<- tmi.PHCheckRequest
 
+	// Non-nil when MirrorConfig.PublishSnapshots is set.
+	publishedSnapshot *atomic.Pointer[tmi.Snapshot]
+
 	addPHRequests        chan<- tmconsensus.ProposedHeader
 	addPrevoteRequests   chan<- tmi.AddPrevoteRequest
 	addPrecommitRequests chan<- tmi.AddPrecommitRequest
 
+	// Nil unless MirrorConfig.AllowTrustedHeaderIngestion is set.
+	trustedHeadersRequests chan<- tmi.TrustedHeadersRequest
+
+	// Used to look up committed headers
+	// when checking precommits for heights the kernel has already committed.
+	hStore tmstore.CommittedHeaderStore
+
+	// Nil unless MirrorConfig.SafetyViolationOut is set.
+	safetyViolationReports chan<- tmelink.SafetyViolation
+
+	// Whether to check proposed header timestamps,
+	// and how far ahead of the local clock they may be.
+	validateHeaderTimestamps      bool
+	headerTimestampMaxFutureDrift time.Duration
+
+	// If positive, the maximum value of [tmconsensus.ProposedHeader.AnnotationBytes]
+	// for an incoming proposed header.
+	maxAnnotationBytes int
+
+	// If non-nil, called on every incoming proposed header before verification.
+	incomingHeaderPrefilter func(tmconsensus.ProposedHeader) bool
+
+	// Whether new vote proofs may only be created
+	// for the nil block or a known proposed header.
+	requireKnownVoteBlockHash bool
+
 	assertEnv gassert.Env
 }
 
@@ -55,15 +94,126 @@ type MirrorConfig struct {
 	InitialHeight       uint64
 	InitialValidatorSet tmconsensus.ValidatorSet
 
+	// The height of the chain's first block, if different from InitialHeight,
+	// such as when the mirror is started from a state sync.
+	// Zero means the same as InitialHeight.
+	// Only proposed headers at the genesis height skip verification of their previous commit proof;
+	// when starting above the genesis height, the CommittedHeaderStore must contain
+	// the header at InitialHeight-1.
+	GenesisHeight uint64
+
 	HashScheme                        tmconsensus.HashScheme
 	SignatureScheme                   tmconsensus.SignatureScheme
 	CommonMessageSignatureProofScheme gcrypto.CommonMessageSignatureProofScheme
 
-	ProposedHeaderFetcher tmelink.ProposedHeaderFetcher
+	ProposedHeaderFetcher    tmelink.ProposedHeaderFetcher
+	FetchedHeaderBufferLimit int
+
+	// If set, reject proposed headers not signed by
+	// the [tmconsensus.ExpectedProposer] for their height and round.
+	RequireExpectedProposer bool
+
+	// If positive, reject proposed headers from a proposer
+	// who already has this many proposed headers in the same round.
+	MaxHeadersPerProposerPerRound int
+
+	// If greater than one, accept proposed headers at the voting height
+	// for up to this many rounds beyond the voting round,
+	// holding them until the voting round catches up.
+	// Otherwise, only headers for the voting round and the next round are accepted.
+	// Headers for later rounds are rejected as
+	// [tmconsensus.HandleProposedHeaderRoundTooFarInFuture].
+	MaxFutureRoundForHeader uint32
+
+	// If set, reject proposed headers whose timestamp
+	// fails [tmconsensus.ValidateHeaderTimestamp],
+	// allowing timestamps up to HeaderTimestampMaxFutureDrift
+	// ahead of the local clock.
+	ValidateHeaderTimestamps      bool
+	HeaderTimestampMaxFutureDrift time.Duration
+
+	// If positive, reject proposed headers whose combined annotations,
+	// as reported by [tmconsensus.ProposedHeader.AnnotationBytes],
+	// are larger than MaxAnnotationBytes.
+	MaxAnnotationBytes int
+
+	// If set, called on every incoming proposed header
+	// before its hash or signature is verified,
+	// and before the kernel is consulted.
+	// Headers for which it returns false are dropped
+	// as [tmconsensus.HandleProposedHeaderPrefiltered].
+	// Because the header is unverified,
+	// the prefilter must only be used to cheaply drop unwanted headers,
+	// such as those claiming a denylisted proposer;
+	// it must not treat any field of the header as trustworthy.
+	// It is called concurrently from every caller of [Mirror.HandleProposedHeader].
+	IncomingHeaderPrefilter func(ph tmconsensus.ProposedHeader) bool
+
+	// If set, incoming prevotes and precommits for a block hash
+	// are only accepted if the hash is empty (a vote for nil)
+	// or matches a proposed header in the vote's round,
+	// or if there is already a proof for that block hash.
+	// Otherwise, any validator could cause the mirror to create
+	// proofs for arbitrary block hashes.
+	RequireKnownVoteBlockHash bool
+
+	// If set, when a block is committed,
+	// the commit proof stored alongside it and carried into the next height
+	// only contains the precommits for the committed block
+	// from the fewest lowest-index signers whose power reaches a majority,
+	// rather than every precommit the mirror has seen.
+	MinimizeCommitProof bool
+
+	// If positive, votes from validators whose power is below this floor
+	// are not forwarded to the gossip strategy,
+	// reducing the gossip generated by validators with little influence.
+	// The mirror still counts those votes, including in commit proofs.
+	//
+	// Signatures are filtered individually,
+	// so with an aggregating signature scheme,
+	// an aggregated signature that includes any validator at or above the floor
+	// is still forwarded.
+	MinVotePowerToGossip uint64
+
+	// If positive, when the voting round advances,
+	// the mirror keeps the abandoned round's view for this long,
+	// so that prevotes and precommits for that round
+	// which were in flight during the advance
+	// are still recorded in the round store, for accountability.
+	// Late votes do not affect the current voting round,
+	// and they are not forwarded to the gossip strategy.
+	LateVoteGracePeriod time.Duration
+
+	// If set, the kernel publishes a copy of its voting and committing views
+	// each time either view changes,
+	// and [Mirror.VotingView] and [Mirror.CommittingView] read the published copy
+	// instead of making a request to the kernel.
+	// This allows many concurrent readers without contending for the kernel,
+	// at the cost of a copy of both views upon every change,
+	// and of the readers possibly observing a view that is slightly behind the kernel.
+	PublishSnapshots bool
+
+	// If set, [Mirror.IngestTrustedHeaders] may be used
+	// to apply committed headers from a source the caller already trusts,
+	// such as a state sync provider,
+	// without fetching the corresponding proposed headers
+	// and without verifying their commit proof signatures.
+	AllowTrustedHeaderIngestion bool
 
 	ReplayedHeadersIn <-chan tmelink.ReplayedHeaderRequest
 	GossipStrategyOut chan<- tmelink.NetworkViewUpdate
 	LagStateOut       chan<- tmelink.LagState
+	BeaconOut         chan<- tmelink.Beacon
+
+	// If set, the mirror sends a SafetyViolation on this channel
+	// when it receives valid precommits with majority power
+	// for a block other than the one it has committed at the same height.
+	// Each conflicting block is reported at most once per round.
+	//
+	// Precommits for the committing round are checked as they are added to the committing view.
+	// Precommits for other rounds at the committing height, or for earlier heights,
+	// are checked against the committed header in the CommittedHeaderStore.
+	SafetyViolationOut chan<- tmelink.SafetyViolation
 
 	StateMachineRoundEntranceIn <-chan tmeil.StateMachineRoundEntrance
 	StateMachineRoundViewOut    chan<- tmeil.StateMachineRoundView
@@ -72,6 +222,10 @@ type MirrorConfig struct {
 
 	Watchdog *gwatchdog.Watchdog
 
+	// Controls how often the watchdog checks the mirror kernel's main loop.
+	// The zero value uses the defaults in [tmeil.WatchdogHeartbeat].
+	WatchdogHeartbeat tmeil.WatchdogHeartbeat
+
 	AssertEnv gassert.Env
 }
 
@@ -89,19 +243,37 @@ func (c MirrorConfig) toKernelConfig() tmi.KernelConfig {
 
 		InitialHeight:       c.InitialHeight,
 		InitialValidatorSet: c.InitialValidatorSet,
+		GenesisHeight:       c.GenesisHeight,
+
+		ProposedHeaderFetcher:    c.ProposedHeaderFetcher,
+		FetchedHeaderBufferLimit: c.FetchedHeaderBufferLimit,
+
+		RequireExpectedProposer: c.RequireExpectedProposer,
+
+		MaxHeadersPerProposerPerRound: c.MaxHeadersPerProposerPerRound,
+
+		MaxFutureRoundForHeader: c.MaxFutureRoundForHeader,
 
-		ProposedHeaderFetcher: c.ProposedHeaderFetcher,
+		MinimizeCommitProof: c.MinimizeCommitProof,
+
+		MinVotePowerToGossip: c.MinVotePowerToGossip,
+
+		LateVoteGracePeriod: c.LateVoteGracePeriod,
 
 		ReplayedHeadersIn: c.ReplayedHeadersIn,
 		GossipStrategyOut: c.GossipStrategyOut,
 		LagStateOut:       c.LagStateOut,
+		BeaconOut:         c.BeaconOut,
+
+		SafetyViolationOut: c.SafetyViolationOut,
 
 		StateMachineRoundEntranceIn: c.StateMachineRoundEntranceIn,
 		StateMachineRoundViewOut:    c.StateMachineRoundViewOut,
 
 		MetricsCollector: c.MetricsCollector,
 
-		Watchdog: c.Watchdog,
+		Watchdog:          c.Watchdog,
+		WatchdogHeartbeat: c.WatchdogHeartbeat,
 
 		AssertEnv: c.AssertEnv,
 	}
@@ -142,12 +314,35 @@ func NewMirror(
 	kCfg.AddPrevoteRequests = addPrevoteRequests
 	kCfg.AddPrecommitRequests = addPrecommitRequests
 
+	// Same as the vote requests, the caller blocks on the response.
+	var trustedHeadersRequests chan tmi.TrustedHeadersRequest
+	if cfg.AllowTrustedHeaderIngestion {
+		trustedHeadersRequests = make(chan tmi.TrustedHeadersRequest)
+		kCfg.TrustedHeadersRequests = trustedHeadersRequests
+	}
+
+	// Only the mirror reports to the kernel,
+	// and the kernel handles each report quickly.
+	var safetyViolationReports chan tmelink.SafetyViolation
+	if cfg.SafetyViolationOut != nil {
+		safetyViolationReports = make(chan tmelink.SafetyViolation, 1)
+		kCfg.SafetyViolationReports = safetyViolationReports
+	}
+
+	var publishedSnapshot *atomic.Pointer[tmi.Snapshot]
+	if cfg.PublishSnapshots {
+		publishedSnapshot = new(atomic.Pointer[tmi.Snapshot])
+		kCfg.PublishedSnapshot = publishedSnapshot
+	}
+
 	k, err := tmi.NewKernel(ctx, log.With("m_sys", "kernel"), kCfg)
 	if err != nil {
 		// Assuming the error format doesn't need additional detail.
 		return nil, err
 	}
 
+	sigLen, fixedSigLen := cfg.SignatureScheme.SignatureLen()
+
 	m := &Mirror{
 		log: log,
 
@@ -159,13 +354,33 @@ func NewMirror(
 		sigScheme:  cfg.SignatureScheme,
 		cmspScheme: cfg.CommonMessageSignatureProofScheme,
 
+		sigLen:      sigLen,
+		fixedSigLen: fixedSigLen,
+
 		snapshotRequests:   snapshotRequests,
 		viewLookupRequests: viewLookupRequests,
 		phCheckRequests:    phCheckRequests,
 
+		publishedSnapshot: publishedSnapshot,
+
 		addPHRequests:        addPHRequests,
 		addPrevoteRequests:   addPrevoteRequests,
 		addPrecommitRequests: addPrecommitRequests,
+
+		trustedHeadersRequests: trustedHeadersRequests,
+
+		hStore: cfg.CommittedHeaderStore,
+
+		safetyViolationReports: safetyViolationReports,
+
+		validateHeaderTimestamps:      cfg.ValidateHeaderTimestamps,
+		headerTimestampMaxFutureDrift: cfg.HeaderTimestampMaxFutureDrift,
+
+		maxAnnotationBytes: cfg.MaxAnnotationBytes,
+
+		incomingHeaderPrefilter: cfg.IncomingHeaderPrefilter,
+
+		requireKnownVoteBlockHash: cfg.RequireKnownVoteBlockHash,
 	}
 
 	return m, nil
@@ -198,6 +413,39 @@ type NetworkHeightRound = tmi.NetworkHeightRound
 func (m *Mirror) HandleProposedHeader(ctx context.Context, ph tmconsensus.ProposedHeader) tmconsensus.HandleProposedHeaderResult {
 	defer trace.StartRegion(ctx, "HandleProposedHeader").End()
 
+	// The prefilter is meant to be cheaper than any of our own checks,
+	// so it runs first.
+	if m.incomingHeaderPrefilter != nil && !m.incomingHeaderPrefilter(ph) {
+		m.log.Debug(
+			"Dropping proposed header rejected by prefilter",
+			"height", ph.Header.Height, "round", ph.Round,
+		)
+		return tmconsensus.HandleProposedHeaderPrefiltered
+	}
+
+	// The annotation size only depends on the header itself,
+	// so check it before involving the kernel.
+	if m.maxAnnotationBytes > 0 && ph.AnnotationBytes() > m.maxAnnotationBytes {
+		m.log.Debug(
+			"Rejecting proposed header with oversized annotations",
+			"height", ph.Header.Height, "round", ph.Round,
+			"annotation_bytes", ph.AnnotationBytes(),
+			"max_annotation_bytes", m.maxAnnotationBytes,
+		)
+		return tmconsensus.HandleProposedHeaderAnnotationsTooLarge
+	}
+
+	// A signature of the wrong length cannot possibly verify,
+	// so reject it without a round trip to the kernel.
+	if m.fixedSigLen && len(ph.Signature) != m.sigLen {
+		m.log.Debug(
+			"Rejecting proposed header with wrong signature length",
+			"height", ph.Header.Height, "round", ph.Round,
+			"sig_len", len(ph.Signature), "want_sig_len", m.sigLen,
+		)
+		return tmconsensus.HandleProposedHeaderBadSignature
+	}
+
 RESTART:
 	req := tmi.PHCheckRequest{
 		PH:   ph,
@@ -230,6 +478,10 @@ RESTART:
 	case tmi.PHCheckSignerUnrecognized:
 		// Cannot continue.
 		return tmconsensus.HandleProposedHeaderSignerUnrecognized
+	case tmi.PHCheckUnexpectedProposer:
+		return tmconsensus.HandleProposedHeaderUnexpectedProposer
+	case tmi.PHCheckProposerQuotaExceeded:
+		return tmconsensus.HandleProposedHeaderProposerQuotaExceeded
 	case tmi.PHCheckNextHeight:
 		// Special case: we make an additional request to the kernel if the PH is for the next height.
 		m.backfillCommitForNextHeightPE(ctx, req.PH)
@@ -255,7 +507,7 @@ RESTART:
 	}
 
 	// Validate the signature based on the public key the kernel reported.
-	signContent, err := tmconsensus.ProposalSignBytes(ph.Header, ph.Round, ph.Annotations, m.sigScheme)
+	signContent, err := ph.SignBytes(m.sigScheme)
 	if err != nil {
 		return tmconsensus.HandleProposedHeaderInternalError
 	}
@@ -263,6 +515,19 @@ RESTART:
 		return tmconsensus.HandleProposedHeaderBadSignature
 	}
 
+	if m.validateHeaderTimestamps {
+		if err := tmconsensus.ValidateHeaderTimestamp(
+			ph.Header, checkResp.PrevTimestamp, time.Now(), m.headerTimestampMaxFutureDrift,
+		); err != nil {
+			m.log.Debug(
+				"Rejecting proposed header with invalid timestamp",
+				"height", ph.Header.Height, "round", ph.Round,
+				"err", err,
+			)
+			return tmconsensus.HandleProposedHeaderBadTimestamp
+		}
+	}
+
 	// Now, make sure that the proposed header's PrevCommitProof matches
 	// what we think the previous commit is supposed to be.
 	// The easiest thing to check first is the validator hash.
@@ -352,14 +617,19 @@ func (m *Mirror) backfillCommitForNextHeightPE(
 ) backfillCommitStatus {
 	defer trace.StartRegion(ctx, "backfillCommitForNextHeightPE").End()
 
-	res := m.handlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+	p := tmconsensus.PrecommitSparseProof{
 		Height: ph.Header.Height - 1,
 		Round:  ph.Header.PrevCommitProof.Round,
 
 		PubKeyHash: ph.Header.PrevCommitProof.PubKeyHash,
 
 		Proofs: ph.Header.PrevCommitProof.Proofs,
-	}, "(*Mirror).backfillCommitForNextHeightPE")
+	}
+
+	// We may not have seen the proposed header for the previous height,
+	// so never require a known block hash when backfilling;
+	// otherwise we would be unable to accept this proposed header.
+	res := m.handlePrecommitProofs(ctx, p, false, "(*Mirror).backfillCommitForNextHeightPE", nil)
 
 	if res != tmconsensus.HandleVoteProofsAccepted {
 		return backfillCommitRejected
@@ -368,7 +638,27 @@ func (m *Mirror) backfillCommitForNextHeightPE(
 	return backfillCommitAccepted
 }
 
+// VoteProofsReport describes signatures that were discarded while handling vote proofs,
+// which is not conveyed by the [tmconsensus.HandleVoteProofsResult];
+// valid new signatures in the same message are still accepted.
+type VoteProofsReport struct {
+	// At least one signature was discarded without verification,
+	// because its key ID or its length could not be valid for the validator set.
+	Malformed bool
+
+	// At least one signature failed verification.
+	InvalidSignature bool
+}
+
 func (m *Mirror) HandlePrevoteProofs(ctx context.Context, p tmconsensus.PrevoteSparseProof) tmconsensus.HandleVoteProofsResult {
+	return m.HandlePrevoteProofsWithReport(ctx, p, nil)
+}
+
+// HandlePrevoteProofsWithReport is like [*Mirror.HandlePrevoteProofs],
+// but if rep is not nil, it is also populated with details about discarded signatures.
+func (m *Mirror) HandlePrevoteProofsWithReport(
+	ctx context.Context, p tmconsensus.PrevoteSparseProof, rep *VoteProofsReport,
+) tmconsensus.HandleVoteProofsResult {
 	defer trace.StartRegion(ctx, "HandlePrevoteProofs").End()
 
 	// NOTE: keep changes to this method synchronized with handlePrecommitProofs --
@@ -381,6 +671,11 @@ func (m *Mirror) HandlePrevoteProofs(ctx context.Context, p tmconsensus.PrevoteS
 
 	try := 1
 
+	fields := tmi.RVValidators | tmi.RVPrevotes
+	if m.requireKnownVoteBlockHash {
+		fields |= tmi.RVProposedBlocks
+	}
+
 	var curPrevoteState tmconsensus.VersionedRoundView
 	vlReq := tmi.ViewLookupRequest{
 		H: p.Height,
@@ -388,7 +683,7 @@ func (m *Mirror) HandlePrevoteProofs(ctx context.Context, p tmconsensus.PrevoteS
 
 		VRV: &curPrevoteState,
 
-		Fields: tmi.RVValidators | tmi.RVPrevotes,
+		Fields: fields,
 
 		Reason: "(*Mirror).HandlePrevoteProofs",
 
@@ -406,13 +701,20 @@ RETRY:
 		return tmconsensus.HandleVoteProofsInternalError
 	}
 
-	if vlResp.Status != tmi.ViewFound {
-		// TODO: consider future view.
-		// TODO: this return value is not quite right.
+	switch vlResp.Status {
+	case tmi.ViewFound:
+		// Okay.
+	case tmi.ViewLaterVotingRound, tmi.ViewFuture:
+		// Votes beyond our next round are not buffered,
+		// so there is nothing to expire if that round never materializes.
+		// The sender is expected to continue gossiping its votes,
+		// so we will see them again if we catch up.
+		return tmconsensus.HandleVoteProofsTooFarInFuture
+	default:
 		return tmconsensus.HandleVoteProofsRoundTooOld
 	}
 	switch vlResp.ID {
-	case tmi.ViewIDVoting, tmi.ViewIDCommitting, tmi.ViewIDNextRound:
+	case tmi.ViewIDVoting, tmi.ViewIDCommitting, tmi.ViewIDNextRound, tmi.ViewIDOrphaned:
 		// Okay.
 	default:
 		panic(fmt.Errorf(
@@ -429,7 +731,10 @@ RETRY:
 	}
 
 	curProofs := curPrevoteState.PrevoteProofs
-	sigsToAdd := m.getSignaturesToAdd(curProofs, p.Proofs, vlReq.VRV.ValidatorSet)
+	sigsToAdd, sawMalformed := m.getSignaturesToAdd(curProofs, p.Proofs, vlReq.VRV.ValidatorSet)
+	if sawMalformed && rep != nil {
+		rep.Malformed = true
+	}
 
 	if len(sigsToAdd) == 0 {
 		// Maybe the message had some valid signatures.
@@ -441,9 +746,16 @@ RETRY:
 	// Attempt to add it here, so we avoid doing unnecessary work in the kernel.
 	voteUpdates := make(map[string]tmi.VoteUpdate, len(sigsToAdd))
 	allValidSignatures := true
+	sawUnknownBlockHash := false
 	for blockHash, sigs := range sigsToAdd {
 		fullProof, ok := curProofs[blockHash]
 		if !ok {
+			if m.requireKnownVoteBlockHash &&
+				!isKnownVoteBlockHash(blockHash, curPrevoteState.ProposedHeaders) {
+				sawUnknownBlockHash = true
+				continue
+			}
+
 			emptyProof, ok := m.makeNewPrevoteProof(
 				p.Height, p.Round, blockHash, curPrevoteState.ValidatorSet,
 			)
@@ -460,6 +772,9 @@ RETRY:
 		}
 		res := fullProof.MergeSparse(sparseProof)
 		allValidSignatures = allValidSignatures && res.AllValidSignatures
+		if !res.AllValidSignatures && rep != nil {
+			rep.InvalidSignature = true
+		}
 		voteUpdates[blockHash] = tmi.VoteUpdate{
 			Proof:       fullProof,
 			PrevVersion: curPrevoteState.PrevoteBlockVersions[blockHash],
@@ -467,6 +782,10 @@ RETRY:
 	}
 
 	if len(voteUpdates) == 0 {
+		if sawUnknownBlockHash {
+			return tmconsensus.HandleVoteProofsUnknownBlockHash
+		}
+
 		// We must have been unable to build the sign bytes or signature proof.
 		// Ignore the message for now.
 		return tmconsensus.HandleVoteProofsNoNewSignatures
@@ -523,9 +842,17 @@ RETRY:
 }
 
 func (m *Mirror) HandlePrecommitProofs(ctx context.Context, p tmconsensus.PrecommitSparseProof) tmconsensus.HandleVoteProofsResult {
+	return m.HandlePrecommitProofsWithReport(ctx, p, nil)
+}
+
+// HandlePrecommitProofsWithReport is like [*Mirror.HandlePrecommitProofs],
+// but if rep is not nil, it is also populated with details about discarded signatures.
+func (m *Mirror) HandlePrecommitProofsWithReport(
+	ctx context.Context, p tmconsensus.PrecommitSparseProof, rep *VoteProofsReport,
+) tmconsensus.HandleVoteProofsResult {
 	defer trace.StartRegion(ctx, "HandlePrecommitProofs").End()
 
-	return m.handlePrecommitProofs(ctx, p, "(*Mirror).HandlePrecommitProofs")
+	return m.handlePrecommitProofs(ctx, p, m.requireKnownVoteBlockHash, "(*Mirror).HandlePrecommitProofs", rep)
 }
 
 // handlePrecommitProofs is the main logic for accepting precommit proofs.
@@ -533,7 +860,18 @@ func (m *Mirror) HandlePrecommitProofs(ctx context.Context, p tmconsensus.Precom
 // the exported HandlePrecommitProofs for handling incoming gossip messages,
 // but also from backfilling precommits due to seeing a valid proposed header
 // earlier than expected.
-func (m *Mirror) handlePrecommitProofs(ctx context.Context, p tmconsensus.PrecommitSparseProof, reason string) tmconsensus.HandleVoteProofsResult {
+//
+// If requireKnownBlockHash is set, new proofs are only created
+// for the nil block or for block hashes matching a proposed header in the round.
+//
+// If rep is not nil, it is populated with details about discarded signatures.
+func (m *Mirror) handlePrecommitProofs(
+	ctx context.Context,
+	p tmconsensus.PrecommitSparseProof,
+	requireKnownBlockHash bool,
+	reason string,
+	rep *VoteProofsReport,
+) tmconsensus.HandleVoteProofsResult {
 	defer trace.StartRegion(ctx, "handlePrecommitProofs").End()
 
 	// NOTE: keep changes to this method synchronized with HandlePrevoteProofs.
@@ -545,6 +883,11 @@ func (m *Mirror) handlePrecommitProofs(ctx context.Context, p tmconsensus.Precom
 
 	try := 1
 
+	fields := tmi.RVValidators | tmi.RVPrecommits
+	if requireKnownBlockHash {
+		fields |= tmi.RVProposedBlocks
+	}
+
 	var curPrecommitState tmconsensus.VersionedRoundView
 	vlReq := tmi.ViewLookupRequest{
 		H: p.Height,
@@ -552,7 +895,7 @@ func (m *Mirror) handlePrecommitProofs(ctx context.Context, p tmconsensus.Precom
 
 		VRV: &curPrecommitState,
 
-		Fields: tmi.RVValidators | tmi.RVPrecommits,
+		Fields: fields,
 
 		Reason: reason,
 
@@ -570,13 +913,26 @@ RETRY:
 		return tmconsensus.HandleVoteProofsInternalError
 	}
 
-	if vlResp.Status != tmi.ViewFound {
-		// TODO: consider future view.
-		// TODO: this return value is not quite right.
+	switch vlResp.Status {
+	case tmi.ViewFound:
+		// Okay.
+	case tmi.ViewLaterVotingRound, tmi.ViewFuture:
+		// Votes beyond our next round are not buffered,
+		// so there is nothing to expire if that round never materializes.
+		// The sender is expected to continue gossiping its votes,
+		// so we will see them again if we catch up.
+		return tmconsensus.HandleVoteProofsTooFarInFuture
+	case tmi.ViewBeforeCommitting, tmi.ViewWrongCommit:
+		// Too old to add to any view,
+		// but a majority for a block other than the one we committed
+		// is evidence of a safety violation.
+		m.checkConflictingCommit(ctx, p)
+		return tmconsensus.HandleVoteProofsRoundTooOld
+	default:
 		return tmconsensus.HandleVoteProofsRoundTooOld
 	}
 	switch vlResp.ID {
-	case tmi.ViewIDVoting, tmi.ViewIDCommitting, tmi.ViewIDNextRound:
+	case tmi.ViewIDVoting, tmi.ViewIDCommitting, tmi.ViewIDNextRound, tmi.ViewIDOrphaned:
 		// Okay.
 	default:
 		panic(fmt.Errorf(
@@ -593,7 +949,10 @@ RETRY:
 	}
 
 	curProofs := curPrecommitState.PrecommitProofs
-	sigsToAdd := m.getSignaturesToAdd(curProofs, p.Proofs, vlReq.VRV.ValidatorSet)
+	sigsToAdd, sawMalformed := m.getSignaturesToAdd(curProofs, p.Proofs, vlReq.VRV.ValidatorSet)
+	if sawMalformed && rep != nil {
+		rep.Malformed = true
+	}
 
 	if len(sigsToAdd) == 0 {
 		// Maybe the message had some valid signatures.
@@ -605,9 +964,16 @@ RETRY:
 	// Attempt to add it here, so we avoid doing unnecessary work in the kernel.
 	voteUpdates := make(map[string]tmi.VoteUpdate, len(sigsToAdd))
 	allValidSignatures := true
+	sawUnknownBlockHash := false
 	for blockHash, sigs := range sigsToAdd {
 		fullProof, ok := curProofs[blockHash]
 		if !ok {
+			if requireKnownBlockHash &&
+				!isKnownVoteBlockHash(blockHash, curPrecommitState.ProposedHeaders) {
+				sawUnknownBlockHash = true
+				continue
+			}
+
 			emptyProof, ok := m.makeNewPrecommitProof(
 				p.Height, p.Round, blockHash, curPrecommitState.ValidatorSet,
 			)
@@ -624,6 +990,9 @@ RETRY:
 		}
 		res := fullProof.MergeSparse(sparseProof)
 		allValidSignatures = allValidSignatures && res.AllValidSignatures
+		if !res.AllValidSignatures && rep != nil {
+			rep.InvalidSignature = true
+		}
 		voteUpdates[blockHash] = tmi.VoteUpdate{
 			Proof:       fullProof,
 			PrevVersion: curPrecommitState.PrecommitBlockVersions[blockHash],
@@ -631,6 +1000,10 @@ RETRY:
 	}
 
 	if len(voteUpdates) == 0 {
+		if sawUnknownBlockHash {
+			return tmconsensus.HandleVoteProofsUnknownBlockHash
+		}
+
 		// We must have been unable to build the sign bytes or signature proof.
 		// Ignore the message for now.
 		return tmconsensus.HandleVoteProofsNoNewSignatures
@@ -692,14 +1065,24 @@ RETRY:
 // The higher-level mirror handles this, in a goroutine independent from the mirror kernel,
 // in order to minimize kernel load.
 //
+// Depending on the signature proof scheme, a sparse signature's key ID
+// may refer to a pre-aggregated set of keys rather than a single key.
+// Those signatures are accepted here as long as the scheme or full proof
+// recognizes the key ID; the signature itself is verified during MergeSparse.
+//
+// Signatures whose key ID has a width the scheme cannot produce,
+// or whose length differs from a fixed length reported by the signature scheme,
+// are discarded before any further key ID checks.
+// The sawMalformed result reports whether any signature was discarded
+// for one of those reasons or for an unrecognized key ID,
+// as opposed to being discarded because it was already present.
+//
 // This is part of HandlePrevoteProofs and HandlePrecommitProofs.
 func (m *Mirror) getSignaturesToAdd(
 	curProofs map[string]gcrypto.CommonMessageSignatureProof,
 	incomingSparseProofs map[string][]gcrypto.SparseSignature,
 	valSet tmconsensus.ValidatorSet,
-) map[string][]gcrypto.SparseSignature {
-	var toAdd map[string][]gcrypto.SparseSignature
-
+) (toAdd map[string][]gcrypto.SparseSignature, sawMalformed bool) {
 	var keyIDChecker gcrypto.KeyIDChecker
 
 	for blockHash, signatures := range incomingSparseProofs {
@@ -711,19 +1094,25 @@ func (m *Mirror) getSignaturesToAdd(
 			// We could probably pick an arbitrary full proof, if we have any, to check validity.
 			// But if we don't we have to use the scheme anyway.
 
-			if keyIDChecker == nil {
-				// Only do this allocation once.
-				pubKeys := tmconsensus.ValidatorsToPubKeys(valSet.Validators)
-				keyIDChecker = m.cmspScheme.KeyIDChecker(pubKeys)
-			}
-
 			for _, sig := range signatures {
+				if !m.cmspScheme.ValidKeyIDWidth(sig.KeyID) || !m.validSigLen(sig.Sig) {
+					sawMalformed = true
+					continue
+				}
+
+				if keyIDChecker == nil {
+					// Only do this allocation once.
+					pubKeys := tmconsensus.ValidatorsToPubKeys(valSet.Validators)
+					keyIDChecker = m.cmspScheme.KeyIDChecker(pubKeys)
+				}
+
 				// TODO: this is the only time we need the pubkeys,
 				// and in the case of aggregated signatures,
 				// this can be considerably expensive.
 				// So, the CommonMessageSignatureProofScheme interface
 				// needs to change so that we can produce a key ID validator only once.
 				if !keyIDChecker.IsValid(sig.KeyID) {
+					sawMalformed = true
 					continue
 				}
 
@@ -732,8 +1121,17 @@ func (m *Mirror) getSignaturesToAdd(
 		} else {
 			// We have an existing full proof, so we can use that to validate the key ID.
 			for _, sig := range signatures {
+				if !m.cmspScheme.ValidKeyIDWidth(sig.KeyID) || !m.validSigLen(sig.Sig) {
+					sawMalformed = true
+					continue
+				}
+
 				has, valid := fullProof.HasSparseKeyID(sig.KeyID)
-				if valid && !has {
+				if !valid {
+					sawMalformed = true
+					continue
+				}
+				if !has {
 					sigsToAdd = append(sigsToAdd, sig)
 				}
 			}
@@ -752,7 +1150,25 @@ func (m *Mirror) getSignaturesToAdd(
 		toAdd[blockHash] = sigsToAdd
 	}
 
-	return toAdd
+	return toAdd, sawMalformed
+}
+
+// validSigLen reports whether sig has the length required by the signature scheme,
+// or true if the scheme does not require a fixed length.
+func (m *Mirror) validSigLen(sig []byte) bool {
+	return !m.fixedSigLen || len(sig) == m.sigLen
+}
+
+// isKnownVoteBlockHash reports whether blockHash is empty, i.e. a vote for nil,
+// or whether it is the hash of one of the proposed headers in phs.
+func isKnownVoteBlockHash(blockHash string, phs []tmconsensus.ProposedHeader) bool {
+	if blockHash == "" {
+		return true
+	}
+
+	return slices.ContainsFunc(phs, func(ph tmconsensus.ProposedHeader) bool {
+		return string(ph.Header.Hash) == blockHash
+	})
 }
 
 // makeNewPrevoteProof returns a signature proof for the given height, round, and block hash.
@@ -798,6 +1214,113 @@ func (m *Mirror) makeNewPrevoteProof(
 	return emptyProof, true
 }
 
+// checkConflictingCommit checks whether p holds valid precommits with majority power
+// for a block other than the committed block at p's height,
+// and if so, reports a safety violation to the kernel.
+// It must only be called for precommits that the kernel could not place in a view
+// because their height, or their round at the committing height, was already committed.
+//
+// The check requires loading the committed header and verifying signatures,
+// so it is skipped unless a safety violation output is configured.
+func (m *Mirror) checkConflictingCommit(ctx context.Context, p tmconsensus.PrecommitSparseProof) {
+	if m.safetyViolationReports == nil {
+		return
+	}
+
+	defer trace.StartRegion(ctx, "checkConflictingCommit").End()
+
+	var (
+		valSet         tmconsensus.ValidatorSet
+		committedRound uint32
+		committedHash  string
+	)
+	ch, err := m.hStore.LoadCommittedHeader(ctx, p.Height)
+	if err == nil {
+		valSet = ch.Header.ValidatorSet
+		committedRound = ch.Proof.Round
+		committedHash = string(ch.Header.Hash)
+	} else {
+		// The committing height is not saved to the header store
+		// until the following height commits,
+		// so compare against the committing view instead.
+		var vrv tmconsensus.VersionedRoundView
+		if err := m.CommittingView(ctx, &vrv); err != nil {
+			return
+		}
+		if vrv.Height != p.Height {
+			// Pruned, or from before we started;
+			// either way, there is nothing to compare against.
+			return
+		}
+		valSet = vrv.ValidatorSet
+		committedRound = vrv.Round
+		committedHash = vrv.VoteSummary.MostVotedPrecommitHash
+	}
+
+	if p.PubKeyHash != string(valSet.PubKeyHash) {
+		return
+	}
+
+	var totalPower uint64
+	for _, v := range valSet.Validators {
+		totalPower += v.Power
+	}
+	maj := tmconsensus.ByzantineMajority(totalPower)
+
+	for blockHash, sigs := range p.Proofs {
+		if blockHash == "" || blockHash == committedHash {
+			continue
+		}
+
+		proof, ok := m.makeNewPrecommitProof(p.Height, p.Round, blockHash, valSet)
+		if !ok {
+			// Already logged.
+			continue
+		}
+
+		res := proof.MergeSparse(gcrypto.SparseSignatureProof{
+			PubKeyHash: p.PubKeyHash,
+			Signatures: sigs,
+		})
+		if !res.AllValidSignatures {
+			continue
+		}
+
+		var bs bitset.BitSet
+		proof.SignatureBitSet(&bs)
+		var pow uint64
+		for i, ok := bs.NextSet(0); ok; i, ok = bs.NextSet(i + 1) {
+			pow += valSet.Validators[i].Power
+		}
+		if pow < maj {
+			continue
+		}
+
+		sparse := proof.AsSparse()
+		_ = gchan.SendC(
+			ctx, m.log,
+			m.safetyViolationReports, tmelink.SafetyViolation{
+				Height: p.Height,
+
+				CommittedRound:     committedRound,
+				CommittedBlockHash: []byte(committedHash),
+
+				ConflictingRound:     p.Round,
+				ConflictingBlockHash: []byte(blockHash),
+
+				Evidence: tmconsensus.CommitProof{
+					Round:      p.Round,
+					PubKeyHash: sparse.PubKeyHash,
+					Proofs: map[string][]gcrypto.SparseSignature{
+						blockHash: sparse.Signatures,
+					},
+				},
+			},
+			"reporting conflicting commit",
+		)
+	}
+}
+
 func (m *Mirror) makeNewPrecommitProof(
 	height uint64,
 	round uint32,
@@ -841,9 +1364,18 @@ func (m *Mirror) makeNewPrecommitProof(
 // VotingView overwrites v with the current state of the mirror's voting view.
 // Existing slices in v will be truncated and appended,
 // so that repeated requests should be able to minimize garbage creation.
+//
+// If the mirror was configured with PublishSnapshots,
+// v is copied from the most recently published snapshot
+// without making a request to the kernel.
 func (m *Mirror) VotingView(ctx context.Context, v *tmconsensus.VersionedRoundView) error {
 	defer trace.StartRegion(ctx, "VotingView").End()
 
+	if ps := m.loadPublishedSnapshot(); ps != nil {
+		tmi.CopySnapshotView(*ps.Voting, v, tmi.RVAll)
+		return nil
+	}
+
 	s := tmi.Snapshot{
 		Voting: v,
 	}
@@ -864,9 +1396,17 @@ func (m *Mirror) VotingView(ctx context.Context, v *tmconsensus.VersionedRoundVi
 // CommittingView overwrites v with the current state of the mirror's committing view.
 // Existing slices in v will be truncated and appended,
 // so that repeated requests should be able to minimize garbage creation.
+//
+// Like [Mirror.VotingView], CommittingView reads the published snapshot
+// if the mirror was configured with PublishSnapshots.
 func (m *Mirror) CommittingView(ctx context.Context, v *tmconsensus.VersionedRoundView) error {
 	defer trace.StartRegion(ctx, "CommittingView").End()
 
+	if ps := m.loadPublishedSnapshot(); ps != nil {
+		tmi.CopySnapshotView(*ps.Committing, v, tmi.RVAll)
+		return nil
+	}
+
 	s := tmi.Snapshot{
 		Committing: v,
 	}
@@ -884,6 +1424,55 @@ func (m *Mirror) CommittingView(ctx context.Context, v *tmconsensus.VersionedRou
 	return nil
 }
 
+// IngestTrustedHeaders applies the given committed headers, in ascending height order,
+// advancing the mirror's voting and committing views past each header
+// without requesting the proposed headers from the network.
+// Headers below the current voting height are ignored.
+//
+// The mirror must have been configured with AllowTrustedHeaderIngestion,
+// otherwise an error is returned.
+//
+// The headers are applied like replayed headers,
+// so their hashes are still checked and their commit proofs
+// must still claim majority voting power.
+// But if the signature proofs implement [gcrypto.TrustedSparseMerger],
+// the commit proof signatures are accepted without verification.
+// If any header fails to apply, the error is returned
+// and the headers after it are not applied.
+func (m *Mirror) IngestTrustedHeaders(ctx context.Context, chs []tmconsensus.CommittedHeader) error {
+	defer trace.StartRegion(ctx, "IngestTrustedHeaders").End()
+
+	if m.trustedHeadersRequests == nil {
+		return errors.New("trusted header ingestion is not enabled")
+	}
+
+	req := tmi.TrustedHeadersRequest{
+		Headers: chs,
+		Resp:    make(chan error, 1),
+	}
+	err, ok := gchan.ReqResp(
+		ctx, m.log,
+		m.trustedHeadersRequests, req,
+		req.Resp,
+		"IngestTrustedHeaders",
+	)
+	if !ok {
+		return context.Cause(ctx)
+	}
+
+	return err
+}
+
+// loadPublishedSnapshot returns the snapshot most recently published by the kernel,
+// or nil if snapshot publishing is disabled
+// or if the kernel has not yet published its first snapshot.
+func (m *Mirror) loadPublishedSnapshot() *tmi.Snapshot {
+	if m.publishedSnapshot == nil {
+		return nil
+	}
+	return m.publishedSnapshot.Load()
+}
+
 // getSnapshot is the low-level implementation to get a copy of the current kernel state.
 // This is called from multiple non-kernel methods, so the requestType parameter
 // is used to distinguish log messages if the context gets cancelled.
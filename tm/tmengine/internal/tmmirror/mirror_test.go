@@ -2,8 +2,12 @@ package tmmirror_test
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bits-and-blooms/bitset"
 	"github.com/gordian-engine/gordian/gcrypto"
@@ -445,6 +449,346 @@ func TestMirror_HandleProposedHeader(t *testing.T) {
 		require.Equal(t, []tmconsensus.ProposedHeader{ph1, ph2}, gso.Voting.ProposedHeaders)
 	})
 
+	t.Run("rejects proposed header from unexpected proposer when required", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 2)
+		mfx.Cfg.RequireExpectedProposer = true
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		initGSO := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+		require.Empty(t, initGSO.Voting.ProposedHeaders)
+
+		// At height 1, round 0, validator 1 is the expected proposer.
+		vs := mfx.Fx.ValSet()
+		require.True(t, tmconsensus.ExpectedProposer(vs, 1, 0).PubKey.Equal(vs.Validators[1].PubKey))
+
+		ph0 := mfx.Fx.NextProposedHeader([]byte("app_data_0"), 0)
+		mfx.Fx.SignProposal(ctx, &ph0, 0)
+		require.Equal(t, tmconsensus.HandleProposedHeaderUnexpectedProposer, m.HandleProposedHeader(ctx, ph0))
+
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+		mfx.Fx.SignProposal(ctx, &ph1, 1)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		// Only the expected proposer's header was added.
+		gso := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+		require.Equal(t, []tmconsensus.ProposedHeader{ph1}, gso.Voting.ProposedHeaders)
+	})
+
+	t.Run("rejects proposed headers beyond per-proposer quota", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 2)
+		mfx.Cfg.MaxHeadersPerProposerPerRound = 2
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		initGSO := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+		require.Empty(t, initGSO.Voting.ProposedHeaders)
+
+		// Validator 0 may propose two distinct headers in the round.
+		var accepted []tmconsensus.ProposedHeader
+		for i := range 2 {
+			ph := mfx.Fx.NextProposedHeader([]byte(fmt.Sprintf("app_data_0_%d", i)), 0)
+			mfx.Fx.SignProposal(ctx, &ph, 0)
+			require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph))
+			accepted = append(accepted, ph)
+
+			gso := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+			require.Equal(t, accepted, gso.Voting.ProposedHeaders)
+		}
+
+		// But a third is rejected.
+		ph0 := mfx.Fx.NextProposedHeader([]byte("app_data_0_2"), 0)
+		mfx.Fx.SignProposal(ctx, &ph0, 0)
+		require.Equal(t, tmconsensus.HandleProposedHeaderProposerQuotaExceeded, m.HandleProposedHeader(ctx, ph0))
+		gtest.NotSendingSoon(t, mfx.GossipStrategyOut)
+
+		// Validator 1 is unaffected by validator 0's quota.
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+		mfx.Fx.SignProposal(ctx, &ph1, 1)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		gso := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+		require.Equal(t, append(accepted, ph1), gso.Voting.ProposedHeaders)
+	})
+
+	t.Run("per-proposer quota ignores replayed headers without a proposer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+		mfx.Cfg.MaxHeadersPerProposerPerRound = 1
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		// Replay height 1, which stores a proposed header with no ProposerPubKey
+		// in the committing view.
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+		voteMap := map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 2, 3},
+		}
+		mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_height_1"), 0, mfx.Fx.PrecommitProofMap(ctx, 1, 0, voteMap))
+		ph2 := mfx.Fx.NextProposedHeader([]byte("app_data_2"), 0)
+
+		respCh := make(chan tmelink.ReplayedHeaderResponse, 1)
+		gtest.SendSoon(t, mfx.ReplayedHeadersIn, tmelink.ReplayedHeaderRequest{
+			Header: ph1.Header,
+			Proof:  ph2.Header.PrevCommitProof,
+			Resp:   respCh,
+		})
+		require.NoError(t, gtest.ReceiveSoon(t, respCh).Err)
+
+		// The signed proposed header for the committing height is checked against the quota,
+		// alongside the replayed header.
+		ph1.ProposerPubKey = mfx.Fx.ValidatorPubKey(0)
+		mfx.Fx.SignProposal(ctx, &ph1, 0)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		// And the quota still applies to the signed header.
+		other := ph1
+		other.Header.DataID = []byte("other_app_data_1")
+		mfx.Fx.RecalculateHash(&other.Header)
+		mfx.Fx.SignProposal(ctx, &other, 0)
+		require.Equal(t, tmconsensus.HandleProposedHeaderProposerQuotaExceeded, m.HandleProposedHeader(ctx, other))
+	})
+
+	t.Run("rejects proposed headers with oversized annotations", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 2)
+		mfx.Cfg.MaxAnnotationBytes = 8
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		initGSO := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+		require.Empty(t, initGSO.Voting.ProposedHeaders)
+
+		// Proposal and header annotations count toward the same limit.
+		ph0 := mfx.Fx.NextProposedHeader([]byte("app_data_0"), 0)
+		ph0.Header.Annotations.User = []byte("12345")
+		mfx.Fx.RecalculateHash(&ph0.Header)
+		ph0.Annotations.Driver = []byte("6789")
+		mfx.Fx.SignProposal(ctx, &ph0, 0)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAnnotationsTooLarge, m.HandleProposedHeader(ctx, ph0))
+		gtest.NotSendingSoon(t, mfx.GossipStrategyOut)
+
+		// A header exactly at the limit is accepted.
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+		ph1.Header.Annotations.User = []byte("12345")
+		mfx.Fx.RecalculateHash(&ph1.Header)
+		ph1.Annotations.Driver = []byte("678")
+		mfx.Fx.SignProposal(ctx, &ph1, 1)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		gso := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+		require.Equal(t, []tmconsensus.ProposedHeader{ph1}, gso.Voting.ProposedHeaders)
+	})
+
+	t.Run("drops prefiltered proposed headers before verification", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 2)
+
+		// Validator 0 is denylisted.
+		denied := mfx.Fx.ValidatorPubKey(0)
+		var mu sync.Mutex
+		var seen []tmconsensus.ProposedHeader
+		mfx.Cfg.IncomingHeaderPrefilter = func(ph tmconsensus.ProposedHeader) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			seen = append(seen, ph)
+			return !denied.Equal(ph.ProposerPubKey)
+		}
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		initGSO := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+		require.Empty(t, initGSO.Voting.ProposedHeaders)
+
+		// The denylisted header has an invalid signature,
+		// so it would be reported as a bad signature if it were verified.
+		ph0 := mfx.Fx.NextProposedHeader([]byte("app_data_0"), 0)
+		mfx.Fx.SignProposal(ctx, &ph0, 0)
+		ph0.Signature = []byte("not a signature")
+		require.Equal(t, tmconsensus.HandleProposedHeaderPrefiltered, m.HandleProposedHeader(ctx, ph0))
+		gtest.NotSendingSoon(t, mfx.GossipStrategyOut)
+
+		// Other proposers are verified and accepted as usual.
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+		mfx.Fx.SignProposal(ctx, &ph1, 1)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		gso := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+		require.Equal(t, []tmconsensus.ProposedHeader{ph1}, gso.Voting.ProposedHeaders)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, []tmconsensus.ProposedHeader{ph0, ph1}, seen)
+	})
+
+	t.Run("rejects proposed headers with invalid timestamps", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		const drift = time.Minute
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+		mfx.Cfg.ValidateHeaderTimestamps = true
+		mfx.Cfg.HeaderTimestampMaxFutureDrift = drift
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		// Helper to build a proposed header at the fixture's current height
+		// with the given timestamp.
+		timestampedPH := func(appData string, ts time.Time) tmconsensus.ProposedHeader {
+			ph := mfx.Fx.NextProposedHeader([]byte(appData), 0)
+			ph.Header.Timestamp = ts
+			mfx.Fx.RecalculateHash(&ph.Header)
+			mfx.Fx.SignProposal(ctx, &ph, 0)
+			return ph
+		}
+
+		// At the initial height there is no parent timestamp,
+		// but the drift bound still applies.
+		ts1 := time.Now().Add(-time.Hour)
+		ph1Future := timestampedPH("app_data_1_future", time.Now().Add(time.Hour))
+		require.Equal(t, tmconsensus.HandleProposedHeaderBadTimestamp, m.HandleProposedHeader(ctx, ph1Future))
+
+		ph1 := timestampedPH("app_data_1", ts1)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		// Commit height 1 so the voting height's parent has a timestamp.
+		voteMap := map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 2, 3},
+		}
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+		require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs:     mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, voteMap),
+		}))
+		mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_1"), 0, mfx.Fx.PrecommitProofMap(ctx, 1, 0, voteMap))
+
+		// A timestamp going backwards is rejected.
+		ph2Backwards := timestampedPH("app_data_2_backwards", ts1.Add(-time.Second))
+		require.Equal(t, tmconsensus.HandleProposedHeaderBadTimestamp, m.HandleProposedHeader(ctx, ph2Backwards))
+
+		// So is one excessively far in the future.
+		ph2Future := timestampedPH("app_data_2_future", time.Now().Add(2*drift))
+		require.Equal(t, tmconsensus.HandleProposedHeaderBadTimestamp, m.HandleProposedHeader(ctx, ph2Future))
+
+		// But an increasing timestamp within the drift bound is accepted.
+		ph2 := timestampedPH("app_data_2", ts1.Add(time.Second))
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph2))
+	})
+
+	t.Run("verifies previous commit at initial height above genesis", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+
+		// Build a chain through height 4 in the fixture,
+		// as though a state sync had brought us to that height.
+		ph := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+		for h := uint64(1); h <= 4; h++ {
+			precommitProofs := mfx.Fx.PrecommitProofMap(ctx, h, 0, map[string][]int{
+				string(ph.Header.Hash): {0, 1, 2, 3},
+			})
+			mfx.Fx.CommitBlock(ph.Header, []byte(fmt.Sprintf("app_state_height_%d", h)), 0, precommitProofs)
+
+			next := mfx.Fx.NextProposedHeader([]byte(fmt.Sprintf("app_data_%d", h+1)), 0)
+			if h == 4 {
+				require.NoError(t, mfx.Cfg.CommittedHeaderStore.SaveCommittedHeader(ctx, tmconsensus.CommittedHeader{
+					Header: ph.Header,
+					Proof:  next.Header.PrevCommitProof,
+				}))
+			}
+			ph = next
+		}
+
+		mfx.Cfg.InitialHeight = 5
+		mfx.Cfg.GenesisHeight = 1
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		// A proposed header without a previous commit proof
+		// is only acceptable at the genesis height.
+		noProof := ph
+		noProof.Header.PrevCommitProof = tmconsensus.CommitProof{
+			Proofs: map[string][]gcrypto.SparseSignature{},
+		}
+		mfx.Fx.RecalculateHash(&noProof.Header)
+		mfx.Fx.SignProposal(ctx, &noProof, 0)
+		require.Equal(
+			t,
+			tmconsensus.HandleProposedHeaderBadPrevCommitProofPubKeyHash,
+			m.HandleProposedHeader(ctx, noProof),
+		)
+
+		// A tampered signature in the previous commit proof is rejected.
+		badSig := ph
+		badSig.Header.PrevCommitProof.Proofs = make(map[string][]gcrypto.SparseSignature)
+		for hash, sigs := range ph.Header.PrevCommitProof.Proofs {
+			sigs = slices.Clone(sigs)
+			sigs[0].Sig = slices.Clone(sigs[0].Sig)
+			sigs[0].Sig[0]++
+			badSig.Header.PrevCommitProof.Proofs[hash] = sigs
+		}
+		mfx.Fx.RecalculateHash(&badSig.Header)
+		mfx.Fx.SignProposal(ctx, &badSig, 0)
+		require.Equal(
+			t,
+			tmconsensus.HandleProposedHeaderBadPrevCommitProofSignature,
+			m.HandleProposedHeader(ctx, badSig),
+		)
+
+		// And the untampered header is accepted.
+		mfx.Fx.SignProposal(ctx, &ph, 0)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph))
+
+		var vv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vv))
+		require.Equal(t, uint64(5), vv.Height)
+		require.Equal(t, []tmconsensus.ProposedHeader{ph}, vv.ProposedHeaders)
+	})
+
 	t.Run("accepts proposed header to committing view", func(t *testing.T) {
 		// If one validator is running slightly behind and proposes a header that reaches the committing view,
 		// it should still be included in updates.
@@ -728,21 +1072,19 @@ func TestMirror_HandlePrevoteProofs(t *testing.T) {
 		require.Equal(t, fullPrevoteProofMap, newFullPrevotes)
 	})
 
-	t.Run("concurrent independent updates accepted", func(t *testing.T) {
+	t.Run("vote for unknown block hash rejected when known block hash required", func(t *testing.T) {
 		t.Parallel()
 
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		const size = 10
-
-		mfx := tmmirrortest.NewFixture(ctx, t, size)
+		mfx := tmmirrortest.NewFixture(ctx, t, 2)
+		mfx.Cfg.RequireKnownVoteBlockHash = true
 
 		m := mfx.NewMirror()
 		defer m.Wait()
 		defer cancel()
 
-		// Sign proposed header, because the mirror actually validates this.
 		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
 		mfx.Fx.SignProposal(ctx, &ph1, 0)
 
@@ -750,65 +1092,224 @@ func TestMirror_HandlePrevoteProofs(t *testing.T) {
 
 		keyHash, _ := mfx.Fx.ValidatorHashes()
 
-		// Start an independent goroutine for each prevote to submit.
-		start := make(chan struct{})
-		feedbackCh := make(chan tmconsensus.HandleVoteProofsResult, size)
-		for i := range size {
-			go func(i int) {
-				voteMap := map[string][]int{
-					string(ph1.Header.Hash): {i},
-				}
-				sparsePrevoteProofMap := mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, voteMap)
-				prevoteProof := tmconsensus.PrevoteSparseProof{
-					Height:     1,
-					Round:      0,
-					PubKeyHash: keyHash,
-					Proofs:     sparsePrevoteProofMap,
-				}
-				// Synchronize all goroutines on the start signal.
-				<-start
-
-				// Channel is buffered properly so don't need to check blocking send.
-				feedbackCh <- m.HandlePrevoteProofs(ctx, prevoteProof)
-			}(i)
-		}
-
-		// Build the full prevote map for later assertion.
-		fullVoteMap := map[string][]int{
-			string(ph1.Header.Hash): {0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, // TODO: decouple from size constant.
+		// A correctly signed prevote, but for a block we have never seen.
+		unknownProof := tmconsensus.PrevoteSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs: mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, map[string][]int{
+				"unknown_block_hash": {0},
+			}),
 		}
-		fullPrevoteProofMap := mfx.Fx.PrevoteProofMap(ctx, 1, 0, fullVoteMap)
+		require.Equal(t, tmconsensus.HandleVoteProofsUnknownBlockHash, m.HandlePrevoteProofs(ctx, unknownProof))
 
-		// Now assert all 10 goroutines have had their message accepted.
-		close(start)
-		for range size {
-			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, gtest.ReceiveSoon(t, feedbackCh))
+		// Votes for the known proposed header and for nil are still accepted.
+		knownProof := tmconsensus.PrevoteSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs: mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, map[string][]int{
+				string(ph1.Header.Hash): {0},
+				"":                      {1},
+			}),
 		}
+		require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrevoteProofs(ctx, knownProof))
 
-		// Only asserting against the RoundView content, ignoring the versioned details.
-		expNV := tmconsensus.RoundView{
-			Height:       1,
-			Round:        0,
-			ValidatorSet: mfx.Fx.ValSet(),
+		var vnv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vnv))
+		require.NotContains(t, vnv.PrevoteProofs, "unknown_block_hash")
+		require.Contains(t, vnv.PrevoteProofs, string(ph1.Header.Hash))
+		require.Contains(t, vnv.PrevoteProofs, "")
+	})
 
-			PrevCommitProof: tmconsensus.CommitProof{Proofs: map[string][]gcrypto.SparseSignature{}},
+	t.Run("signatures of the wrong length rejected when scheme has fixed signature length", func(t *testing.T) {
+		t.Parallel()
 
-			ProposedHeaders: []tmconsensus.ProposedHeader{ph1},
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
-			PrevoteProofs:   fullPrevoteProofMap,
-			PrecommitProofs: mfx.Fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{}),
+		mfx := tmmirrortest.NewFixture(ctx, t, 2)
+		mfx.Cfg.SignatureScheme = fixedLenSignatureScheme{
+			SignatureScheme: mfx.Fx.SignatureScheme,
+			N:               ed25519.SignatureSize,
 		}
 
-		vs := tmconsensus.NewVoteSummary()
-		vs.SetAvailablePower(expNV.ValidatorSet.Validators)
-		vs.SetPrevotePowers(expNV.ValidatorSet.Validators, expNV.PrevoteProofs)
-		expNV.VoteSummary = vs
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
 
-		var vnv tmconsensus.VersionedRoundView
-		require.NoError(t, m.VotingView(ctx, &vnv))
-		require.Equal(t, expNV, vnv.RoundView)
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+		mfx.Fx.SignProposal(ctx, &ph1, 0)
 
-		_, prevotes, _, err := mfx.Cfg.RoundStore.LoadRoundState(ctx, 1, 0)
+		// A truncated signature is rejected before verification.
+		truncatedPH := ph1
+		truncatedPH.Signature = ph1.Signature[:len(ph1.Signature)-1]
+		require.Equal(t, tmconsensus.HandleProposedHeaderBadSignature, m.HandleProposedHeader(ctx, truncatedPH))
+
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+
+		// Only sparse signatures of the wrong length, so nothing is added.
+		badProofs := mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, map[string][]int{
+			string(ph1.Header.Hash): {0},
+		})
+		for _, sigs := range badProofs {
+			for i := range sigs {
+				sigs[i].Sig = append(sigs[i].Sig, 0)
+			}
+		}
+		var rep tmmirror.VoteProofsReport
+		require.Equal(t, tmconsensus.HandleVoteProofsNoNewSignatures, m.HandlePrevoteProofsWithReport(ctx, tmconsensus.PrevoteSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs:     badProofs,
+		}, &rep))
+
+		// The report distinguishes the discarded signatures from duplicates.
+		require.Equal(t, tmmirror.VoteProofsReport{Malformed: true}, rep)
+
+		// Correctly sized signatures are still accepted.
+		require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrevoteProofs(ctx, tmconsensus.PrevoteSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs: mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, map[string][]int{
+				string(ph1.Header.Hash): {0},
+			}),
+		}))
+	})
+
+	t.Run("invalid signatures reported while valid signatures are applied", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 2)
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+		mfx.Fx.SignProposal(ctx, &ph1, 0)
+
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+
+		// Validator 0's prevote is valid, but validator 1's nil prevote is corrupted.
+		proofs := mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, map[string][]int{
+			string(ph1.Header.Hash): {0},
+			"":                      {1},
+		})
+		proofs[""][0].Sig[0]++
+
+		// The valid signature is accepted,
+		// and the invalid one is only reported through the report.
+		var rep tmmirror.VoteProofsReport
+		require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrevoteProofsWithReport(ctx, tmconsensus.PrevoteSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs:     proofs,
+		}, &rep))
+		require.Equal(t, tmmirror.VoteProofsReport{InvalidSignature: true}, rep)
+
+		var vnv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vnv))
+
+		var bs bitset.BitSet
+		vnv.PrevoteProofs[string(ph1.Header.Hash)].SignatureBitSet(&bs)
+		require.True(t, bs.Test(0))
+
+		vnv.PrevoteProofs[""].SignatureBitSet(&bs)
+		require.Zero(t, bs.Count())
+	})
+
+	t.Run("concurrent independent updates accepted", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		const size = 10
+
+		mfx := tmmirrortest.NewFixture(ctx, t, size)
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		// Sign proposed header, because the mirror actually validates this.
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+		mfx.Fx.SignProposal(ctx, &ph1, 0)
+
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+
+		// Start an independent goroutine for each prevote to submit.
+		start := make(chan struct{})
+		feedbackCh := make(chan tmconsensus.HandleVoteProofsResult, size)
+		for i := range size {
+			go func(i int) {
+				voteMap := map[string][]int{
+					string(ph1.Header.Hash): {i},
+				}
+				sparsePrevoteProofMap := mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, voteMap)
+				prevoteProof := tmconsensus.PrevoteSparseProof{
+					Height:     1,
+					Round:      0,
+					PubKeyHash: keyHash,
+					Proofs:     sparsePrevoteProofMap,
+				}
+				// Synchronize all goroutines on the start signal.
+				<-start
+
+				// Channel is buffered properly so don't need to check blocking send.
+				feedbackCh <- m.HandlePrevoteProofs(ctx, prevoteProof)
+			}(i)
+		}
+
+		// Build the full prevote map for later assertion.
+		fullVoteMap := map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, // TODO: decouple from size constant.
+		}
+		fullPrevoteProofMap := mfx.Fx.PrevoteProofMap(ctx, 1, 0, fullVoteMap)
+
+		// Now assert all 10 goroutines have had their message accepted.
+		close(start)
+		for range size {
+			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, gtest.ReceiveSoon(t, feedbackCh))
+		}
+
+		// Only asserting against the RoundView content, ignoring the versioned details.
+		expNV := tmconsensus.RoundView{
+			Height:       1,
+			Round:        0,
+			ValidatorSet: mfx.Fx.ValSet(),
+
+			PrevCommitProof: tmconsensus.CommitProof{Proofs: map[string][]gcrypto.SparseSignature{}},
+
+			ProposedHeaders: []tmconsensus.ProposedHeader{ph1},
+
+			PrevoteProofs:   fullPrevoteProofMap,
+			PrecommitProofs: mfx.Fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{}),
+		}
+
+		vs := tmconsensus.NewVoteSummary()
+		vs.SetAvailablePower(expNV.ValidatorSet.Validators)
+		vs.SetPrevotePowers(expNV.ValidatorSet.Validators, expNV.PrevoteProofs)
+		expNV.VoteSummary = vs
+
+		var vnv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vnv))
+		require.Equal(t, expNV, vnv.RoundView)
+
+		_, prevotes, _, err := mfx.Cfg.RoundStore.LoadRoundState(ctx, 1, 0)
 		require.NoError(t, err)
 
 		newFullPrevotes, err := prevotes.ToFullPrevoteProofMap(
@@ -909,6 +1410,87 @@ func TestMirror_HandlePrecommitProofs(t *testing.T) {
 
 		require.Equal(t, fullPrecommitProofMap, newFullPrecommits)
 	})
+
+	t.Run("vote for unknown block hash rejected when known block hash required", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+		mfx.Cfg.RequireKnownVoteBlockHash = true
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+
+		// No proposed header has been seen, so only nil precommits may be accepted.
+		unknownProof := tmconsensus.PrecommitSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, map[string][]int{
+				"unknown_block_hash": {0},
+			}),
+		}
+		require.Equal(t, tmconsensus.HandleVoteProofsUnknownBlockHash, m.HandlePrecommitProofs(ctx, unknownProof))
+
+		nilProof := tmconsensus.PrecommitSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, map[string][]int{
+				"": {1},
+			}),
+		}
+		require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, nilProof))
+
+		var vnv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vnv))
+		require.NotContains(t, vnv.PrecommitProofs, "unknown_block_hash")
+	})
+}
+
+func TestMirror_sparseProofsFromFullProofs(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mfx := tmmirrortest.NewFixture(ctx, t, 4)
+
+	m := mfx.NewMirror()
+	defer m.Wait()
+	defer cancel()
+
+	ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+	mfx.Fx.SignProposal(ctx, &ph1, 0)
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+	// A gossip strategy holding full proofs converts them to sparse form
+	// before sending them, and the receiving mirror reconstructs the same full proofs.
+	// Stay below 2/3 so the round does not advance.
+	voteMap := map[string][]int{
+		string(ph1.Header.Hash): {0},
+		"":                      {1},
+	}
+
+	fullPrevotes := mfx.Fx.PrevoteProofMap(ctx, 1, 0, voteMap)
+	prevoteProof, err := tmconsensus.PrevoteSparseProofFromFullProof(1, 0, fullPrevotes)
+	require.NoError(t, err)
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrevoteProofs(ctx, prevoteProof))
+
+	fullPrecommits := mfx.Fx.PrecommitProofMap(ctx, 1, 0, voteMap)
+	precommitProof, err := tmconsensus.PrecommitSparseProofFromFullProof(1, 0, fullPrecommits)
+	require.NoError(t, err)
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, precommitProof))
+
+	var vnv tmconsensus.VersionedRoundView
+	require.NoError(t, m.VotingView(ctx, &vnv))
+	require.Equal(t, fullPrevotes, vnv.PrevoteProofs)
+	require.Equal(t, fullPrecommits, vnv.PrecommitProofs)
 }
 
 func TestMirror_FullRound(t *testing.T) {
@@ -1252,14 +1834,14 @@ func TestMirror_restart(t *testing.T) {
 	})
 }
 
-func TestMirror_CommitToBlockStore(t *testing.T) {
+func TestMirror_minimizeCommitProof(t *testing.T) {
 	t.Parallel()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	mfx := tmmirrortest.NewFixture(ctx, t, 4)
-	mCh := mfx.UseMetrics(t, ctx)
+	mfx.Cfg.MinimizeCommitProof = true
 
 	m := mfx.NewMirror()
 	defer m.Wait()
@@ -1270,14 +1852,11 @@ func TestMirror_CommitToBlockStore(t *testing.T) {
 
 	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
 
-	// Now advance the fixture with a full set of precommits.
+	// Everyone precommits for the block,
+	// which is more than the minimum needed.
 	voteMap1 := map[string][]int{
 		string(ph1.Header.Hash): {0, 1, 2, 3},
 	}
-	precommitProofs1 := mfx.Fx.PrecommitProofMap(ctx, 1, 0, voteMap1)
-	mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_height_1"), 0, precommitProofs1)
-
-	// And advance the mirror by submitting a full set of precommits.
 	keyHash, _ := mfx.Fx.ValidatorHashes()
 	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
 		Height: 1,
@@ -1288,61 +1867,270 @@ func TestMirror_CommitToBlockStore(t *testing.T) {
 		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, voteMap1),
 	}))
 
-	// Read a gossip strategy value in order to synchronize here.
-	_ = gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+	// Read gossip strategy values until the voting view reaches height 2.
+	gso := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+	for gso.Voting == nil || gso.Voting.Height != 2 {
+		gso = gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+	}
 
-	// Now on height 2, we have a committing view at height 1.
-	// So, the subjective commit for 1 should be in the header store.
 	h, err := mfx.Cfg.CommittedHeaderStore.LoadCommittedHeader(ctx, 1)
 	require.NoError(t, err)
-	require.Equal(t, tmconsensus.CommittedHeader{
-		Header: ph1.Header,
-		Proof: tmconsensus.CommitProof{
-			Round:      0,
-			PubKeyHash: keyHash,
-			Proofs:     mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, voteMap1),
-		},
-	}, h)
-
-	// Now propose and commit height 2.
-	ph2 := mfx.Fx.NextProposedHeader([]byte("app_data_2"), 0)
-	mfx.Fx.SignProposal(ctx, &ph2, 0)
+	require.Equal(t, keyHash, h.Proof.PubKeyHash)
 
-	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph2))
+	// The gossip strategy sees the same minimized proof that was stored.
+	require.Equal(t, h.Proof, gso.Voting.PrevCommitProof)
+	require.NotNil(t, gso.NextRound)
+	require.Equal(t, h.Proof, gso.NextRound.PrevCommitProof)
+	require.Len(t, h.Proof.Proofs, 1)
 
-	// Drain metrics.
-	_ = gtest.ReceiveSoon(t, mCh)
+	sigs := h.Proof.Proofs[string(ph1.Header.Hash)]
+	require.NotEmpty(t, sigs)
 
-	// Update the fixture for height 2.
-	voteMap2 := map[string][]int{
-		string(ph2.Header.Hash): {0, 1, 2, 3},
-	}
-	precommitProofs2 := mfx.Fx.PrecommitProofMap(ctx, 2, 0, voteMap2)
-	mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_height_2"), 0, precommitProofs2)
+	// The minimized proof is still valid.
+	fullProofs := mfx.Fx.PrecommitProofMap(ctx, 1, 0, voteMap1)
+	p := fullProofs[string(ph1.Header.Hash)].Derive()
+	res := p.MergeSparse(gcrypto.SparseSignatureProof{
+		PubKeyHash: keyHash,
+		Signatures: sigs,
+	})
+	require.True(t, res.AllValidSignatures)
 
-	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
-		Height: 2,
-		Round:  0,
+	// And it has the lowest-index signers reaching a majority,
+	// which is 3 of the 4 equally weighted validators.
+	var bs bitset.BitSet
+	p.SignatureBitSet(&bs)
+	require.Equal(t, uint(3), bs.Count())
+	require.True(t, bs.Test(0))
+	require.True(t, bs.Test(1))
+	require.True(t, bs.Test(2))
+}
 
-		PubKeyHash: keyHash,
+func TestMirror_publishedSnapshots(t *testing.T) {
+	t.Parallel()
 
-		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 2, 0, voteMap2),
-	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// Synchronize on metrics before inspecting the mirror store.
-	_ = gtest.ReceiveSoon(t, mCh)
+	mfx := tmmirrortest.NewFixture(ctx, t, 4)
+	mfx.Cfg.PublishSnapshots = true
 
-	// Now we are voting on height 3, and committing height 2.
-	wantNHR := tmmirror.NetworkHeightRound{
-		VotingHeight: 3,
-		VotingRound:  0,
+	m := mfx.NewMirror()
+	defer m.Wait()
+	defer cancel()
 
-		CommittingHeight: 2,
-		CommittingRound:  0,
-	}
-	nhr, err := tmi.NetworkHeightRoundFromStore(mfx.Store().NetworkHeightRound(ctx))
-	require.NoError(t, err)
-	require.Equal(t, wantNHR, nhr)
+	ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+	mfx.Fx.SignProposal(ctx, &ph1, 0)
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+	// Wait for the proposed header to appear in the published snapshot.
+	var vrv tmconsensus.VersionedRoundView
+	require.Eventually(t, func() bool {
+		if err := m.VotingView(ctx, &vrv); err != nil {
+			return false
+		}
+		return len(vrv.ProposedHeaders) == 1
+	}, time.Duration(gtest.ScaleMs(500)), time.Millisecond)
+
+	// The published snapshot is read without a kernel request,
+	// so even a canceled context still gets a view.
+	canceledCtx, cancelReadCtx := context.WithCancel(ctx)
+	cancelReadCtx()
+	require.NoError(t, m.VotingView(canceledCtx, &vrv))
+	require.Len(t, vrv.ProposedHeaders, 1)
+
+	vals := mfx.Fx.Vals()
+
+	// Readers continuously read the voting view while prevotes are applied.
+	const nReaders = 4
+	stopReading := make(chan struct{})
+	readErrs := make(chan error, nReaders)
+	var readersDone sync.WaitGroup
+	for range nReaders {
+		readersDone.Add(1)
+		go func() {
+			defer readersDone.Done()
+
+			var v tmconsensus.VersionedRoundView
+			var lastVersion uint32
+			for {
+				select {
+				case <-stopReading:
+					readErrs <- nil
+					return
+				default:
+				}
+
+				if err := m.VotingView(ctx, &v); err != nil {
+					readErrs <- err
+					return
+				}
+
+				if v.Height != 1 || v.Round != 0 {
+					readErrs <- fmt.Errorf("unexpected height/round %d/%d", v.Height, v.Round)
+					return
+				}
+				if v.Version < lastVersion {
+					readErrs <- fmt.Errorf("version went backwards from %d to %d", lastVersion, v.Version)
+					return
+				}
+				lastVersion = v.Version
+
+				// The vote summary must agree with the proofs in the same view.
+				var bs bitset.BitSet
+				var prevotePower uint64
+				for _, proof := range v.PrevoteProofs {
+					proof.SignatureBitSet(&bs)
+					for i, ok := bs.NextSet(0); ok; i, ok = bs.NextSet(i + 1) {
+						prevotePower += vals[i].Power
+					}
+				}
+				if prevotePower != v.VoteSummary.TotalPrevotePower {
+					readErrs <- fmt.Errorf(
+						"inconsistent view at version %d: proofs have power %d, summary has %d",
+						v.Version, prevotePower, v.VoteSummary.TotalPrevotePower,
+					)
+					return
+				}
+			}
+		}()
+	}
+
+	// Apply one prevote at a time, so that there are several distinct versions to observe.
+	keyHash, _ := mfx.Fx.ValidatorHashes()
+	votesDone := make(chan error, 1)
+	go func() {
+		for i := range vals {
+			voteMap := map[string][]int{
+				string(ph1.Header.Hash): {i},
+			}
+			res := m.HandlePrevoteProofs(ctx, tmconsensus.PrevoteSparseProof{
+				Height: 1,
+				Round:  0,
+
+				PubKeyHash: keyHash,
+
+				Proofs: mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, voteMap),
+			})
+			if res != tmconsensus.HandleVoteProofsAccepted {
+				votesDone <- fmt.Errorf("prevote %d not accepted: %s", i, res)
+				return
+			}
+		}
+		votesDone <- nil
+	}()
+
+	// The readers must not stall vote application.
+	// The timeout is more generous than usual,
+	// as the busy readers compete with the kernel for CPU time.
+	require.NoError(t, gtest.ReceiveOrTimeout(t, votesDone, gtest.ScaleMs(2000)))
+
+	close(stopReading)
+	readersDone.Wait()
+	for range nReaders {
+		require.NoError(t, <-readErrs)
+	}
+
+	// And the published view eventually catches up to every prevote.
+	require.Eventually(t, func() bool {
+		if err := m.VotingView(ctx, &vrv); err != nil {
+			return false
+		}
+		return len(vrv.PrevoteProofs) == 1 &&
+			vrv.VoteSummary.TotalPrevotePower == vrv.VoteSummary.AvailablePower
+	}, time.Duration(gtest.ScaleMs(500)), time.Millisecond)
+}
+
+func TestMirror_CommitToBlockStore(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mfx := tmmirrortest.NewFixture(ctx, t, 4)
+	mCh := mfx.UseMetrics(t, ctx)
+
+	m := mfx.NewMirror()
+	defer m.Wait()
+	defer cancel()
+
+	ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+	mfx.Fx.SignProposal(ctx, &ph1, 0)
+
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+	// Now advance the fixture with a full set of precommits.
+	voteMap1 := map[string][]int{
+		string(ph1.Header.Hash): {0, 1, 2, 3},
+	}
+	precommitProofs1 := mfx.Fx.PrecommitProofMap(ctx, 1, 0, voteMap1)
+	mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_height_1"), 0, precommitProofs1)
+
+	// And advance the mirror by submitting a full set of precommits.
+	keyHash, _ := mfx.Fx.ValidatorHashes()
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height: 1,
+		Round:  0,
+
+		PubKeyHash: keyHash,
+
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, voteMap1),
+	}))
+
+	// Read a gossip strategy value in order to synchronize here.
+	_ = gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+
+	// Now on height 2, we have a committing view at height 1.
+	// So, the subjective commit for 1 should be in the header store.
+	h, err := mfx.Cfg.CommittedHeaderStore.LoadCommittedHeader(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, tmconsensus.CommittedHeader{
+		Header: ph1.Header,
+		Proof: tmconsensus.CommitProof{
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs:     mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, voteMap1),
+		},
+	}, h)
+
+	// Now propose and commit height 2.
+	ph2 := mfx.Fx.NextProposedHeader([]byte("app_data_2"), 0)
+	mfx.Fx.SignProposal(ctx, &ph2, 0)
+
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph2))
+
+	// Drain metrics.
+	_ = gtest.ReceiveSoon(t, mCh)
+
+	// Update the fixture for height 2.
+	voteMap2 := map[string][]int{
+		string(ph2.Header.Hash): {0, 1, 2, 3},
+	}
+	precommitProofs2 := mfx.Fx.PrecommitProofMap(ctx, 2, 0, voteMap2)
+	mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_height_2"), 0, precommitProofs2)
+
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height: 2,
+		Round:  0,
+
+		PubKeyHash: keyHash,
+
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 2, 0, voteMap2),
+	}))
+
+	// Synchronize on metrics before inspecting the mirror store.
+	_ = gtest.ReceiveSoon(t, mCh)
+
+	// Now we are voting on height 3, and committing height 2.
+	wantNHR := tmmirror.NetworkHeightRound{
+		VotingHeight: 3,
+		VotingRound:  0,
+
+		CommittingHeight: 2,
+		CommittingRound:  0,
+	}
+	nhr, err := tmi.NetworkHeightRoundFromStore(mfx.Store().NetworkHeightRound(ctx))
+	require.NoError(t, err)
+	require.Equal(t, wantNHR, nhr)
 
 	// This means height 1 must be in the header store.
 	cb1, err := mfx.Cfg.CommittedHeaderStore.LoadCommittedHeader(ctx, 1)
@@ -1355,6 +2143,85 @@ func TestMirror_CommitToBlockStore(t *testing.T) {
 	}, cb1)
 }
 
+func TestMirror_maxFutureRoundForHeader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default accepts only through next round", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+		ph1.Round = 1
+		mfx.Fx.SignProposal(ctx, &ph1, 0)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+		ph2 := mfx.Fx.NextProposedHeader([]byte("app_data_2"), 1)
+		ph2.Round = 2
+		mfx.Fx.SignProposal(ctx, &ph2, 1)
+		require.Equal(t, tmconsensus.HandleProposedHeaderRoundTooFarInFuture, m.HandleProposedHeader(ctx, ph2))
+	})
+
+	t.Run("held headers enter the view when the round catches up", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+		mfx.Cfg.MaxFutureRoundForHeader = 3
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		_ = gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+
+		// Round 3 is at the boundary, so it is accepted.
+		ph3 := mfx.Fx.NextProposedHeader([]byte("app_data_3"), 0)
+		ph3.Round = 3
+		mfx.Fx.SignProposal(ctx, &ph3, 0)
+		require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph3))
+		require.Equal(t, tmconsensus.HandleProposedHeaderAlreadyStored, m.HandleProposedHeader(ctx, ph3))
+
+		// But the held header is not yet shared with the gossip strategy.
+		gtest.NotSendingSoon(t, mfx.GossipStrategyOut)
+
+		// Round 4 is beyond the boundary.
+		ph4 := mfx.Fx.NextProposedHeader([]byte("app_data_4"), 1)
+		ph4.Round = 4
+		mfx.Fx.SignProposal(ctx, &ph4, 1)
+		require.Equal(t, tmconsensus.HandleProposedHeaderRoundTooFarInFuture, m.HandleProposedHeader(ctx, ph4))
+
+		// Advance through rounds 0, 1, and 2 with nil precommits.
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+		for r := uint32(0); r < 3; r++ {
+			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+				Height: 1,
+				Round:  r,
+
+				PubKeyHash: keyHash,
+
+				Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, r, map[string][]int{
+					"": {0, 1, 2, 3},
+				}),
+			}))
+		}
+
+		var vrv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vrv))
+		require.Equal(t, uint32(3), vrv.Round)
+		require.Equal(t, []tmconsensus.ProposedHeader{ph3}, vrv.ProposedHeaders)
+	})
+}
+
 func TestMirror_nilPrecommitAdvancesRound(t *testing.T) {
 	t.Parallel()
 
@@ -1651,65 +2518,221 @@ func TestMirror_votesBeforeVotingRound(t *testing.T) {
 	}
 }
 
-func TestMirror_fetchProposedBlock(t *testing.T) {
-	for _, vt := range voteTypes {
-		vt := vt
-		t.Run("fetch initiated when "+vt.Name+"s exceed minority voting power", func(t *testing.T) {
-			t.Parallel()
+func TestMirror_lateVoteGracePeriod(t *testing.T) {
+	t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
+	// advance returns a mirror that has advanced from 1/0 to 1/1
+	// on nil precommits from all but the last of four validators.
+	advance := func(ctx context.Context, t *testing.T, grace time.Duration) (
+		*tmmirrortest.Fixture, *tmmirror.Mirror,
+	) {
+		t.Helper()
 
-			mfx := tmmirrortest.NewFixture(ctx, t, 4)
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+		mfx.Cfg.LateVoteGracePeriod = grace
 
-			phf := tmelinktest.NewPHFetcher(1, 1)
-			mfx.Cfg.ProposedHeaderFetcher = phf.ProposedHeaderFetcher()
+		m := mfx.NewMirror()
+		t.Cleanup(m.Wait)
 
-			m := mfx.NewMirror()
-			defer m.Wait()
-			defer cancel()
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+		require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, map[string][]int{
+				"": {0, 1, 2},
+			}),
+		}))
 
-			voter := vt.VoterFunc(mfx, m)
+		var vrv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vrv))
+		require.Equal(t, uint64(1), vrv.Height)
+		require.Equal(t, uint32(1), vrv.Round)
 
-			// Make the proposed header but don't give it to the mirror.
-			ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
-			mfx.Fx.SignProposal(ctx, &ph1, 0)
+		return mfx, m
+	}
 
-			// With one ote there is no fetch request.
-			voteMap := map[string][]int{
-				string(ph1.Header.Hash): {0},
-			}
+	t.Run("late votes within the window are recorded", func(t *testing.T) {
+		t.Parallel()
 
-			res := voter.HandleProofs(ctx, 1, 0, voteMap)
-			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, res)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
-			gtest.NotSending(t, phf.ReqCh)
+		mfx, m := advance(ctx, t, time.Minute)
+		defer cancel()
 
-			// With a second vote, we have exceeded minority power, so there must be a fetch request.
-			voteMap[string(ph1.Header.Hash)] = []int{1}
-			res = voter.HandleProofs(ctx, 1, 0, voteMap)
-			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, res)
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+		lateVotes := map[string][]int{"": {3}}
+		require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrevoteProofs(ctx, tmconsensus.PrevoteSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs:     mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, lateVotes),
+		}))
+		require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs:     mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, lateVotes),
+		}))
 
-			req := gtest.ReceiveSoon(t, phf.ReqCh)
-			require.Equal(t, uint64(1), req.Height)
-			require.Equal(t, string(ph1.Header.Hash), req.BlockHash)
-			require.NoError(t, req.Ctx.Err()) // Context is non-nil and has not been canceled.
+		// The late votes are in the round store.
+		_, prevotes, precommits, err := mfx.Cfg.RoundStore.LoadRoundState(ctx, 1, 0)
+		require.NoError(t, err)
+		require.Len(t, prevotes.BlockSignatures[""], 1)
+		require.Len(t, precommits.BlockSignatures[""], 4)
 
-			// And with a third vote, there is no new request.
-			voteMap[string(ph1.Header.Hash)] = []int{2}
-			res = voter.HandleProofs(ctx, 1, 0, voteMap)
-			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, res)
+		// And the voting view is unaffected.
+		var vrv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vrv))
+		require.Equal(t, uint32(1), vrv.Round)
+		require.Empty(t, vrv.PrevoteProofs)
+		require.Empty(t, vrv.PrecommitProofs)
+	})
 
-			gtest.NotSending(t, phf.ReqCh)
+	t.Run("late votes after the window are rejected", func(t *testing.T) {
+		t.Parallel()
 
-			// Drain the voting view before we send the proposed header via the fetch channel.
-			vrv := gtest.ReceiveSoon(t, mfx.GossipStrategyOut).Voting
-			require.Empty(t, vrv.ProposedHeaders) // Sanity check.
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
-			gtest.SendSoon(t, phf.FetchedCh, ph1)
+		mfx, m := advance(ctx, t, time.Millisecond)
+		defer cancel()
 
-			if vt.Name == "prevote" {
-				// The arrival of the fetched header should be reflected in the voting view.
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+		proof := tmconsensus.PrecommitSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, map[string][]int{
+				"": {3},
+			}),
+		}
+		require.Eventually(t, func() bool {
+			return m.HandlePrecommitProofs(ctx, proof) == tmconsensus.HandleVoteProofsRoundTooOld
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("without a grace period, late votes are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx, m := advance(ctx, t, 0)
+		defer cancel()
+
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+		require.Equal(t, tmconsensus.HandleVoteProofsRoundTooOld, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+			Height:     1,
+			Round:      0,
+			PubKeyHash: keyHash,
+			Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, map[string][]int{
+				"": {3},
+			}),
+		}))
+	})
+}
+
+func TestMirror_votesAfterNextRound(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		h    uint64
+		r    uint32
+	}{
+		{name: "later round in voting height", h: 1, r: 2},
+		{name: "future height", h: 3, r: 0},
+	} {
+		tc := tc
+		for _, vt := range voteTypes {
+			vt := vt
+			t.Run(vt.Name+" at "+tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				mfx := tmmirrortest.NewFixture(ctx, t, 2)
+
+				m := mfx.NewMirror()
+				defer m.Wait()
+				defer cancel()
+
+				voter := vt.VoterFunc(mfx, m)
+				res := voter.HandleProofs(ctx, tc.h, tc.r, map[string][]int{"": {0}})
+				require.Equal(t, tmconsensus.HandleVoteProofsTooFarInFuture, res)
+
+				// The votes were not buffered, so the voting view is unaffected.
+				var vrv tmconsensus.VersionedRoundView
+				require.NoError(t, m.VotingView(ctx, &vrv))
+				require.Equal(t, uint64(1), vrv.Height)
+				require.Zero(t, vrv.Round)
+				require.Empty(t, vrv.PrevoteProofs)
+				require.Empty(t, vrv.PrecommitProofs)
+			})
+		}
+	}
+}
+
+func TestMirror_fetchProposedBlock(t *testing.T) {
+	for _, vt := range voteTypes {
+		vt := vt
+		t.Run("fetch initiated when "+vt.Name+"s exceed minority voting power", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			mfx := tmmirrortest.NewFixture(ctx, t, 4)
+
+			phf := tmelinktest.NewPHFetcher(1, 1)
+			mfx.Cfg.ProposedHeaderFetcher = phf.ProposedHeaderFetcher()
+
+			m := mfx.NewMirror()
+			defer m.Wait()
+			defer cancel()
+
+			voter := vt.VoterFunc(mfx, m)
+
+			// Make the proposed header but don't give it to the mirror.
+			ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+			mfx.Fx.SignProposal(ctx, &ph1, 0)
+
+			// With one ote there is no fetch request.
+			voteMap := map[string][]int{
+				string(ph1.Header.Hash): {0},
+			}
+
+			res := voter.HandleProofs(ctx, 1, 0, voteMap)
+			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, res)
+
+			gtest.NotSending(t, phf.ReqCh)
+
+			// With a second vote, we have exceeded minority power, so there must be a fetch request.
+			voteMap[string(ph1.Header.Hash)] = []int{1}
+			res = voter.HandleProofs(ctx, 1, 0, voteMap)
+			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, res)
+
+			req := gtest.ReceiveSoon(t, phf.ReqCh)
+			require.Equal(t, uint64(1), req.Height)
+			require.Equal(t, string(ph1.Header.Hash), req.BlockHash)
+			require.NoError(t, req.Ctx.Err()) // Context is non-nil and has not been canceled.
+
+			// And with a third vote, there is no new request.
+			voteMap[string(ph1.Header.Hash)] = []int{2}
+			res = voter.HandleProofs(ctx, 1, 0, voteMap)
+			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, res)
+
+			gtest.NotSending(t, phf.ReqCh)
+
+			// Drain the voting view before we send the proposed header via the fetch channel.
+			vrv := gtest.ReceiveSoon(t, mfx.GossipStrategyOut).Voting
+			require.Empty(t, vrv.ProposedHeaders) // Sanity check.
+
+			gtest.SendSoon(t, phf.FetchedCh, ph1)
+
+			if vt.Name == "prevote" {
+				// The arrival of the fetched header should be reflected in the voting view.
 				vrv = gtest.ReceiveSoon(t, mfx.GossipStrategyOut).Voting
 				require.Equal(t, []tmconsensus.ProposedHeader{ph1}, vrv.ProposedHeaders)
 
@@ -1780,6 +2803,65 @@ func TestMirror_fetchProposedBlock(t *testing.T) {
 		// And its arrival should have canceled the context, as a matter of cleanup.
 		require.Error(t, req.Ctx.Err())
 	})
+
+	t.Run("fetch requests are deferred at the buffer limit", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+
+		phf := tmelinktest.NewPHFetcher(2, 2)
+		mfx.Cfg.ProposedHeaderFetcher = phf.ProposedHeaderFetcher()
+		mfx.Cfg.FetchedHeaderBufferLimit = 1
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		// Two distinct proposed headers that the mirror does not have.
+		ph1A := mfx.Fx.NextProposedHeader([]byte("app_data_1A"), 0)
+		mfx.Fx.SignProposal(ctx, &ph1A, 0)
+		ph1B := mfx.Fx.NextProposedHeader([]byte("app_data_1B"), 1)
+		mfx.Fx.SignProposal(ctx, &ph1B, 1)
+
+		keyHash, _ := mfx.Fx.ValidatorHashes()
+		handlePrevotes := func(voteMap map[string][]int) {
+			res := m.HandlePrevoteProofs(ctx, tmconsensus.PrevoteSparseProof{
+				Height: 1,
+				Round:  0,
+
+				PubKeyHash: keyHash,
+
+				Proofs: mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, voteMap),
+			})
+			require.Equal(t, tmconsensus.HandleVoteProofsAccepted, res)
+		}
+
+		// Minority prevotes for A cause a fetch request.
+		handlePrevotes(map[string][]int{string(ph1A.Header.Hash): {0, 1}})
+
+		reqA := gtest.ReceiveSoon(t, phf.ReqCh)
+		require.Equal(t, string(ph1A.Header.Hash), reqA.BlockHash)
+
+		// Minority prevotes for B would cause a fetch request,
+		// but the limit of one outstanding fetch has been reached.
+		handlePrevotes(map[string][]int{string(ph1B.Header.Hash): {2, 3}})
+		gtest.NotSendingSoon(t, phf.ReqCh)
+
+		// Once the fetched A header is consumed,
+		// the mirror resumes and requests B.
+		gtest.SendSoon(t, phf.FetchedCh, ph1A)
+
+		reqB := gtest.ReceiveSoon(t, phf.ReqCh)
+		require.Equal(t, uint64(1), reqB.Height)
+		require.Equal(t, string(ph1B.Header.Hash), reqB.BlockHash)
+		require.NoError(t, reqB.Ctx.Err())
+
+		// A's fetch was cleaned up as usual.
+		require.Error(t, reqA.Ctx.Err())
+	})
 }
 
 func TestMirror_nextRound(t *testing.T) {
@@ -3260,6 +4342,500 @@ func TestMirror_replayedHeaders(t *testing.T) {
 	})
 }
 
+func TestMirror_IngestTrustedHeaders(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+		require.Error(t, m.IngestTrustedHeaders(ctx, []tmconsensus.CommittedHeader{
+			{Header: ph1.Header},
+		}))
+	})
+
+	t.Run("advances height without fetching", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+		mfx.Cfg.AllowTrustedHeaderIngestion = true
+
+		phf := tmelinktest.NewPHFetcher(1, 1)
+		mfx.Cfg.ProposedHeaderFetcher = phf.ProposedHeaderFetcher()
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		// Build a chain of three committed headers,
+		// where each commit proof is taken from the following header.
+		var chs []tmconsensus.CommittedHeader
+		ph := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+		for h := uint64(1); h <= 3; h++ {
+			precommitProofs := mfx.Fx.PrecommitProofMap(ctx, h, 0, map[string][]int{
+				string(ph.Header.Hash): {0, 1, 2, 3},
+			})
+			mfx.Fx.CommitBlock(ph.Header, []byte(fmt.Sprintf("app_state_height_%d", h)), 0, precommitProofs)
+
+			next := mfx.Fx.NextProposedHeader([]byte(fmt.Sprintf("app_data_%d", h+1)), 0)
+			chs = append(chs, tmconsensus.CommittedHeader{
+				Header: ph.Header,
+				Proof:  next.Header.PrevCommitProof,
+			})
+			ph = next
+		}
+
+		require.NoError(t, m.IngestTrustedHeaders(ctx, chs))
+
+		var vv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vv))
+		require.Equal(t, uint64(4), vv.Height)
+		require.Zero(t, vv.Round)
+
+		require.NoError(t, m.CommittingView(ctx, &vv))
+		require.Equal(t, uint64(3), vv.Height)
+		require.Equal(t, chs[2].Header, vv.ProposedHeaders[0].Header)
+
+		// Every trusted header was saved as committed.
+		for _, ch := range chs[:2] {
+			got, err := mfx.Cfg.CommittedHeaderStore.LoadCommittedHeader(ctx, ch.Header.Height)
+			require.NoError(t, err)
+			require.Equal(t, ch, got)
+		}
+
+		// And the fetcher was never asked for any of the headers.
+		gtest.NotSending(t, phf.ReqCh)
+
+		// Overlapping headers below the voting height are ignored.
+		require.NoError(t, m.IngestTrustedHeaders(ctx, chs))
+		require.NoError(t, m.VotingView(ctx, &vv))
+		require.Equal(t, uint64(4), vv.Height)
+	})
+
+	t.Run("skips signature verification", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+		mfx.Cfg.AllowTrustedHeaderIngestion = true
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+		precommitProofs := mfx.Fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 2, 3},
+		})
+		mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_height_1"), 0, precommitProofs)
+		ph2 := mfx.Fx.NextProposedHeader([]byte("app_data_2"), 0)
+
+		// Corrupt every signature, which would fail verification.
+		proof := ph2.Header.PrevCommitProof.Clone()
+		for _, sig := range proof.Proofs[string(ph1.Header.Hash)] {
+			sig.Sig[0]++
+		}
+
+		// The same header is rejected as a replayed header.
+		replayResp := make(chan tmelink.ReplayedHeaderResponse, 1)
+		gtest.SendSoon(t, mfx.ReplayedHeadersIn, tmelink.ReplayedHeaderRequest{
+			Header: ph1.Header,
+			Proof:  proof,
+			Resp:   replayResp,
+		})
+		resp := gtest.ReceiveSoon(t, replayResp)
+		require.ErrorAs(t, resp.Err, new(tmelink.ReplayedHeaderValidationError))
+
+		require.NoError(t, m.IngestTrustedHeaders(ctx, []tmconsensus.CommittedHeader{
+			{Header: ph1.Header, Proof: proof},
+		}))
+
+		var vv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vv))
+		require.Equal(t, uint64(2), vv.Height)
+	})
+
+	t.Run("still checks hash and voting power", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mfx := tmmirrortest.NewFixture(ctx, t, 4)
+		mfx.Cfg.AllowTrustedHeaderIngestion = true
+
+		m := mfx.NewMirror()
+		defer m.Wait()
+		defer cancel()
+
+		ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+
+		// Only half the voting power.
+		minorityProof := tmconsensus.CommitProof{
+			Round:      0,
+			PubKeyHash: string(ph1.Header.ValidatorSet.PubKeyHash),
+			Proofs: tmconsensus.FullProofsToSparse(mfx.Fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+				string(ph1.Header.Hash): {0, 1},
+			})).BlockSignatures,
+		}
+		require.Error(t, m.IngestTrustedHeaders(ctx, []tmconsensus.CommittedHeader{
+			{Header: ph1.Header, Proof: minorityProof},
+		}))
+
+		precommitProofs := mfx.Fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 2, 3},
+		})
+		mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_height_1"), 0, precommitProofs)
+		ph2 := mfx.Fx.NextProposedHeader([]byte("app_data_2"), 0)
+
+		// A header whose hash does not match its content.
+		badHeader := ph1.Header
+		badHeader.DataID = []byte("different_data")
+		require.Error(t, m.IngestTrustedHeaders(ctx, []tmconsensus.CommittedHeader{
+			{Header: badHeader, Proof: ph2.Header.PrevCommitProof},
+		}))
+
+		var vv tmconsensus.VersionedRoundView
+		require.NoError(t, m.VotingView(ctx, &vv))
+		require.Equal(t, uint64(1), vv.Height)
+	})
+}
+
+func TestMirror_BeaconOut(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mfx := tmmirrortest.NewFixture(ctx, t, 4)
+	beaconCh := make(chan tmelink.Beacon, 1)
+	mfx.Cfg.BeaconOut = beaconCh
+
+	m := mfx.NewMirror()
+	defer m.Wait()
+	defer cancel()
+
+	// Locally, the mirror sees every precommit for height 1,
+	// but the canonical proof in the header at height 2 only has three.
+	ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+	localProof1 := tmconsensus.CommitProof{
+		Round:      0,
+		PubKeyHash: string(ph1.Header.ValidatorSet.PubKeyHash),
+		Proofs: tmconsensus.FullProofsToSparse(mfx.Fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 2, 3},
+		})).BlockSignatures,
+	}
+	mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_height_1"), 0, mfx.Fx.PrecommitProofMap(ctx, 1, 0, map[string][]int{
+		string(ph1.Header.Hash): {0, 1, 2},
+	}))
+
+	ph2 := mfx.Fx.NextProposedHeader([]byte("app_data_2"), 0)
+	mfx.Fx.CommitBlock(ph2.Header, []byte("app_state_height_2"), 0, mfx.Fx.PrecommitProofMap(ctx, 2, 0, map[string][]int{
+		string(ph2.Header.Hash): {0, 1, 2, 3},
+	}))
+	ph3 := mfx.Fx.NextProposedHeader([]byte("app_data_3"), 0)
+
+	replay := func(h tmconsensus.Header, proof tmconsensus.CommitProof) {
+		t.Helper()
+		respCh := make(chan tmelink.ReplayedHeaderResponse, 1)
+		gtest.SendSoon(t, mfx.ReplayedHeadersIn, tmelink.ReplayedHeaderRequest{
+			Header: h,
+			Proof:  proof,
+			Resp:   respCh,
+		})
+		require.NoError(t, gtest.ReceiveSoon(t, respCh).Err)
+	}
+
+	// Committing height 1 does not emit a beacon yet,
+	// because its canonical proof is not known until height 2 is committed.
+	replay(ph1.Header, localProof1)
+	gtest.NotSendingSoon(t, beaconCh)
+
+	replay(ph2.Header, ph3.Header.PrevCommitProof)
+	b := gtest.ReceiveSoon(t, beaconCh)
+	require.Equal(t, tmelink.NewBeacon(1, ph1.Header.Hash, ph2.Header.PrevCommitProof), b)
+	require.NotEqual(t, tmelink.NewBeacon(1, ph1.Header.Hash, localProof1).Seed, b.Seed)
+}
+
+func TestMirror_SafetyViolationOut(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mfx := tmmirrortest.NewFixture(ctx, t, 4)
+	svCh := make(chan tmelink.SafetyViolation, 1)
+	mfx.Cfg.SafetyViolationOut = svCh
+
+	m := mfx.NewMirror()
+	defer m.Wait()
+	defer cancel()
+
+	// Two different headers proposed at height 1, round 0.
+	ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+	mfx.Fx.SignProposal(ctx, &ph1, 0)
+	ph1Conflict := mfx.Fx.NextProposedHeader([]byte("app_data_1_conflict"), 1)
+	mfx.Fx.SignProposal(ctx, &ph1Conflict, 1)
+	require.NotEqual(t, ph1.Header.Hash, ph1Conflict.Header.Hash)
+
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+	// Everyone precommits for the first header, so the mirror commits it.
+	keyHash, _ := mfx.Fx.ValidatorHashes()
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     1,
+		Round:      0,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 2, 3},
+		}),
+	}))
+
+	var vv tmconsensus.VersionedRoundView
+	require.NoError(t, m.CommittingView(ctx, &vv))
+	require.Equal(t, uint64(1), vv.Height)
+
+	// No violation yet.
+	gtest.NotSending(t, svCh)
+
+	// Then three of the four validators also precommit for the conflicting header,
+	// producing a second valid commit for height 1.
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     1,
+		Round:      0,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, map[string][]int{
+			string(ph1Conflict.Header.Hash): {0, 1, 2},
+		}),
+	}))
+
+	sv := gtest.ReceiveSoon(t, svCh)
+	require.Equal(t, uint64(1), sv.Height)
+	require.Zero(t, sv.CommittedRound)
+	require.Equal(t, ph1.Header.Hash, sv.CommittedBlockHash)
+	require.Zero(t, sv.ConflictingRound)
+	require.Equal(t, ph1Conflict.Header.Hash, sv.ConflictingBlockHash)
+
+	// The evidence only contains the conflicting precommits.
+	require.Zero(t, sv.Evidence.Round)
+	require.Equal(t, keyHash, sv.Evidence.PubKeyHash)
+	require.Len(t, sv.Evidence.Proofs, 1)
+	require.NotEmpty(t, sv.Evidence.Proofs[string(ph1Conflict.Header.Hash)])
+
+	// An additional precommit for the same conflicting block is not reported again.
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     1,
+		Round:      0,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, map[string][]int{
+			string(ph1Conflict.Header.Hash): {0, 1, 2, 3},
+		}),
+	}))
+	gtest.NotSendingSoon(t, svCh)
+}
+
+func TestMirror_SafetyViolationOut_otherRoundsAndHeights(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mfx := tmmirrortest.NewFixture(ctx, t, 4)
+	svCh := make(chan tmelink.SafetyViolation, 1)
+	mfx.Cfg.SafetyViolationOut = svCh
+
+	m := mfx.NewMirror()
+	defer m.Wait()
+	defer cancel()
+
+	keyHash, _ := mfx.Fx.ValidatorHashes()
+
+	// Commit height 1 in round 0.
+	ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+	mfx.Fx.SignProposal(ctx, &ph1, 0)
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+
+	voteMap1 := map[string][]int{
+		string(ph1.Header.Hash): {0, 1, 2, 3},
+	}
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     1,
+		Round:      0,
+		PubKeyHash: keyHash,
+		Proofs:     mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, voteMap1),
+	}))
+
+	// A majority for a different block in a later round at the committing height
+	// is compared against the committing view.
+	conflictHash1 := "conflicting_block_1"
+	require.Equal(t, tmconsensus.HandleVoteProofsRoundTooOld, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     1,
+		Round:      2,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 2, map[string][]int{
+			conflictHash1: {0, 1, 2},
+		}),
+	}))
+
+	sv := gtest.ReceiveSoon(t, svCh)
+	require.Equal(t, uint64(1), sv.Height)
+	require.Zero(t, sv.CommittedRound)
+	require.Equal(t, ph1.Header.Hash, sv.CommittedBlockHash)
+	require.Equal(t, uint32(2), sv.ConflictingRound)
+	require.Equal(t, []byte(conflictHash1), sv.ConflictingBlockHash)
+	require.Equal(t, uint32(2), sv.Evidence.Round)
+	require.Len(t, sv.Evidence.Proofs, 1)
+	require.NotEmpty(t, sv.Evidence.Proofs[conflictHash1])
+
+	// Commit height 2, so that height 1 is saved to the header store.
+	mfx.Fx.CommitBlock(ph1.Header, []byte("app_state_1"), 0, mfx.Fx.PrecommitProofMap(ctx, 1, 0, voteMap1))
+	ph2 := mfx.Fx.NextProposedHeader([]byte("app_data_2"), 0)
+	mfx.Fx.SignProposal(ctx, &ph2, 0)
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph2))
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     2,
+		Round:      0,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 2, 0, map[string][]int{
+			string(ph2.Header.Hash): {0, 1, 2, 3},
+		}),
+	}))
+
+	var vv tmconsensus.VersionedRoundView
+	require.NoError(t, m.CommittingView(ctx, &vv))
+	require.Equal(t, uint64(2), vv.Height)
+	_, err := mfx.Cfg.CommittedHeaderStore.LoadCommittedHeader(ctx, 1)
+	require.NoError(t, err)
+
+	// The same conflicting precommits, now for a height before committing,
+	// are not reported a second time.
+	require.Equal(t, tmconsensus.HandleVoteProofsRoundTooOld, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     1,
+		Round:      2,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 2, map[string][]int{
+			conflictHash1: {0, 1, 2, 3},
+		}),
+	}))
+	gtest.NotSendingSoon(t, svCh)
+
+	// But a conflicting block in another round of the stored height is reported,
+	// using the committed header from the store.
+	conflictHash2 := "conflicting_block_2"
+	require.Equal(t, tmconsensus.HandleVoteProofsRoundTooOld, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     1,
+		Round:      1,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 1, map[string][]int{
+			conflictHash2: {1, 2, 3},
+		}),
+	}))
+
+	sv = gtest.ReceiveSoon(t, svCh)
+	require.Equal(t, uint64(1), sv.Height)
+	require.Zero(t, sv.CommittedRound)
+	require.Equal(t, ph1.Header.Hash, sv.CommittedBlockHash)
+	require.Equal(t, uint32(1), sv.ConflictingRound)
+	require.Equal(t, []byte(conflictHash2), sv.ConflictingBlockHash)
+
+	// Precommits without a majority are not a violation.
+	require.Equal(t, tmconsensus.HandleVoteProofsRoundTooOld, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     1,
+		Round:      3,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 3, map[string][]int{
+			"conflicting_block_3": {0, 1},
+		}),
+	}))
+	gtest.NotSendingSoon(t, svCh)
+}
+
+func TestMirror_MinVotePowerToGossip(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mfx := tmmirrortest.NewFixture(ctx, t, 4)
+
+	// The fixture's validators have descending power,
+	// so only the last validator is below the floor.
+	vals := mfx.Fx.Vals()
+	mfx.Cfg.MinVotePowerToGossip = vals[2].Power
+	require.Less(t, vals[3].Power, mfx.Cfg.MinVotePowerToGossip)
+
+	m := mfx.NewMirror()
+	defer m.Wait()
+	defer cancel()
+
+	// Drain initial gossip strategy output.
+	_ = gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+
+	ph1 := mfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+	mfx.Fx.SignProposal(ctx, &ph1, 0)
+	require.Equal(t, tmconsensus.HandleProposedHeaderAccepted, m.HandleProposedHeader(ctx, ph1))
+	_ = gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+
+	keyHash, _ := mfx.Fx.ValidatorHashes()
+	hash1 := string(ph1.Header.Hash)
+
+	// A prevote from only the below-floor validator is not gossiped at all.
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrevoteProofs(ctx, tmconsensus.PrevoteSparseProof{
+		Height:     1,
+		Round:      0,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrevoteProofMap(ctx, 1, 0, map[string][]int{
+			hash1: {3},
+		}),
+	}))
+	gso := gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+	require.NotContains(t, gso.Voting.PrevoteProofs, hash1)
+
+	// But it is still counted.
+	var vrv tmconsensus.VersionedRoundView
+	require.NoError(t, m.VotingView(ctx, &vrv))
+	require.Equal(t, vals[3].Power, vrv.VoteSummary.PrevoteBlockPower[hash1])
+
+	// Everyone precommits, so the block is committed.
+	require.Equal(t, tmconsensus.HandleVoteProofsAccepted, m.HandlePrecommitProofs(ctx, tmconsensus.PrecommitSparseProof{
+		Height:     1,
+		Round:      0,
+		PubKeyHash: keyHash,
+		Proofs: mfx.Fx.SparsePrecommitProofMap(ctx, 1, 0, map[string][]int{
+			hash1: {0, 1, 2, 3},
+		}),
+	}))
+
+	// The gossiped committing view lacks the below-floor precommit.
+	gso = gtest.ReceiveSoon(t, mfx.GossipStrategyOut)
+	require.NotNil(t, gso.Committing)
+	var bs bitset.BitSet
+	gso.Committing.PrecommitProofs[hash1].SignatureBitSet(&bs)
+	require.Equal(t, uint(3), bs.Count())
+	require.False(t, bs.Test(3))
+
+	// The commit proof still includes it.
+	require.NoError(t, m.VotingView(ctx, &vrv))
+	require.Equal(t, uint64(2), vrv.Height)
+	require.Len(t, vrv.PrevCommitProof.Proofs[hash1], 4)
+
+	require.NoError(t, m.CommittingView(ctx, &vrv))
+	vrv.PrecommitProofs[hash1].SignatureBitSet(&bs)
+	require.Equal(t, uint(4), bs.Count())
+}
+
 func TestMirror_metrics(t *testing.T) {
 	t.Parallel()
 
@@ -3445,3 +5021,16 @@ func TestMirror_stateMachineCatchup_lateInitialization(t *testing.T) {
 	require.False(t, rer.IsCH())
 	require.True(t, rer.IsVRV())
 }
+
+// fixedLenSignatureScheme wraps a SignatureScheme
+// and reports a fixed signature length,
+// in order to test rejection of wrongly sized signatures.
+type fixedLenSignatureScheme struct {
+	tmconsensus.SignatureScheme
+
+	N int
+}
+
+func (s fixedLenSignatureScheme) SignatureLen() (int, bool) {
+	return s.N, true
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/bits-and-blooms/bitset"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
 	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmmirror"
 	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmmirror/internal/tmi"
 	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmmirror/tmmirrortest"
@@ -40,7 +41,7 @@ func TestFixture_CommitInitialHeight(t *testing.T) {
 			require.Len(t, phs, 1)
 
 			ph := phs[0]
-			require.Equal(t, []byte("initial_height"), ph.Header.DataID)
+			require.Equal(t, tmconsensus.DataID("initial_height"), ph.Header.DataID)
 
 			require.Len(t, precommits.BlockSignatures, 1)
 			fullPrecommits, err := precommits.ToFullPrecommitProofMap(
@@ -0,0 +1,82 @@
+package tmstate
+
+import (
+	"context"
+	"sync"
+)
+
+// asyncObserver calls an observer function for each value
+// that the state machine reports to it,
+// on a dedicated goroutine so that a slow observer never blocks the kernel.
+// Values are passed to the function in the order they were observed.
+//
+// The queue is not bounded, because Observe must never block or drop a value.
+// The state machine only reports a few values per round,
+// but the queue keeps growing for as long as the observer function
+// is slower than the rate of rounds,
+// so observer functions must keep up with consensus on average.
+type asyncObserver[T any] struct {
+	fn func(T)
+
+	mu    sync.Mutex
+	queue []T
+
+	// 1-buffered, signaling that the queue has new entries.
+	ready chan struct{}
+
+	done chan struct{}
+}
+
+func newAsyncObserver[T any](ctx context.Context, fn func(T)) *asyncObserver[T] {
+	o := &asyncObserver[T]{
+		fn: fn,
+
+		ready: make(chan struct{}, 1),
+
+		done: make(chan struct{}),
+	}
+
+	go o.run(ctx)
+
+	return o
+}
+
+// Wait blocks until o's background goroutine has finished.
+func (o *asyncObserver[T]) Wait() {
+	<-o.done
+}
+
+// Observe queues v for the observer function without blocking.
+// The caller must not modify v afterwards.
+func (o *asyncObserver[T]) Observe(v T) {
+	o.mu.Lock()
+	o.queue = append(o.queue, v)
+	o.mu.Unlock()
+
+	select {
+	case o.ready <- struct{}{}:
+	default:
+		// Already signaled.
+	}
+}
+
+func (o *asyncObserver[T]) run(ctx context.Context) {
+	defer close(o.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.ready:
+		}
+
+		o.mu.Lock()
+		q := o.queue
+		o.queue = nil
+		o.mu.Unlock()
+
+		for _, v := range q {
+			o.fn(v)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tmstate
+
+import (
+	"context"
+
+	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmemetrics"
+	"github.com/gordian-engine/gordian/tm/tmengine/tmelink"
+)
+
+// blockDataArrivalQueue reads block data arrivals from the driver's channel
+// into a bounded buffer that the kernel reads from.
+//
+// The driver is never blocked by a busy kernel.
+// When the buffer is full, the oldest queued arrival is discarded
+// to make room for the new one,
+// as newer arrivals are more likely to be relevant to the current round.
+type blockDataArrivalQueue struct {
+	in  <-chan tmelink.BlockDataArrival
+	out chan tmelink.BlockDataArrival
+
+	// Nil if not collecting metrics.
+	mc *tmemetrics.Collector
+
+	done chan struct{}
+}
+
+func newBlockDataArrivalQueue(
+	ctx context.Context,
+	in <-chan tmelink.BlockDataArrival,
+	size int,
+	mc *tmemetrics.Collector,
+) *blockDataArrivalQueue {
+	q := &blockDataArrivalQueue{
+		in:  in,
+		out: make(chan tmelink.BlockDataArrival, size),
+
+		mc: mc,
+
+		done: make(chan struct{}),
+	}
+
+	go q.run(ctx)
+
+	return q
+}
+
+// Out returns the channel for the kernel to read arrivals from.
+// The kernel may drain the channel without blocking,
+// as it does when gathering queued arrivals.
+func (q *blockDataArrivalQueue) Out() <-chan tmelink.BlockDataArrival {
+	return q.out
+}
+
+// Wait blocks until q's background goroutine has finished.
+func (q *blockDataArrivalQueue) Wait() {
+	<-q.done
+}
+
+func (q *blockDataArrivalQueue) run(ctx context.Context) {
+	defer close(q.done)
+
+	for {
+		var a tmelink.BlockDataArrival
+		select {
+		case <-ctx.Done():
+			return
+		case a = <-q.in:
+		}
+
+		q.push(a)
+	}
+}
+
+// push adds a to the output buffer,
+// discarding the oldest buffered arrivals as needed.
+func (q *blockDataArrivalQueue) push(a tmelink.BlockDataArrival) {
+	dropped := false
+	for {
+		select {
+		case q.out <- a:
+			if dropped && q.mc != nil {
+				q.mc.IncrementDroppedBlockDataArrivals()
+			}
+			return
+		default:
+		}
+
+		// The buffer is full.
+		// The kernel may have just read a value,
+		// so this receive must not block either.
+		select {
+		case <-q.out:
+			dropped = true
+		default:
+		}
+	}
+}
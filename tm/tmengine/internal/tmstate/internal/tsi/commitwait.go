@@ -0,0 +1,83 @@
+package tsi
+
+import "fmt"
+
+// CommitEvent is an event that moves a round lifecycle
+// through the end of a committed round.
+// See [*RoundLifecycle.HandleCommitEvent] for the transitions.
+type CommitEvent uint8
+
+const (
+	// The driver's finalization response has been handled and stored,
+	// so the round lifecycle's Finalized fields are set.
+	CommitEventFinalized CommitEvent = iota + 1
+
+	// The commit wait is over,
+	// either because the commit wait timer elapsed,
+	// because the mirror reported that the height was committed,
+	// or because the state machine was configured to end commit wait early.
+	CommitEventWaitElapsed
+)
+
+// HasFinalization reports whether rlc holds the driver's finalization
+// for the current round.
+func (rlc *RoundLifecycle) HasFinalization() bool {
+	return len(rlc.FinalizedValSet.Validators) > 0
+}
+
+// HandleCommitEvent applies e to rlc and reports whether
+// the state machine must now advance to the next height.
+//
+// The commit wait and the finalization are independent,
+// and the height only advances once both have happened, in either order:
+//
+//   - In [StepCommitWait] without a finalization,
+//     CommitEventFinalized stays in StepCommitWait,
+//     and CommitEventWaitElapsed moves to [StepAwaitingFinalization].
+//   - In StepCommitWait with a finalization,
+//     CommitEventWaitElapsed advances the height.
+//   - In StepAwaitingFinalization,
+//     CommitEventFinalized advances the height,
+//     and a repeated CommitEventWaitElapsed has no effect.
+//
+// CommitEventWaitElapsed also stops any outstanding step timer.
+// It is a bug to deliver CommitEventWaitElapsed in any other step.
+// CommitEventFinalized in any other step has no effect,
+// as the finalization is held until the round reaches commit wait.
+func (rlc *RoundLifecycle) HandleCommitEvent(e CommitEvent) (advanceHeight bool) {
+	switch e {
+	case CommitEventFinalized:
+		return rlc.S == StepAwaitingFinalization
+
+	case CommitEventWaitElapsed:
+		rlc.CommitWaitElapsed = true
+
+		if rlc.CancelTimer != nil {
+			rlc.CancelTimer()
+		}
+		rlc.StepTimer = nil
+		rlc.CancelTimer = nil
+
+		switch rlc.S {
+		case StepCommitWait:
+			if rlc.HasFinalization() {
+				return true
+			}
+
+			rlc.S = StepAwaitingFinalization
+			return false
+
+		case StepAwaitingFinalization:
+			// Still waiting on the finalization.
+			return false
+
+		default:
+			panic(fmt.Errorf(
+				"BUG: commit wait elapsed during step %s", rlc.S,
+			))
+		}
+
+	default:
+		panic(fmt.Errorf("BUG: unknown commit event %d", e))
+	}
+}
@@ -0,0 +1,94 @@
+package tsi_test
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmstate/internal/tsi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundLifecycle_HandleCommitEvent(t *testing.T) {
+	t.Parallel()
+
+	vs := tmconsensustest.NewStandardFixture(2).ValSet()
+
+	seed := uint64(time.Now().UnixNano())
+	t.Logf("seed: %d", seed)
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	for i := range 500 {
+		var rlc tsi.RoundLifecycle
+		rlc.S = tsi.StepCommitWait
+
+		timerCanceled := 0
+		rlc.StepTimer = make(chan struct{})
+		rlc.CancelTimer = func() { timerCanceled++ }
+
+		// The commit wait may be reported more than once,
+		// e.g. by the mirror's height committed signal and then the timer.
+		events := []tsi.CommitEvent{tsi.CommitEventFinalized, tsi.CommitEventWaitElapsed}
+		for range rng.IntN(3) {
+			events = append(events, tsi.CommitEventWaitElapsed)
+		}
+		rng.Shuffle(len(events), func(i, j int) {
+			events[i], events[j] = events[j], events[i]
+		})
+
+		var finalized, elapsed, advanced bool
+		for _, e := range events {
+			if e == tsi.CommitEventFinalized {
+				rlc.FinalizedValSet = vs
+				finalized = true
+			} else {
+				elapsed = true
+			}
+
+			adv := rlc.HandleCommitEvent(e)
+			require.Falsef(t, adv && advanced, "iteration %d: advanced twice with events %v", i, events)
+			advanced = advanced || adv
+
+			require.Equalf(t, finalized && elapsed, advanced, "iteration %d: events %v", i, events)
+			require.Equal(t, elapsed, rlc.CommitWaitElapsed)
+			require.Equal(t, finalized, rlc.HasFinalization())
+
+			if elapsed {
+				require.Nil(t, rlc.StepTimer)
+				require.Nil(t, rlc.CancelTimer)
+			}
+
+			if !advanced {
+				if elapsed {
+					require.Equal(t, tsi.StepAwaitingFinalization, rlc.S)
+				} else {
+					require.Equal(t, tsi.StepCommitWait, rlc.S)
+				}
+			}
+
+			if advanced {
+				// The caller resets the round lifecycle when advancing the height,
+				// so no further events are delivered for this round.
+				break
+			}
+		}
+
+		require.Truef(t, advanced, "iteration %d: never advanced with events %v", i, events)
+		require.Equal(t, 1, timerCanceled)
+	}
+}
+
+func TestRoundLifecycle_HandleCommitEvent_unexpectedStep(t *testing.T) {
+	t.Parallel()
+
+	var rlc tsi.RoundLifecycle
+	rlc.S = tsi.StepAwaitingPrecommits
+
+	require.False(t, rlc.HandleCommitEvent(tsi.CommitEventFinalized))
+	require.Equal(t, tsi.StepAwaitingPrecommits, rlc.S)
+
+	require.Panics(t, func() {
+		rlc.HandleCommitEvent(tsi.CommitEventWaitElapsed)
+	})
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"log/slog"
 	"runtime/trace"
+	"slices"
+	"time"
 
 	"github.com/gordian-engine/gordian/internal/gchan"
+	"github.com/gordian-engine/gordian/internal/glog"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmemetrics"
 )
 
 // ConsensusManager is a subsystem of the state machine
@@ -17,12 +21,37 @@ type ConsensusManager struct {
 
 	strat tmconsensus.ConsensusStrategy
 
+	// If positive, the maximum duration to wait for the strategy
+	// to respond to ConsiderProposedBlocks, ChooseProposedBlock, or DecidePrecommit.
+	responseTimeout time.Duration
+	rt              StrategyResponseTimer
+
+	// Closed when a strategy call that exceeded the response timeout finally returns.
+	// Nil when there is no such outstanding call.
+	// Only accessed from the kernel goroutine.
+	abandonedCall <-chan struct{}
+
+	// The height and round most recently entered on the strategy,
+	// used to label response timers.
+	// Only accessed from the kernel goroutine.
+	h uint64
+	r uint32
+
+	// If set, proposed headers whose data is not available
+	// are withheld from the strategy.
+	daChecker DataAvailabilityChecker
+	daTimeout time.Duration
+
+	mc *tmemetrics.Collector
+
 	EnterRoundRequests             chan EnterRoundRequest
 	ConsiderProposedBlocksRequests chan ConsiderProposedBlocksRequest
 	ChooseProposedBlockRequests    chan ChooseProposedBlockRequest
 
 	DecidePrecommitRequests chan DecidePrecommitRequest
 
+	RoundJumpedRequests chan RoundJumpedRequest
+
 	done chan struct{}
 }
 
@@ -30,16 +59,34 @@ type ConsensusManager struct {
 // requesting a call to [tmconsensus.ConsensusStrategy.EnterRound].
 type EnterRoundRequest struct {
 	RV     tmconsensus.RoundView
-	Result chan error
+	Result chan EnterRoundResult
 
 	// If the strategy is going to propose a block for this round,
 	// the proposal data must be sent on this channel.
 	ProposalOut chan tmconsensus.Proposal
 }
 
+// EnterRoundResult is the result of a call to [tmconsensus.ConsensusStrategy.EnterRound],
+// along with the proposal delay if the strategy implements [tmconsensus.ProposalDelayer].
+type EnterRoundResult struct {
+	ProposalDelay time.Duration
+	Err           error
+}
+
+// RoundJumpedRequest is the request type sent by the state machine
+// when it jumps ahead to a later round.
+// If the consensus strategy implements [tmconsensus.RoundJumpObserver],
+// the consensus manager calls its RoundJumped method;
+// otherwise the request is ignored.
+type RoundJumpedRequest struct {
+	From, To tmconsensus.RoundPointer
+}
+
 // ConsiderProposedBlocksRequest is the request type sent by the state machine
 // requesting a call to [tmconsensus.ConsensusStrategy.ConsiderProposedBlocks].
 type ConsiderProposedBlocksRequest struct {
+	// PHs is sorted by header hash,
+	// so that the order does not depend on arrival order.
 	PHs    []tmconsensus.ProposedHeader
 	Reason tmconsensus.ConsiderProposedBlocksReason
 	Result chan HashSelection
@@ -64,6 +111,7 @@ func (r *ConsiderProposedBlocksRequest) MarkReasonNewHashes(rlc *RoundLifecycle)
 // ChooseProposedBlockRequest is the request type sent by the state machine
 // requesting a call to [tmconsensus.ConsensusStrategy.ChooseProposedBlock].
 type ChooseProposedBlockRequest struct {
+	// PHs is sorted by header hash, as in [ConsiderProposedBlocksRequest].
 	PHs    []tmconsensus.ProposedHeader
 	Result chan HashSelection
 }
@@ -82,14 +130,56 @@ type HashSelection struct {
 	Err  error
 }
 
+// StrategyResponseTimer starts the timers for the strategy response timeout.
+// The state machine's RoundTimer satisfies this interface.
+type StrategyResponseTimer interface {
+	StrategyResponseTimer(
+		ctx context.Context, height uint64, round uint32, d time.Duration,
+	) (ch <-chan struct{}, cancel func())
+}
+
+// DataAvailabilityChecker reports whether the block data identified by dataID,
+// for the header proposed at the given height and round,
+// is available, for instance retrievable from a data availability layer.
+type DataAvailabilityChecker func(
+	ctx context.Context, height uint64, round uint32, dataID string,
+) (available bool, err error)
+
 // NewConsensusManager returns an initialized ConsensusManager.
+//
+// If responseTimeout is positive, calls to the strategy's
+// ConsiderProposedBlocks, ChooseProposedBlock, and DecidePrecommit methods
+// are abandoned if they do not return within that duration, as timed by rt.
+// Strategies are not required to be safe for concurrent use,
+// so no strategy method is called until an abandoned call has returned.
+//
+// If daChecker is not nil, it is called for each proposed header
+// before the headers are passed to ConsiderProposedBlocks or ChooseProposedBlock,
+// and headers whose data is not available are withheld from the strategy.
+// Each check is limited to daTimeout, if positive.
+// The mc argument may be nil.
 func NewConsensusManager(
-	ctx context.Context, log *slog.Logger, strat tmconsensus.ConsensusStrategy,
+	ctx context.Context,
+	log *slog.Logger,
+	strat tmconsensus.ConsensusStrategy,
+	responseTimeout time.Duration,
+	rt StrategyResponseTimer,
+	daChecker DataAvailabilityChecker,
+	daTimeout time.Duration,
+	mc *tmemetrics.Collector,
 ) *ConsensusManager {
 	m := &ConsensusManager{
 		log:   log,
 		strat: strat,
 
+		responseTimeout: responseTimeout,
+		rt:              rt,
+
+		daChecker: daChecker,
+		daTimeout: daTimeout,
+
+		mc: mc,
+
 		// Currently, the state machine needs to synchronize
 		// with all of the consensus strategy interactions;
 		// therefore all of these channels are unbuffered.
@@ -104,6 +194,7 @@ func NewConsensusManager(
 		ConsiderProposedBlocksRequests: make(chan ConsiderProposedBlocksRequest),
 		ChooseProposedBlockRequests:    make(chan ChooseProposedBlockRequest),
 		DecidePrecommitRequests:        make(chan DecidePrecommitRequest),
+		RoundJumpedRequests:            make(chan RoundJumpedRequest),
 
 		done: make(chan struct{}),
 	}
@@ -142,6 +233,9 @@ func (m *ConsensusManager) kernel(ctx context.Context) {
 
 		case req := <-m.DecidePrecommitRequests:
 			m.handleDecidePrecommit(ctx, req)
+
+		case req := <-m.RoundJumpedRequests:
+			m.handleRoundJumped(ctx, req)
 		}
 	}
 }
@@ -149,27 +243,108 @@ func (m *ConsensusManager) kernel(ctx context.Context) {
 func (m *ConsensusManager) handleEnterRound(ctx context.Context, req EnterRoundRequest) {
 	defer trace.StartRegion(ctx, "handleEnterRound").End()
 
-	err := m.strat.EnterRound(ctx, req.RV, req.ProposalOut)
+	m.h, m.r = req.RV.Height, req.RV.Round
+	if !m.awaitStrategy(ctx, "EnterRound") {
+		// Context canceled.
+		return
+	}
+
+	proposalOut := req.ProposalOut
+	if req.RV.Round > 0 && proposalOut != nil {
+		if p, ok := m.strat.(tmconsensus.PreviousRoundProposer); ok {
+			reuse, dataID := p.PreviousRoundProposal(ctx, req.RV.Height, req.RV.Round-1)
+			if reuse {
+				// The proposal channel is buffered and fresh for every round,
+				// so nothing else could have written to it yet.
+				select {
+				case proposalOut <- tmconsensus.Proposal{DataID: dataID}:
+				default:
+					m.log.Warn(
+						"Dropping re-proposal due to full proposal channel",
+						"height", req.RV.Height, "round", req.RV.Round,
+					)
+				}
+
+				// The strategy already made its proposal for this round.
+				proposalOut = nil
+			}
+		}
+	}
+
+	err := m.strat.EnterRound(ctx, req.RV, proposalOut)
+
+	var d time.Duration
+	if err == nil && proposalOut != nil {
+		if p, ok := m.strat.(tmconsensus.ProposalDelayer); ok {
+			d = p.ProposalDelay(ctx, req.RV.Height, req.RV.Round)
+		}
+	}
 
 	_ = gchan.SendC(
 		ctx, m.log,
-		req.Result, err,
+		req.Result, EnterRoundResult{ProposalDelay: d, Err: err},
 		"sending EnterRound result",
 	)
 }
 
+func (m *ConsensusManager) handleRoundJumped(ctx context.Context, req RoundJumpedRequest) {
+	defer trace.StartRegion(ctx, "handleRoundJumped").End()
+
+	m.h, m.r = req.To.Height, req.To.Round
+
+	o, ok := m.strat.(tmconsensus.RoundJumpObserver)
+	if !ok {
+		return
+	}
+
+	if !m.awaitStrategy(ctx, "RoundJumped") {
+		// Context canceled.
+		return
+	}
+
+	o.RoundJumped(ctx, req.From, req.To)
+}
+
 func (m *ConsensusManager) handleConsiderPBs(ctx context.Context, req ConsiderProposedBlocksRequest) {
 	defer trace.StartRegion(ctx, "handleConsiderPBs").End()
 
-	hash, err := m.strat.ConsiderProposedBlocks(ctx, req.PHs, req.Reason)
-	if err == tmconsensus.ErrProposedBlockChoiceNotReady {
+	if m.daChecker != nil {
+		req.PHs = m.availablePHs(ctx, req.PHs)
+		if len(req.PHs) == 0 {
+			// Nothing to consider yet.
+			// The state machine will make a ChooseProposedBlock call
+			// once its proposal timer elapses.
+			return
+		}
+
+		// Don't report new hashes for headers the strategy will not see.
+		req.Reason.NewProposedBlocks = slices.DeleteFunc(
+			slices.Clone(req.Reason.NewProposedBlocks),
+			func(hash string) bool {
+				return !slices.ContainsFunc(req.PHs, func(ph tmconsensus.ProposedHeader) bool {
+					return string(ph.Header.Hash) == hash
+				})
+			},
+		)
+	}
+
+	sel, timedOut := m.callStrategy(ctx, "ConsiderProposedBlocks", func(ctx context.Context) (string, error) {
+		return m.strat.ConsiderProposedBlocks(ctx, req.PHs, req.Reason)
+	})
+	if timedOut {
+		// A strategy that fails to respond in time is treated as not being ready to choose.
+		// The state machine will make a ChooseProposedBlock call
+		// once its proposal timer elapses.
+		return
+	}
+	if sel.Err == tmconsensus.ErrProposedBlockChoiceNotReady {
 		// Don't bother with a send if we aren't choosing yet.
 		return
 	}
 
 	_ = gchan.SendC(
 		ctx, m.log,
-		req.Result, HashSelection{Hash: hash, Err: err},
+		req.Result, sel,
 		"sending ConsiderProposedBlocks result",
 	)
 }
@@ -177,10 +352,22 @@ func (m *ConsensusManager) handleConsiderPBs(ctx context.Context, req ConsiderPr
 func (m *ConsensusManager) handleChoosePB(ctx context.Context, req ChooseProposedBlockRequest) {
 	defer trace.StartRegion(ctx, "handleChoosePB").End()
 
-	hash, err := m.strat.ChooseProposedBlock(ctx, req.PHs)
+	if m.daChecker != nil {
+		req.PHs = m.availablePHs(ctx, req.PHs)
+	}
+
+	sel, timedOut := m.callStrategy(ctx, "ChooseProposedBlock", func(ctx context.Context) (string, error) {
+		return m.strat.ChooseProposedBlock(ctx, req.PHs)
+	})
+	if timedOut {
+		// The state machine only calls ChooseProposedBlock when a prevote is required,
+		// so the only way to proceed is to prevote nil.
+		sel = HashSelection{}
+	}
+
 	_ = gchan.SendC(
 		ctx, m.log,
-		req.Result, HashSelection{Hash: hash, Err: err},
+		req.Result, sel,
 		"sending ChooseProposedBlock result",
 	)
 }
@@ -188,10 +375,178 @@ func (m *ConsensusManager) handleChoosePB(ctx context.Context, req ChoosePropose
 func (m *ConsensusManager) handleDecidePrecommit(ctx context.Context, req DecidePrecommitRequest) {
 	defer trace.StartRegion(ctx, "handleDecidePrecommit").End()
 
-	hash, err := m.strat.DecidePrecommit(ctx, req.VS)
+	sel, timedOut := m.callStrategy(ctx, "DecidePrecommit", func(ctx context.Context) (string, error) {
+		return m.strat.DecidePrecommit(ctx, req.VS)
+	})
+	if timedOut {
+		// Like ChooseProposedBlock, a precommit is required at this point,
+		// so precommit nil.
+		sel = HashSelection{}
+	}
+
 	_ = gchan.SendC(
 		ctx, m.log,
-		req.Result, HashSelection{Hash: hash, Err: err},
+		req.Result, sel,
 		"sending DecidePrecommit result",
 	)
 }
+
+// availablePHs returns the subset of phs whose block data
+// m.daChecker reports as available.
+// A check that fails or exceeds m.daTimeout is logged
+// and treated as unavailable.
+func (m *ConsensusManager) availablePHs(
+	ctx context.Context, phs []tmconsensus.ProposedHeader,
+) []tmconsensus.ProposedHeader {
+	out := make([]tmconsensus.ProposedHeader, 0, len(phs))
+	for _, ph := range phs {
+		if m.isDataAvailable(ctx, ph.Header.Height, ph.Round, string(ph.Header.DataID)) {
+			out = append(out, ph)
+		}
+	}
+	return out
+}
+
+func (m *ConsensusManager) isDataAvailable(
+	ctx context.Context, height uint64, round uint32, dataID string,
+) bool {
+	if m.daTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.daTimeout)
+		defer cancel()
+	}
+
+	available, err := m.daChecker(ctx, height, round, dataID)
+	if err != nil {
+		m.log.Warn(
+			"Data availability check failed; withholding proposed header from strategy",
+			"height", height, "round", round,
+			"data_id", glog.Hex(dataID),
+			"err", err,
+		)
+		return false
+	}
+
+	return available
+}
+
+// callStrategy calls fn, which must call a single method on m.strat.
+//
+// If m has no response timeout, fn is called directly.
+// Otherwise, fn is called in a separate goroutine,
+// and if it does not return within the response timeout,
+// the context passed to fn is canceled, the timeout is logged and recorded as a metric,
+// and callStrategy returns a zero HashSelection and timedOut=true.
+//
+// If a previously abandoned call still has not returned,
+// callStrategy waits for that call, within the same timeout,
+// through [*ConsensusManager.awaitAbandonedCall], before calling fn.
+func (m *ConsensusManager) callStrategy(
+	ctx context.Context, name string, fn func(context.Context) (string, error),
+) (sel HashSelection, timedOut bool) {
+	if m.responseTimeout <= 0 {
+		sel.Hash, sel.Err = fn(ctx)
+		return sel, false
+	}
+
+	timer, cancelTimer := m.rt.StrategyResponseTimer(ctx, m.h, m.r, m.responseTimeout)
+	defer cancelTimer()
+
+	if !m.awaitAbandonedCall(ctx, timer) {
+		if ctx.Err() != nil {
+			return HashSelection{Err: context.Cause(ctx)}, false
+		}
+		m.recordTimeout(name, true)
+		return HashSelection{}, true
+	}
+
+	resCh := make(chan HashSelection, 1)
+	returned := make(chan struct{})
+
+	callCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer close(returned)
+		h, e := fn(callCtx)
+		resCh <- HashSelection{Hash: h, Err: e}
+	}()
+
+	select {
+	case <-ctx.Done():
+		cancel()
+		return HashSelection{Err: context.Cause(ctx)}, false
+	case sel := <-resCh:
+		cancel()
+		return sel, false
+	case <-timer:
+		// Cancel the call's context in case the strategy respects cancellation,
+		// but do not wait for it to return.
+		cancel()
+		m.abandonedCall = returned
+		m.recordTimeout(name, false)
+		return HashSelection{}, true
+	}
+}
+
+// awaitStrategy is called before a strategy method that has no response timeout,
+// such as EnterRound, to wait for any abandoned call to return.
+// Those methods cannot be skipped,
+// so if the wait exceeds the response timeout, it is logged and recorded,
+// and the wait continues.
+// It returns false only if ctx is canceled.
+func (m *ConsensusManager) awaitStrategy(ctx context.Context, name string) bool {
+	if m.abandonedCall == nil {
+		return true
+	}
+
+	timer, cancelTimer := m.rt.StrategyResponseTimer(ctx, m.h, m.r, m.responseTimeout)
+	defer cancelTimer()
+
+	if m.awaitAbandonedCall(ctx, timer) {
+		return true
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+
+	m.recordTimeout(name, true)
+	return m.awaitAbandonedCall(ctx, nil)
+}
+
+// awaitAbandonedCall blocks until the strategy call most recently abandoned by callStrategy returns,
+// as strategies are not required to be safe for concurrent use.
+// Every strategy call must be preceded by a successful call to awaitAbandonedCall,
+// directly or through awaitStrategy or callStrategy.
+//
+// It returns false if ctx is canceled or if timer is closed first,
+// in which case the abandoned call is still outstanding.
+// A nil timer never closes.
+func (m *ConsensusManager) awaitAbandonedCall(ctx context.Context, timer <-chan struct{}) bool {
+	if m.abandonedCall == nil {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-m.abandonedCall:
+		m.abandonedCall = nil
+		return true
+	case <-timer:
+		return false
+	}
+}
+
+// recordTimeout logs and records a metric for a strategy call
+// that did not respond within the response timeout.
+func (m *ConsensusManager) recordTimeout(name string, blockedByPrevious bool) {
+	m.log.Warn(
+		"Consensus strategy did not respond within timeout",
+		"method", name,
+		"timeout", m.responseTimeout,
+		"blocked_by_previous_call", blockedByPrevious,
+	)
+
+	if m.mc != nil {
+		m.mc.IncrementStrategyTimeouts()
+	}
+}
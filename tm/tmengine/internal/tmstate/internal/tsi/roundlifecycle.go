@@ -53,6 +53,10 @@ type RoundLifecycle struct {
 	// no need to include blocks that were excluded due to app hash mismatches, etc.
 	PrevConsideredHashes map[string]struct{}
 
+	// Hashes of proposed headers that were withheld from the consensus strategy,
+	// so that each filtered header is only counted once in the metrics.
+	FilteredHashes map[string]struct{}
+
 	// Channel to alert Mirror of actions we've taken in this round.
 	// Nil when in replay mode.
 	OutgoingActionsCh chan tmeil.StateMachineRoundAction
@@ -62,6 +66,14 @@ type RoundLifecycle struct {
 	PrevoteHashCh   chan HashSelection
 	PrecommitHashCh chan HashSelection
 
+	// When the consensus strategy requests a proposal delay upon entering the round,
+	// ProposalCh is moved to DelayedProposalCh until ProposalDelayTimer elapses
+	// or the proposal timer elapses, whichever happens first.
+	// The timer and cancel func are produced from the [tmstate.RoundTimer].
+	DelayedProposalCh        chan tmconsensus.Proposal
+	ProposalDelayTimer       <-chan struct{}
+	CancelProposalDelayTimer func()
+
 	// For the driver to write directly.
 	FinalizeRespCh chan tmdriver.FinalizeBlockResponse
 
@@ -92,6 +104,8 @@ func (rlc *RoundLifecycle) Reset(ctx context.Context, h uint64, r uint32) {
 		rlc.StepTimer = nil
 	}
 
+	rlc.EndProposalDelay()
+
 	// These are probably correct as 1-buffered,
 	// so that an accidental stale send would not block.
 	// Although if the send respects rlc.Ctx, that might achieve the same effect.
@@ -107,18 +121,45 @@ func (rlc *RoundLifecycle) Reset(ctx context.Context, h uint64, r uint32) {
 	// The hashes may have been cleared already in some circumstances,
 	// but a second clear won't hurt.
 	clear(rlc.PrevConsideredHashes)
+	clear(rlc.FilteredHashes)
 }
 
 // MarkCatchingUp marks the rlc as catching up,
 // which sets the action-related channels to nil (for earlier GC)
 // and marks the commit wait as having elapsed.
 func (rlc *RoundLifecycle) MarkCatchingUp() {
+	rlc.EndProposalDelay()
 	rlc.ProposalCh = nil
 	rlc.PrevoteHashCh = nil
 	rlc.PrecommitHashCh = nil
 	rlc.CommitWaitElapsed = true
 }
 
+// DelayProposal withholds ProposalCh until the timer elapses,
+// so that a proposal from the consensus strategy is not handled
+// until [*RoundLifecycle.EndProposalDelay] is called.
+// The timer and cancel func are produced from the [tmstate.RoundTimer].
+func (rlc *RoundLifecycle) DelayProposal(timer <-chan struct{}, cancel func()) {
+	rlc.DelayedProposalCh = rlc.ProposalCh
+	rlc.ProposalCh = nil
+	rlc.ProposalDelayTimer, rlc.CancelProposalDelayTimer = timer, cancel
+}
+
+// EndProposalDelay restores ProposalCh if it was withheld by DelayProposal.
+// It is safe to call when there is no outstanding proposal delay.
+func (rlc *RoundLifecycle) EndProposalDelay() {
+	if rlc.CancelProposalDelayTimer != nil {
+		rlc.CancelProposalDelayTimer()
+		rlc.CancelProposalDelayTimer = nil
+		rlc.ProposalDelayTimer = nil
+	}
+
+	if rlc.DelayedProposalCh != nil {
+		rlc.ProposalCh = rlc.DelayedProposalCh
+		rlc.DelayedProposalCh = nil
+	}
+}
+
 func (rlc RoundLifecycle) IsReplaying() bool {
 	return rlc.VRV == nil
 }
@@ -54,10 +54,12 @@ const (
 	// which is what should happen under normal circumstances,
 	// we remain in StepCommitWait until the timeout elapses,
 	// and then "fast-forward" through StepAwaitingFinalization.
+	// See [*RoundLifecycle.HandleCommitEvent] for the full set of transitions.
 	StepCommitWait
 
 	// The commit wait has elapsed, but the app has not yet
 	// finalized the block.
+	// The height advances as soon as the finalization arrives.
 	StepAwaitingFinalization
 )
 
@@ -0,0 +1,80 @@
+package tmstate
+
+import (
+	"time"
+
+	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmeil"
+)
+
+// peerWait holds the state machine's votes at startup,
+// until the gossip strategy reports enough connected peers
+// or until the wait times out.
+// Votes sent to the mirror before the node has any peers
+// would never reach the rest of the network.
+//
+// A peerWait is only accessed from the kernel goroutine.
+type peerWait struct {
+	minPeers int
+
+	counts <-chan int
+
+	// Zero if the wait has no timeout.
+	timeout time.Duration
+
+	// Nil until the timeout timer is started,
+	// and nil if the wait has no timeout.
+	// The timer is created through the state machine's RoundTimer.
+	timer       <-chan struct{}
+	cancelTimer func()
+
+	held []heldVote
+}
+
+// heldVote is a vote that was signed and saved to the action store
+// while the state machine was waiting for peers.
+type heldVote struct {
+	H uint64
+	R uint32
+
+	Action tmeil.StateMachineRoundAction
+}
+
+func newPeerWait(minPeers int, counts <-chan int, timeout time.Duration) *peerWait {
+	return &peerWait{
+		minPeers: minPeers,
+		counts:   counts,
+		timeout:  timeout,
+	}
+}
+
+// StartTimer sets the timer for w's timeout.
+// It must only be called once, and only if w has a positive timeout.
+func (w *peerWait) StartTimer(timer <-chan struct{}, cancel func()) {
+	w.timer = timer
+	w.cancelTimer = cancel
+}
+
+// Counts returns the channel of peer counts,
+// or nil if w is nil.
+func (w *peerWait) Counts() <-chan int {
+	if w == nil {
+		return nil
+	}
+	return w.counts
+}
+
+// TimerC returns the channel of w's timeout timer,
+// or nil if w is nil or has no timeout.
+func (w *peerWait) TimerC() <-chan struct{} {
+	if w == nil {
+		return nil
+	}
+	return w.timer
+}
+
+// Stop stops w's timer, if it has one.
+func (w *peerWait) Stop() {
+	if w.cancelTimer != nil {
+		w.cancelTimer()
+	}
+}
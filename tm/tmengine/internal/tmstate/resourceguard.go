@@ -0,0 +1,124 @@
+package tmstate
+
+import (
+	"time"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmdriver"
+	"github.com/gordian-engine/gordian/tm/tmengine/tmelink"
+)
+
+// resourceGuard pauses the state machine's actions
+// while the configured guard function reports that durable writes are unsafe,
+// for instance because the disk is nearly full.
+// A partial write could corrupt the action, finalization, or state machine store,
+// so paused actions are neither signed nor saved until the guard recovers.
+//
+// A resourceGuard is only accessed from the kernel goroutine.
+type resourceGuard struct {
+	check        func() (ok bool, reason string)
+	pollInterval time.Duration
+
+	// Nil unless resource guard alerts were requested.
+	alertOut chan<- tmelink.ResourceGuardAlert
+
+	// Non-nil while paused.
+	// The poll timer is created through the state machine's RoundTimer.
+	timer       <-chan struct{}
+	cancelTimer func()
+
+	held []pausedAction
+}
+
+// pausedActionKind identifies which action a pausedAction holds.
+type pausedActionKind uint8
+
+const (
+	pausedProposal pausedActionKind = iota + 1
+	pausedPrevote
+	pausedPrecommit
+	pausedFinalization
+	pausedHeightRound
+)
+
+// pausedAction is an action the state machine decided on while paused,
+// before it was signed or saved.
+type pausedAction struct {
+	H uint64
+	R uint32
+
+	Kind pausedActionKind
+
+	// Set when Kind is pausedProposal.
+	Proposal tmconsensus.Proposal
+
+	// Set when Kind is pausedPrevote or pausedPrecommit.
+	TargetHash string
+
+	// Set when Kind is pausedFinalization.
+	Finalization tmdriver.FinalizeBlockResponse
+
+	// There is no additional data when Kind is pausedHeightRound;
+	// the state machine saves its current height and round on resume.
+}
+
+func newResourceGuard(
+	check func() (bool, string),
+	pollInterval time.Duration,
+	alertOut chan<- tmelink.ResourceGuardAlert,
+) *resourceGuard {
+	return &resourceGuard{
+		check:        check,
+		pollInterval: pollInterval,
+		alertOut:     alertOut,
+	}
+}
+
+// Paused reports whether g is currently holding actions.
+// It reports false if g is nil.
+func (g *resourceGuard) Paused() bool {
+	return g != nil && g.timer != nil
+}
+
+// TimerC returns the channel of g's poll timer,
+// or nil if g is nil or not paused.
+func (g *resourceGuard) TimerC() <-chan struct{} {
+	if g == nil {
+		return nil
+	}
+	return g.timer
+}
+
+// pause sets the timer for the next poll of the guard function.
+func (g *resourceGuard) pause(timer <-chan struct{}, cancel func()) {
+	g.timer = timer
+	g.cancelTimer = cancel
+}
+
+// resume stops polling and returns the held actions,
+// clearing them from g.
+func (g *resourceGuard) resume() []pausedAction {
+	g.cancelTimer()
+	g.timer = nil
+	g.cancelTimer = nil
+
+	held := g.held
+	g.held = nil
+	return held
+}
+
+// alert sends a to g's alert output, if one was configured.
+// It reports false if the output was not ready to receive,
+// in which case a is discarded.
+func (g *resourceGuard) alert(a tmelink.ResourceGuardAlert) (sent bool) {
+	if g.alertOut == nil {
+		return true
+	}
+
+	select {
+	case g.alertOut <- a:
+		return true
+	default:
+		return false
+	}
+}
@@ -26,6 +26,26 @@ type RoundTimer interface {
 	PrevoteDelayTimer(ctx context.Context, height uint64, round uint32) (ch <-chan struct{}, cancel func())
 	PrecommitDelayTimer(ctx context.Context, height uint64, round uint32) (ch <-chan struct{}, cancel func())
 	CommitWaitTimer(ctx context.Context, height uint64, round uint32) (ch <-chan struct{}, cancel func())
+
+	// ProposalDelayTimer is for a proposal delay requested by the consensus strategy,
+	// through [tmconsensus.ProposalDelayer].
+	// Unlike the other timers, it runs alongside the proposal timer.
+	ProposalDelayTimer(ctx context.Context, height uint64, round uint32, d time.Duration) (ch <-chan struct{}, cancel func())
+
+	// ResourceGuardPollTimer is for polling the resource guard while durable writes are paused.
+	// It also runs alongside the step timers.
+	ResourceGuardPollTimer(ctx context.Context, height uint64, round uint32, d time.Duration) (ch <-chan struct{}, cancel func())
+
+	// PeerWaitTimer is for the timeout on waiting for peers before voting at startup.
+	// The height and round are those of the state machine when the wait began.
+	// It also runs alongside the step timers.
+	PeerWaitTimer(ctx context.Context, height uint64, round uint32, d time.Duration) (ch <-chan struct{}, cancel func())
+
+	// StrategyResponseTimer is for the consensus strategy response timeout,
+	// including any wait for an earlier abandoned strategy call to return.
+	// The height and round are those of the round most recently entered on the consensus strategy.
+	// It also runs alongside the step timers.
+	StrategyResponseTimer(ctx context.Context, height uint64, round uint32, d time.Duration) (ch <-chan struct{}, cancel func())
 }
 
 // TimeoutStrategy defines how to calculate the timeout durations
@@ -197,3 +217,37 @@ func (t *StandardRoundTimer) PrecommitDelayTimer(ctx context.Context, height uin
 func (t *StandardRoundTimer) CommitWaitTimer(ctx context.Context, height uint64, round uint32) (<-chan struct{}, func()) {
 	return t.getTimer(ctx, t.strat.CommitWaitTimeout(height, round))
 }
+
+// ProposalDelayTimer returns a timer that runs independently of the step timers,
+// as it is started while the proposal timer is active.
+func (t *StandardRoundTimer) ProposalDelayTimer(_ context.Context, _ uint64, _ uint32, d time.Duration) (<-chan struct{}, func()) {
+	return independentTimer(d)
+}
+
+// ResourceGuardPollTimer returns a timer that runs independently of the step timers,
+// as the resource guard may be paused during any step.
+func (t *StandardRoundTimer) ResourceGuardPollTimer(_ context.Context, _ uint64, _ uint32, d time.Duration) (<-chan struct{}, func()) {
+	return independentTimer(d)
+}
+
+// PeerWaitTimer returns a timer that runs independently of the step timers,
+// as the state machine may wait for peers across many steps.
+func (t *StandardRoundTimer) PeerWaitTimer(_ context.Context, _ uint64, _ uint32, d time.Duration) (<-chan struct{}, func()) {
+	return independentTimer(d)
+}
+
+// StrategyResponseTimer returns a timer that runs independently of the step timers,
+// as the consensus strategy may be called during any step.
+func (t *StandardRoundTimer) StrategyResponseTimer(_ context.Context, _ uint64, _ uint32, d time.Duration) (<-chan struct{}, func()) {
+	return independentTimer(d)
+}
+
+// independentTimer returns a channel that is closed after d,
+// and a cancel function that stops the timer without closing the channel.
+// Unlike the step timers, it does not go through the background goroutine,
+// so any number of independent timers may be active at once.
+func independentTimer(d time.Duration) (<-chan struct{}, func()) {
+	ch := make(chan struct{})
+	timer := time.AfterFunc(d, func() { close(ch) })
+	return ch, func() { timer.Stop() }
+}
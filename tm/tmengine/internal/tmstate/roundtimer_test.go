@@ -180,6 +180,47 @@ func TestStandardRoundTimer(t *testing.T) {
 		})
 	})
 
+	t.Run("ProposalDelayTimer", func(t *testing.T) {
+		t.Run("channel closed upon elapse while proposal timer active", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			rt := tmstate.NewStandardRoundTimer(ctx, s25)
+			defer rt.Wait()
+			defer cancel()
+
+			_, pCancel := rt.ProposalTimer(ctx, 1, 0)
+			defer pCancel()
+
+			ch, tCancel := rt.ProposalDelayTimer(ctx, 1, 0, time.Millisecond)
+			defer tCancel()
+
+			_ = gtest.ReceiveOrTimeout(t, ch, gtest.ScaleMs(50))
+		})
+
+		t.Run("channel not closed upon cancel", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			rt := tmstate.NewStandardRoundTimer(ctx, s25)
+			defer rt.Wait()
+			defer cancel()
+
+			ch, tCancel := rt.ProposalDelayTimer(ctx, 1, 0, time.Duration(ms25))
+			tCancel() // Immediate cancel.
+			tCancel() // Safe to call multiple times.
+
+			// Sleep longer than what would elapse.
+			gtest.Sleep(gtest.ScaleMs(25 + 5))
+
+			gtest.NotSending(t, ch)
+		})
+	})
+
 	t.Run("return values when context is cancelled", func(t *testing.T) {
 		t.Parallel()
 
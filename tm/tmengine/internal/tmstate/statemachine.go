@@ -1,6 +1,7 @@
 package tmstate
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"slices"
 	"time"
 
+	"github.com/bits-and-blooms/bitset"
 	"github.com/gordian-engine/gordian/gassert"
 	"github.com/gordian-engine/gordian/gwatchdog"
 	"github.com/gordian-engine/gordian/internal/gchan"
@@ -37,11 +39,66 @@ type StateMachine struct {
 
 	rt RoundTimer
 
+	endCommitWaitOnFullPrecommits bool
+	skipSingleValidatorCommitWait bool
+
+	watchdogFinalizationFlushTimeout time.Duration
+
+	maxRoundsPerHeight uint32
+
+	haltOnSelfEquivocation bool
+
+	vsTransitionValidator tmconsensus.ValidatorSetTransitionValidator
+
+	proposalAnnotator func(height uint64, round uint32) (proposalAnn, blockAnn []byte, err error)
+
+	maxAnnotationBytes int
+
+	// Nil unless an enter round observer was configured.
+	ero *asyncObserver[tmconsensus.RoundView]
+
+	initialValSetProvider func(context.Context) (tmconsensus.ValidatorSet, uint64, error)
+
+	// Finalization response received from the driver
+	// that failed to be saved to the finalization store.
+	// Only accessed from the kernel goroutine.
+	unsavedFinalization *tmdriver.FinalizeBlockResponse
+
 	cm *tsi.ConsensusManager
 
+	// Nil unless an action observer was configured.
+	ao *asyncObserver[tmelink.StateMachineRoundAction]
+
+	// Nil unless a block data arrival buffer was configured.
+	bdaq *blockDataArrivalQueue
+
+	// Nil unless waiting for peers before voting.
+	// Only accessed from the kernel goroutine.
+	pw *peerWait
+
+	// Nil unless a resource guard was configured.
+	// Only accessed from the kernel goroutine.
+	rg *resourceGuard
+
+	// Nil unless validator set updates were requested.
+	valSetUpdatesOut chan tmelink.ValidatorSetUpdate
+
+	// Nil unless proposal prepare notifications were requested.
+	proposalPrepareOut chan<- tmelink.ProposalPrepare
+
+	// The height and round of the last proposal prepare notification,
+	// so that a notification sent when the previous height commits
+	// is not repeated when entering the round.
+	proposalPrepareH uint64
+	proposalPrepareR uint32
+
+	// Nil unless commit wait notifications were requested.
+	commitWaitEnteredOut chan<- tmelink.CommitWaitEntered
+
 	mc *tmemetrics.Collector
 
-	wd *gwatchdog.Watchdog
+	wd   *gwatchdog.Watchdog
+	wdHB tmeil.WatchdogHeartbeat
 
 	viewInCh               <-chan tmeil.StateMachineRoundView
 	roundEntranceOutCh     chan<- tmeil.StateMachineRoundEntrance
@@ -66,12 +123,169 @@ type StateMachineConfig struct {
 
 	RoundTimer RoundTimer
 
+	// If true, the commit wait step ends without waiting for its timer to elapse,
+	// once the finalization for the committing block has been stored
+	// and precommits from 100% of the voting power are present.
+	EndCommitWaitOnFullPrecommits bool
+
+	// If true, the commit wait step ends as soon as
+	// the finalization for the committing block has been stored,
+	// for any height whose validator set has exactly one member.
+	// With a single validator there are no other precommits to wait for,
+	// so the commit wait timer would only add latency.
+	SkipSingleValidatorCommitWait bool
+
+	// If positive, when the watchdog terminates the state machine
+	// while a finalization response from the driver has not yet been saved,
+	// the state machine attempts to save it to the finalization store,
+	// waiting at most this duration.
+	// The driver may have already committed the block,
+	// so saving the finalization keeps the store consistent with the application.
+	// Zero disables the attempt.
+	WatchdogFinalizationFlushTimeout time.Duration
+
+	// If positive, the number of rounds the state machine may attempt at a single height.
+	// Advancing past the final allowed round terminates the watchdog,
+	// halting the engine instead of continuing through rounds indefinitely.
+	// Zero means no limit.
+	MaxRoundsPerHeight uint32
+
+	// If set, before signing a prevote or precommit,
+	// the state machine checks the action store for a prior vote in the same round
+	// with a different target.
+	// Such a vote indicates that this validator's key was used elsewhere,
+	// for instance by a misconfigured failover,
+	// so the state machine terminates the watchdog
+	// rather than signing a conflicting vote.
+	HaltOnSelfEquivocation bool
+
+	// If set, the validators in each finalization response from the driver
+	// are checked as a transition from the next validator set of the finalized header.
+	// A rejected transition terminates the watchdog,
+	// halting the engine before the finalization is stored.
+	ValidatorSetTransitionValidator tmconsensus.ValidatorSetTransitionValidator
+
+	// If set, called when the state machine builds a proposed header.
+	// Non-nil return values are set as the Driver field of,
+	// respectively, the proposed header's annotations and the header's annotations.
+	// An error causes the state machine to skip proposing in that round.
+	ProposalAnnotator func(height uint64, round uint32) (proposalAnn, blockAnn []byte, err error)
+
+	// If positive, the state machine does not propose a header
+	// whose combined annotations, as reported by [tmconsensus.ProposedHeader.AnnotationBytes],
+	// are larger than MaxAnnotationBytes;
+	// the mirrors of other validators with the same limit would reject it.
+	MaxAnnotationBytes int
+
+	// If set, called with a clone of the round view passed to [tmconsensus.ConsensusStrategy.EnterRound]
+	// for each call.
+	// The function is called on a dedicated goroutine, in round order,
+	// so a slow observer does not block the state machine.
+	EnterRoundObserver func(tmconsensus.RoundView)
+
+	// If set, called at startup when the state machine store is uninitialized,
+	// to get the current validator set and the height to begin at,
+	// instead of beginning at the genesis height with the genesis validators.
+	// This supports a node that has fast-synced to a later height;
+	// the finalization for the height before the returned height
+	// must already be present in the FinalizationStore.
+	InitialValidatorSetProvider func(context.Context) (tmconsensus.ValidatorSet, uint64, error)
+
 	ConsensusStrategy tmconsensus.ConsensusStrategy
 
+	// If positive, the maximum time to wait for the consensus strategy
+	// to respond to a ConsiderProposedBlocks, ChooseProposedBlock, or DecidePrecommit call.
+	// A ConsiderProposedBlocks call that times out is treated as not ready,
+	// so the state machine proceeds when its proposal timer elapses;
+	// ChooseProposedBlock and DecidePrecommit calls that time out result in a nil vote.
+	// No other strategy call is made until a timed out call returns.
+	// Timed by the RoundTimer.
+	// Zero means to wait indefinitely.
+	StrategyResponseTimeout time.Duration
+
+	// If set, consulted before proposed headers are passed to the consensus strategy;
+	// headers whose block data is reported unavailable are withheld from the strategy.
+	// Each check is bounded by DataAvailabilityTimeout, if positive.
+	// A check that errors or times out is treated as unavailable.
+	DataAvailabilityChecker func(ctx context.Context, dataID string) (available bool, err error)
+	DataAvailabilityTimeout time.Duration
+
+	// Like DataAvailabilityChecker, but for drivers that track block data
+	// per height and round and never fail the check.
+	// It is also bounded by DataAvailabilityTimeout, if positive.
+	// At most one of DataAvailabilityChecker and BlockDataAvailabilityFunc may be set.
+	BlockDataAvailabilityFunc func(ctx context.Context, height uint64, round uint32, dataID string) (available bool)
+
+	// If set, called with every proposed header, prevote, and precommit
+	// that the state machine produces, after it has been saved to the action store.
+	// The function is called on a separate goroutine in the order the actions were produced,
+	// so a slow observer does not block the state machine.
+	ActionObserver func(tmelink.StateMachineRoundAction)
+
+	// If positive, the state machine holds its prevotes and precommits at startup,
+	// rather than sending them to the mirror,
+	// until a peer count of at least MinPeersBeforeVoting is received on PeerCountCh,
+	// or until MinPeersTimeout elapses, if positive, as timed by the RoundTimer.
+	// Held votes are still signed and saved to the action store.
+	// When the wait ends, held votes for the current round are sent to the mirror,
+	// and held votes for earlier rounds are discarded.
+	// PeerCountCh must be set if MinPeersBeforeVoting is positive.
+	MinPeersBeforeVoting int
+	MinPeersTimeout      time.Duration
+	PeerCountCh          <-chan int
+
+	// If set, called before the state machine signs and saves
+	// a proposed header, prevote, or precommit to the action store,
+	// before it saves a finalization to the finalization store,
+	// and before it saves its height and round to the state machine store.
+	// If it reports not ok, the state machine logs an error with the reason
+	// and pauses those writes instead of risking a partial write,
+	// calling ResourceGuard again every ResourceGuardPollInterval,
+	// as timed by the RoundTimer.
+	// Once it reports ok, the paused actions for the current round
+	// are signed, saved, and sent to the mirror;
+	// paused actions for earlier rounds are discarded.
+	// ResourceGuardPollInterval must be positive if ResourceGuard is set.
+	ResourceGuard             func() (ok bool, reason string)
+	ResourceGuardPollInterval time.Duration
+
+	// If set, the state machine sends an alert on this channel
+	// when the ResourceGuard pauses writes and when they resume.
+	// Sends never block: if the channel is not ready to receive,
+	// the alert is discarded.
+	ResourceGuardAlertOut chan<- tmelink.ResourceGuardAlert
+
+	// If set, the state machine sends an update on this channel
+	// whenever a finalization changes the validator set.
+	// Sends never block: the channel must be buffered,
+	// and when it is full, the oldest buffered update is discarded
+	// to make room for the new one.
+	ValidatorSetUpdatesOut chan tmelink.ValidatorSetUpdate
+
+	// If set, the state machine sends a notification on this channel
+	// before entering a round in which it is the expected proposer.
+	// Sends never block: if the channel is not ready to receive,
+	// the notification is discarded.
+	ProposalPrepareOut chan<- tmelink.ProposalPrepare
+
+	// If set, the state machine sends a notification on this channel
+	// each time it enters the commit wait step,
+	// indicating whether the commit was driven by votes from the rest of the network.
+	// Sends never block: if the channel is not ready to receive,
+	// the notification is discarded.
+	CommitWaitEnteredOut chan<- tmelink.CommitWaitEntered
+
 	RoundViewInCh      <-chan tmeil.StateMachineRoundView
 	RoundEntranceOutCh chan<- tmeil.StateMachineRoundEntrance
 
-	BlockDataArrivalCh <-chan tmelink.BlockDataArrival
+	// Arrivals are read directly from BlockDataArrivalCh by default,
+	// so the channel's own buffer is the only queue,
+	// and a sender blocks while the state machine is busy.
+	// If BlockDataArrivalBuffer is positive,
+	// arrivals are instead moved into a buffer of that size as soon as they are sent;
+	// when that buffer is full, the oldest buffered arrival is discarded.
+	BlockDataArrivalCh     <-chan tmelink.BlockDataArrival
+	BlockDataArrivalBuffer int
 
 	FinalizeBlockRequestCh chan<- tmdriver.FinalizeBlockRequest
 
@@ -79,10 +293,50 @@ type StateMachineConfig struct {
 
 	Watchdog *gwatchdog.Watchdog
 
+	// Controls how often the watchdog checks the state machine's main loop.
+	// The zero value uses the defaults in [tmeil.WatchdogHeartbeat].
+	WatchdogHeartbeat tmeil.WatchdogHeartbeat
+
 	AssertEnv gassert.Env
 }
 
+// dataAvailabilityChecker returns the consensus manager's data availability checker,
+// adapted from whichever of c.DataAvailabilityChecker or c.BlockDataAvailabilityFunc is set.
+// It returns nil if neither is set, and an error if both are set.
+func (c StateMachineConfig) dataAvailabilityChecker() (tsi.DataAvailabilityChecker, error) {
+	switch {
+	case c.DataAvailabilityChecker != nil && c.BlockDataAvailabilityFunc != nil:
+		return nil, errors.New(
+			"at most one of DataAvailabilityChecker and BlockDataAvailabilityFunc may be set",
+		)
+	case c.DataAvailabilityChecker != nil:
+		fn := c.DataAvailabilityChecker
+		return func(ctx context.Context, _ uint64, _ uint32, dataID string) (bool, error) {
+			return fn(ctx, dataID)
+		}, nil
+	case c.BlockDataAvailabilityFunc != nil:
+		fn := c.BlockDataAvailabilityFunc
+		return func(ctx context.Context, height uint64, round uint32, dataID string) (bool, error) {
+			return fn(ctx, height, round, dataID), nil
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
 func NewStateMachine(ctx context.Context, log *slog.Logger, cfg StateMachineConfig) (*StateMachine, error) {
+	daChecker, err := cfg.dataAvailabilityChecker()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MinPeersBeforeVoting > 0 && cfg.PeerCountCh == nil {
+		return nil, errors.New("PeerCountCh must be set when MinPeersBeforeVoting is positive")
+	}
+	if cfg.ResourceGuard != nil && cfg.ResourceGuardPollInterval <= 0 {
+		return nil, errors.New("ResourceGuardPollInterval must be positive when ResourceGuard is set")
+	}
+
 	m := &StateMachine{
 		log: log,
 
@@ -98,11 +352,40 @@ func NewStateMachine(ctx context.Context, log *slog.Logger, cfg StateMachineConf
 
 		rt: cfg.RoundTimer,
 
-		cm: tsi.NewConsensusManager(ctx, log.With("sm_sys", "consmgr"), cfg.ConsensusStrategy),
+		endCommitWaitOnFullPrecommits: cfg.EndCommitWaitOnFullPrecommits,
+		skipSingleValidatorCommitWait: cfg.SkipSingleValidatorCommitWait,
+
+		watchdogFinalizationFlushTimeout: cfg.WatchdogFinalizationFlushTimeout,
+
+		maxRoundsPerHeight: cfg.MaxRoundsPerHeight,
+
+		haltOnSelfEquivocation: cfg.HaltOnSelfEquivocation,
+
+		vsTransitionValidator: cfg.ValidatorSetTransitionValidator,
+
+		proposalAnnotator: cfg.ProposalAnnotator,
+
+		maxAnnotationBytes: cfg.MaxAnnotationBytes,
+
+		initialValSetProvider: cfg.InitialValidatorSetProvider,
+
+		cm: tsi.NewConsensusManager(
+			ctx, log.With("sm_sys", "consmgr"),
+			cfg.ConsensusStrategy, cfg.StrategyResponseTimeout, cfg.RoundTimer,
+			daChecker, cfg.DataAvailabilityTimeout,
+			cfg.MetricsCollector,
+		),
+
+		valSetUpdatesOut: cfg.ValidatorSetUpdatesOut,
+
+		proposalPrepareOut: cfg.ProposalPrepareOut,
+
+		commitWaitEnteredOut: cfg.CommitWaitEnteredOut,
 
 		mc: cfg.MetricsCollector,
 
-		wd: cfg.Watchdog,
+		wd:   cfg.Watchdog,
+		wdHB: cfg.WatchdogHeartbeat,
 
 		assertEnv: cfg.AssertEnv,
 
@@ -114,6 +397,29 @@ func NewStateMachine(ctx context.Context, log *slog.Logger, cfg StateMachineConf
 		kernelDone: make(chan struct{}),
 	}
 
+	if cfg.ActionObserver != nil {
+		m.ao = newAsyncObserver(ctx, cfg.ActionObserver)
+	}
+
+	if cfg.EnterRoundObserver != nil {
+		m.ero = newAsyncObserver(ctx, cfg.EnterRoundObserver)
+	}
+
+	if cfg.BlockDataArrivalBuffer > 0 && cfg.BlockDataArrivalCh != nil {
+		m.bdaq = newBlockDataArrivalQueue(
+			ctx, cfg.BlockDataArrivalCh, cfg.BlockDataArrivalBuffer, cfg.MetricsCollector,
+		)
+		m.blockDataArrivalCh = m.bdaq.Out()
+	}
+
+	if cfg.MinPeersBeforeVoting > 0 {
+		m.pw = newPeerWait(cfg.MinPeersBeforeVoting, cfg.PeerCountCh, cfg.MinPeersTimeout)
+	}
+
+	if cfg.ResourceGuard != nil {
+		m.rg = newResourceGuard(cfg.ResourceGuard, cfg.ResourceGuardPollInterval, cfg.ResourceGuardAlertOut)
+	}
+
 	go m.kernel(ctx)
 
 	if m.signer == nil {
@@ -126,6 +432,18 @@ func NewStateMachine(ctx context.Context, log *slog.Logger, cfg StateMachineConf
 func (m *StateMachine) Wait() {
 	m.cm.Wait()
 	<-m.kernelDone
+
+	if m.ao != nil {
+		m.ao.Wait()
+	}
+
+	if m.ero != nil {
+		m.ero.Wait()
+	}
+
+	if m.bdaq != nil {
+		m.bdaq.Wait()
+	}
 }
 
 func (m *StateMachine) kernel(ctx context.Context) {
@@ -141,11 +459,11 @@ func (m *StateMachine) kernel(ctx context.Context) {
 		return
 	}
 
-	wSig := m.wd.Monitor(ctx, gwatchdog.MonitorConfig{
-		Name:     "StateMachine",
-		Interval: 10 * time.Second, Jitter: time.Second,
-		ResponseTimeout: time.Second,
-	})
+	if m.pw != nil && m.pw.timeout > 0 {
+		m.pw.StartTimer(m.rt.PeerWaitTimer(ctx, rlc.H, rlc.R, m.pw.timeout))
+	}
+
+	wSig := m.wd.Monitor(ctx, m.wdHB.MonitorConfig("StateMachine"))
 
 	defer func() {
 		if !gwatchdog.IsTermination(ctx) {
@@ -161,6 +479,10 @@ func (m *StateMachine) kernel(ctx context.Context) {
 				slog.Any("VRV", rlc.VRV),
 			),
 		)
+
+		if m.watchdogFinalizationFlushTimeout > 0 {
+			m.flushPendingFinalization(ctx, &rlc)
+		}
 	}()
 
 	for {
@@ -207,6 +529,12 @@ func (m *StateMachine) handleCatchupEvent(
 			if !m.handleFinalization(ctx, rlc, resp) {
 				return false
 			}
+
+		case <-m.rg.TimerC():
+			// The finalization may have been held by the resource guard.
+			if !m.pollResourceGuard(ctx, rlc) {
+				return false
+			}
 		}
 	}
 }
@@ -244,6 +572,9 @@ func (m *StateMachine) handleLiveEvent(
 
 		rlc.ProposalCh = nil
 
+	case <-rlc.ProposalDelayTimer:
+		rlc.EndProposalDelay()
+
 	case he := <-rlc.PrevoteHashCh:
 		if he.Err != nil {
 			glog.HRE(m.log, rlc.H, rlc.R, he.Err).Error(
@@ -297,10 +628,35 @@ func (m *StateMachine) handleLiveEvent(
 			return false
 		}
 
+	case n := <-m.pw.Counts():
+		if n >= m.pw.minPeers {
+			m.endPeerWait(rlc, "enough peers connected", "peers", n)
+		}
+
+	case <-m.pw.TimerC():
+		m.endPeerWait(rlc, "timed out waiting for peers")
+
+	case <-m.rg.TimerC():
+		if !m.pollResourceGuard(ctx, rlc) {
+			return false
+		}
+
 	case sig := <-wSig:
 		close(sig.Alive)
 	}
 
+	// The proposal delay is bounded by the proposal timeout,
+	// so stop withholding our proposal once we are past awaiting proposals.
+	if rlc.DelayedProposalCh != nil && rlc.S != tsi.StepAwaitingProposal {
+		rlc.EndProposalDelay()
+	}
+
+	// Any of the above events may have supplied the last piece
+	// (the finalization or the final precommits) to end commit wait early.
+	if m.canEndCommitWaitEarly(rlc) {
+		return m.endCommitWaitEarly(ctx, rlc)
+	}
+
 	return true
 }
 
@@ -310,36 +666,11 @@ func (m *StateMachine) handleHeightCommitted(ctx context.Context, rlc *tsi.Round
 	// Don't read from the channel again, especially since it's closed.
 	rlc.HeightCommitted = nil
 
-	rlc.CommitWaitElapsed = true
-
-	if rlc.CancelTimer != nil {
-		rlc.CancelTimer()
-	}
-	rlc.StepTimer = nil
-	rlc.CancelTimer = nil
-
-	if rlc.S == tsi.StepAwaitingFinalization {
-		// We were already awaiting finalization,
-		// so nothing to do here.
-		return true
-	}
-
-	if rlc.S != tsi.StepCommitWait {
-		// It's probably also acceptable to be on tsi.StepAwaitingFinalization?
-		panic(fmt.Errorf(
-			"BUG: expected to be on step commit wait, got %s", rlc.S,
-		))
-	}
-
-	if len(rlc.FinalizedValSet.Validators) == 0 {
-		// We don't have a finalization yet,
-		// so that's the step we are waiting on.
-		rlc.S = tsi.StepAwaitingFinalization
+	if !rlc.HandleCommitEvent(tsi.CommitEventWaitElapsed) {
+		// Still waiting on the finalization.
 		return true
 	}
 
-	// We already had a finalization and now the commit wait has effectively elapsed,
-	// so now we have to advance the height.
 	return m.advanceHeight(ctx, rlc)
 }
 
@@ -357,6 +688,8 @@ func (m *StateMachine) initializeRLC(ctx context.Context) (rlc tsi.RoundLifecycl
 	//   3. The process immediately restarted at the same height/round/step.
 	//   4. The mirror could request the same action again, which could potentially cause
 	//      a double sign, or perhaps another crash due to an attempt at a duplicate action.
+	// Prevotes are at least guarded against a double sign,
+	// as recordPrevote checks the action store before signing.
 
 	if !su.IsVRV() {
 		// We are replaying, so we don't need special begin round handling.
@@ -419,14 +752,16 @@ func (m *StateMachine) initializeRLC(ctx context.Context) (rlc tsi.RoundLifecycl
 	// The state update was a VRV.
 	// We need to send the enter round request to the consensus strategy,
 	// now that we have potentially modified the proposal out channel.
+	m.notifyProposalPrepare(&rlc)
 	req := tsi.EnterRoundRequest{
 		RV:     su.VRV.RoundView,
-		Result: make(chan error), // Unbuffered since both sides sync on this.
+		Result: make(chan tsi.EnterRoundResult), // Unbuffered since both sides sync on this.
 
 		ProposalOut: rlc.ProposalCh,
 	}
+	m.observeEnterRound(req.RV)
 
-	err, ok := gchan.ReqResp(
+	res, ok := gchan.ReqResp(
 		ctx, m.log,
 		m.cm.EnterRoundRequests, req,
 		req.Result,
@@ -436,15 +771,18 @@ func (m *StateMachine) initializeRLC(ctx context.Context) (rlc tsi.RoundLifecycl
 		// Context cancelled, we cannot continue.
 		return rlc, false
 	}
-	if err != nil {
+	if res.Err != nil {
 		m.log.Error(
 			"Error when calling ConsensusStrategy.EnterRound",
-			"err", err,
+			"err", res.Err,
 		)
 		return rlc, false
 	}
 
 	ok = m.beginRoundLive(ctx, &rlc, su.VRV)
+	if ok {
+		m.delayProposal(ctx, &rlc, res.ProposalDelay)
+	}
 	return rlc, ok
 }
 
@@ -468,7 +806,7 @@ func (m *StateMachine) beginRoundLive(
 	switch curStep {
 	case tsi.StepAwaitingProposal:
 		// Only send the filtered proposed blocks.
-		if okPHs := m.rejectMismatchedProposedHeaders(initVRV.ProposedHeaders, rlc); len(okPHs) > 0 {
+		if okPHs := m.strategyProposedHeaders(initVRV.ProposedHeaders, rlc); len(okPHs) > 0 {
 			req := tsi.ConsiderProposedBlocksRequest{
 				PHs:    okPHs,
 				Result: rlc.PrevoteHashCh,
@@ -537,6 +875,19 @@ func (m *StateMachine) beginRoundLive(
 	return true
 }
 
+// delayProposal applies the proposal delay requested by the consensus strategy
+// through [tmconsensus.ProposalDelayer].
+// The delay only applies while awaiting proposals,
+// as it is bounded by the proposal timeout.
+func (m *StateMachine) delayProposal(ctx context.Context, rlc *tsi.RoundLifecycle, d time.Duration) {
+	if d <= 0 || rlc.S != tsi.StepAwaitingProposal || rlc.ProposalCh == nil {
+		return
+	}
+
+	timer, cancel := m.rt.ProposalDelayTimer(ctx, rlc.H, rlc.R, d)
+	rlc.DelayProposal(timer, cancel)
+}
+
 func (m *StateMachine) startInitialTimer(ctx context.Context, rlc *tsi.RoundLifecycle) {
 	switch rlc.S {
 	case tsi.StepAwaitingProposal:
@@ -567,6 +918,9 @@ func (m *StateMachine) sendInitialActionSet(ctx context.Context) (
 	// (In all other cases, we rely on rlc.Reset to create the channel.)
 	hc := make(chan struct{})
 
+	// Set if the initial validator set provider was consulted.
+	var haveValSets bool
+
 	h, r, err := m.smStore.StateMachineHeightRound(ctx)
 	if err != nil {
 		if err == tmstore.ErrStoreUninitialized {
@@ -577,6 +931,31 @@ func (m *StateMachine) sendInitialActionSet(ctx context.Context) (
 			// we would still be at the genesis height anyway.
 			h = m.genesis.InitialHeight
 			r = 0
+
+			if m.initialValSetProvider != nil {
+				// But, the node may have fast-synced past genesis,
+				// in which case we start at the height the provider gives us.
+				// There is no finalization history to derive the validators from,
+				// so the provided set is used as both the current and previous set.
+				valSet, provHeight, err := m.initialValSetProvider(ctx)
+				if err != nil {
+					m.log.Error("Failed to get initial validator set from provider", "err", err)
+					return rlc, rer, false
+				}
+				if provHeight < m.genesis.InitialHeight {
+					m.log.Error(
+						"Initial validator set provider returned height before genesis",
+						"provided_height", provHeight,
+						"genesis_height", m.genesis.InitialHeight,
+					)
+					return rlc, rer, false
+				}
+
+				h = provHeight
+				rlc.CurValSet = valSet
+				rlc.PrevValSet = valSet
+				haveValSets = true
+			}
 		} else {
 			m.log.Error("Failed to get state machine height and round from store", "err", err)
 			return rlc, rer, false
@@ -623,7 +1002,9 @@ func (m *StateMachine) sendInitialActionSet(ctx context.Context) (
 	// i.e. we are a validator in the current set.
 	// Although we set the rest of the rlc values later,
 	// we need the current validator set now to determine participation.
-	if isGenesis {
+	if haveValSets {
+		// Already set from the initial validator set provider.
+	} else if isGenesis {
 		rlc.CurValSet = m.genesis.ValidatorSet
 		rlc.PrevValSet = m.genesis.ValidatorSet
 	} else {
@@ -639,10 +1020,11 @@ func (m *StateMachine) sendInitialActionSet(ctx context.Context) (
 			return rlc, rer, false
 		}
 
-		if h-3 > m.genesis.InitialHeight {
+		if h > m.genesis.InitialHeight+3 {
 			// If the current validator set was declared at h-2,
 			// then the previous validator set must have been declared at h-3.
 			// If we don't have that finalization then we need to fall back to genesis.
+			// (The condition avoids computing h-3, which underflows at height 2.)
 			_, _, rlc.PrevValSet, _, err = m.fStore.LoadFinalizationByHeight(ctx, h-3)
 			if err != nil {
 				m.log.Error(
@@ -673,11 +1055,16 @@ func (m *StateMachine) sendInitialActionSet(ctx context.Context) (
 	// but it is a little helpful in tests in case a StateMachineRoundEntrance is mistakenly reused.
 	close(initRE.Response)
 
+	if !m.checkRoundEntranceValidators(initRE, rer) {
+		return rlc, rer, false
+	}
+
 	rlc.AssertEnv = m.assertEnv
 
 	// Initialize this to a default size;
 	// it needs to be a non-nil map regardless of the initial update.
 	rlc.PrevConsideredHashes = map[string]struct{}{}
+	rlc.FilteredHashes = map[string]struct{}{}
 
 	// We have a response -- do we need to call into the consensus strategy,
 	// or do we only need to replay the block?
@@ -737,6 +1124,8 @@ func (m *StateMachine) sendInitialActionSet(ctx context.Context) (
 			Header: rer.CH.Header,
 			Round:  rer.CH.Proof.Round,
 
+			IsCatchup: true,
+
 			Resp: rlc.FinalizeRespCh,
 		}
 
@@ -932,7 +1321,7 @@ func (m *StateMachine) handleProposalViewUpdate(
 
 		// And we are making a request to choose or consider in either case too.
 		req := tsi.ChooseProposedBlockRequest{
-			PHs: m.rejectMismatchedProposedHeaders(vrv.ProposedHeaders, rlc),
+			PHs: m.strategyProposedHeaders(vrv.ProposedHeaders, rlc),
 
 			Result: rlc.PrevoteHashCh,
 		}
@@ -1015,7 +1404,7 @@ func (m *StateMachine) handleProposalViewUpdate(
 		//
 		// Operate on clones to avoid mutating either of the canonical slices.
 
-		incoming := m.rejectMismatchedProposedHeaders(vrv.ProposedHeaders, rlc)
+		incoming := m.strategyProposedHeaders(vrv.ProposedHeaders, rlc)
 		have := m.rejectMismatchedProposedHeaders(rlc.VRV.ProposedHeaders, rlc)
 		// TODO: we could be more efficient than building up the have slice
 		// only to check its length.
@@ -1147,33 +1536,30 @@ func (m *StateMachine) recordPrevote(
 	targetHash string,
 ) (ok bool) {
 	if m.isParticipating(rlc) {
-		// Record to the action store first.
-		h, r := rlc.H, rlc.R
-		vt := tmconsensus.VoteTarget{
-			Height: h, Round: r,
-			BlockHash: targetHash,
-		}
-		signContent, sig, err := m.signer.Prevote(ctx, vt)
-		if err != nil {
-			glog.HRE(m.log, h, r, err).Error(
-				"Failed to sign prevote",
-				"target_hash", glog.Hex(targetHash),
-			)
+		if !m.checkSelfEquivocation(ctx, rlc, "prevote", targetHash) {
 			return false
 		}
 
-		if err := m.aStore.SavePrevoteAction(ctx, m.signer.PubKey(), vt, sig); err != nil {
-			glog.HRE(m.log, h, r, err).Error("Failed to save prevote to action store")
+		// Never sign a second prevote for a round,
+		// for instance if we re-entered this round after a restart.
+		signed, err := m.aStore.HasSignedPrevote(ctx, rlc.H, rlc.R)
+		if err != nil {
+			glog.HRE(m.log, rlc.H, rlc.R, err).Error("Failed to check action store for existing prevote")
 			return false
 		}
 
-		// The OutgoingActionsCh is 3-buffered so we assume this will never block.
-		rlc.OutgoingActionsCh <- tmeil.StateMachineRoundAction{
-			Prevote: tmeil.ScopedSignature{
-				TargetHash:  targetHash,
-				SignContent: signContent,
-				Sig:         sig,
-			},
+		if signed {
+			m.log.Warn(
+				"Refusing to sign second prevote for round",
+				"height", rlc.H, "round", rlc.R,
+				"target_hash", glog.Hex(targetHash),
+			)
+		} else if !m.resourcesAvailable(ctx, rlc, pausedAction{
+			Kind: pausedPrevote, TargetHash: targetHash,
+		}) {
+			// Held until the resource guard recovers.
+		} else if !m.signPrevote(ctx, rlc, targetHash) {
+			return false
 		}
 	}
 
@@ -1189,6 +1575,283 @@ func (m *StateMachine) recordPrevote(
 	return true
 }
 
+// signPrevote signs a prevote for targetHash,
+// records it to the action store, and sends it to the mirror.
+func (m *StateMachine) signPrevote(
+	ctx context.Context,
+	rlc *tsi.RoundLifecycle,
+	targetHash string,
+) (ok bool) {
+	// Record to the action store first.
+	h, r := rlc.H, rlc.R
+	vt := tmconsensus.VoteTarget{
+		Height: h, Round: r,
+		BlockHash: targetHash,
+	}
+	signContent, sig, err := m.signer.Prevote(ctx, vt)
+	if err != nil {
+		glog.HRE(m.log, h, r, err).Error(
+			"Failed to sign prevote",
+			"target_hash", glog.Hex(targetHash),
+		)
+		return false
+	}
+
+	if err := m.aStore.SavePrevoteAction(ctx, m.signer.PubKey(), vt, sig); err != nil {
+		glog.HRE(m.log, h, r, err).Error("Failed to save prevote to action store")
+		return false
+	}
+
+	m.emitVote(rlc, tmeil.StateMachineRoundAction{
+		Prevote: tmeil.ScopedSignature{
+			TargetHash:  targetHash,
+			SignContent: signContent,
+			Sig:         sig,
+		},
+	})
+
+	m.observeAction(tmelink.StateMachineRoundAction{
+		Height: h, Round: r,
+		Prevote: tmelink.VoteAction{
+			TargetHash:  targetHash,
+			SignContent: signContent,
+			Sig:         sig,
+		},
+	})
+
+	return true
+}
+
+// emitVote sends the prevote or precommit in a to the mirror,
+// unless the state machine is still waiting for peers,
+// in which case the vote is held until the wait ends.
+func (m *StateMachine) emitVote(rlc *tsi.RoundLifecycle, a tmeil.StateMachineRoundAction) {
+	if m.pw != nil {
+		m.pw.held = append(m.pw.held, heldVote{H: rlc.H, R: rlc.R, Action: a})
+		return
+	}
+
+	// The OutgoingActionsCh is 3-buffered so we assume this will never block.
+	rlc.OutgoingActionsCh <- a
+}
+
+// endPeerWait stops waiting for peers before voting,
+// sending any held votes for the current round to the mirror.
+// Held votes for earlier rounds can no longer be sent,
+// as the mirror only accepts actions for the current round.
+func (m *StateMachine) endPeerWait(rlc *tsi.RoundLifecycle, reason string, logArgs ...any) {
+	pw := m.pw
+	m.pw = nil
+	pw.Stop()
+
+	m.log.Info(
+		"Done waiting for peers before voting",
+		append([]any{"reason", reason, "held_votes", len(pw.held)}, logArgs...)...,
+	)
+
+	for _, v := range pw.held {
+		if v.H != rlc.H || v.R != rlc.R {
+			m.log.Debug(
+				"Discarding held vote from earlier round",
+				"vote_height", v.H, "vote_round", v.R,
+				"height", rlc.H, "round", rlc.R,
+			)
+			continue
+		}
+
+		// At most one prevote and one precommit are held for the current round,
+		// and the OutgoingActionsCh is 3-buffered, so this will not block.
+		rlc.OutgoingActionsCh <- v.Action
+	}
+}
+
+// resourcesAvailable reports whether the state machine may make
+// the durable writes for the action in a for the current round.
+// It always reports true if no resource guard is configured.
+//
+// If the resource guard is already paused, or if it reports not ok,
+// a is held until the guard recovers and resourcesAvailable reports false.
+func (m *StateMachine) resourcesAvailable(ctx context.Context, rlc *tsi.RoundLifecycle, a pausedAction) bool {
+	if m.rg == nil {
+		return true
+	}
+
+	if !m.rg.Paused() {
+		ok, reason := m.rg.check()
+		if ok {
+			return true
+		}
+
+		m.log.Error(
+			"Resource guard reported unsafe to write; pausing actions",
+			"height", rlc.H, "round", rlc.R,
+			"reason", reason,
+		)
+		m.pauseResourceGuard(ctx, rlc)
+
+		if !m.rg.alert(tmelink.ResourceGuardAlert{
+			Height: rlc.H, Round: rlc.R,
+			Paused: true,
+			Reason: reason,
+		}) {
+			m.log.Debug(
+				"Dropping resource guard alert because output channel was not ready",
+				"height", rlc.H, "round", rlc.R,
+			)
+		}
+	}
+
+	a.H, a.R = rlc.H, rlc.R
+	m.rg.held = append(m.rg.held, a)
+	return false
+}
+
+// pollResourceGuard is called when the paused resource guard's poll timer elapses.
+// If the guard still reports not ok, polling continues.
+// Otherwise, the held actions for the current round are recorded,
+// and held actions for earlier rounds are discarded.
+func (m *StateMachine) pollResourceGuard(ctx context.Context, rlc *tsi.RoundLifecycle) (ok bool) {
+	if ok, reason := m.rg.check(); !ok {
+		m.log.Debug(
+			"Resource guard still reports unsafe to write",
+			"height", rlc.H, "round", rlc.R,
+			"reason", reason,
+		)
+		m.pauseResourceGuard(ctx, rlc)
+		return true
+	}
+
+	held := m.rg.resume()
+	m.log.Info(
+		"Resource guard recovered; resuming actions",
+		"height", rlc.H, "round", rlc.R,
+		"held_actions", len(held),
+	)
+
+	if !m.rg.alert(tmelink.ResourceGuardAlert{
+		Height: rlc.H, Round: rlc.R,
+	}) {
+		m.log.Debug(
+			"Dropping resource guard alert because output channel was not ready",
+			"height", rlc.H, "round", rlc.R,
+		)
+	}
+
+	for _, a := range held {
+		if a.H != rlc.H || a.R != rlc.R {
+			m.log.Debug(
+				"Discarding paused action from earlier round",
+				"action_height", a.H, "action_round", a.R,
+				"height", rlc.H, "round", rlc.R,
+			)
+			continue
+		}
+
+		var ok bool
+		switch a.Kind {
+		case pausedProposal:
+			ok = m.recordProposedHeader(ctx, *rlc, a.Proposal)
+		case pausedPrevote:
+			ok = m.recordPrevote(ctx, rlc, a.TargetHash)
+		case pausedPrecommit:
+			ok = m.recordPrecommit(ctx, rlc, a.TargetHash)
+		case pausedFinalization:
+			ok = m.handleFinalization(ctx, rlc, a.Finalization)
+		case pausedHeightRound:
+			ok = m.saveHeightRound(ctx, rlc)
+		default:
+			panic(fmt.Errorf("BUG: unknown paused action kind %d", a.Kind))
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// saveHeightRound saves the current height and round to the state machine store.
+// It is only used when releasing a height and round write held by the resource guard;
+// advancing the height or round saves directly.
+func (m *StateMachine) saveHeightRound(ctx context.Context, rlc *tsi.RoundLifecycle) (ok bool) {
+	if err := m.smStore.SetStateMachineHeightRound(ctx, rlc.H, rlc.R); err != nil {
+		m.log.Error(
+			"Failed to set state machine height/round after resource guard recovered",
+			"h", rlc.H,
+			"r", rlc.R,
+			"err", err,
+		)
+		return false
+	}
+	return true
+}
+
+// pauseResourceGuard starts the timer for the next poll of the paused resource guard.
+func (m *StateMachine) pauseResourceGuard(ctx context.Context, rlc *tsi.RoundLifecycle) {
+	m.rg.pause(m.rt.ResourceGuardPollTimer(ctx, rlc.H, rlc.R, m.rg.pollInterval))
+}
+
+// checkSelfEquivocation reports whether it is safe to sign a vote of the given type for targetHash.
+// It always reports true unless the state machine was configured with HaltOnSelfEquivocation.
+//
+// If the action store already has a vote of the same type in the current round
+// with a different target, this validator's key has already signed a conflicting vote.
+// Signing again would be equivocation, so the watchdog is terminated and false is returned.
+func (m *StateMachine) checkSelfEquivocation(
+	ctx context.Context,
+	rlc *tsi.RoundLifecycle,
+	voteType string,
+	targetHash string,
+) (ok bool) {
+	if !m.haltOnSelfEquivocation {
+		return true
+	}
+
+	ra, err := m.aStore.LoadActions(ctx, rlc.H, rlc.R)
+	if err != nil {
+		if errors.Is(err, tmconsensus.RoundUnknownError{
+			WantHeight: rlc.H,
+			WantRound:  rlc.R,
+		}) {
+			// Nothing recorded yet in this round.
+			return true
+		}
+
+		glog.HRE(m.log, rlc.H, rlc.R, err).Error(
+			"Failed to load actions to check for self-equivocation",
+		)
+		return false
+	}
+
+	var priorSig, priorTarget string
+	switch voteType {
+	case "prevote":
+		priorSig, priorTarget = ra.PrevoteSignature, ra.PrevoteTarget
+	case "precommit":
+		priorSig, priorTarget = ra.PrecommitSignature, ra.PrecommitTarget
+	default:
+		panic(fmt.Errorf("BUG: unknown vote type %q", voteType))
+	}
+
+	if priorSig == "" || priorTarget == targetHash {
+		return true
+	}
+
+	m.log.Error(
+		"FATAL: action store has a conflicting vote from this validator; halting to avoid equivocation",
+		"height", rlc.H, "round", rlc.R,
+		"vote_type", voteType,
+		"recorded_target_hash", glog.Hex(priorTarget),
+		"new_target_hash", glog.Hex(targetHash),
+	)
+
+	m.wd.Terminate(fmt.Sprintf(
+		"self-equivocation detected: action store has %s for %x at height %d, round %d; refusing to sign %s for %x",
+		voteType, priorTarget, rlc.H, rlc.R, voteType, targetHash,
+	))
+	return false
+}
+
 func (m *StateMachine) handlePrecommitViewUpdate(
 	ctx context.Context,
 	rlc *tsi.RoundLifecycle,
@@ -1242,9 +1905,20 @@ func (m *StateMachine) recordPrecommit(
 		return true
 	}
 
-	// Record to the action store first.
-	h, r := rlc.H, rlc.R
-	vt := tmconsensus.VoteTarget{
+	if !m.checkSelfEquivocation(ctx, rlc, "precommit", targetHash) {
+		return false
+	}
+
+	if !m.resourcesAvailable(ctx, rlc, pausedAction{
+		Kind: pausedPrecommit, TargetHash: targetHash,
+	}) {
+		// Held until the resource guard recovers.
+		return true
+	}
+
+	// Record to the action store first.
+	h, r := rlc.H, rlc.R
+	vt := tmconsensus.VoteTarget{
 		Height: h, Round: r,
 		BlockHash: targetHash,
 	}
@@ -1262,14 +1936,22 @@ func (m *StateMachine) recordPrecommit(
 		return false
 	}
 
-	// The OutgoingActionsCh is 3-buffered so we assume this will never block.
-	rlc.OutgoingActionsCh <- tmeil.StateMachineRoundAction{
+	m.emitVote(rlc, tmeil.StateMachineRoundAction{
 		Precommit: tmeil.ScopedSignature{
 			TargetHash:  targetHash,
 			SignContent: signContent,
 			Sig:         sig,
 		},
-	}
+	})
+
+	m.observeAction(tmelink.StateMachineRoundAction{
+		Height: h, Round: r,
+		Precommit: tmelink.VoteAction{
+			TargetHash:  targetHash,
+			SignContent: signContent,
+			Sig:         sig,
+		},
+	})
 
 	return true
 }
@@ -1327,6 +2009,30 @@ func (m *StateMachine) recordProposedHeader(
 ) (ok bool) {
 	h, r := rlc.H, rlc.R
 
+	if !m.resourcesAvailable(ctx, &rlc, pausedAction{
+		Kind: pausedProposal, Proposal: p,
+	}) {
+		// Held until the resource guard recovers.
+		return true
+	}
+
+	if m.proposalAnnotator != nil {
+		proposalAnn, blockAnn, err := m.proposalAnnotator(h, r)
+		if err != nil {
+			glog.HRE(m.log, h, r, err).Error(
+				"Proposal annotator failed; not proposing a header this round",
+			)
+			return true
+		}
+
+		if proposalAnn != nil {
+			p.ProposalAnnotations.Driver = proposalAnn
+		}
+		if blockAnn != nil {
+			p.BlockAnnotations.Driver = blockAnn
+		}
+	}
+
 	ph := tmconsensus.ProposedHeader{
 		Header: tmconsensus.Header{
 			PrevBlockHash: []byte(rlc.PrevBlockHash),
@@ -1342,6 +2048,8 @@ func (m *StateMachine) recordProposedHeader(
 
 			PrevAppStateHash: []byte(rlc.PrevFinAppStateHash),
 
+			Timestamp: p.Timestamp,
+
 			Annotations: p.BlockAnnotations,
 		},
 
@@ -1352,6 +2060,16 @@ func (m *StateMachine) recordProposedHeader(
 		Annotations: p.ProposalAnnotations,
 	}
 
+	if m.maxAnnotationBytes > 0 && ph.AnnotationBytes() > m.maxAnnotationBytes {
+		m.log.Warn(
+			"Proposal annotations exceed configured limit; not proposing a header this round",
+			"height", h, "round", r,
+			"annotation_bytes", ph.AnnotationBytes(),
+			"max_annotation_bytes", m.maxAnnotationBytes,
+		)
+		return true
+	}
+
 	hash, err := m.hashScheme.Block(ph.Header)
 	if err != nil {
 		glog.HRE(m.log, h, r, err).Error("Failed to calculate hash for proposed block")
@@ -1374,6 +2092,11 @@ func (m *StateMachine) recordProposedHeader(
 		PH: ph,
 	}
 
+	m.observeAction(tmelink.StateMachineRoundAction{
+		Height: h, Round: r,
+		PH: ph,
+	})
+
 	return true
 }
 
@@ -1388,6 +2111,8 @@ func (m *StateMachine) beginCommit(
 	rlc.S = tsi.StepCommitWait
 	rlc.StepTimer, rlc.CancelTimer = m.rt.CommitWaitTimer(ctx, rlc.H, rlc.R)
 
+	m.notifyCommitWaitEntered(rlc, vrv)
+
 	idx := slices.IndexFunc(vrv.ProposedHeaders, func(ph tmconsensus.ProposedHeader) bool {
 		return string(ph.Header.Hash) == vrv.VoteSummary.MostVotedPrecommitHash
 	})
@@ -1401,6 +2126,11 @@ func (m *StateMachine) beginCommit(
 		return
 	}
 
+	// The committing header determines the validators for the next height,
+	// so the application can begin preparing its proposal now,
+	// instead of waiting until the next height's first round is entered.
+	m.sendProposalPrepare(rlc.H+1, 0, vrv.ProposedHeaders[idx].Header.NextValidatorSet)
+
 	return gchan.SendC(
 		ctx, m.log,
 		m.finalizeBlockRequestCh, tmdriver.FinalizeBlockRequest{
@@ -1413,37 +2143,121 @@ func (m *StateMachine) beginCommit(
 	)
 }
 
+// notifyCommitWaitEntered sends a commit wait notification for the committing block in vrv,
+// if one was requested.
+// It never blocks.
+func (m *StateMachine) notifyCommitWaitEntered(rlc *tsi.RoundLifecycle, vrv tmconsensus.VersionedRoundView) {
+	if m.commitWaitEnteredOut == nil {
+		return
+	}
+
+	blockHash := vrv.VoteSummary.MostVotedPrecommitHash
+	e := tmelink.CommitWaitEntered{
+		Height: rlc.H, Round: rlc.R,
+		BlockHash: []byte(blockHash),
+		External:  !m.hasOwnPrecommit(vrv, blockHash),
+	}
+
+	select {
+	case m.commitWaitEnteredOut <- e:
+	default:
+		m.log.Debug(
+			"Dropping commit wait notification because output channel was not ready",
+			"h", rlc.H, "r", rlc.R,
+		)
+	}
+}
+
+// hasOwnPrecommit reports whether vrv contains this validator's precommit for blockHash.
+func (m *StateMachine) hasOwnPrecommit(vrv tmconsensus.VersionedRoundView, blockHash string) bool {
+	if m.signer == nil {
+		return false
+	}
+
+	proof, ok := vrv.PrecommitProofs[blockHash]
+	if !ok {
+		return false
+	}
+
+	key := m.signer.PubKey()
+	idx := slices.IndexFunc(vrv.ValidatorSet.Validators, func(v tmconsensus.Validator) bool {
+		return v.PubKey.Equal(key)
+	})
+	if idx < 0 {
+		return false
+	}
+
+	signed := bitset.New(uint(len(vrv.ValidatorSet.Validators)))
+	proof.SignatureBitSet(signed)
+	return signed.Test(uint(idx))
+}
+
 func (m *StateMachine) handleFinalization(
 	ctx context.Context,
 	rlc *tsi.RoundLifecycle,
 	resp tmdriver.FinalizeBlockResponse,
 ) (ok bool) {
-	if len(resp.Validators) == 0 {
-		panic(fmt.Errorf(
-			"BUG: application did not set validators in finalization response (height=%d round=%d block_hash=%x)",
-			resp.Height, resp.Round, resp.BlockHash,
+	if resp.Height != rlc.H || resp.Round != rlc.R {
+		// This indicates a bug in the driver.
+		// Check this before touching rlc,
+		// so that a mismatched response cannot be stored against the current height.
+		m.log.Error(
+			"FATAL: driver sent finalization response for unexpected height/round; halting",
+			"height", rlc.H, "round", rlc.R,
+			"resp_height", resp.Height, "resp_round", resp.Round,
+			"resp_block_hash", glog.Hex(resp.BlockHash),
+		)
+		m.wd.Terminate(fmt.Sprintf(
+			"driver sent finalization for height/round %d/%d in response to request for %d/%d",
+			resp.Height, resp.Round, rlc.H, rlc.R,
 		))
+		return false
+	}
+
+	if !m.resourcesAvailable(ctx, rlc, pausedAction{
+		Kind: pausedFinalization, Finalization: resp,
+	}) {
+		// Held until the resource guard recovers.
+		// Leave the finalization fields on rlc unset until then,
+		// so that commit wait cannot end before the finalization is saved.
+		// The driver only sends one response, so stop reading from its channel.
+		//
+		// m.unsavedFinalization is deliberately left unset:
+		// flushPendingFinalization is skipped while the guard is paused,
+		// so a watchdog termination before the guard recovers drops the held finalization.
+		// It was never saved, so the driver finalizes the block again on restart.
+		rlc.FinalizeRespCh = nil
+		return true
 	}
 
-	var err error
-	rlc.FinalizedValSet, err = tmconsensus.NewValidatorSet(resp.Validators, m.hashScheme)
+	valSet, err := m.validateFinalization(rlc, resp)
 	if err != nil {
+		if errors.Is(err, errDisallowedValidatorSetTransition) {
+			glog.HRE(m.log, rlc.H, rlc.R, err).Error(
+				"FATAL: application returned disallowed validator set transition in finalization response; halting",
+				"block_hash", glog.Hex(resp.BlockHash),
+			)
+			m.wd.Terminate(fmt.Sprintf(
+				"driver finalized height %d with disallowed validator set transition: %v",
+				rlc.H, err,
+			))
+			return false
+		}
+
+		// Saving this finalization would leave the chain unable to make progress,
+		// so halt instead of committing to an unusable validator set.
 		glog.HRE(m.log, rlc.H, rlc.R, err).Error(
-			"Failed to calculate hashes for newly finalized validator set",
+			"Application returned invalid validators in finalization response; halting",
+			"block_hash", glog.Hex(resp.BlockHash),
 		)
 		return false
 	}
-	rlc.FinalizedAppStateHash = string(resp.AppStateHash)
-	rlc.FinalizedBlockHash = string(resp.BlockHash)
 
+	rlc.FinalizedValSet = valSet
 	rlc.FinalizeRespCh = nil
 
-	if resp.Height != rlc.H || resp.Round != rlc.R {
-		panic(fmt.Errorf(
-			"BUG: driver sent height/round %d/%d differing from current (%d/%d)",
-			resp.Height, resp.Round, rlc.H, rlc.R,
-		))
-	}
+	rlc.FinalizedAppStateHash = string(resp.AppStateHash)
+	rlc.FinalizedBlockHash = string(resp.BlockHash)
 
 	if err := m.fStore.SaveFinalization(
 		ctx,
@@ -1455,11 +2269,21 @@ func (m *StateMachine) handleFinalization(
 		glog.HRE(m.log, rlc.H, rlc.R, err).Error(
 			"Failed to save finalization to Finalization Store",
 		)
+
+		// Possibly retried in flushPendingFinalization.
+		m.unsavedFinalization = &resp
 		return false
 	}
 
-	// The step is AwaitingFinalization if the commit wait timer has already elapsed.
-	if rlc.S == tsi.StepAwaitingFinalization {
+	if !rlc.FinalizedValSet.Equal(rlc.PrevFinNextValSet) {
+		m.sendValidatorSetUpdate(tmelink.ValidatorSetUpdate{
+			Height: rlc.H + 2,
+			Set:    rlc.FinalizedValSet,
+		})
+	}
+
+	// Advance if the commit wait had already elapsed.
+	if rlc.HandleCommitEvent(tsi.CommitEventFinalized) {
 		if !m.advanceHeight(ctx, rlc) {
 			return false
 		}
@@ -1468,6 +2292,186 @@ func (m *StateMachine) handleFinalization(
 	return true
 }
 
+// errDisallowedValidatorSetTransition is wrapped in the error from validateFinalization
+// when the configured validator set transition validator rejects the finalized validators.
+var errDisallowedValidatorSetTransition = errors.New("disallowed validator set transition")
+
+// validateFinalization checks the validators in resp,
+// the driver's finalization response for rlc's height and round,
+// and returns the validator set to save with the finalization.
+// Both handleFinalization and flushPendingFinalization call it,
+// so that the watchdog flush never saves a finalization that handleFinalization would reject.
+func (m *StateMachine) validateFinalization(
+	rlc *tsi.RoundLifecycle,
+	resp tmdriver.FinalizeBlockResponse,
+) (tmconsensus.ValidatorSet, error) {
+	if err := tmconsensus.ValidateValidators(resp.Validators); err != nil {
+		return tmconsensus.ValidatorSet{}, fmt.Errorf("invalid validators: %w", err)
+	}
+
+	valSet, err := tmconsensus.NewValidatorSet(resp.Validators, m.hashScheme)
+	if err != nil {
+		return tmconsensus.ValidatorSet{}, fmt.Errorf(
+			"failed to calculate hashes for finalized validator set: %w", err,
+		)
+	}
+
+	if m.vsTransitionValidator != nil {
+		// The finalized validators take effect two heights later,
+		// replacing the finalized header's next validator set.
+		if err := m.vsTransitionValidator.ValidateValidatorSetTransition(
+			rlc.H+2, rlc.PrevFinNextValSet, valSet,
+		); err != nil {
+			return tmconsensus.ValidatorSet{}, fmt.Errorf(
+				"%w: %w", errDisallowedValidatorSetTransition, err,
+			)
+		}
+	}
+
+	return valSet, nil
+}
+
+// sendValidatorSetUpdate sends u on the validator set updates channel, if one is set,
+// discarding the oldest buffered update if the channel is full.
+func (m *StateMachine) sendValidatorSetUpdate(u tmelink.ValidatorSetUpdate) {
+	if m.valSetUpdatesOut == nil {
+		return
+	}
+
+	for {
+		select {
+		case m.valSetUpdatesOut <- u:
+			return
+		default:
+		}
+
+		// The channel was full, so make room.
+		// The reader may have drained it in the meantime,
+		// in which case the next send attempt succeeds regardless.
+		select {
+		case old := <-m.valSetUpdatesOut:
+			m.log.Debug(
+				"Discarding unread validator set update",
+				"height", old.Height,
+			)
+		default:
+		}
+	}
+}
+
+// flushPendingFinalization is called from the kernel when the watchdog terminates the state machine.
+// If the driver's finalization response had arrived but was not yet saved,
+// either because it is still buffered in rlc.FinalizeRespCh
+// or because saving it was interrupted,
+// it is saved now, bounded by m.watchdogFinalizationFlushTimeout.
+//
+// Nothing is flushed while the resource guard is paused,
+// including a finalization that the guard is holding;
+// that finalization is only ever saved when the guard recovers.
+func (m *StateMachine) flushPendingFinalization(ctx context.Context, rlc *tsi.RoundLifecycle) {
+	if m.rg.Paused() {
+		// The resource guard reported that durable writes are unsafe,
+		// so a flush could corrupt the finalization store.
+		m.log.Warn(
+			"Skipping flush of pending finalization while resource guard is paused",
+			"height", rlc.H, "round", rlc.R,
+		)
+		return
+	}
+
+	resp := m.unsavedFinalization
+	if resp == nil {
+		// Receiving from a nil channel is never ready, so this is safe
+		// even if the finalization was already handled.
+		select {
+		case r := <-rlc.FinalizeRespCh:
+			resp = &r
+		default:
+			// Nothing pending.
+			return
+		}
+	}
+
+	if resp.Height != rlc.H || resp.Round != rlc.R {
+		m.log.Warn(
+			"Skipping flush of finalization for unexpected height/round",
+			"height", rlc.H, "round", rlc.R,
+			"resp_height", resp.Height, "resp_round", resp.Round,
+		)
+		return
+	}
+
+	valSet, err := m.validateFinalization(rlc, *resp)
+	if err != nil {
+		glog.HRE(m.log, rlc.H, rlc.R, err).Warn(
+			"Skipping flush of finalization that failed validation",
+		)
+		return
+	}
+
+	// The kernel context is already cancelled,
+	// so detach from it while keeping its values.
+	fCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.watchdogFinalizationFlushTimeout)
+	defer cancel()
+
+	if err := m.fStore.SaveFinalization(
+		fCtx,
+		rlc.H, rlc.R,
+		string(resp.BlockHash),
+		valSet,
+		string(resp.AppStateHash),
+	); err != nil {
+		glog.HRE(m.log, rlc.H, rlc.R, err).Error(
+			"Failed to flush finalization to Finalization Store during watchdog termination",
+		)
+		return
+	}
+
+	m.unsavedFinalization = nil
+	m.log.Info(
+		"Flushed pending finalization during watchdog termination",
+		"height", rlc.H, "round", rlc.R,
+		"block_hash", glog.Hex(resp.BlockHash),
+	)
+}
+
+// canEndCommitWaitEarly reports whether the state machine is configured
+// to end commit wait before its timer elapses,
+// and whether rlc has a stored finalization
+// along with either 100% precommits or a single validator.
+func (m *StateMachine) canEndCommitWaitEarly(rlc *tsi.RoundLifecycle) bool {
+	if rlc.S != tsi.StepCommitWait {
+		return false
+	}
+
+	singleValidator := m.skipSingleValidatorCommitWait && len(rlc.CurValSet.Validators) == 1
+	if !m.endCommitWaitOnFullPrecommits && !singleValidator {
+		return false
+	}
+
+	if !rlc.HasFinalization() {
+		return false
+	}
+
+	if singleValidator {
+		// The lone validator's precommit was what brought us to commit wait.
+		return true
+	}
+
+	vs := rlc.VRV.VoteSummary
+	return vs.AvailablePower > 0 && vs.TotalPrecommitPower == vs.AvailablePower
+}
+
+// endCommitWaitEarly treats the commit wait timer as elapsed and advances the height.
+// The caller must ensure [*StateMachine.canEndCommitWaitEarly] is true.
+func (m *StateMachine) endCommitWaitEarly(ctx context.Context, rlc *tsi.RoundLifecycle) (ok bool) {
+	if !rlc.HandleCommitEvent(tsi.CommitEventWaitElapsed) {
+		panic(errors.New("BUG: ended commit wait early without a finalization"))
+	}
+
+	return m.advanceHeight(ctx, rlc)
+}
+
 func (m *StateMachine) handleTimerElapsed(ctx context.Context, rlc *tsi.RoundLifecycle) (ok bool) {
 	defer trace.StartRegion(ctx, "handleTimerElapsed").End()
 
@@ -1477,7 +2481,7 @@ func (m *StateMachine) handleTimerElapsed(ctx context.Context, rlc *tsi.RoundLif
 			ctx, m.log,
 			m.cm.ChooseProposedBlockRequests, tsi.ChooseProposedBlockRequest{
 				// Exclude invalid proposed blocks.
-				PHs:    m.rejectMismatchedProposedHeaders(rlc.VRV.ProposedHeaders, rlc),
+				PHs:    m.strategyProposedHeaders(rlc.VRV.ProposedHeaders, rlc),
 				Result: rlc.PrevoteHashCh, // Is it ever possible this channel is nil?
 			},
 			"choosing proposed block following proposal timeout",
@@ -1528,20 +2532,11 @@ func (m *StateMachine) handleTimerElapsed(ctx context.Context, rlc *tsi.RoundLif
 		}
 
 	case tsi.StepCommitWait:
-		rlc.CommitWaitElapsed = true
-
-		rlc.CancelTimer()
-		rlc.StepTimer = nil
-		rlc.CancelTimer = nil
-
-		if len(rlc.FinalizedValSet.Validators) == 0 {
-			// The timer has elapsed but we don't have a finalization yet.
-			rlc.S = tsi.StepAwaitingFinalization
+		if !rlc.HandleCommitEvent(tsi.CommitEventWaitElapsed) {
+			// Still waiting on the finalization.
 			return true
 		}
 
-		// We already finalized and now the commit wait has elapsed,
-		// so now we have to advance the height.
 		if !m.advanceHeight(ctx, rlc) {
 			return false
 		}
@@ -1575,7 +2570,7 @@ func (m *StateMachine) handleBlockDataArrival(ctx context.Context, rlc *tsi.Roun
 
 	// The height and round match, and we are able to prevote,
 	// so now we need to construct the consider block request.
-	okPHs := m.rejectMismatchedProposedHeaders(rlc.VRV.ProposedHeaders, rlc)
+	okPHs := m.strategyProposedHeaders(rlc.VRV.ProposedHeaders, rlc)
 	if len(okPHs) == 0 {
 		return true
 	}
@@ -1589,7 +2584,7 @@ func (m *StateMachine) handleBlockDataArrival(ctx context.Context, rlc *tsi.Roun
 	// But we do need to construct the slice of updated block IDs.
 	// Gather any other incoming data arrivals.
 	dataIDMap := make(map[string]struct{}, 1+len(m.blockDataArrivalCh))
-	dataIDMap[a.ID] = struct{}{}
+	dataIDMap[string(a.ID)] = struct{}{}
 GATHER_ARRIVALS:
 	for {
 		select {
@@ -1599,7 +2594,7 @@ GATHER_ARRIVALS:
 			if x.Height != a.Height || x.Round != a.Round {
 				continue GATHER_ARRIVALS
 			}
-			dataIDMap[x.ID] = struct{}{}
+			dataIDMap[string(x.ID)] = struct{}{}
 		case <-ctx.Done():
 			m.log.Info(
 				"Quitting due to context cancellation while gathering block data arrivals",
@@ -1614,14 +2609,14 @@ GATHER_ARRIVALS:
 
 	// We have a list of data IDs that have arrived.
 	// Exclude any that do not map to the proposed blocks we are re-checking.
-	req.Reason.UpdatedBlockDataIDs = make([]string, 0, max(len(req.PHs), len(dataIDMap)))
+	req.Reason.UpdatedBlockDataIDs = make([]tmconsensus.DataID, 0, max(len(req.PHs), len(dataIDMap)))
 	for _, ph := range req.PHs {
 		_, dataArrived := dataIDMap[string(ph.Header.DataID)]
 		if !dataArrived {
 			continue
 		}
 
-		req.Reason.UpdatedBlockDataIDs = append(req.Reason.UpdatedBlockDataIDs, string(ph.Header.DataID))
+		req.Reason.UpdatedBlockDataIDs = append(req.Reason.UpdatedBlockDataIDs, ph.Header.DataID)
 	}
 
 	if len(req.Reason.UpdatedBlockDataIDs) == 0 {
@@ -1644,7 +2639,10 @@ func (m *StateMachine) advanceHeight(ctx context.Context, rlc *tsi.RoundLifecycl
 	rlc.CycleFinalization()
 	rlc.Reset(ctx, rlc.H+1, 0)
 
-	if err := m.smStore.SetStateMachineHeightRound(ctx, rlc.H, 0); err != nil {
+	if !m.resourcesAvailable(ctx, rlc, pausedAction{Kind: pausedHeightRound}) {
+		// Saved when the resource guard recovers, if we are still in this round.
+		// Entering the round is not itself a durable write, so continue.
+	} else if err := m.smStore.SetStateMachineHeightRound(ctx, rlc.H, 0); err != nil {
 		m.log.Error(
 			"Failed to set state machine height/round when advancing height",
 			"h", rlc.H,
@@ -1665,10 +2663,33 @@ func (m *StateMachine) advanceHeight(ctx context.Context, rlc *tsi.RoundLifecycl
 }
 
 func (m *StateMachine) advanceRound(ctx context.Context, rlc *tsi.RoundLifecycle) (ok bool) {
+	if m.maxRoundsPerHeight > 0 && rlc.R+1 >= m.maxRoundsPerHeight {
+		logArgs := []any{
+			"h", rlc.H,
+			"r", rlc.R,
+			"step", rlc.S,
+			"max_rounds", m.maxRoundsPerHeight,
+		}
+		if rlc.VRV != nil {
+			// The VRV may be unset if the round was skipped from its initial state.
+			logArgs = append(logArgs, "vote_summary", rlc.VRV.VoteSummary)
+		}
+		m.log.Error("Exceeded maximum rounds per height; halting", logArgs...)
+
+		m.wd.Terminate(fmt.Sprintf(
+			"state machine exceeded maximum of %d rounds at height %d",
+			m.maxRoundsPerHeight, rlc.H,
+		))
+		return false
+	}
+
 	// TODO: do we need to do anything with the finalizations?
 	rlc.Reset(ctx, rlc.H, rlc.R+1)
 
-	if err := m.smStore.SetStateMachineHeightRound(ctx, rlc.H, rlc.R); err != nil {
+	if !m.resourcesAvailable(ctx, rlc, pausedAction{Kind: pausedHeightRound}) {
+		// Saved when the resource guard recovers, if we are still in this round.
+		// Entering the round is not itself a durable write, so continue.
+	} else if err := m.smStore.SetStateMachineHeightRound(ctx, rlc.H, rlc.R); err != nil {
 		m.log.Error(
 			"Failed to set state machine height/round when advancing round",
 			"h", rlc.H,
@@ -1690,6 +2711,80 @@ func (m *StateMachine) advanceRound(ctx context.Context, rlc *tsi.RoundLifecycle
 	return m.advance(ctx, rlc, re)
 }
 
+// observeEnterRound passes a clone of rv to the configured enter round observer, if any.
+// It never blocks.
+func (m *StateMachine) observeEnterRound(rv tmconsensus.RoundView) {
+	if m.ero != nil {
+		m.ero.Observe(rv.Clone())
+	}
+}
+
+// notifyProposalPrepare sends a proposal prepare notification for rlc's round,
+// as described in [*StateMachine.sendProposalPrepare].
+// Rounds where this validator has already proposed are skipped.
+func (m *StateMachine) notifyProposalPrepare(rlc *tsi.RoundLifecycle) {
+	if rlc.ProposalCh == nil {
+		return
+	}
+
+	m.sendProposalPrepare(rlc.H, rlc.R, rlc.CurValSet)
+}
+
+// sendProposalPrepare sends a proposal prepare notification for h and r,
+// if one was requested and this validator is the expected proposer in vs.
+// A notification that was already sent for h and r is not repeated.
+// It never blocks.
+func (m *StateMachine) sendProposalPrepare(h uint64, r uint32, vs tmconsensus.ValidatorSet) {
+	if m.proposalPrepareOut == nil || m.signer == nil {
+		return
+	}
+
+	if h == m.proposalPrepareH && r == m.proposalPrepareR {
+		return
+	}
+
+	if len(vs.Validators) == 0 {
+		return
+	}
+	if !tmconsensus.ExpectedProposer(vs, h, r).PubKey.Equal(m.signer.PubKey()) {
+		return
+	}
+
+	m.proposalPrepareH, m.proposalPrepareR = h, r
+
+	select {
+	case m.proposalPrepareOut <- tmelink.ProposalPrepare{Height: h, Round: r}:
+	default:
+		m.log.Debug(
+			"Dropping proposal prepare notification because output channel was not ready",
+			"h", h, "r", r,
+		)
+	}
+}
+
+// checkRoundEntranceValidators reports whether rer carries a usable validator set.
+// A round view without any validators would make every voting threshold meaningless,
+// so it can only indicate a bug or corrupted state in the mirror or its stores;
+// in that case the state machine halts through the watchdog and reports false.
+func (m *StateMachine) checkRoundEntranceValidators(
+	re tmeil.StateMachineRoundEntrance, rer tmeil.RoundEntranceResponse,
+) bool {
+	if !rer.IsVRV() || len(rer.VRV.ValidatorSet.Validators) > 0 {
+		return true
+	}
+
+	m.log.Error(
+		"FATAL: round entrance response has an empty validator set; halting",
+		"h", re.H, "r", re.R,
+		"vrv_h", rer.VRV.Height, "vrv_r", rer.VRV.Round,
+	)
+	m.wd.Terminate(fmt.Sprintf(
+		"state machine received empty validator set when entering round %d/%d",
+		re.H, re.R,
+	))
+	return false
+}
+
 // advance handles advancing to a new height or round,
 // depending on the content in re.
 // This is the common code between advanceHeight and advanceRound.
@@ -1716,6 +2811,9 @@ func (m *StateMachine) advance(
 	if !ok {
 		return false
 	}
+	if !m.checkRoundEntranceValidators(re, rer) {
+		return false
+	}
 
 	// This is similar to the handling in sendInitialActionSet,
 	// but it differs because at this point it is impossible for us to be on the initial height;
@@ -1725,14 +2823,16 @@ func (m *StateMachine) advance(
 
 		// We have to synchronously enter the round,
 		// but we still enter through the consensus manager for this.
+		m.notifyProposalPrepare(rlc)
 		req := tsi.EnterRoundRequest{
 			RV:     rer.VRV.RoundView,
-			Result: make(chan error), // Unbuffered since both sides sync on this.
+			Result: make(chan tsi.EnterRoundResult), // Unbuffered since both sides sync on this.
 
 			ProposalOut: rlc.ProposalCh,
 		}
+		m.observeEnterRound(req.RV)
 
-		err, ok := gchan.ReqResp(
+		res, ok := gchan.ReqResp(
 			ctx, m.log,
 			m.cm.EnterRoundRequests, req,
 			req.Result,
@@ -1742,15 +2842,16 @@ func (m *StateMachine) advance(
 			// Context cancelled, we cannot continue.
 			return false
 		}
-		if err != nil {
+		if res.Err != nil {
 			panic(fmt.Errorf(
-				"FATAL: error when calling ConsensusStrategy.EnterRound while advancing height: %v", err,
+				"FATAL: error when calling ConsensusStrategy.EnterRound while advancing height: %v", res.Err,
 			))
 		}
 
 		if !m.beginRoundLive(ctx, rlc, rer.VRV) {
 			return false
 		}
+		m.delayProposal(ctx, rlc, res.ProposalDelay)
 	} else {
 		// The state machine is still catching up with the mirror.
 		rlc.MarkCatchingUp()
@@ -1760,6 +2861,8 @@ func (m *StateMachine) advance(
 			Header: rer.CH.Header,
 			Round:  rer.CH.Proof.Round,
 
+			IsCatchup: true,
+
 			Resp: rlc.FinalizeRespCh,
 		}
 
@@ -1796,6 +2899,21 @@ func (m *StateMachine) handleJumpAhead(
 
 	// It's a valid round-forward move.
 	oldRound := rlc.R
+
+	// Let the consensus strategy know about the jump
+	// before it is asked to enter the new round.
+	if !gchan.SendC(
+		ctx, m.log,
+		m.cm.RoundJumpedRequests, tsi.RoundJumpedRequest{
+			From: tmconsensus.RoundPointer{Height: rlc.H, Round: oldRound},
+			To:   tmconsensus.RoundPointer{Height: rlc.H, Round: oldRound + 1},
+		},
+		"sending round jumped request",
+	) {
+		// Context cancelled and logged. Quit.
+		return
+	}
+
 	_ = m.advanceRound(ctx, rlc)
 	m.log.Info(
 		"Jumped ahead following signal from mirror",
@@ -1818,13 +2936,16 @@ func (m *StateMachine) rejectMismatchedProposedHeaders(
 	out := make([]tmconsensus.ProposedHeader, 0, len(in))
 	for _, ph := range in {
 		if string(ph.Header.PrevAppStateHash) != rlc.PrevFinAppStateHash {
+			m.countFilteredProposedHeader(rlc, ph, tmemetrics.FilterPrevAppStateHashMismatch)
 			continue
 		}
 
 		if !ph.Header.ValidatorSet.Equal(rlc.CurValSet) {
+			m.countFilteredProposedHeader(rlc, ph, tmemetrics.FilterValidatorSetMismatch)
 			continue
 		}
 		if !ph.Header.NextValidatorSet.Equal(rlc.PrevFinNextValSet) {
+			m.countFilteredProposedHeader(rlc, ph, tmemetrics.FilterNextValidatorSetMismatch)
 			continue
 		}
 
@@ -1834,6 +2955,48 @@ func (m *StateMachine) rejectMismatchedProposedHeaders(
 	return slices.Clip(out)
 }
 
+// countFilteredProposedHeader increments the filtered proposed header metric for reason,
+// if we are tracking metrics.
+// The same proposed header is filtered every time the round's headers are presented to the strategy,
+// so each header is only counted the first time it is filtered in the round.
+func (m *StateMachine) countFilteredProposedHeader(
+	rlc *tsi.RoundLifecycle, ph tmconsensus.ProposedHeader, reason tmemetrics.ProposedHeaderFilterReason,
+) {
+	if m.mc == nil {
+		return
+	}
+
+	if _, ok := rlc.FilteredHashes[string(ph.Header.Hash)]; ok {
+		return
+	}
+	rlc.FilteredHashes[string(ph.Header.Hash)] = struct{}{}
+
+	m.mc.IncrementFilteredProposedHeaders(reason)
+}
+
+// observeAction passes a to the configured action observer, if any.
+// It never blocks.
+func (m *StateMachine) observeAction(a tmelink.StateMachineRoundAction) {
+	if m.ao != nil {
+		m.ao.Observe(a)
+	}
+}
+
+// strategyProposedHeaders returns the result of [*StateMachine.rejectMismatchedProposedHeaders],
+// sorted by header hash.
+// Proposed headers may arrive in any order,
+// so sorting them gives every node the same view of the candidates
+// when the consensus strategy is asked to consider or choose a proposed block.
+func (m *StateMachine) strategyProposedHeaders(
+	in []tmconsensus.ProposedHeader, rlc *tsi.RoundLifecycle,
+) []tmconsensus.ProposedHeader {
+	out := m.rejectMismatchedProposedHeaders(in, rlc)
+	slices.SortFunc(out, func(a, b tmconsensus.ProposedHeader) int {
+		return bytes.Compare(a.Header.Hash, b.Header.Hash)
+	})
+	return out
+}
+
 // isParticipating reports whether m has a signer that is part of the current validator set
 // according to rlc.
 func (m *StateMachine) isParticipating(rlc *tsi.RoundLifecycle) bool {
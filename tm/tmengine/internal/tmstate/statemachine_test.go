@@ -1,20 +1,28 @@
 package tmstate_test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"math/rand/v2"
 	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/gwatchdog"
 	"github.com/gordian-engine/gordian/internal/gtest"
 	"github.com/gordian-engine/gordian/tm/tmconsensus"
 	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
 	"github.com/gordian-engine/gordian/tm/tmdriver"
 	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmeil"
 	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmemetrics"
+	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmstate"
 	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmstate/tmstatetest"
 	"github.com/gordian-engine/gordian/tm/tmengine/tmelink"
+	"github.com/gordian-engine/gordian/tm/tmstore"
 	"github.com/stretchr/testify/require"
 )
 
@@ -368,6 +376,9 @@ func TestStateMachine_catchup(t *testing.T) {
 		require.Equal(t, ph1.Header, req.Header)
 		require.Zero(t, req.Round)
 
+		// The finalization was driven by a committed header, not live votes.
+		require.True(t, req.IsCatchup)
+
 		require.Equal(t, 1, cap(req.Resp))
 
 		// The driver sends a response.
@@ -415,6 +426,7 @@ func TestStateMachine_catchup(t *testing.T) {
 		req = gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
 		require.Equal(t, ph2.Header, req.Header)
 		require.Zero(t, req.Round)
+		require.True(t, req.IsCatchup)
 	})
 
 	t.Run("normal start, then catchup on advanced round", func(t *testing.T) {
@@ -488,6 +500,133 @@ func TestStateMachine_catchup(t *testing.T) {
 	})
 }
 
+func TestStateMachine_finalizationInvalidValidators(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		vals func(sfx *tmstatetest.Fixture) []tmconsensus.Validator
+	}{
+		{
+			name: "empty validators",
+			vals: func(*tmstatetest.Fixture) []tmconsensus.Validator {
+				return nil
+			},
+		},
+		{
+			name: "zero total power",
+			vals: func(sfx *tmstatetest.Fixture) []tmconsensus.Validator {
+				vals := sfx.Fx.Vals()
+				for i := range vals {
+					vals[i].Power = 0
+				}
+				return vals
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+			sm := sfx.NewStateMachine()
+			defer sm.Wait()
+			defer cancel()
+
+			re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+			ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+			vt := tmconsensus.VoteTarget{Height: 1, Round: 0, BlockHash: string(ph1.Header.Hash)}
+			sfx.Fx.CommitBlock(ph1.Header, []byte("app_state_1"), 0, map[string]gcrypto.CommonMessageSignatureProof{
+				string(ph1.Header.Hash): sfx.Fx.PrecommitSignatureProof(ctx, vt, nil, []int{1, 2, 3}),
+			})
+
+			ph2 := sfx.Fx.NextProposedHeader([]byte("app_data_2"), 1)
+
+			re.Response <- tmeil.RoundEntranceResponse{
+				CH: tmconsensus.CommittedHeader{
+					Header: ph1.Header,
+					Proof:  ph2.Header.PrevCommitProof,
+				},
+			}
+
+			req := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+			gtest.SendSoon(t, req.Resp, tmdriver.FinalizeBlockResponse{
+				Height: 1, Round: 0,
+				BlockHash: ph1.Header.Hash,
+
+				Validators: tc.vals(sfx),
+
+				AppStateHash: []byte("app_state_1"),
+			})
+
+			// The state machine halts instead of advancing to the next height.
+			gtest.NotSendingSoon(t, sfx.RoundEntranceOutCh)
+
+			// And it did not save the finalization.
+			_, _, _, _, err := sfx.Cfg.FinalizationStore.LoadFinalizationByHeight(ctx, 1)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestStateMachine_finalizationMismatchedHeight(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+	vt := tmconsensus.VoteTarget{Height: 1, Round: 0, BlockHash: string(ph1.Header.Hash)}
+	sfx.Fx.CommitBlock(ph1.Header, []byte("app_state_1"), 0, map[string]gcrypto.CommonMessageSignatureProof{
+		string(ph1.Header.Hash): sfx.Fx.PrecommitSignatureProof(ctx, vt, nil, []int{1, 2, 3}),
+	})
+
+	ph2 := sfx.Fx.NextProposedHeader([]byte("app_data_2"), 1)
+
+	re.Response <- tmeil.RoundEntranceResponse{
+		CH: tmconsensus.CommittedHeader{
+			Header: ph1.Header,
+			Proof:  ph2.Header.PrevCommitProof,
+		},
+	}
+
+	// The driver responds with the wrong height.
+	req := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+	require.Equal(t, uint64(1), req.Header.Height)
+	gtest.SendSoon(t, req.Resp, tmdriver.FinalizeBlockResponse{
+		Height: 2, Round: 0,
+		BlockHash: ph1.Header.Hash,
+
+		Validators: sfx.Fx.Vals(),
+
+		AppStateHash: []byte("app_state_1"),
+	})
+
+	// The state machine halts with a clear reason.
+	_ = gtest.ReceiveSoon(t, sfx.WatchdogCtx.Done())
+	require.True(t, gwatchdog.IsTermination(sfx.WatchdogCtx))
+
+	var ft gwatchdog.ForcedTerminationError
+	require.ErrorAs(t, context.Cause(sfx.WatchdogCtx), &ft)
+	require.Contains(t, ft.Reason, "finalization for height/round 2/0 in response to request for 1/0")
+
+	// And nothing was stored for either height.
+	_, _, _, _, err := sfx.Cfg.FinalizationStore.LoadFinalizationByHeight(ctx, 1)
+	require.Error(t, err)
+	_, _, _, _, err = sfx.Cfg.FinalizationStore.LoadFinalizationByHeight(ctx, 2)
+	require.Error(t, err)
+}
+
 func TestStateMachine_stateTransitions(t *testing.T) {
 	t.Run("from awaiting proposal", func(t *testing.T) {
 		for _, tc := range []struct {
@@ -1698,6 +1837,186 @@ func TestStateMachine_proposedHeaderFiltering(t *testing.T) {
 	}
 }
 
+func TestStateMachine_proposedHeaderOrdering(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Two independent state machines with identical, deterministic validators,
+	// standing in for two separate nodes.
+	sfxA := tmstatetest.NewFixture(ctx, t, 4)
+	sfxB := tmstatetest.NewFixture(ctx, t, 4)
+
+	smA := sfxA.NewStateMachine()
+	defer smA.Wait()
+	smB := sfxB.NewStateMachine()
+	defer smB.Wait()
+	defer cancel()
+
+	phs := make([]tmconsensus.ProposedHeader, 3)
+	for i := range phs {
+		phs[i] = sfxA.Fx.NextProposedHeader([]byte{byte(i)}, i)
+		sfxA.Fx.SignProposal(ctx, &phs[i], i)
+	}
+
+	reversed := slices.Clone(phs)
+	slices.Reverse(reversed)
+
+	considerPHs := func(sfx *tmstatetest.Fixture, arrived []tmconsensus.ProposedHeader) []tmconsensus.ProposedHeader {
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		enterCh := sfx.CStrat.ExpectEnterRound(1, 0, nil)
+
+		vrv := sfx.EmptyVRV(1, 0)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+		_ = gtest.ReceiveSoon(t, enterCh)
+
+		// All proposed headers arrive together in the given order.
+		vrv.ProposedHeaders = arrived
+		vrv.Version++
+		gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+		pbReq := gtest.ReceiveSoon(t, sfx.CStrat.ConsiderProposedBlocksRequests)
+		gtest.SendSoon(t, pbReq.ChoiceError, tmconsensus.ErrProposedBlockChoiceNotReady)
+		return pbReq.PHs
+	}
+
+	gotA := considerPHs(sfxA, phs)
+	gotB := considerPHs(sfxB, reversed)
+
+	require.Equal(t, gotA, gotB)
+	require.ElementsMatch(t, phs, gotA)
+	require.True(t, slices.IsSortedFunc(gotA, func(a, b tmconsensus.ProposedHeader) int {
+		return bytes.Compare(a.Header.Hash, b.Header.Hash)
+	}))
+}
+
+func TestStateMachine_dataAvailability(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+	ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+	sfx.Fx.SignProposal(ctx, &ph1, 1)
+	ph2 := sfx.Fx.NextProposedHeader([]byte("app_data_2"), 2)
+	sfx.Fx.SignProposal(ctx, &ph2, 2)
+
+	// Only ph1's data is available.
+	sfx.Cfg.DataAvailabilityChecker = func(_ context.Context, dataID string) (bool, error) {
+		return dataID == string(ph1.Header.DataID), nil
+	}
+	sfx.Cfg.DataAvailabilityTimeout = time.Second
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	cStrat := sfx.CStrat
+	_ = cStrat.ExpectEnterRound(1, 0, nil)
+
+	// Channel is 1-buffered, don't have to select.
+	vrv := sfx.EmptyVRV(1, 0)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	vrv = vrv.Clone()
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1, ph2}
+	vrv.Version++
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	// The header with unavailable data is withheld from the strategy.
+	considerReq := gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+	require.Equal(t, []tmconsensus.ProposedHeader{ph1}, considerReq.PHs)
+	require.Equal(t, []string{string(ph1.Header.Hash)}, considerReq.Reason.NewProposedBlocks)
+	gtest.SendSoon(t, considerReq.ChoiceError, tmconsensus.ErrProposedBlockChoiceNotReady)
+
+	// Likewise when the proposal timer elapses.
+	require.NoError(t, sfx.RoundTimer.ElapseProposalTimer(1, 0))
+	chooseReq := gtest.ReceiveSoon(t, cStrat.ChooseProposedBlockRequests)
+	require.Equal(t, []tmconsensus.ProposedHeader{ph1}, chooseReq.Input)
+}
+
+func TestStateMachine_blockDataAvailabilityFunc(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+	ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+	sfx.Fx.SignProposal(ctx, &ph1, 1)
+	ph2 := sfx.Fx.NextProposedHeader([]byte("app_data_2"), 2)
+	sfx.Fx.SignProposal(ctx, &ph2, 2)
+
+	// Initially only ph1's data is available.
+	var mu sync.Mutex
+	available := map[string]bool{string(ph1.Header.DataID): true}
+	sfx.Cfg.BlockDataAvailabilityFunc = func(_ context.Context, h uint64, r uint32, dataID string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if h != 1 || r != 0 {
+			return false
+		}
+		return available[dataID]
+	}
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	cStrat := sfx.CStrat
+	_ = cStrat.ExpectEnterRound(1, 0, nil)
+
+	// Channel is 1-buffered, don't have to select.
+	vrv := sfx.EmptyVRV(1, 0)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	vrv = vrv.Clone()
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1, ph2}
+	vrv.Version++
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	// The header whose data the driver does not have is withheld from the strategy.
+	considerReq := gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+	require.Equal(t, []tmconsensus.ProposedHeader{ph1}, considerReq.PHs)
+	gtest.SendSoon(t, considerReq.ChoiceError, tmconsensus.ErrProposedBlockChoiceNotReady)
+
+	// Once the driver has ph2's data, the next query surfaces it.
+	mu.Lock()
+	available[string(ph2.Header.DataID)] = true
+	mu.Unlock()
+
+	require.NoError(t, sfx.RoundTimer.ElapseProposalTimer(1, 0))
+	chooseReq := gtest.ReceiveSoon(t, cStrat.ChooseProposedBlockRequests)
+	require.Equal(t, []tmconsensus.ProposedHeader{ph1, ph2}, chooseReq.Input)
+}
+
+func TestStateMachine_blockDataAvailabilityFunc_exclusiveWithChecker(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+	sfx.Cfg.DataAvailabilityChecker = func(context.Context, string) (bool, error) {
+		return true, nil
+	}
+	sfx.Cfg.BlockDataAvailabilityFunc = func(context.Context, uint64, uint32, string) bool {
+		return true
+	}
+
+	_, err := tmstate.NewStateMachine(sfx.WatchdogCtx, sfx.Log, sfx.Cfg)
+	require.Error(t, err)
+}
+
 func TestStateMachine_decidePrecommit(t *testing.T) {
 	t.Run("majority prevotes at initialization", func(t *testing.T) {
 		t.Parallel()
@@ -2302,6 +2621,9 @@ func TestStateMachine_finalization(t *testing.T) {
 		require.Equal(t, ph1.Header, finReq.Header)
 		require.Zero(t, finReq.Round)
 
+		// The finalization was driven by live votes, not a committed header.
+		require.False(t, finReq.IsCatchup)
+
 		// Response channel must be 1-buffered to avoid the app blocking on send.
 		require.Equal(t, 1, cap(finReq.Resp))
 
@@ -2850,6 +3172,91 @@ func TestStateMachine_finalization(t *testing.T) {
 		}
 		_ = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
 	})
+
+	t.Run("randomized order of finalization and commit wait", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 3)
+		sfx.Cfg.Signer = nil
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		const nHeights = 8
+
+		seed := uint64(time.Now().UnixNano())
+		t.Logf("seed: %d", seed)
+		rng := rand.New(rand.NewPCG(seed, seed))
+
+		cStrat := sfx.CStrat
+		for h := uint64(1); h <= nHeights; h++ {
+			_ = cStrat.ExpectEnterRound(h, 0, nil)
+		}
+
+		var prevPH tmconsensus.ProposedHeader
+		for h := uint64(1); h <= nHeights; h++ {
+			re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+			require.Equal(t, h, re.H)
+
+			vrv := sfx.EmptyVRV(h, 0)
+			if h > 1 {
+				sfx.Fx.CommitBlock(prevPH.Header, []byte(fmt.Sprintf("state_%d", h-1)), 0, map[string]gcrypto.CommonMessageSignatureProof{
+					string(prevPH.Header.Hash): sfx.Fx.PrecommitSignatureProof(ctx, tmconsensus.VoteTarget{
+						Height:    h - 1,
+						Round:     0,
+						BlockHash: string(prevPH.Header.Hash),
+					}, nil, []int{0, 1, 2}),
+				})
+			}
+			ph := sfx.Fx.NextProposedHeader([]byte(fmt.Sprintf("app_data_%d", h)), 0)
+			vrv.PrevCommitProof = ph.Header.PrevCommitProof.Clone()
+			re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+			sfx.Fx.SignProposal(ctx, &ph, 0)
+			vrv = vrv.Clone()
+			vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph}
+			vrv = sfx.Fx.UpdateVRVPrevotes(ctx, vrv, map[string][]int{
+				string(ph.Header.Hash): {0, 1, 2},
+			})
+			vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+				string(ph.Header.Hash): {0, 1, 2},
+			})
+			gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+			finReq := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+			sfx.RoundTimer.RequireActiveCommitWaitTimer(t, h, 0)
+
+			finResp := tmdriver.FinalizeBlockResponse{
+				Height: h, Round: 0,
+				BlockHash:    ph.Header.Hash,
+				Validators:   sfx.Fx.Vals(),
+				AppStateHash: []byte(fmt.Sprintf("state_%d", h)),
+			}
+
+			// As in the previous test, there is no good synchronization point
+			// between the two events, so sleep briefly to ensure the first one is handled.
+			if rng.IntN(2) == 0 {
+				t.Logf("height %d: finalization before commit wait", h)
+				gtest.SendSoon(t, finReq.Resp, finResp)
+				gtest.Sleep(gtest.ScaleMs(10))
+				require.NoError(t, sfx.RoundTimer.ElapseCommitWaitTimer(h, 0))
+			} else {
+				t.Logf("height %d: commit wait before finalization", h)
+				require.NoError(t, sfx.RoundTimer.ElapseCommitWaitTimer(h, 0))
+				gtest.Sleep(gtest.ScaleMs(10))
+				gtest.SendSoon(t, finReq.Resp, finResp)
+			}
+
+			prevPH = ph
+		}
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.Equal(t, uint64(nHeights+1), re.H)
+	})
 }
 
 func TestStateMachine_notParticipating(t *testing.T) {
@@ -3153,6 +3560,11 @@ func TestStateMachine_timers(t *testing.T) {
 
 			require.NoError(t, sfx.RoundTimer.ElapseProposalTimer(1, 0))
 			choosePBReq := gtest.ReceiveSoon(t, cStrat.ChooseProposedBlockRequests)
+
+			// The strategy sees the proposed headers sorted by hash.
+			slices.SortFunc(phs, func(a, b tmconsensus.ProposedHeader) int {
+				return bytes.Compare(a.Header.Hash, b.Header.Hash)
+			})
 			require.Equal(t, phs, choosePBReq.Input)
 
 			// Now choosing one of the PHs causes if the strategy makes a choice, it gets sent to the mirror.
@@ -3534,23 +3946,74 @@ func TestStateMachine_jumpAhead(t *testing.T) {
 
 		_ = gtest.ReceiveSoon(t, er11Ch)
 	})
+
+	t.Run("strategy observing round jumps", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		cStrat := &roundJumpObservingStrategy{
+			MockConsensusStrategy: sfx.CStrat,
+			jumps:                 make(chan [2]tmconsensus.RoundPointer, 1),
+		}
+		sfx.Cfg.ConsensusStrategy = cStrat
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		_ = cStrat.ExpectEnterRound(1, 0, nil)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 0)}
+
+		er11Ch := cStrat.ExpectEnterRound(1, 1, nil)
+
+		nextVRV := sfx.EmptyVRV(1, 1)
+		nextVRV = sfx.Fx.UpdateVRVPrevotes(ctx, nextVRV, map[string][]int{
+			"": {1, 2, 3},
+		})
+
+		gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{
+			JumpAheadRoundView: &nextVRV,
+		})
+
+		// The strategy is notified of the jump before entering the new round.
+		jump := gtest.ReceiveSoon(t, cStrat.jumps)
+		require.Equal(t, tmconsensus.RoundPointer{Height: 1, Round: 0}, jump[0])
+		require.Equal(t, tmconsensus.RoundPointer{Height: 1, Round: 1}, jump[1])
+
+		re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.Equal(t, uint64(1), re.H)
+		require.Equal(t, uint32(1), re.R)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: nextVRV}
+
+		_ = gtest.ReceiveSoon(t, er11Ch)
+
+		// No further jumps were reported.
+		gtest.NotSendingSoon(t, cStrat.jumps)
+	})
 }
 
-func TestStateMachine_heightCommittedSignal(t *testing.T) {
-	t.Run("before commit wait timer elapses and before finalization response", func(t *testing.T) {
+func TestStateMachine_commitWaitEnteredNotification(t *testing.T) {
+	t.Run("external precommits", func(t *testing.T) {
 		t.Parallel()
 
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
 		sfx := tmstatetest.NewFixture(ctx, t, 4)
+		cwCh := make(chan tmelink.CommitWaitEntered, 1)
+		sfx.Cfg.CommitWaitEnteredOut = cwCh
 
 		sm := sfx.NewStateMachine()
 		defer sm.Wait()
 		defer cancel()
 
 		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
-		require.NotNil(t, re.HeightCommitted)
 
 		cStrat := sfx.CStrat
 		er10Ch := cStrat.ExpectEnterRound(1, 0, nil)
@@ -3559,7 +4022,7 @@ func TestStateMachine_heightCommittedSignal(t *testing.T) {
 		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
 		_ = gtest.ReceiveSoon(t, er10Ch)
 
-		// Now just send an effectively committed block.
+		// The rest of the network commits the block before this validator votes.
 		ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 3)
 		sfx.Fx.SignProposal(ctx, &ph1, 3)
 		vrv = vrv.Clone()
@@ -3572,8 +4035,106 @@ func TestStateMachine_heightCommittedSignal(t *testing.T) {
 		vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
 			string(ph1.Header.Hash): {1, 2, 3},
 		})
+		gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
 
-		// This causes the state machine to be in commit wait.
+		cw := gtest.ReceiveSoon(t, cwCh)
+		require.Equal(t, tmelink.CommitWaitEntered{
+			Height: 1, Round: 0,
+			BlockHash: ph1.Header.Hash,
+			External:  true,
+		}, cw)
+
+		_ = gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+	})
+
+	t.Run("own precommit", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+		cwCh := make(chan tmelink.CommitWaitEntered, 1)
+		sfx.Cfg.CommitWaitEnteredOut = cwCh
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		vrv := sfx.EmptyVRV(1, 0)
+		ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+		vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+		vrv = sfx.Fx.UpdateVRVPrevotes(ctx, vrv, map[string][]int{
+			string(ph1.Header.Hash): {1, 2, 3},
+		})
+
+		cStrat := sfx.CStrat
+		_ = cStrat.ExpectEnterRound(1, 0, nil)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+		// This validator precommits for the block.
+		cReq := gtest.ReceiveSoon(t, cStrat.DecidePrecommitRequests)
+		gtest.SendSoon(t, cReq.ChoiceHash, string(ph1.Header.Hash))
+		act := gtest.ReceiveSoon(t, re.Actions)
+		require.NotEmpty(t, act.Precommit.Sig)
+
+		gtest.NotSending(t, cwCh)
+
+		// The mirror reports our precommit as part of the majority.
+		vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 2},
+		})
+		gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+		cw := gtest.ReceiveSoon(t, cwCh)
+		require.Equal(t, tmelink.CommitWaitEntered{
+			Height: 1, Round: 0,
+			BlockHash: ph1.Header.Hash,
+			External:  false,
+		}, cw)
+	})
+}
+
+func TestStateMachine_heightCommittedSignal(t *testing.T) {
+	t.Run("before commit wait timer elapses and before finalization response", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.NotNil(t, re.HeightCommitted)
+
+		cStrat := sfx.CStrat
+		er10Ch := cStrat.ExpectEnterRound(1, 0, nil)
+
+		vrv := sfx.EmptyVRV(1, 0)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+		_ = gtest.ReceiveSoon(t, er10Ch)
+
+		// Now just send an effectively committed block.
+		ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 3)
+		sfx.Fx.SignProposal(ctx, &ph1, 3)
+		vrv = vrv.Clone()
+		vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+		vrv.Version++
+
+		vrv = sfx.Fx.UpdateVRVPrevotes(ctx, vrv, map[string][]int{
+			string(ph1.Header.Hash): {1, 2, 3},
+		})
+		vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+			string(ph1.Header.Hash): {1, 2, 3},
+		})
+
+		// This causes the state machine to be in commit wait.
 		commitWaitStart := sfx.RoundTimer.CommitWaitStartNotification(1, 0)
 		gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{
 			VRV: vrv.Clone(),
@@ -3760,7 +4321,7 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 		// Now the block data arrives.
 		gtest.SendSoon(t, sfx.BlockDataArrivalCh, tmelink.BlockDataArrival{
 			Height: 1, Round: 0,
-			ID: string(ph1.Header.DataID),
+			ID: ph1.Header.DataID,
 		})
 
 		// This triggers a new consider request.
@@ -3773,7 +4334,7 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 		require.Empty(t, pbReq.Reason.NewProposedBlocks)
 
 		// The block data is indicated as the reason.
-		require.Equal(t, []string{string(ph1.Header.DataID)}, pbReq.Reason.UpdatedBlockDataIDs)
+		require.Equal(t, []tmconsensus.DataID{ph1.Header.DataID}, pbReq.Reason.UpdatedBlockDataIDs)
 	})
 
 	t.Run("matching, after proposed block received during enter round", func(t *testing.T) {
@@ -3819,7 +4380,7 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 		// Now the block data arrives.
 		gtest.SendSoon(t, sfx.BlockDataArrivalCh, tmelink.BlockDataArrival{
 			Height: 1, Round: 0,
-			ID: string(ph1.Header.DataID),
+			ID: ph1.Header.DataID,
 		})
 
 		// This triggers a new consider request.
@@ -3832,7 +4393,7 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 		require.Empty(t, pbReq.Reason.NewProposedBlocks)
 
 		// The block data is indicated as the reason.
-		require.Equal(t, []string{string(ph1.Header.DataID)}, pbReq.Reason.UpdatedBlockDataIDs)
+		require.Equal(t, []tmconsensus.DataID{ph1.Header.DataID}, pbReq.Reason.UpdatedBlockDataIDs)
 	})
 
 	t.Run("separate, multiple, valid block data arrivals", func(t *testing.T) {
@@ -3861,6 +4422,12 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 		sfx.Fx.SignProposal(ctx, &ph1, 2)
 		vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1, ph2}
 
+		// The consensus strategy always sees the proposed headers sorted by hash.
+		sortedPHs := slices.Clone(vrv.ProposedHeaders)
+		slices.SortFunc(sortedPHs, func(a, b tmconsensus.ProposedHeader) int {
+			return bytes.Compare(a.Header.Hash, b.Header.Hash)
+		})
+
 		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
 
 		// And since we sent a VRV, the state machine calls into consensus strategy,
@@ -3870,7 +4437,7 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 
 		// ... forces the consensus strategy to consider the available proposed blocks.
 		pbReq := gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
-		require.Equal(t, []tmconsensus.ProposedHeader{ph1, ph2}, pbReq.PHs)
+		require.Equal(t, sortedPHs, pbReq.PHs)
 		require.ElementsMatch(t, []string{string(ph1.Header.Hash), string(ph2.Header.Hash)}, pbReq.Reason.NewProposedBlocks)
 
 		// Don't make a decision yet.
@@ -3880,20 +4447,20 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 		// Now the block data arrives for one.
 		gtest.SendSoon(t, sfx.BlockDataArrivalCh, tmelink.BlockDataArrival{
 			Height: 1, Round: 0,
-			ID: string(ph1.Header.DataID),
+			ID: ph1.Header.DataID,
 		})
 
 		// This triggers a new consider request.
 		pbReq = gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
 
 		// Proposed blocks unchanged.
-		require.Equal(t, []tmconsensus.ProposedHeader{ph1, ph2}, pbReq.PHs)
+		require.Equal(t, sortedPHs, pbReq.PHs)
 
 		// No new blocks.
 		require.Empty(t, pbReq.Reason.NewProposedBlocks)
 
 		// The block data is indicated as the reason.
-		require.Equal(t, []string{string(ph1.Header.DataID)}, pbReq.Reason.UpdatedBlockDataIDs)
+		require.Equal(t, []tmconsensus.DataID{ph1.Header.DataID}, pbReq.Reason.UpdatedBlockDataIDs)
 
 		// No choice yet.
 		gtest.SendSoon(t, pbReq.ChoiceError, tmconsensus.ErrProposedBlockChoiceNotReady)
@@ -3901,14 +4468,14 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 		// Now the other data arrives.
 		gtest.SendSoon(t, sfx.BlockDataArrivalCh, tmelink.BlockDataArrival{
 			Height: 1, Round: 0,
-			ID: string(ph2.Header.DataID),
+			ID: ph2.Header.DataID,
 		})
 
 		// This triggers a new consider request.
 		pbReq = gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
-		require.Equal(t, []tmconsensus.ProposedHeader{ph1, ph2}, pbReq.PHs)
+		require.Equal(t, sortedPHs, pbReq.PHs)
 		require.Empty(t, pbReq.Reason.NewProposedBlocks)
-		require.Equal(t, []string{string(ph2.Header.DataID)}, pbReq.Reason.UpdatedBlockDataIDs)
+		require.Equal(t, []tmconsensus.DataID{ph2.Header.DataID}, pbReq.Reason.UpdatedBlockDataIDs)
 	})
 
 	t.Run("block data arrives before first proposed block", func(t *testing.T) {
@@ -3946,7 +4513,7 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 		// Maybe it's a push model, or maybe the proposed block connection was just slow.
 		gtest.SendSoon(t, sfx.BlockDataArrivalCh, tmelink.BlockDataArrival{
 			Height: 1, Round: 0,
-			ID: string(ph1.Header.DataID),
+			ID: ph1.Header.DataID,
 		})
 
 		// The consider proposed block request isn't sending yet.
@@ -4011,15 +4578,15 @@ func TestStateMachine_blockDataArrival(t *testing.T) {
 		// Now a variety of block data arrives.
 		gtest.SendSoon(t, sfx.BlockDataArrivalCh, tmelink.BlockDataArrival{
 			Height: 1, Round: 1, // Right height, wrong round.
-			ID: string(ph1.Header.DataID),
+			ID: ph1.Header.DataID,
 		})
 		gtest.SendSoon(t, sfx.BlockDataArrivalCh, tmelink.BlockDataArrival{
 			Height: 2, Round: 0, // Wrong height, right round.
-			ID: string(ph1.Header.DataID),
+			ID: ph1.Header.DataID,
 		})
 		gtest.SendSoon(t, sfx.BlockDataArrivalCh, tmelink.BlockDataArrival{
 			Height: 1, Round: 0, // Right height and round.
-			ID: string(ph1.Header.DataID) + "!", // Modified ID.
+			ID: append(slices.Clone(ph1.Header.DataID), '!'), // Modified ID.
 		})
 
 		// None of these triggered a consider request.
@@ -4107,3 +4674,2027 @@ func TestStateMachine_metrics(t *testing.T) {
 	require.Equal(t, uint64(2), m.StateMachineHeight)
 	require.Zero(t, m.StateMachineRound)
 }
+
+func TestStateMachine_filteredProposedHeaderMetrics(t *testing.T) {
+	mutations := tmstatetest.UnacceptableProposedHeaderMutations(tmconsensustest.SimpleHashScheme{}, 4, 4)
+	expCounts := []tmemetrics.FilteredProposedHeaderCounts{
+		{PrevAppStateHashMismatch: 1},
+		{ValidatorSetMismatch: 1},
+		{NextValidatorSetMismatch: 1},
+	}
+	require.Len(t, mutations, len(expCounts))
+
+	for i, tc := range mutations {
+		exp := expCounts[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+			mCh := make(chan tmemetrics.Metrics)
+			mc := tmemetrics.NewCollector(ctx, 4, mCh)
+			defer mc.Wait()
+			defer cancel()
+			mc.UpdateMirror(tmemetrics.MirrorMetrics{
+				VH: 1, VR: 0,
+			})
+			sfx.Cfg.MetricsCollector = mc
+
+			sm := sfx.NewStateMachine()
+			defer sm.Wait()
+			defer cancel()
+
+			re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+			vrv := sfx.EmptyVRV(1, 0)
+			ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 3)
+			tc.Mutate(&ph1)
+			vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+
+			_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+			re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+			// The header is withheld from the strategy.
+			gtest.NotSendingSoon(t, sfx.CStrat.ConsiderProposedBlocksRequests)
+
+			// Presenting the same header again, with an unrelated view update,
+			// does not count it a second time.
+			vrv = sfx.Fx.UpdateVRVPrevotes(ctx, vrv, map[string][]int{
+				"": {0},
+			})
+			gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+			gtest.NotSendingSoon(t, sfx.CStrat.ConsiderProposedBlocksRequests)
+
+			// Earlier metrics may have been published before the header was filtered,
+			// so drain until the latest value is available.
+			var m tmemetrics.Metrics
+			for m.StateMachineFilteredProposedHeaders == (tmemetrics.FilteredProposedHeaderCounts{}) {
+				m = gtest.ReceiveSoon(t, mCh)
+			}
+			require.Equal(t, exp, m.StateMachineFilteredProposedHeaders)
+		})
+	}
+}
+
+func TestStateMachine_blockDataArrivalBuffer(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+	sfx.Cfg.BlockDataArrivalBuffer = 2
+
+	mCh := make(chan tmemetrics.Metrics)
+	mc := tmemetrics.NewCollector(ctx, 4, mCh)
+	defer mc.Wait()
+	defer cancel()
+	sfx.Cfg.MetricsCollector = mc
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	// The kernel is blocked on the round entrance,
+	// so it cannot read any arrivals yet.
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	vrv := sfx.EmptyVRV(1, 0)
+	var phs []tmconsensus.ProposedHeader
+	for i := range 3 {
+		ph := sfx.Fx.NextProposedHeader([]byte(fmt.Sprintf("app_data_1_%d", i)), i)
+		sfx.Fx.SignProposal(ctx, &ph, i)
+		phs = append(phs, ph)
+	}
+	vrv.ProposedHeaders = phs
+
+	// Sending a third arrival does not block,
+	// and it displaces the first arrival.
+	for _, ph := range phs {
+		gtest.SendSoon(t, sfx.BlockDataArrivalCh, tmelink.BlockDataArrival{
+			Height: 1, Round: 0,
+			ID: ph.Header.DataID,
+		})
+	}
+
+	// Metrics are only published once both mirror and state machine values are set.
+	mc.UpdateMirror(tmemetrics.MirrorMetrics{VH: 1})
+	mc.UpdateStateMachine(tmemetrics.StateMachineMetrics{H: 1})
+	var m tmemetrics.Metrics
+	for m.StateMachineDroppedBlockDataArrivals == 0 {
+		m = gtest.ReceiveSoon(t, mCh)
+	}
+	require.Equal(t, uint64(1), m.StateMachineDroppedBlockDataArrivals)
+
+	cStrat := sfx.CStrat
+	_ = cStrat.ExpectEnterRound(1, 0, nil)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	pbReq := gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+	require.Len(t, pbReq.Reason.NewProposedBlocks, 3)
+	gtest.SendSoon(t, pbReq.ChoiceError, tmconsensus.ErrProposedBlockChoiceNotReady)
+
+	// Only the two newest arrivals remained in the buffer.
+	pbReq = gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+	require.Empty(t, pbReq.Reason.NewProposedBlocks)
+	require.ElementsMatch(t, []tmconsensus.DataID{
+		phs[1].Header.DataID, phs[2].Header.DataID,
+	}, pbReq.Reason.UpdatedBlockDataIDs)
+}
+
+func TestStateMachine_strategyResponseTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+	sfx.Cfg.StrategyResponseTimeout = 20 * time.Millisecond
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	cStrat := sfx.CStrat
+	_ = cStrat.ExpectEnterRound(1, 0, nil)
+
+	vrv := sfx.EmptyVRV(1, 0)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	// A proposed header arrives, and the consensus strategy is asked to consider it.
+	ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 3)
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+	vrv.Version++
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	// The strategy never responds to the consider request.
+	_ = gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+	d, ok := sfx.RoundTimer.ActiveStrategyResponse(1, 0)
+	require.True(t, ok)
+	require.Equal(t, 20*time.Millisecond, d)
+	require.NoError(t, sfx.RoundTimer.ElapseStrategyResponseTimer(1, 0))
+
+	// So once the proposal timer elapses, the strategy is asked to choose.
+	require.NoError(t, sfx.RoundTimer.ElapseProposalTimer(1, 0))
+	_ = gtest.ReceiveSoon(t, cStrat.ChooseProposedBlockRequests)
+
+	// The strategy does not respond to the choose request either,
+	// so the state machine prevotes nil.
+	require.NoError(t, sfx.RoundTimer.ElapseStrategyResponseTimer(1, 0))
+	act := gtest.ReceiveSoon(t, re.Actions)
+	require.Empty(t, act.Prevote.TargetHash)
+	require.NotEmpty(t, act.Prevote.Sig)
+
+	// Everyone else prevotes nil too.
+	vrv = sfx.Fx.UpdateVRVPrevotes(ctx, vrv, map[string][]int{
+		"": {0, 1, 2, 3},
+	})
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	// The strategy does not respond to the precommit decision,
+	// so the state machine precommits nil.
+	_ = gtest.ReceiveSoon(t, cStrat.DecidePrecommitRequests)
+	require.NoError(t, sfx.RoundTimer.ElapseStrategyResponseTimer(1, 0))
+	act = gtest.ReceiveSoon(t, re.Actions)
+	require.Empty(t, act.Precommit.TargetHash)
+	require.NotEmpty(t, act.Precommit.Sig)
+
+	// Once everyone precommits nil, the round advances.
+	ercCh := cStrat.ExpectEnterRound(1, 1, nil)
+	vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+		"": {0, 1, 2, 3},
+	})
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+	require.Equal(t, uint64(1), re.H)
+	require.Equal(t, uint32(1), re.R)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 1)}
+
+	erc := gtest.ReceiveSoon(t, ercCh)
+	require.Equal(t, uint64(1), erc.RV.Height)
+	require.Equal(t, uint32(1), erc.RV.Round)
+}
+
+func TestStateMachine_strategyResponseTimeout_enterRoundWaitsForAbandonedCall(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+	sfx.Cfg.StrategyResponseTimeout = time.Second
+
+	strat := &ignoreCancelConsiderStrategy{
+		MockConsensusStrategy: sfx.CStrat,
+
+		considerStarted: make(chan struct{}),
+		release:         make(chan struct{}),
+	}
+	sfx.Cfg.ConsensusStrategy = strat
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+	_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+	vrv := sfx.EmptyVRV(1, 0)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	// A proposed header arrives, and the strategy's consider call never returns,
+	// even after its context is canceled.
+	ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 3)
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+	vrv.Version++
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+	_ = gtest.ReceiveSoon(t, strat.considerStarted)
+	require.NoError(t, sfx.RoundTimer.ElapseStrategyResponseTimer(1, 0))
+
+	// Everyone precommits nil, so the state machine advances to round 1.
+	ercCh := sfx.CStrat.ExpectEnterRound(1, 1, nil)
+	vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+		"": {0, 1, 2, 3},
+	})
+	vrv.Version++
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+	require.Equal(t, uint32(1), re.R)
+	waitStarted := sfx.RoundTimer.StrategyResponseStartNotification(1, 1)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 1)}
+
+	// EnterRound is held while the abandoned consider call is still running,
+	// and the wait is timed by the round timer.
+	_ = gtest.ReceiveSoon(t, waitStarted)
+	gtest.NotSending(t, ercCh)
+
+	// Even once the wait exceeds the timeout, EnterRound cannot be skipped.
+	require.NoError(t, sfx.RoundTimer.ElapseStrategyResponseTimer(1, 1))
+	gtest.NotSending(t, ercCh)
+
+	// Once the consider call returns, the round is entered.
+	close(strat.release)
+	erc := gtest.ReceiveSoon(t, ercCh)
+	require.Equal(t, uint32(1), erc.RV.Round)
+
+	require.False(t, strat.overlapped.Load())
+}
+
+// ignoreCancelConsiderStrategy is a consensus strategy
+// whose ConsiderProposedBlocks method ignores context cancellation
+// and only returns once release is closed,
+// recording whether EnterRound was called while it was running.
+type ignoreCancelConsiderStrategy struct {
+	*tmconsensustest.MockConsensusStrategy
+
+	considerStarted chan struct{}
+	release         chan struct{}
+
+	considering atomic.Bool
+	overlapped  atomic.Bool
+}
+
+func (s *ignoreCancelConsiderStrategy) ConsiderProposedBlocks(
+	context.Context, []tmconsensus.ProposedHeader, tmconsensus.ConsiderProposedBlocksReason,
+) (string, error) {
+	s.considering.Store(true)
+	defer s.considering.Store(false)
+
+	close(s.considerStarted)
+	<-s.release
+	return "", tmconsensus.ErrProposedBlockChoiceNotReady
+}
+
+func (s *ignoreCancelConsiderStrategy) EnterRound(
+	ctx context.Context, rv tmconsensus.RoundView, proposalOut chan<- tmconsensus.Proposal,
+) error {
+	if s.considering.Load() {
+		s.overlapped.Store(true)
+	}
+	return s.MockConsensusStrategy.EnterRound(ctx, rv, proposalOut)
+}
+
+func TestStateMachine_endCommitWaitOnFullPrecommits(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "enabled", enabled: true},
+		{name: "disabled", enabled: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sfx := tmstatetest.NewFixture(ctx, t, 4)
+			sfx.Cfg.EndCommitWaitOnFullPrecommits = tc.enabled
+
+			sm := sfx.NewStateMachine()
+			defer sm.Wait()
+			defer cancel()
+
+			re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+			vrv := sfx.EmptyVRV(1, 0)
+			ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+			vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+			vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+				string(ph1.Header.Hash): {1, 2, 3},
+			})
+
+			_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+			re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+			finReq := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+			sfx.RoundTimer.RequireActiveCommitWaitTimer(t, 1, 0)
+
+			finReq.Resp <- tmdriver.FinalizeBlockResponse{
+				Height: 1, Round: 0,
+				BlockHash: ph1.Header.Hash,
+
+				Validators: sfx.Fx.Vals(),
+
+				AppStateHash: []byte("app_state_1"),
+			}
+
+			// With only 3/4 precommits, the finalization alone does not end commit wait.
+			gtest.NotSendingSoon(t, sfx.RoundEntranceOutCh)
+
+			// Now the final precommit arrives, before the commit wait timer elapses.
+			vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+				string(ph1.Header.Hash): {0, 1, 2, 3},
+			})
+			gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+			if !tc.enabled {
+				// Without the option, the state machine still waits for the timer.
+				gtest.NotSendingSoon(t, sfx.RoundEntranceOutCh)
+				sfx.RoundTimer.RequireActiveCommitWaitTimer(t, 1, 0)
+				require.NoError(t, sfx.RoundTimer.ElapseCommitWaitTimer(1, 0))
+			}
+
+			re2 := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+			require.Equal(t, uint64(2), re2.H)
+			require.Zero(t, re2.R)
+
+			_, blockHash, _, _, err := sfx.Cfg.FinalizationStore.LoadFinalizationByHeight(ctx, 1)
+			require.NoError(t, err)
+			require.Equal(t, string(ph1.Header.Hash), blockHash)
+		})
+	}
+}
+
+func TestStateMachine_skipSingleValidatorCommitWait(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "enabled", enabled: true},
+		{name: "disabled", enabled: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sfx := tmstatetest.NewFixture(ctx, t, 1)
+			sfx.Cfg.SkipSingleValidatorCommitWait = tc.enabled
+
+			sm := sfx.NewStateMachine()
+			defer sm.Wait()
+			defer cancel()
+
+			re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+			vrv := sfx.EmptyVRV(1, 0)
+			ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 0)
+			vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+			vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+				string(ph1.Header.Hash): {0},
+			})
+
+			_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+			re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+			finReq := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+			sfx.RoundTimer.RequireActiveCommitWaitTimer(t, 1, 0)
+
+			finReq.Resp <- tmdriver.FinalizeBlockResponse{
+				Height: 1, Round: 0,
+				BlockHash: ph1.Header.Hash,
+
+				Validators: sfx.Fx.Vals(),
+
+				AppStateHash: []byte("app_state_1"),
+			}
+
+			if !tc.enabled {
+				// Without the option, the state machine still waits for the timer.
+				gtest.NotSendingSoon(t, sfx.RoundEntranceOutCh)
+				sfx.RoundTimer.RequireActiveCommitWaitTimer(t, 1, 0)
+				require.NoError(t, sfx.RoundTimer.ElapseCommitWaitTimer(1, 0))
+			}
+
+			// With the option, the next height begins as soon as the finalization is stored,
+			// without the commit wait timer elapsing.
+			re2 := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+			require.Equal(t, uint64(2), re2.H)
+			require.Zero(t, re2.R)
+		})
+	}
+}
+
+func TestStateMachine_validatorSetUpdates(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+	updates := make(chan tmelink.ValidatorSetUpdate, 1)
+	sfx.Cfg.ValidatorSetUpdatesOut = updates
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	vrv := sfx.EmptyVRV(1, 0)
+	ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+	vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+		string(ph1.Header.Hash): {1, 2, 3},
+	})
+
+	_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	finReq := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+
+	// The application adds a fifth validator at height 1.
+	newVals := tmconsensustest.DeterministicValidatorsEd25519(5).Vals()
+	finReq.Resp <- tmdriver.FinalizeBlockResponse{
+		Height: 1, Round: 0,
+		BlockHash: ph1.Header.Hash,
+
+		Validators: newVals,
+
+		AppStateHash: []byte("app_state_1"),
+	}
+
+	// The new set takes effect two heights later.
+	u := gtest.ReceiveSoon(t, updates)
+	require.Equal(t, uint64(3), u.Height)
+	require.True(t, tmconsensus.ValidatorSlicesEqual(newVals, u.Set.Validators))
+
+	wantSet, err := tmconsensus.NewValidatorSet(newVals, sfx.Fx.HashScheme)
+	require.NoError(t, err)
+	require.True(t, wantSet.Equal(u.Set))
+
+	// Only one update for the one change.
+	gtest.NotSendingSoon(t, updates)
+}
+
+func TestStateMachine_actionObserver(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+	// Buffered so the observer never blocks;
+	// the test reads all observed actions at the end of the round.
+	observed := make(chan tmelink.StateMachineRoundAction, 3)
+	sfx.Cfg.ActionObserver = func(a tmelink.StateMachineRoundAction) {
+		observed <- a
+	}
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	vrv := sfx.EmptyVRV(1, 0)
+
+	cStrat := sfx.CStrat
+	ercCh := cStrat.ExpectEnterRound(1, 0, nil)
+
+	// Channel is 1-buffered, don't have to select.
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	// Propose a block.
+	erc := gtest.ReceiveSoon(t, ercCh)
+	erc.ProposalOut <- tmconsensus.Proposal{DataID: "app_data"}
+	ph := gtest.ReceiveSoon(t, re.Actions).PH
+
+	// The mirror reports our proposed header back,
+	// and the strategy prevotes for it.
+	vrv = vrv.Clone()
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph}
+	vrv.Version++
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	considerReq := gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+	gtest.SendSoon(t, considerReq.ChoiceHash, string(ph.Header.Hash))
+	prevote := gtest.ReceiveSoon(t, re.Actions).Prevote
+
+	// Majority prevotes for our header, and the strategy precommits for it.
+	vrv = sfx.Fx.UpdateVRVPrevotes(ctx, vrv, map[string][]int{
+		string(ph.Header.Hash): {0, 1, 2},
+	})
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	precommitReq := gtest.ReceiveSoon(t, cStrat.DecidePrecommitRequests)
+	gtest.SendSoon(t, precommitReq.ChoiceHash, string(ph.Header.Hash))
+	precommit := gtest.ReceiveSoon(t, re.Actions).Precommit
+
+	// Each action sent to the mirror was also observed, in order.
+	a := gtest.ReceiveSoon(t, observed)
+	require.Equal(t, uint64(1), a.Height)
+	require.Zero(t, a.Round)
+	require.Equal(t, ph, a.PH)
+	require.Empty(t, a.Prevote.Sig)
+	require.Empty(t, a.Precommit.Sig)
+
+	a = gtest.ReceiveSoon(t, observed)
+	require.Empty(t, a.PH.Header.Hash)
+	require.Equal(t, prevote.TargetHash, a.Prevote.TargetHash)
+	require.Equal(t, prevote.SignContent, a.Prevote.SignContent)
+	require.Equal(t, prevote.Sig, a.Prevote.Sig)
+	require.Empty(t, a.Precommit.Sig)
+
+	a = gtest.ReceiveSoon(t, observed)
+	require.Empty(t, a.PH.Header.Hash)
+	require.Empty(t, a.Prevote.Sig)
+	require.Equal(t, precommit.TargetHash, a.Precommit.TargetHash)
+	require.Equal(t, precommit.SignContent, a.Precommit.SignContent)
+	require.Equal(t, precommit.Sig, a.Precommit.Sig)
+}
+
+func TestStateMachine_noDoublePrevote(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+	// Simulate a prior run that prevoted for nil at 1/0 before restarting.
+	nilVT := tmconsensus.VoteTarget{Height: 1, Round: 0}
+	nilSig := sfx.Fx.PrevoteSignature(ctx, nilVT, 0)
+	require.NoError(t, sfx.Cfg.ActionStore.SavePrevoteAction(
+		ctx, sfx.Fx.ValidatorPubKey(0), nilVT, nilSig,
+	))
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	cStrat := sfx.CStrat
+	_ = cStrat.ExpectEnterRound(1, 0, nil)
+
+	// Channel is 1-buffered, don't have to select.
+	vrv := sfx.EmptyVRV(1, 0)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	ph := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+	sfx.Fx.SignProposal(ctx, &ph, 1)
+	vrv = vrv.Clone()
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph}
+	vrv.Version++
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	// The strategy chooses a block that conflicts with the earlier nil prevote.
+	considerReq := gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+	gtest.SendSoon(t, considerReq.ChoiceHash, string(ph.Header.Hash))
+
+	// No second prevote is sent.
+	gtest.NotSendingSoon(t, re.Actions)
+
+	// And the action store still only has the original prevote.
+	ra, err := sfx.Cfg.ActionStore.LoadActions(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Empty(t, ra.PrevoteTarget)
+	require.Equal(t, string(nilSig), ra.PrevoteSignature)
+
+	// The state machine is still running and proceeds to precommit
+	// once the rest of the network reaches a majority prevote.
+	vrv = sfx.Fx.UpdateVRVPrevotes(ctx, vrv, map[string][]int{
+		string(ph.Header.Hash): {1, 2, 3},
+	})
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+	_ = gtest.ReceiveSoon(t, cStrat.DecidePrecommitRequests)
+}
+
+func TestStateMachine_restartDuringCommitWait(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first state machine runs under its own context,
+	// so that it can be stopped independently to simulate a process restart.
+	ctx1, cancel1 := context.WithCancel(ctx)
+	defer cancel1()
+
+	sfx := tmstatetest.NewFixture(ctx1, t, 4)
+
+	// The engine stores the genesis finalization before the chain's initial height.
+	// The state machine needs it after restarting at height 2,
+	// to determine the validators.
+	require.NoError(t, sfx.Cfg.FinalizationStore.SaveFinalization(
+		ctx,
+		0, 0,
+		"genesis_block_hash",
+		sfx.Fx.ValSet(),
+		"genesis_app_state_hash",
+	))
+
+	sm1 := sfx.NewStateMachine()
+	defer sm1.Wait()
+	defer cancel1()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	vrv := sfx.EmptyVRV(1, 0)
+	ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+	vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+		string(ph1.Header.Hash): {1, 2, 3},
+	})
+
+	_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	finReq := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+	finReq.Resp <- tmdriver.FinalizeBlockResponse{
+		Height: 1, Round: 0,
+		BlockHash: ph1.Header.Hash,
+
+		Validators: sfx.Fx.Vals(),
+
+		AppStateHash: []byte("app_state_1"),
+	}
+
+	// Wait for the finalization to be saved while still in commit wait.
+	require.Eventually(t, func() bool {
+		_, _, _, _, err := sfx.Cfg.FinalizationStore.LoadFinalizationByHeight(ctx, 1)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	sfx.RoundTimer.RequireActiveCommitWaitTimer(t, 1, 0)
+
+	// The process stops before the commit wait timer elapses.
+	cancel1()
+	sm1.Wait()
+
+	// Start a new state machine with the same stores.
+	// The round timer belongs to the stopped process, so it is replaced too.
+	cfg := sfx.Cfg
+	cfg.RoundTimer = new(tmstatetest.MockRoundTimer)
+	wd, wCtx := gwatchdog.NewNopWatchdog(ctx, sfx.Log.With("sys", "watchdog2"))
+	cfg.Watchdog = wd
+	sm2, err := tmstate.NewStateMachine(wCtx, sfx.Log, cfg)
+	require.NoError(t, err)
+	defer sm2.Wait()
+	defer cancel()
+
+	// The stored finalization for height 1 is recognized,
+	// so the new state machine resumes at the next height
+	// instead of re-entering commit wait at height 1.
+	re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+	require.Equal(t, uint64(2), re.H)
+	require.Zero(t, re.R)
+
+	_ = sfx.CStrat.ExpectEnterRound(2, 0, nil)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(2, 0)}
+
+	// And the driver is not asked to finalize height 1 a second time.
+	gtest.NotSendingSoon(t, sfx.FinalizeBlockRequests)
+}
+
+func TestStateMachine_proposalPrepareNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expected proposer in initial round", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		// Validator 1 is the expected proposer at 1/0.
+		sfx.Cfg.Signer = tmconsensus.PassthroughSigner{
+			Signer:          sfx.Fx.PrivVals[1].Signer,
+			SignatureScheme: sfx.Fx.SignatureScheme,
+		}
+		ppCh := make(chan tmelink.ProposalPrepare, 1)
+		sfx.Cfg.ProposalPrepareOut = ppCh
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		// The notification arrives before the consensus strategy enters the round.
+		enterCh := sfx.CStrat.ExpectEnterRound(1, 0, nil)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 0)}
+
+		pp := gtest.ReceiveSoon(t, ppCh)
+		require.Equal(t, tmelink.ProposalPrepare{Height: 1, Round: 0}, pp)
+		_ = gtest.ReceiveSoon(t, enterCh)
+
+		gtest.NotSending(t, ppCh)
+	})
+
+	t.Run("only rounds where this node is the expected proposer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		// Validator 2 is the expected proposer at 1/1, but not at 1/0.
+		sfx.Cfg.Signer = tmconsensus.PassthroughSigner{
+			Signer:          sfx.Fx.PrivVals[2].Signer,
+			SignatureScheme: sfx.Fx.SignatureScheme,
+		}
+		ppCh := make(chan tmelink.ProposalPrepare, 1)
+		sfx.Cfg.ProposalPrepareOut = ppCh
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		enterCh := sfx.CStrat.ExpectEnterRound(1, 0, nil)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 0)}
+		_ = gtest.ReceiveSoon(t, enterCh)
+
+		gtest.NotSending(t, ppCh)
+
+		// Everyone precommits nil, so the state machine advances to round 1.
+		vrv := sfx.Fx.UpdateVRVPrecommits(ctx, sfx.EmptyVRV(1, 0), map[string][]int{
+			"": {0, 1, 2, 3},
+		})
+		vrv.Version++
+		gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+		re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.Equal(t, uint32(1), re.R)
+		enterCh = sfx.CStrat.ExpectEnterRound(1, 1, nil)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 1)}
+
+		pp := gtest.ReceiveSoon(t, ppCh)
+		require.Equal(t, tmelink.ProposalPrepare{Height: 1, Round: 1}, pp)
+		_ = gtest.ReceiveSoon(t, enterCh)
+	})
+
+	t.Run("sent when the previous height commits", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		// Validator 2 is the expected proposer at 2/0, but not at 1/0.
+		sfx.Cfg.Signer = tmconsensus.PassthroughSigner{
+			Signer:          sfx.Fx.PrivVals[2].Signer,
+			SignatureScheme: sfx.Fx.SignatureScheme,
+		}
+		ppCh := make(chan tmelink.ProposalPrepare, 1)
+		sfx.Cfg.ProposalPrepareOut = ppCh
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		vrv := sfx.EmptyVRV(1, 0)
+		ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+		vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+		vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+			string(ph1.Header.Hash): {0, 1, 3},
+		})
+
+		_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+		// Height 1 is committing, so the notification for 2/0 arrives
+		// before the finalization and the end of commit wait.
+		finReq := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+		pp := gtest.ReceiveSoon(t, ppCh)
+		require.Equal(t, tmelink.ProposalPrepare{Height: 2, Round: 0}, pp)
+
+		finReq.Resp <- tmdriver.FinalizeBlockResponse{
+			Height: 1, Round: 0,
+			BlockHash: ph1.Header.Hash,
+
+			Validators: sfx.Fx.Vals(),
+
+			AppStateHash: []byte("app_state_1"),
+		}
+		require.NoError(t, sfx.RoundTimer.ElapseCommitWaitTimer(1, 0))
+
+		re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.Equal(t, uint64(2), re.H)
+		enterCh := sfx.CStrat.ExpectEnterRound(2, 0, nil)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(2, 0)}
+		_ = gtest.ReceiveSoon(t, enterCh)
+
+		// Entering the round does not repeat the notification.
+		gtest.NotSending(t, ppCh)
+	})
+}
+
+func TestStateMachine_maxRoundsPerHeight(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+	sfx.Cfg.MaxRoundsPerHeight = 2
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	// Round 0 has already failed with everyone precommitting nil.
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+	_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+	vrv := sfx.Fx.UpdateVRVPrecommits(ctx, sfx.EmptyVRV(1, 0), map[string][]int{
+		"": {0, 1, 2, 3},
+	})
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	// Round 1 is still within the limit.
+	ercCh := sfx.CStrat.ExpectEnterRound(1, 1, nil)
+	re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+	require.Equal(t, uint64(1), re.H)
+	require.Equal(t, uint32(1), re.R)
+
+	// But once round 1 fails too, the state machine halts instead of entering round 2.
+	vrv = sfx.Fx.UpdateVRVPrecommits(ctx, sfx.EmptyVRV(1, 1), map[string][]int{
+		"": {0, 1, 2, 3},
+	})
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+	_ = gtest.ReceiveSoon(t, ercCh)
+
+	_ = gtest.ReceiveSoon(t, sfx.WatchdogCtx.Done())
+	require.True(t, gwatchdog.IsTermination(sfx.WatchdogCtx))
+
+	var ft gwatchdog.ForcedTerminationError
+	require.ErrorAs(t, context.Cause(sfx.WatchdogCtx), &ft)
+	require.Contains(t, ft.Reason, "maximum of 2 rounds at height 1")
+
+	gtest.NotSendingSoon(t, sfx.RoundEntranceOutCh)
+}
+
+func TestStateMachine_emptyValidatorSetHalts(t *testing.T) {
+	t.Parallel()
+
+	requireHalted := func(t *testing.T, sfx *tmstatetest.Fixture, hr string) {
+		t.Helper()
+
+		_ = gtest.ReceiveSoon(t, sfx.WatchdogCtx.Done())
+		require.True(t, gwatchdog.IsTermination(sfx.WatchdogCtx))
+
+		var ft gwatchdog.ForcedTerminationError
+		require.ErrorAs(t, context.Cause(sfx.WatchdogCtx), &ft)
+		require.Contains(t, ft.Reason, "empty validator set when entering round "+hr)
+
+		gtest.NotSendingSoon(t, sfx.RoundEntranceOutCh)
+	}
+
+	t.Run("initial round entrance", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		enterCh := sfx.CStrat.ExpectEnterRound(1, 0, nil)
+		vrv := sfx.EmptyVRV(1, 0)
+		vrv.ValidatorSet = tmconsensus.ValidatorSet{}
+		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+		requireHalted(t, sfx, "1/0")
+
+		// The consensus strategy was never entered.
+		gtest.NotSending(t, enterCh)
+	})
+
+	t.Run("advancing round", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		// Round 0 has already failed with everyone precommitting nil.
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+		vrv := sfx.Fx.UpdateVRVPrecommits(ctx, sfx.EmptyVRV(1, 0), map[string][]int{
+			"": {0, 1, 2, 3},
+		})
+		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+		re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.Equal(t, uint32(1), re.R)
+		vrv = sfx.EmptyVRV(1, 1)
+		vrv.ValidatorSet = tmconsensus.ValidatorSet{}
+		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+		requireHalted(t, sfx, "1/1")
+	})
+}
+
+func TestStateMachine_selfEquivocationGuard(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+	sfx.Cfg.HaltOnSelfEquivocation = true
+
+	// Another process using our key already prevoted for a block at 1/0,
+	// and recorded it in the shared action store.
+	otherVT := tmconsensus.VoteTarget{Height: 1, Round: 0, BlockHash: "other_block"}
+	require.NoError(t, sfx.Cfg.ActionStore.SavePrevoteAction(
+		ctx,
+		sfx.Fx.ValidatorPubKey(0),
+		otherVT,
+		sfx.Fx.PrevoteSignature(ctx, otherVT, 0),
+	))
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+	_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+	proposalStarted := sfx.RoundTimer.ProposalStartNotification(1, 0)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 0)}
+	_ = gtest.ReceiveSoon(t, proposalStarted)
+
+	// The proposal timer elapses and the strategy chooses to prevote nil,
+	// which conflicts with the recorded prevote.
+	require.NoError(t, sfx.RoundTimer.ElapseProposalTimer(1, 0))
+	cReq := gtest.ReceiveSoon(t, sfx.CStrat.ChooseProposedBlockRequests)
+	gtest.SendSoon(t, cReq.ChoiceHash, "")
+
+	_ = gtest.ReceiveSoon(t, sfx.WatchdogCtx.Done())
+	require.True(t, gwatchdog.IsTermination(sfx.WatchdogCtx))
+
+	var ft gwatchdog.ForcedTerminationError
+	require.ErrorAs(t, context.Cause(sfx.WatchdogCtx), &ft)
+	require.Contains(t, ft.Reason, "self-equivocation detected")
+
+	// And no conflicting prevote was sent to the mirror.
+	gtest.NotSendingSoon(t, re.Actions)
+}
+
+func TestStateMachine_watchdogFinalizationFlush(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		timeout time.Duration
+	}{
+		{name: "enabled", timeout: time.Second},
+		{name: "disabled", timeout: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sfx := tmstatetest.NewFixture(ctx, t, 4)
+			sfx.Cfg.WatchdogFinalizationFlushTimeout = tc.timeout
+
+			// The first save blocks until the watchdog terminates the state machine,
+			// simulating a slow write in progress during termination.
+			fStore := &stallingFinalizationStore{
+				FinalizationStore: sfx.Cfg.FinalizationStore,
+				saving:            make(chan struct{}),
+			}
+			sfx.Cfg.FinalizationStore = fStore
+
+			sm := sfx.NewStateMachine()
+			defer sm.Wait()
+			defer cancel()
+
+			re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+			vrv := sfx.EmptyVRV(1, 0)
+			ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+			vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+			vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+				string(ph1.Header.Hash): {1, 2, 3},
+			})
+
+			_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+			re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+			finReq := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+
+			// The driver has finalized the block and responded.
+			finReq.Resp <- tmdriver.FinalizeBlockResponse{
+				Height: 1, Round: 0,
+				BlockHash: ph1.Header.Hash,
+
+				Validators: sfx.Fx.Vals(),
+
+				AppStateHash: []byte("app_state_1"),
+			}
+
+			// Terminate while the state machine is saving the finalization.
+			_ = gtest.ReceiveSoon(t, fStore.saving)
+			sfx.Cfg.Watchdog.Terminate("test termination during finalization")
+			sm.Wait()
+
+			_, blockHash, _, appStateHash, err := sfx.Cfg.FinalizationStore.LoadFinalizationByHeight(ctx, 1)
+			if tc.timeout == 0 {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, string(ph1.Header.Hash), blockHash)
+			require.Equal(t, "app_state_1", appStateHash)
+		})
+	}
+}
+
+func TestStateMachine_watchdogHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+	// Use a real watchdog with a fast heartbeat,
+	// instead of the fixture's nop watchdog.
+	wd, wCtx := gwatchdog.NewWatchdog(ctx, sfx.Log.With("sys", "watchdog2"))
+	t.Cleanup(wd.Wait)
+	sfx.Cfg.Watchdog = wd
+	sfx.Cfg.WatchdogHeartbeat = tmeil.WatchdogHeartbeat{
+		Interval:        20 * time.Millisecond,
+		Jitter:          5 * time.Millisecond,
+		ResponseTimeout: 50 * time.Millisecond,
+	}
+
+	// Saving the finalization blocks the state machine's main loop
+	// until the watchdog cancels its context.
+	fStore := &stallingFinalizationStore{
+		FinalizationStore: sfx.Cfg.FinalizationStore,
+		saving:            make(chan struct{}),
+	}
+	sfx.Cfg.FinalizationStore = fStore
+
+	sm, err := tmstate.NewStateMachine(wCtx, sfx.Log, sfx.Cfg)
+	require.NoError(t, err)
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	// While the main loop is responsive, several heartbeats pass without termination.
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, wCtx.Err())
+
+	vrv := sfx.EmptyVRV(1, 0)
+	ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph1}
+	vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+		string(ph1.Header.Hash): {1, 2, 3},
+	})
+
+	_ = sfx.CStrat.ExpectEnterRound(1, 0, nil)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	finReq := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+	finReq.Resp <- tmdriver.FinalizeBlockResponse{
+		Height: 1, Round: 0,
+		BlockHash: ph1.Header.Hash,
+
+		Validators: sfx.Fx.Vals(),
+
+		AppStateHash: []byte("app_state_1"),
+	}
+
+	// Now the main loop is stalled, so the watchdog terminates it.
+	_ = gtest.ReceiveSoon(t, fStore.saving)
+	_ = gtest.ReceiveSoon(t, wCtx.Done())
+	require.True(t, gwatchdog.IsTermination(wCtx))
+
+	var ftr gwatchdog.FailureToRespondError
+	require.ErrorAs(t, context.Cause(wCtx), &ftr)
+	require.Equal(t, "StateMachine", ftr.SubsystemName)
+}
+
+func TestStateMachine_proposalAnnotator(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 2)
+
+	type annotatorCall struct {
+		H uint64
+		R uint32
+	}
+	var calls []annotatorCall
+	sfx.Cfg.ProposalAnnotator = func(h uint64, r uint32) ([]byte, []byte, error) {
+		calls = append(calls, annotatorCall{H: h, R: r})
+		return []byte("proposal_ann"), []byte("block_ann"), nil
+	}
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	enterCh := sfx.CStrat.ExpectEnterRound(1, 0, nil)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 0)}
+	erc := gtest.ReceiveSoon(t, enterCh)
+
+	gtest.SendSoon(t, erc.ProposalOut, tmconsensus.Proposal{
+		DataID: "foobar",
+
+		ProposalAnnotations: tmconsensus.Annotations{User: []byte("strategy_proposal_ann")},
+	})
+
+	action := gtest.ReceiveSoon(t, re.Actions)
+
+	// The annotator's values are set as driver annotations,
+	// without disturbing the strategy's user annotations.
+	require.Equal(t, []byte("proposal_ann"), action.PH.Annotations.Driver)
+	require.Equal(t, []byte("strategy_proposal_ann"), action.PH.Annotations.User)
+	require.Equal(t, []byte("block_ann"), action.PH.Header.Annotations.Driver)
+	require.Equal(t, []annotatorCall{{H: 1, R: 0}}, calls)
+
+	// The header hash and proposal signature both account for the annotations.
+	expPH := sfx.Fx.NextProposedHeader([]byte("foobar"), 0)
+	expPH.Header.Annotations.Driver = []byte("block_ann")
+	sfx.Fx.RecalculateHash(&expPH.Header)
+	expPH.Annotations = tmconsensus.Annotations{
+		User:   []byte("strategy_proposal_ann"),
+		Driver: []byte("proposal_ann"),
+	}
+	sfx.Fx.SignProposal(ctx, &expPH, 0)
+	require.Equal(t, expPH, action.PH)
+}
+
+func TestStateMachine_maxAnnotationBytes(t *testing.T) {
+	t.Parallel()
+
+	// The annotator and the strategy contribute 12+9+8 = 29 bytes of annotations.
+	for _, tc := range []struct {
+		name     string
+		limit    int
+		proposes bool
+	}{
+		{name: "at limit", limit: 29, proposes: true},
+		{name: "over limit", limit: 28, proposes: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sfx := tmstatetest.NewFixture(ctx, t, 2)
+			sfx.Cfg.MaxAnnotationBytes = tc.limit
+			sfx.Cfg.ProposalAnnotator = func(uint64, uint32) ([]byte, []byte, error) {
+				return []byte("proposal_ann"), []byte("block_ann"), nil
+			}
+
+			sm := sfx.NewStateMachine()
+			defer sm.Wait()
+			defer cancel()
+
+			re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+			enterCh := sfx.CStrat.ExpectEnterRound(1, 0, nil)
+			re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 0)}
+			erc := gtest.ReceiveSoon(t, enterCh)
+
+			gtest.SendSoon(t, erc.ProposalOut, tmconsensus.Proposal{
+				DataID: "foobar",
+
+				BlockAnnotations: tmconsensus.Annotations{User: []byte("user_ann")},
+			})
+
+			if tc.proposes {
+				action := gtest.ReceiveSoon(t, re.Actions)
+				require.Equal(t, tc.limit, action.PH.AnnotationBytes())
+				return
+			}
+
+			// The oversized header is neither sent nor recorded.
+			gtest.NotSendingSoon(t, re.Actions)
+			_, err := sfx.Cfg.ActionStore.LoadActions(ctx, 1, 0)
+			require.ErrorIs(t, err, tmconsensus.RoundUnknownError{WantHeight: 1, WantRound: 0})
+		})
+	}
+}
+
+func TestStateMachine_enterRoundObserver(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+	sfx.Cfg.Signer = nil
+
+	// The observer blocks until released,
+	// which must not hold up the state machine.
+	release := make(chan struct{})
+	observed := make(chan tmconsensus.RoundView, 2)
+	sfx.Cfg.EnterRoundObserver = func(rv tmconsensus.RoundView) {
+		<-release
+		observed <- rv
+	}
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	// Majority nil precommits in the initial round,
+	// so the state machine advances to the next round immediately.
+	vrv := sfx.EmptyVRV(1, 0)
+	vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+		"": {0, 1, 2},
+	})
+
+	enterCh := sfx.CStrat.ExpectEnterRound(1, 0, nil)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	erc10 := gtest.ReceiveSoon(t, enterCh)
+
+	re11 := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+	require.Equal(t, uint64(1), re11.H)
+	require.Equal(t, uint32(1), re11.R)
+
+	enterCh = sfx.CStrat.ExpectEnterRound(1, 1, nil)
+	re11.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 1)}
+
+	erc11 := gtest.ReceiveSoon(t, enterCh)
+	require.Equal(t, uint32(1), erc11.RV.Round)
+
+	// Both round views are delivered in order once the observer is released.
+	close(release)
+	rv10 := gtest.ReceiveSoon(t, observed)
+	require.Equal(t, erc10.RV, rv10)
+	require.Equal(t, erc11.RV, gtest.ReceiveSoon(t, observed))
+
+	// The observer receives its own copy of the round view.
+	require.NotEmpty(t, rv10.PrecommitProofs)
+	delete(rv10.PrecommitProofs, "")
+	require.Contains(t, erc10.RV.PrecommitProofs, "")
+}
+
+func TestStateMachine_initialValidatorSetProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("provided set is used for the first round entrance", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 2)
+
+		// The genesis validators do not include our signer,
+		// but the provided set does.
+		withoutSigner, err := tmconsensus.NewValidatorSet(sfx.Fx.Vals()[1:], sfx.Fx.HashScheme)
+		require.NoError(t, err)
+		sfx.Cfg.Genesis.ValidatorSet = withoutSigner
+		sfx.Cfg.InitialValidatorSetProvider = func(context.Context) (tmconsensus.ValidatorSet, uint64, error) {
+			return sfx.Fx.ValSet(), 10, nil
+		}
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.Equal(t, uint64(10), re.H)
+		require.Zero(t, re.R)
+
+		// We are participating, according to the provided set.
+		require.NotNil(t, re.Actions)
+	})
+
+	t.Run("provided set without our signer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 2)
+
+		withoutSigner, err := tmconsensus.NewValidatorSet(sfx.Fx.Vals()[1:], sfx.Fx.HashScheme)
+		require.NoError(t, err)
+		sfx.Cfg.InitialValidatorSetProvider = func(context.Context) (tmconsensus.ValidatorSet, uint64, error) {
+			return withoutSigner, 10, nil
+		}
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.Equal(t, uint64(10), re.H)
+
+		// Our signer is in the genesis set but not the provided set,
+		// so we are not participating.
+		require.Nil(t, re.Actions)
+	})
+
+	t.Run("provider not consulted with stored progress", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 2)
+
+		require.NoError(t, sfx.Cfg.StateMachineStore.SetStateMachineHeightRound(ctx, 3, 0))
+		require.NoError(t, sfx.Cfg.FinalizationStore.SaveFinalization(
+			ctx,
+			1, 0,
+			"some_block_hash",
+			sfx.Fx.ValSet(),
+			"some_app_state_hash",
+		))
+
+		var called bool
+		sfx.Cfg.InitialValidatorSetProvider = func(context.Context) (tmconsensus.ValidatorSet, uint64, error) {
+			called = true
+			return sfx.Fx.ValSet(), 10, nil
+		}
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.Equal(t, uint64(3), re.H)
+		require.False(t, called)
+	})
+}
+
+func TestStateMachine_previousRoundProposer(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 2)
+
+	cStrat := &reproposingStrategy{
+		MockConsensusStrategy: sfx.CStrat,
+		calls:                 make(chan reproposalCall, 1),
+		dataID:                "app_data_1",
+	}
+	sfx.Cfg.ConsensusStrategy = cStrat
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	// The strategy is not consulted for round zero,
+	// so it proposes through the proposal channel as usual.
+	enterCh := cStrat.ExpectEnterRound(1, 0, nil)
+	vrv := sfx.EmptyVRV(1, 0)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+	erc := gtest.ReceiveSoon(t, enterCh)
+	require.NotNil(t, erc.ProposalOut)
+
+	gtest.SendSoon(t, erc.ProposalOut, tmconsensus.Proposal{DataID: "app_data_1"})
+
+	action := gtest.ReceiveSoon(t, re.Actions)
+	require.Equal(t, tmconsensus.DataID("app_data_1"), action.PH.Header.DataID)
+	require.Zero(t, action.PH.Round)
+
+	// Everyone precommits nil, so the round fails.
+	enterCh = cStrat.ExpectEnterRound(1, 1, nil)
+	vrv = sfx.Fx.UpdateVRVPrecommits(ctx, vrv, map[string][]int{
+		"": {0, 1},
+	})
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 1)}
+
+	// The strategy is asked about the failed round before entering the new one.
+	call := gtest.ReceiveSoon(t, cStrat.calls)
+	require.Equal(t, reproposalCall{Height: 1, PrevRound: 0}, call)
+
+	// Since the strategy chose to re-propose,
+	// EnterRound does not receive a proposal channel.
+	erc = gtest.ReceiveSoon(t, enterCh)
+	require.Equal(t, uint32(1), erc.RV.Round)
+	require.Nil(t, erc.ProposalOut)
+
+	// And the same data ID is proposed in round 1.
+	action = gtest.ReceiveSoon(t, re.Actions)
+	require.Equal(t, tmconsensus.DataID("app_data_1"), action.PH.Header.DataID)
+	require.Equal(t, uint32(1), action.PH.Round)
+}
+
+func TestStateMachine_proposalDelay(t *testing.T) {
+	t.Run("proposal action is delayed by requested duration", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 2)
+
+		cStrat := &proposalDelayingStrategy{
+			MockConsensusStrategy: sfx.CStrat,
+			delay:                 250 * time.Millisecond,
+		}
+		sfx.Cfg.ConsensusStrategy = cStrat
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		enterCh := cStrat.ExpectEnterRound(1, 0, nil)
+		delayStarted := sfx.RoundTimer.ProposalDelayStartNotification(1, 0)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 0)}
+		erc := gtest.ReceiveSoon(t, enterCh)
+
+		// The delay timer runs alongside the proposal timer,
+		// for the duration the strategy requested.
+		_ = gtest.ReceiveSoon(t, delayStarted)
+		sfx.RoundTimer.RequireActiveProposalTimer(t, 1, 0)
+		d, ok := sfx.RoundTimer.ActiveProposalDelay(1, 0)
+		require.True(t, ok)
+		require.Equal(t, 250*time.Millisecond, d)
+
+		// The strategy proposes immediately,
+		// but the state machine holds the proposal until the delay elapses.
+		gtest.SendSoon(t, erc.ProposalOut, tmconsensus.Proposal{DataID: "app_data"})
+		gtest.NotSendingSoon(t, re.Actions)
+
+		require.NoError(t, sfx.RoundTimer.ElapseProposalDelayTimer(1, 0))
+
+		action := gtest.ReceiveSoon(t, re.Actions)
+		require.Equal(t, tmconsensus.DataID("app_data"), action.PH.Header.DataID)
+	})
+
+	t.Run("delay is bounded by proposal timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 2)
+
+		cStrat := &proposalDelayingStrategy{
+			MockConsensusStrategy: sfx.CStrat,
+			delay:                 time.Hour,
+		}
+		sfx.Cfg.ConsensusStrategy = cStrat
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		enterCh := cStrat.ExpectEnterRound(1, 0, nil)
+		delayStarted := sfx.RoundTimer.ProposalDelayStartNotification(1, 0)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 0)}
+		erc := gtest.ReceiveSoon(t, enterCh)
+		_ = gtest.ReceiveSoon(t, delayStarted)
+
+		gtest.SendSoon(t, erc.ProposalOut, tmconsensus.Proposal{DataID: "app_data"})
+		gtest.NotSendingSoon(t, re.Actions)
+
+		// Once the proposal timer elapses, the state machine chooses a proposed block
+		// and no longer withholds the proposal.
+		require.NoError(t, sfx.RoundTimer.ElapseProposalTimer(1, 0))
+		_ = gtest.ReceiveSoon(t, sfx.CStrat.ChooseProposedBlockRequests)
+
+		action := gtest.ReceiveSoon(t, re.Actions)
+		require.Equal(t, tmconsensus.DataID("app_data"), action.PH.Header.DataID)
+
+		// And the delay timer was cancelled.
+		_, ok := sfx.RoundTimer.ActiveProposalDelay(1, 0)
+		require.False(t, ok)
+	})
+
+	t.Run("strategy without ProposalDelayer is not delayed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 2)
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		enterCh := sfx.CStrat.ExpectEnterRound(1, 0, nil)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: sfx.EmptyVRV(1, 0)}
+		erc := gtest.ReceiveSoon(t, enterCh)
+
+		gtest.SendSoon(t, erc.ProposalOut, tmconsensus.Proposal{DataID: "app_data"})
+
+		action := gtest.ReceiveSoon(t, re.Actions)
+		require.Equal(t, tmconsensus.DataID("app_data"), action.PH.Header.DataID)
+
+		_, ok := sfx.RoundTimer.ActiveProposalDelay(1, 0)
+		require.False(t, ok)
+	})
+}
+
+func TestStateMachine_minPeersBeforeVoting(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+	peerCounts := make(chan int, 1)
+	sfx.Cfg.MinPeersBeforeVoting = 2
+	sfx.Cfg.PeerCountCh = peerCounts
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	cStrat := sfx.CStrat
+	_ = cStrat.ExpectEnterRound(1, 0, nil)
+
+	// Channel is 1-buffered, don't have to select.
+	vrv := sfx.EmptyVRV(1, 0)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	ph := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+	sfx.Fx.SignProposal(ctx, &ph, 1)
+	vrv = vrv.Clone()
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph}
+	vrv.Version++
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	considerReq := gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+	gtest.SendSoon(t, considerReq.ChoiceHash, string(ph.Header.Hash))
+
+	// The prevote is signed and saved, but not sent to the mirror.
+	gtest.NotSendingSoon(t, re.Actions)
+	ra, err := sfx.Cfg.ActionStore.LoadActions(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, string(ph.Header.Hash), ra.PrevoteTarget)
+
+	// Too few peers.
+	gtest.SendSoon(t, peerCounts, 1)
+	gtest.NotSendingSoon(t, re.Actions)
+
+	// Without a timeout, there is no peer wait timer.
+	_, ok := sfx.RoundTimer.ActivePeerWait(1, 0)
+	require.False(t, ok)
+
+	// Once enough peers are connected, the held prevote is sent.
+	gtest.SendSoon(t, peerCounts, 2)
+	prevote := gtest.ReceiveSoon(t, re.Actions).Prevote
+	require.Equal(t, string(ph.Header.Hash), prevote.TargetHash)
+
+	// Later votes are sent immediately.
+	vrv = sfx.Fx.UpdateVRVPrevotes(ctx, vrv, map[string][]int{
+		string(ph.Header.Hash): {0, 1, 2},
+	})
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	precommitReq := gtest.ReceiveSoon(t, cStrat.DecidePrecommitRequests)
+	gtest.SendSoon(t, precommitReq.ChoiceHash, string(ph.Header.Hash))
+	precommit := gtest.ReceiveSoon(t, re.Actions).Precommit
+	require.Equal(t, string(ph.Header.Hash), precommit.TargetHash)
+}
+
+func TestStateMachine_minPeersBeforeVoting_timeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+	peerCounts := make(chan int, 1)
+	sfx.Cfg.MinPeersBeforeVoting = 2
+	sfx.Cfg.MinPeersTimeout = 45 * time.Second
+	sfx.Cfg.PeerCountCh = peerCounts
+
+	waitStarted := sfx.RoundTimer.PeerWaitStartNotification(1, 0)
+
+	sm := sfx.NewStateMachine()
+	defer sm.Wait()
+	defer cancel()
+
+	re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+	cStrat := sfx.CStrat
+	_ = cStrat.ExpectEnterRound(1, 0, nil)
+
+	// Channel is 1-buffered, don't have to select.
+	vrv := sfx.EmptyVRV(1, 0)
+	re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+	// The timeout starts once the initial round is known.
+	_ = gtest.ReceiveSoon(t, waitStarted)
+	d, ok := sfx.RoundTimer.ActivePeerWait(1, 0)
+	require.True(t, ok)
+	require.Equal(t, 45*time.Second, d)
+
+	ph := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+	sfx.Fx.SignProposal(ctx, &ph, 1)
+	vrv = vrv.Clone()
+	vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph}
+	vrv.Version++
+	gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+	considerReq := gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+	gtest.SendSoon(t, considerReq.ChoiceHash, string(ph.Header.Hash))
+
+	// Too few peers, so the prevote is held.
+	gtest.SendSoon(t, peerCounts, 1)
+	gtest.NotSendingSoon(t, re.Actions)
+
+	// When the wait times out, the held prevote is sent anyway.
+	require.NoError(t, sfx.RoundTimer.ElapsePeerWaitTimer(1, 0))
+	prevote := gtest.ReceiveSoon(t, re.Actions).Prevote
+	require.Equal(t, string(ph.Header.Hash), prevote.TargetHash)
+}
+
+func TestStateMachine_resourceGuard(t *testing.T) {
+	t.Parallel()
+
+	t.Run("votes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		var unsafe atomic.Bool
+		unsafe.Store(true)
+		sfx.Cfg.ResourceGuard = func() (bool, string) {
+			if unsafe.Load() {
+				return false, "disk almost full"
+			}
+			return true, ""
+		}
+		sfx.Cfg.ResourceGuardPollInterval = 5 * time.Second
+		alerts := make(chan tmelink.ResourceGuardAlert, 1)
+		sfx.Cfg.ResourceGuardAlertOut = alerts
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		cStrat := sfx.CStrat
+		_ = cStrat.ExpectEnterRound(1, 0, nil)
+
+		// Channel is 1-buffered, don't have to select.
+		vrv := sfx.EmptyVRV(1, 0)
+		re.Response <- tmeil.RoundEntranceResponse{VRV: vrv}
+
+		ph := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+		sfx.Fx.SignProposal(ctx, &ph, 1)
+		vrv = vrv.Clone()
+		vrv.ProposedHeaders = []tmconsensus.ProposedHeader{ph}
+		vrv.Version++
+		gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+		pollStarted := sfx.RoundTimer.ResourceGuardPollStartNotification(1, 0)
+
+		considerReq := gtest.ReceiveSoon(t, cStrat.ConsiderProposedBlocksRequests)
+		gtest.SendSoon(t, considerReq.ChoiceHash, string(ph.Header.Hash))
+
+		// The failing guard pauses the prevote before it is signed or saved.
+		alert := gtest.ReceiveSoon(t, alerts)
+		require.Equal(t, tmelink.ResourceGuardAlert{
+			Height: 1, Round: 0,
+			Paused: true,
+			Reason: "disk almost full",
+		}, alert)
+		_ = gtest.ReceiveSoon(t, pollStarted)
+		d, ok := sfx.RoundTimer.ActiveResourceGuardPoll(1, 0)
+		require.True(t, ok)
+		require.Equal(t, 5*time.Second, d)
+
+		gtest.NotSendingSoon(t, re.Actions)
+		_, err := sfx.Cfg.ActionStore.LoadActions(ctx, 1, 0)
+		require.ErrorIs(t, err, tmconsensus.RoundUnknownError{WantHeight: 1, WantRound: 0})
+
+		// The guard keeps being polled while paused, without another alert.
+		pollStarted = sfx.RoundTimer.ResourceGuardPollStartNotification(1, 0)
+		require.NoError(t, sfx.RoundTimer.ElapseResourceGuardPollTimer(1, 0))
+		_ = gtest.ReceiveSoon(t, pollStarted)
+		gtest.NotSending(t, alerts)
+		gtest.NotSending(t, re.Actions)
+
+		// Once the guard recovers, the paused prevote is signed, saved, and sent.
+		unsafe.Store(false)
+		require.NoError(t, sfx.RoundTimer.ElapseResourceGuardPollTimer(1, 0))
+		prevote := gtest.ReceiveSoon(t, re.Actions).Prevote
+		require.Equal(t, string(ph.Header.Hash), prevote.TargetHash)
+
+		alert = gtest.ReceiveSoon(t, alerts)
+		require.Equal(t, tmelink.ResourceGuardAlert{Height: 1, Round: 0}, alert)
+		_, ok = sfx.RoundTimer.ActiveResourceGuardPoll(1, 0)
+		require.False(t, ok)
+
+		ra, err := sfx.Cfg.ActionStore.LoadActions(ctx, 1, 0)
+		require.NoError(t, err)
+		require.Equal(t, string(ph.Header.Hash), ra.PrevoteTarget)
+
+		// Later votes are sent immediately.
+		vrv = sfx.Fx.UpdateVRVPrevotes(ctx, vrv, map[string][]int{
+			string(ph.Header.Hash): {0, 1, 2},
+		})
+		gtest.SendSoon(t, sfx.RoundViewInCh, tmeil.StateMachineRoundView{VRV: vrv})
+
+		precommitReq := gtest.ReceiveSoon(t, cStrat.DecidePrecommitRequests)
+		gtest.SendSoon(t, precommitReq.ChoiceHash, string(ph.Header.Hash))
+		precommit := gtest.ReceiveSoon(t, re.Actions).Precommit
+		require.Equal(t, string(ph.Header.Hash), precommit.TargetHash)
+	})
+
+	t.Run("finalization", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		var unsafe atomic.Bool
+		unsafe.Store(true)
+		sfx.Cfg.ResourceGuard = func() (bool, string) {
+			if unsafe.Load() {
+				return false, "disk almost full"
+			}
+			return true, ""
+		}
+		sfx.Cfg.ResourceGuardPollInterval = time.Second
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+		vt := tmconsensus.VoteTarget{Height: 1, Round: 0, BlockHash: string(ph1.Header.Hash)}
+		sfx.Fx.CommitBlock(ph1.Header, []byte("app_state_1"), 0, map[string]gcrypto.CommonMessageSignatureProof{
+			string(ph1.Header.Hash): sfx.Fx.PrecommitSignatureProof(ctx, vt, nil, []int{1, 2, 3}),
+		})
+
+		ph2 := sfx.Fx.NextProposedHeader([]byte("app_data_2"), 1)
+
+		re.Response <- tmeil.RoundEntranceResponse{
+			CH: tmconsensus.CommittedHeader{
+				Header: ph1.Header,
+				Proof:  ph2.Header.PrevCommitProof,
+			},
+		}
+
+		pollStarted := sfx.RoundTimer.ResourceGuardPollStartNotification(1, 0)
+
+		req := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+		gtest.SendSoon(t, req.Resp, tmdriver.FinalizeBlockResponse{
+			Height: 1, Round: 0,
+			BlockHash: ph1.Header.Hash,
+
+			Validators: sfx.Fx.Vals(),
+
+			AppStateHash: []byte("app_state_1"),
+		})
+
+		// The finalization is held, so it is not saved and the height does not advance.
+		_ = gtest.ReceiveSoon(t, pollStarted)
+		gtest.NotSendingSoon(t, sfx.RoundEntranceOutCh)
+		_, _, _, _, err := sfx.Cfg.FinalizationStore.LoadFinalizationByHeight(ctx, 1)
+		require.Error(t, err)
+
+		// Once the guard recovers, the finalization is saved
+		// and the state machine advances, saving its new height and round.
+		unsafe.Store(false)
+		require.NoError(t, sfx.RoundTimer.ElapseResourceGuardPollTimer(1, 0))
+
+		re = gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+		require.Equal(t, uint64(2), re.H)
+		require.Zero(t, re.R)
+
+		_, _, _, appStateHash, err := sfx.Cfg.FinalizationStore.LoadFinalizationByHeight(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "app_state_1", appStateHash)
+
+		h, r, err := sfx.Cfg.StateMachineStore.StateMachineHeightRound(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), h)
+		require.Zero(t, r)
+	})
+
+	t.Run("finalization then watchdog termination", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sfx := tmstatetest.NewFixture(ctx, t, 4)
+
+		sfx.Cfg.ResourceGuard = func() (bool, string) {
+			return false, "disk almost full"
+		}
+		sfx.Cfg.ResourceGuardPollInterval = time.Second
+		sfx.Cfg.WatchdogFinalizationFlushTimeout = time.Second
+
+		sm := sfx.NewStateMachine()
+		defer sm.Wait()
+		defer cancel()
+
+		re := gtest.ReceiveSoon(t, sfx.RoundEntranceOutCh)
+
+		ph1 := sfx.Fx.NextProposedHeader([]byte("app_data_1"), 1)
+		vt := tmconsensus.VoteTarget{Height: 1, Round: 0, BlockHash: string(ph1.Header.Hash)}
+		sfx.Fx.CommitBlock(ph1.Header, []byte("app_state_1"), 0, map[string]gcrypto.CommonMessageSignatureProof{
+			string(ph1.Header.Hash): sfx.Fx.PrecommitSignatureProof(ctx, vt, nil, []int{1, 2, 3}),
+		})
+
+		ph2 := sfx.Fx.NextProposedHeader([]byte("app_data_2"), 1)
+
+		re.Response <- tmeil.RoundEntranceResponse{
+			CH: tmconsensus.CommittedHeader{
+				Header: ph1.Header,
+				Proof:  ph2.Header.PrevCommitProof,
+			},
+		}
+
+		pollStarted := sfx.RoundTimer.ResourceGuardPollStartNotification(1, 0)
+
+		req := gtest.ReceiveSoon(t, sfx.FinalizeBlockRequests)
+		gtest.SendSoon(t, req.Resp, tmdriver.FinalizeBlockResponse{
+			Height: 1, Round: 0,
+			BlockHash: ph1.Header.Hash,
+
+			Validators: sfx.Fx.Vals(),
+
+			AppStateHash: []byte("app_state_1"),
+		})
+		_ = gtest.ReceiveSoon(t, pollStarted)
+
+		// The watchdog fires while the finalization is held.
+		// Writes are still unsafe, so the flush is skipped
+		// and the held finalization is not saved.
+		sfx.Cfg.Watchdog.Terminate("test termination while resource guard paused")
+		sm.Wait()
+
+		_, _, _, _, err := sfx.Cfg.FinalizationStore.LoadFinalizationByHeight(ctx, 1)
+		require.Error(t, err)
+	})
+}
+
+// stallingFinalizationStore wraps a FinalizationStore
+// such that its first SaveFinalization call
+// blocks until the context is cancelled and then fails.
+type stallingFinalizationStore struct {
+	tmstore.FinalizationStore
+
+	// Closed upon the first call to SaveFinalization.
+	saving chan struct{}
+}
+
+func (s *stallingFinalizationStore) SaveFinalization(
+	ctx context.Context,
+	height uint64, round uint32,
+	blockHash string,
+	valSet tmconsensus.ValidatorSet,
+	appStateHash string,
+) error {
+	select {
+	case <-s.saving:
+		// Already stalled once, so delegate.
+		return s.FinalizationStore.SaveFinalization(ctx, height, round, blockHash, valSet, appStateHash)
+	default:
+	}
+
+	close(s.saving)
+	<-ctx.Done()
+	return context.Cause(ctx)
+}
+
+// roundJumpObservingStrategy wraps a MockConsensusStrategy
+// to additionally implement [tmconsensus.RoundJumpObserver].
+type roundJumpObservingStrategy struct {
+	*tmconsensustest.MockConsensusStrategy
+
+	// Receives the from and to values of each RoundJumped call.
+	jumps chan [2]tmconsensus.RoundPointer
+}
+
+func (s *roundJumpObservingStrategy) RoundJumped(
+	ctx context.Context, from, to tmconsensus.RoundPointer,
+) {
+	select {
+	case <-ctx.Done():
+	case s.jumps <- [2]tmconsensus.RoundPointer{from, to}:
+	}
+}
+
+// proposalDelayingStrategy wraps a MockConsensusStrategy
+// to additionally implement [tmconsensus.ProposalDelayer],
+// always requesting the same delay.
+type proposalDelayingStrategy struct {
+	*tmconsensustest.MockConsensusStrategy
+
+	delay time.Duration
+}
+
+func (s *proposalDelayingStrategy) ProposalDelay(context.Context, uint64, uint32) time.Duration {
+	return s.delay
+}
+
+// reproposalCall holds the arguments of a PreviousRoundProposal call.
+type reproposalCall struct {
+	Height    uint64
+	PrevRound uint32
+}
+
+// reproposingStrategy wraps a MockConsensusStrategy
+// to additionally implement [tmconsensus.PreviousRoundProposer],
+// always choosing to re-propose dataID.
+type reproposingStrategy struct {
+	*tmconsensustest.MockConsensusStrategy
+
+	// Receives the arguments of each PreviousRoundProposal call.
+	calls chan reproposalCall
+
+	dataID string
+}
+
+func (s *reproposingStrategy) PreviousRoundProposal(
+	ctx context.Context, height uint64, prevRound uint32,
+) (bool, string) {
+	select {
+	case <-ctx.Done():
+	case s.calls <- reproposalCall{Height: height, PrevRound: prevRound}:
+	}
+	return true, s.dataID
+}
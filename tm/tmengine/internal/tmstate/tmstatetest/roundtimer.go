@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 )
 
 const (
@@ -12,6 +13,11 @@ const (
 	prevoteDelayTimerName   = "PrevoteDelayTimer"
 	precommitDelayTimerName = "PrecommitDelayTimer"
 	commitWaitTimerName     = "CommitWaitTimer"
+
+	proposalDelayTimerName     = "ProposalDelayTimer"
+	resourceGuardPollTimerName = "ResourceGuardPollTimer"
+	peerWaitTimerName          = "PeerWaitTimer"
+	strategyResponseTimerName  = "StrategyResponseTimer"
 )
 
 type MockRoundTimer struct {
@@ -25,6 +31,14 @@ type MockRoundTimer struct {
 	activeName string
 	activeH    uint64
 	activeR    uint32
+
+	// Timers that may be active alongside the step timer above.
+	independent map[startNotification]independentTimer
+}
+
+type independentTimer struct {
+	ch chan struct{}
+	d  time.Duration
 }
 
 type startNotification struct {
@@ -49,6 +63,30 @@ func (t *MockRoundTimer) CommitWaitTimer(_ context.Context, h uint64, r uint32)
 	return t.makeTimer(commitWaitTimerName, h, r)
 }
 
+func (t *MockRoundTimer) ProposalDelayTimer(
+	_ context.Context, h uint64, r uint32, d time.Duration,
+) (<-chan struct{}, func()) {
+	return t.makeIndependentTimer(proposalDelayTimerName, h, r, d)
+}
+
+func (t *MockRoundTimer) ResourceGuardPollTimer(
+	_ context.Context, h uint64, r uint32, d time.Duration,
+) (<-chan struct{}, func()) {
+	return t.makeIndependentTimer(resourceGuardPollTimerName, h, r, d)
+}
+
+func (t *MockRoundTimer) PeerWaitTimer(
+	_ context.Context, h uint64, r uint32, d time.Duration,
+) (<-chan struct{}, func()) {
+	return t.makeIndependentTimer(peerWaitTimerName, h, r, d)
+}
+
+func (t *MockRoundTimer) StrategyResponseTimer(
+	_ context.Context, h uint64, r uint32, d time.Duration,
+) (<-chan struct{}, func()) {
+	return t.makeIndependentTimer(strategyResponseTimerName, h, r, d)
+}
+
 func (t *MockRoundTimer) makeTimer(name string, h uint64, r uint32) (<-chan struct{}, func()) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -92,6 +130,43 @@ func (t *MockRoundTimer) makeTimer(name string, h uint64, r uint32) (<-chan stru
 	return t.ch, t.cancel
 }
 
+func (t *MockRoundTimer) makeIndependentTimer(
+	name string, h uint64, r uint32, d time.Duration,
+) (<-chan struct{}, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := startNotification{Name: name, H: h, R: r}
+	if _, ok := t.independent[key]; ok {
+		panic(fmt.Errorf(
+			"BUG: cannot create %s at %d/%d before previous timer elapses or is cancelled",
+			name, h, r,
+		))
+	}
+
+	if t.independent == nil {
+		t.independent = make(map[startNotification]independentTimer)
+	}
+
+	ch := make(chan struct{})
+	t.independent[key] = independentTimer{ch: ch, d: d}
+
+	if nch, ok := t.notifications[key]; ok {
+		close(nch)
+		delete(t.notifications, key)
+	}
+
+	return ch, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		// Guard against late/deferred cancel affecting a newer timer.
+		if it, ok := t.independent[key]; ok && it.ch == ch {
+			delete(t.independent, key)
+		}
+	}
+}
+
 func (t *MockRoundTimer) ActiveTimer() (name string, h uint64, r uint32) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -115,6 +190,22 @@ func (t *MockRoundTimer) ElapseCommitWaitTimer(h uint64, r uint32) error {
 	return t.elapse(commitWaitTimerName, h, r)
 }
 
+func (t *MockRoundTimer) ElapseProposalDelayTimer(h uint64, r uint32) error {
+	return t.elapseIndependent(proposalDelayTimerName, h, r)
+}
+
+func (t *MockRoundTimer) ElapseResourceGuardPollTimer(h uint64, r uint32) error {
+	return t.elapseIndependent(resourceGuardPollTimerName, h, r)
+}
+
+func (t *MockRoundTimer) ElapsePeerWaitTimer(h uint64, r uint32) error {
+	return t.elapseIndependent(peerWaitTimerName, h, r)
+}
+
+func (t *MockRoundTimer) ElapseStrategyResponseTimer(h uint64, r uint32) error {
+	return t.elapseIndependent(strategyResponseTimerName, h, r)
+}
+
 func (t *MockRoundTimer) elapse(name string, h uint64, r uint32) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -143,6 +234,62 @@ func (t *MockRoundTimer) elapse(name string, h uint64, r uint32) error {
 	return nil
 }
 
+func (t *MockRoundTimer) elapseIndependent(name string, h uint64, r uint32) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := startNotification{Name: name, H: h, R: r}
+	it, ok := t.independent[key]
+	if !ok {
+		return fmt.Errorf("requested to elapse timer %q at %d/%d, but it is not active", name, h, r)
+	}
+
+	close(it.ch)
+	delete(t.independent, key)
+
+	return nil
+}
+
+// ActiveProposalDelay returns the duration requested for the active proposal delay timer at h/r.
+// The ok result is false if no such timer is active.
+func (t *MockRoundTimer) ActiveProposalDelay(h uint64, r uint32) (d time.Duration, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	it, ok := t.independent[startNotification{Name: proposalDelayTimerName, H: h, R: r}]
+	return it.d, ok
+}
+
+// ActiveResourceGuardPoll returns the duration requested for the active resource guard poll timer at h/r.
+// The ok result is false if no such timer is active.
+func (t *MockRoundTimer) ActiveResourceGuardPoll(h uint64, r uint32) (d time.Duration, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	it, ok := t.independent[startNotification{Name: resourceGuardPollTimerName, H: h, R: r}]
+	return it.d, ok
+}
+
+// ActivePeerWait returns the duration requested for the active peer wait timer at h/r.
+// The ok result is false if no such timer is active.
+func (t *MockRoundTimer) ActivePeerWait(h uint64, r uint32) (d time.Duration, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	it, ok := t.independent[startNotification{Name: peerWaitTimerName, H: h, R: r}]
+	return it.d, ok
+}
+
+// ActiveStrategyResponse returns the duration requested for the active strategy response timer at h/r.
+// The ok result is false if no such timer is active.
+func (t *MockRoundTimer) ActiveStrategyResponse(h uint64, r uint32) (d time.Duration, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	it, ok := t.independent[startNotification{Name: strategyResponseTimerName, H: h, R: r}]
+	return it.d, ok
+}
+
 func (t *MockRoundTimer) ProposalStartNotification(h uint64, r uint32) <-chan struct{} {
 	return t.startNotification(proposalTimerName, h, r)
 }
@@ -159,6 +306,22 @@ func (t *MockRoundTimer) CommitWaitStartNotification(h uint64, r uint32) <-chan
 	return t.startNotification(commitWaitTimerName, h, r)
 }
 
+func (t *MockRoundTimer) ProposalDelayStartNotification(h uint64, r uint32) <-chan struct{} {
+	return t.startNotification(proposalDelayTimerName, h, r)
+}
+
+func (t *MockRoundTimer) ResourceGuardPollStartNotification(h uint64, r uint32) <-chan struct{} {
+	return t.startNotification(resourceGuardPollTimerName, h, r)
+}
+
+func (t *MockRoundTimer) PeerWaitStartNotification(h uint64, r uint32) <-chan struct{} {
+	return t.startNotification(peerWaitTimerName, h, r)
+}
+
+func (t *MockRoundTimer) StrategyResponseStartNotification(h uint64, r uint32) <-chan struct{} {
+	return t.startNotification(strategyResponseTimerName, h, r)
+}
+
 func (t *MockRoundTimer) startNotification(name string, h uint64, r uint32) <-chan struct{} {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -9,3 +9,10 @@ import "github.com/gordian-engine/gordian/tm/tmengine/internal/tmemetrics"
 // The type alias is somewhat unfortunate,
 // but the alternative would be creating yet another package...
 type Metrics = tmemetrics.Metrics
+
+// FilteredProposedHeaderCounts is the type of [Metrics.StateMachineFilteredProposedHeaders].
+type FilteredProposedHeaderCounts = tmemetrics.FilteredProposedHeaderCounts
+
+// DivergenceAlert is sent on the channel set through [WithDivergenceAlert]
+// when the mirror's voting height is too far ahead of the state machine's height.
+type DivergenceAlert = tmemetrics.DivergenceAlert
@@ -26,21 +26,24 @@ type Mirror interface {
 
 func NewMirror(ctx context.Context, log *slog.Logger, opts ...Opt) (Mirror, error) {
 	// We borrow the engine options to configure the mirror,
-	// so we need an Engine value to collect the options.
-	// Note that we never start the Engine we instantiate.
-	var e Engine
+	// only using the mirror's settings from the resulting config.
+	var eCfg EngineConfig
 
 	var err error
 	for _, opt := range opts {
-		err = errors.Join(opt(&e, nil))
+		err = errors.Join(err, opt(&eCfg))
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	cfg := e.mCfg
-	cfg.InitialHeight = e.genesis.InitialHeight
-	cfg.InitialValidatorSet = e.genesis.GenesisValidatorSet
+	if err := eCfg.validateValues(); err != nil {
+		return nil, err
+	}
+
+	cfg := eCfg.mirrorConfig()
+	cfg.InitialHeight = eCfg.Genesis.InitialHeight
+	cfg.InitialValidatorSet = eCfg.Genesis.GenesisValidatorSet
 
 	if err := validateMirrorSettings(cfg); err != nil {
 		return nil, err
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gordian-engine/gordian/gassert"
 	"github.com/gordian-engine/gordian/gcrypto"
@@ -17,15 +18,100 @@ import (
 )
 
 // Opt is an option for the Engine.
+// Each Opt sets fields of an [EngineConfig].
 // The underlying function signature for Opt is subject to change at any time.
 // Only Opt values returned by With* functions may be considered stable values.
-type Opt func(*Engine, *tmstate.StateMachineConfig) error
+type Opt func(*EngineConfig) error
 
 // WithConsensusStrategy sets the engine's consensus strategy.
 // This option is required.
 func WithConsensusStrategy(cs tmconsensus.ConsensusStrategy) Opt {
-	return func(_ *Engine, smc *tmstate.StateMachineConfig) error {
-		smc.ConsensusStrategy = cs
+	return func(cfg *EngineConfig) error {
+		cfg.ConsensusStrategy = cs
+		return nil
+	}
+}
+
+// WithStrategyResponseTimeout sets the maximum duration the engine waits
+// for the consensus strategy to respond to
+// ConsiderProposedBlocks, ChooseProposedBlock, or DecidePrecommit.
+//
+// A ConsiderProposedBlocks call exceeding the timeout is treated
+// as the strategy not yet being ready to choose,
+// and the engine proceeds once its proposal timer elapses.
+// ChooseProposedBlock and DecidePrecommit calls exceeding the timeout
+// result in a nil prevote or nil precommit, respectively.
+// Each timeout is logged and counted in the engine's metrics.
+//
+// Consensus strategies are not required to be safe for concurrent use,
+// so after a timeout, the engine does not call the strategy again
+// until the timed out call returns.
+// Waiting for that call counts against the timeout of the next call,
+// except for EnterRound, which is never skipped.
+//
+// This option is not required.
+// If omitted or set to zero, the engine waits indefinitely for strategy responses.
+func WithStrategyResponseTimeout(d time.Duration) Opt {
+	return func(cfg *EngineConfig) error {
+		if d < 0 {
+			return fmt.Errorf("WithStrategyResponseTimeout: duration must not be negative (got %s)", d)
+		}
+		cfg.StrategyResponseTimeout = d
+		return nil
+	}
+}
+
+// WithDataAvailabilityChecker sets a function that the engine consults
+// before passing proposed headers to the consensus strategy's
+// ConsiderProposedBlocks or ChooseProposedBlock methods.
+// The function is called with the proposed header's DataID,
+// and headers whose data is not available are withheld from the strategy,
+// so that the strategy never prevotes for a block whose data cannot be retrieved.
+//
+// Each call to fn is bounded by timeout, which must be positive.
+// A call that returns an error or exceeds the timeout
+// is logged and treated as unavailable.
+// The checks run outside the engine's state machine goroutine,
+// but they do delay the strategy calls.
+//
+// This option is not required.
+// If omitted, all proposed headers are passed to the strategy.
+func WithDataAvailabilityChecker(
+	fn func(ctx context.Context, dataID string) (available bool, err error),
+	timeout time.Duration,
+) Opt {
+	return func(cfg *EngineConfig) error {
+		if timeout <= 0 {
+			return fmt.Errorf("WithDataAvailabilityChecker: timeout must be positive (got %s)", timeout)
+		}
+		cfg.DataAvailabilityChecker = fn
+		cfg.DataAvailabilityTimeout = timeout
+		return nil
+	}
+}
+
+// WithBlockDataAvailabilityFunc sets a function that the engine calls
+// to pull block data availability from the driver,
+// as an alternative to pushing arrivals through [WithBlockDataArrivalChannel].
+// The function is called with the height, round, and DataID of each proposed header
+// before the header is passed to the consensus strategy's
+// ConsiderProposedBlocks or ChooseProposedBlock methods,
+// and headers whose data is not available are withheld from the strategy.
+//
+// Withheld headers are checked again whenever the engine next considers the round's headers,
+// such as when the round view changes or when the proposal timer elapses.
+// Drivers may still use a block data arrival channel
+// to prompt the engine to query fn again as soon as data arrives.
+//
+// This option may not be combined with [WithDataAvailabilityChecker].
+//
+// This option is not required.
+// If omitted, all proposed headers are passed to the strategy.
+func WithBlockDataAvailabilityFunc(
+	fn func(ctx context.Context, height uint64, round uint32, dataID string) (available bool),
+) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.BlockDataAvailabilityFunc = fn
 		return nil
 	}
 }
@@ -33,8 +119,91 @@ func WithConsensusStrategy(cs tmconsensus.ConsensusStrategy) Opt {
 // WithGossipStrategy sets the engine's gossip strategy.
 // This option is required.
 func WithGossipStrategy(gs tmgossip.Strategy) Opt {
-	return func(e *Engine, _ *tmstate.StateMachineConfig) error {
-		e.gs = gs
+	return func(cfg *EngineConfig) error {
+		cfg.GossipStrategy = gs
+		return nil
+	}
+}
+
+// WithMinPeersBeforeVoting delays the engine's first votes after startup
+// until the gossip strategy reports at least n connected peers,
+// or until timeout has elapsed, whichever comes first.
+// The timeout must be positive; 30 seconds is a reasonable default.
+// Votes sent before the node is connected to any peers would be wasted.
+//
+// Votes are still signed and saved to the action store while they are held.
+// Once the wait ends, held votes for the current round are sent,
+// and held votes for earlier rounds are discarded.
+// Proposed headers are not delayed.
+//
+// The gossip strategy set in [WithGossipStrategy] must implement [tmgossip.PeerCounter].
+//
+// This option is not required.
+// If omitted, votes are sent as soon as they are made.
+func WithMinPeersBeforeVoting(n int, timeout time.Duration) Opt {
+	return func(cfg *EngineConfig) error {
+		if n <= 0 {
+			return fmt.Errorf("WithMinPeersBeforeVoting: n must be positive (got %d)", n)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("WithMinPeersBeforeVoting: timeout must be positive (got %s)", timeout)
+		}
+		cfg.MinPeersBeforeVoting = n
+		cfg.MinPeersTimeout = timeout
+		return nil
+	}
+}
+
+// WithResourceGuard sets a function that the engine calls
+// before signing and saving a proposed header, prevote, or precommit,
+// before saving a finalization,
+// and before saving its state machine's height and round,
+// to confirm that it is safe to write to its stores,
+// for instance by checking for free disk space or memory.
+//
+// When guard reports not ok, the engine logs an error with the reported reason
+// and pauses those writes rather than risking a partial write.
+// While paused, the engine calls guard every pollInterval, which must be positive.
+// Once guard reports ok, the paused actions for the current round are signed, saved, and sent,
+// and paused actions for earlier rounds are discarded.
+// Use [WithResourceGuardAlertOutput] to be notified when writes pause and resume.
+//
+// The guard is called on the engine's state machine goroutine,
+// so it should return quickly.
+//
+// This option is not required.
+// If omitted, the engine does not check resources before writing.
+func WithResourceGuard(guard func() (ok bool, reason string), pollInterval time.Duration) Opt {
+	return func(cfg *EngineConfig) error {
+		if guard == nil {
+			return errors.New("WithResourceGuard: guard must not be nil")
+		}
+		if pollInterval <= 0 {
+			return fmt.Errorf("WithResourceGuard: pollInterval must be positive (got %s)", pollInterval)
+		}
+		cfg.ResourceGuard = guard
+		cfg.ResourceGuardPollInterval = pollInterval
+		return nil
+	}
+}
+
+// WithResourceGuardAlertOutput sets the channel that the engine writes to
+// when the guard set in [WithResourceGuard] pauses the engine's writes,
+// and again when the writes resume.
+// See [tmelink.ResourceGuardAlert] for details.
+//
+// The engine never blocks sending on ch,
+// so ch must be buffered, and alerts are discarded while it is full.
+//
+// This option is not required.
+// If omitted, pausing and resuming are only logged.
+func WithResourceGuardAlertOutput(ch chan<- tmelink.ResourceGuardAlert) Opt {
+	return func(cfg *EngineConfig) error {
+		if cap(ch) == 0 {
+			return errors.New("WithResourceGuardAlertOutput: channel must be buffered")
+		}
+
+		cfg.ResourceGuardAlertOutput = ch
 		return nil
 	}
 }
@@ -42,8 +211,19 @@ func WithGossipStrategy(gs tmgossip.Strategy) Opt {
 // WithActionStore sets the engine's action store.
 // This option is required if using a non-nil signer.
 func WithActionStore(s tmstore.ActionStore) Opt {
-	return func(_ *Engine, smc *tmstate.StateMachineConfig) error {
-		smc.ActionStore = s
+	return func(cfg *EngineConfig) error {
+		cfg.ActionStore = s
+		return nil
+	}
+}
+
+// WithGenesisAnnotationStore sets the store where the engine saves
+// the genesis's GenesisValidatorAnnotations when it initializes the chain.
+// The annotations can later be loaded directly from s.
+// This option is required if the genesis has any validator annotations.
+func WithGenesisAnnotationStore(s tmstore.GenesisAnnotationStore) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.GenesisAnnotationStore = s
 		return nil
 	}
 }
@@ -51,8 +231,8 @@ func WithActionStore(s tmstore.ActionStore) Opt {
 // WithCommittedHeaderStore sets the engine's committed header store.
 // This option is required.
 func WithCommittedHeaderStore(s tmstore.CommittedHeaderStore) Opt {
-	return func(e *Engine, _ *tmstate.StateMachineConfig) error {
-		e.mCfg.CommittedHeaderStore = s
+	return func(cfg *EngineConfig) error {
+		cfg.CommittedHeaderStore = s
 		return nil
 	}
 }
@@ -60,8 +240,8 @@ func WithCommittedHeaderStore(s tmstore.CommittedHeaderStore) Opt {
 // WithFinalizationStore sets the engine's finalization store.
 // This option is required.
 func WithFinalizationStore(s tmstore.FinalizationStore) Opt {
-	return func(_ *Engine, smc *tmstate.StateMachineConfig) error {
-		smc.FinalizationStore = s
+	return func(cfg *EngineConfig) error {
+		cfg.FinalizationStore = s
 		return nil
 	}
 }
@@ -69,8 +249,8 @@ func WithFinalizationStore(s tmstore.FinalizationStore) Opt {
 // WithMirrorStore sets the engine's mirror store.
 // This option is required.
 func WithMirrorStore(s tmstore.MirrorStore) Opt {
-	return func(e *Engine, _ *tmstate.StateMachineConfig) error {
-		e.mCfg.Store = s
+	return func(cfg *EngineConfig) error {
+		cfg.MirrorStore = s
 		return nil
 	}
 }
@@ -78,17 +258,15 @@ func WithMirrorStore(s tmstore.MirrorStore) Opt {
 // WithRoundStore sets the engine's round store.
 // This option is required.
 func WithRoundStore(s tmstore.RoundStore) Opt {
-	return func(e *Engine, smc *tmstate.StateMachineConfig) error {
-		if e != nil {
-			e.mCfg.RoundStore = s
-		}
+	return func(cfg *EngineConfig) error {
+		cfg.RoundStore = s
 		return nil
 	}
 }
 
 func WithStateMachineStore(s tmstore.StateMachineStore) Opt {
-	return func(_ *Engine, smc *tmstate.StateMachineConfig) error {
-		smc.StateMachineStore = s
+	return func(cfg *EngineConfig) error {
+		cfg.StateMachineStore = s
 		return nil
 	}
 }
@@ -96,8 +274,8 @@ func WithStateMachineStore(s tmstore.StateMachineStore) Opt {
 // WithValidatorStore sets the engine's validator store.
 // This option is required.
 func WithValidatorStore(s tmstore.ValidatorStore) Opt {
-	return func(e *Engine, smc *tmstate.StateMachineConfig) error {
-		e.mCfg.ValidatorStore = s
+	return func(cfg *EngineConfig) error {
+		cfg.ValidatorStore = s
 		return nil
 	}
 }
@@ -105,9 +283,8 @@ func WithValidatorStore(s tmstore.ValidatorStore) Opt {
 // WithSignatureScheme sets the engine's signature scheme.
 // This option is required.
 func WithSignatureScheme(s tmconsensus.SignatureScheme) Opt {
-	return func(e *Engine, smc *tmstate.StateMachineConfig) error {
-		e.sigScheme = s
-		e.mCfg.SignatureScheme = s
+	return func(cfg *EngineConfig) error {
+		cfg.SignatureScheme = s
 		return nil
 	}
 }
@@ -115,10 +292,8 @@ func WithSignatureScheme(s tmconsensus.SignatureScheme) Opt {
 // WithHashScheme sets the engine's hash scheme.
 // This option is required.
 func WithHashScheme(h tmconsensus.HashScheme) Opt {
-	return func(e *Engine, smc *tmstate.StateMachineConfig) error {
-		e.hashScheme = h
-		e.mCfg.HashScheme = h
-		smc.HashScheme = h
+	return func(cfg *EngineConfig) error {
+		cfg.HashScheme = h
 		return nil
 	}
 }
@@ -126,9 +301,8 @@ func WithHashScheme(h tmconsensus.HashScheme) Opt {
 // WithCommonMessageSignatureProofScheme sets the engine's common message signature proof scheme.
 // This option is required.
 func WithCommonMessageSignatureProofScheme(s gcrypto.CommonMessageSignatureProofScheme) Opt {
-	return func(e *Engine, smc *tmstate.StateMachineConfig) error {
-		e.cmspScheme = s
-		e.mCfg.CommonMessageSignatureProofScheme = s
+	return func(cfg *EngineConfig) error {
+		cfg.CommonMessageSignatureProofScheme = s
 		return nil
 	}
 }
@@ -137,8 +311,8 @@ func WithCommonMessageSignatureProofScheme(s gcrypto.CommonMessageSignatureProof
 // If omitted or set to nil, the engine will never actively participate in consensus;
 // it will only operate as an observer.
 func WithSigner(s tmconsensus.Signer) Opt {
-	return func(_ *Engine, smc *tmstate.StateMachineConfig) error {
-		smc.Signer = s
+	return func(cfg *EngineConfig) error {
+		cfg.Signer = s
 		return nil
 	}
 }
@@ -146,8 +320,8 @@ func WithSigner(s tmconsensus.Signer) Opt {
 // WithGenesis sets the engine's ExternalGenesis.
 // This option is required.
 func WithGenesis(g *tmconsensus.ExternalGenesis) Opt {
-	return func(e *Engine, smc *tmstate.StateMachineConfig) error {
-		e.genesis = g
+	return func(cfg *EngineConfig) error {
+		cfg.Genesis = g
 		return nil
 	}
 }
@@ -155,8 +329,8 @@ func WithGenesis(g *tmconsensus.ExternalGenesis) Opt {
 // WithInitChainChannel sets the init chain channel for the engine to send on.
 // This option is only required if the chain has not yet been initialized.
 func WithInitChainChannel(ch chan<- tmdriver.InitChainRequest) Opt {
-	return func(e *Engine, _ *tmstate.StateMachineConfig) error {
-		e.initChainCh = ch
+	return func(cfg *EngineConfig) error {
+		cfg.InitChainChannel = ch
 		return nil
 	}
 }
@@ -166,8 +340,8 @@ func WithInitChainChannel(ch chan<- tmdriver.InitChainRequest) Opt {
 // The application must receive from this channel.
 // This option is required.
 func WithBlockFinalizationChannel(ch chan<- tmdriver.FinalizeBlockRequest) Opt {
-	return func(_ *Engine, smc *tmstate.StateMachineConfig) error {
-		smc.FinalizeBlockRequestCh = ch
+	return func(cfg *EngineConfig) error {
+		cfg.BlockFinalizationChannel = ch
 		return nil
 	}
 }
@@ -176,9 +350,36 @@ func WithBlockFinalizationChannel(ch chan<- tmdriver.FinalizeBlockRequest) Opt {
 // in order to refresh the consensus strategy,
 // in the event that application data is received
 // later than a proposed block is received.
+//
+// By default, the engine reads directly from ch,
+// so sends on ch block while the engine is busy,
+// once any buffer on ch is full.
+// Use [WithBlockDataArrivalBuffer] to have the engine
+// queue arrivals without blocking the sender.
 func WithBlockDataArrivalChannel(ch <-chan tmelink.BlockDataArrival) Opt {
-	return func(_ *Engine, smc *tmstate.StateMachineConfig) error {
-		smc.BlockDataArrivalCh = ch
+	return func(cfg *EngineConfig) error {
+		cfg.BlockDataArrivalChannel = ch
+		return nil
+	}
+}
+
+// WithBlockDataArrivalBuffer sets the maximum number of block data arrivals
+// that the engine queues internally,
+// after reading them from the channel set by [WithBlockDataArrivalChannel].
+// With the buffer in place, the engine reads arrivals as soon as they are sent.
+// When the buffer is full, the oldest queued arrival is discarded
+// to make room for the new one,
+// and the discard is counted in the engine's metrics.
+//
+// This option is not required.
+// If omitted, the engine reads directly from the arrival channel
+// and applies no additional buffering.
+func WithBlockDataArrivalBuffer(n int) Opt {
+	return func(cfg *EngineConfig) error {
+		if n <= 0 {
+			return fmt.Errorf("WithBlockDataArrivalBuffer: size must be positive (got %d)", n)
+		}
+		cfg.BlockDataArrivalBuffer = n
 		return nil
 	}
 }
@@ -187,14 +388,293 @@ func WithBlockDataArrivalChannel(ch <-chan tmelink.BlockDataArrival) Opt {
 // when its lag state changes.
 // This option is not required, but is strongly recommended.
 func WithLagStateChannel(ch chan<- tmelink.LagState) Opt {
-	return func(e *Engine, _ *tmstate.StateMachineConfig) error {
+	return func(cfg *EngineConfig) error {
 		if cap(ch) != 0 {
 			// cap(nil) is also 0, notably.
 			// We'll allow a nil ch for now, but one could argue against allowing that.
 			return fmt.Errorf("WithLagStateChannel: capacity of channel must be zero (got %d)", cap(ch))
 		}
 
-		e.mCfg.LagStateOut = ch
+		cfg.LagStateChannel = ch
+		return nil
+	}
+}
+
+// WithBeaconOutput sets the channel that the engine writes to
+// once the header following each committed header is committed,
+// with a deterministic seed derived from the canonical commit proof
+// that the following header carries.
+// The channel is read in the same order that headers are committed.
+//
+// See the [tmelink.Beacon] documentation for security caveats
+// before using the seed as a source of randomness.
+//
+// This option is not required.
+// If set, the application must read from the channel promptly,
+// as unsent beacons are held in memory until they are read.
+func WithBeaconOutput(ch chan<- tmelink.Beacon) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.BeaconOutput = ch
+		return nil
+	}
+}
+
+// WithSafetyViolationOutput sets the channel that the engine writes to
+// when it observes valid majority precommits for a block
+// conflicting with a block it has already committed at the same height,
+// in any round.
+// Each conflicting block is reported once per round.
+// See [tmelink.SafetyViolation] for details.
+//
+// This option is not required.
+// If omitted, conflicting commits in the committed round are only logged,
+// and precommits for earlier heights or other rounds are not checked.
+func WithSafetyViolationOutput(ch chan<- tmelink.SafetyViolation) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.SafetyViolationOutput = ch
+		return nil
+	}
+}
+
+// WithFetchedHeaderBufferLimit sets the maximum number of proposed headers
+// that the engine may have requested from its proposed header fetcher
+// without having processed them yet.
+// Once the limit is reached, the engine stops making new fetch requests
+// until one of the outstanding headers has been processed.
+// This bounds the memory used by fetched headers, particularly when catching up.
+//
+// This option is not required.
+// If omitted or set to zero, the number of outstanding fetches is unlimited.
+func WithFetchedHeaderBufferLimit(n int) Opt {
+	return func(cfg *EngineConfig) error {
+		if n < 0 {
+			return fmt.Errorf("WithFetchedHeaderBufferLimit: limit must not be negative (got %d)", n)
+		}
+		cfg.FetchedHeaderBufferLimit = n
+		return nil
+	}
+}
+
+// WithRequireExpectedProposer controls whether the engine rejects proposed headers
+// signed by a known validator other than the [tmconsensus.ExpectedProposer]
+// for the header's height and round.
+// Rejected headers are reported as [tmconsensus.HandleProposedHeaderUnexpectedProposer].
+//
+// Only enable this option if the chain's consensus strategy
+// uses the same proposer selection as [tmconsensus.ExpectedProposer].
+//
+// This option is not required.
+// If omitted, any validator in the current set may propose a header.
+func WithRequireExpectedProposer(enabled bool) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.RequireExpectedProposer = enabled
+		return nil
+	}
+}
+
+// WithMaxHeadersPerProposerPerRound sets the maximum number of proposed headers
+// the engine accepts from a single proposer in a single round.
+// Further headers from that proposer in the same round are reported as
+// [tmconsensus.HandleProposedHeaderProposerQuotaExceeded],
+// while headers from other proposers are unaffected.
+//
+// This option is not required.
+// If omitted or set to zero, the number of headers per proposer is unlimited.
+func WithMaxHeadersPerProposerPerRound(n int) Opt {
+	return func(cfg *EngineConfig) error {
+		if n < 0 {
+			return fmt.Errorf("WithMaxHeadersPerProposerPerRound: limit must not be negative (got %d)", n)
+		}
+		cfg.MaxHeadersPerProposerPerRound = n
+		return nil
+	}
+}
+
+// WithHeaderTimestampValidation causes the engine to reject proposed headers
+// whose timestamps fail [tmconsensus.ValidateHeaderTimestamp],
+// reporting them as [tmconsensus.HandleProposedHeaderBadTimestamp].
+// That is, a header's timestamp must be after its parent's timestamp,
+// and it must be no more than maxFutureDrift ahead of the local clock.
+//
+// The engine's own proposals take their timestamps from [tmconsensus.Proposal.Timestamp],
+// so the consensus strategy must set that field when this option is in use.
+//
+// This option is not required.
+// If omitted, header timestamps are not validated.
+func WithHeaderTimestampValidation(maxFutureDrift time.Duration) Opt {
+	return func(cfg *EngineConfig) error {
+		if maxFutureDrift < 0 {
+			return fmt.Errorf("WithHeaderTimestampValidation: drift must not be negative (got %s)", maxFutureDrift)
+		}
+		cfg.ValidateHeaderTimestamps = true
+		cfg.HeaderTimestampMaxFutureDrift = maxFutureDrift
+		return nil
+	}
+}
+
+// WithMaxFutureRoundForHeader sets how many rounds beyond its current voting round
+// the engine accepts proposed headers for, at its current voting height.
+// Proposed headers for rounds further ahead are reported as
+// [tmconsensus.HandleProposedHeaderRoundTooFarInFuture].
+//
+// A larger value lets the engine hold headers for rounds it has not reached yet,
+// so that they are already available when it catches up to those rounds,
+// at the cost of holding more headers that a faulty validator could send.
+// Headers for the round after the voting round are always accepted,
+// so values of zero and one are equivalent.
+//
+// This option is not required.
+// If omitted, only headers for the voting round and the next round are accepted.
+func WithMaxFutureRoundForHeader(n uint32) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.MaxFutureRoundForHeader = n
+		return nil
+	}
+}
+
+// WithMaxAnnotationBytes limits the combined size of the annotations
+// on a proposed header and on its header,
+// as reported by [tmconsensus.ProposedHeader.AnnotationBytes],
+// to n bytes.
+// Incoming proposed headers over the limit are rejected
+// as [tmconsensus.HandleProposedHeaderAnnotationsTooLarge],
+// and the engine does not propose a header over the limit;
+// it skips proposing in that round instead.
+//
+// This option is not required.
+// If omitted, annotation sizes are not limited.
+func WithMaxAnnotationBytes(n int) Opt {
+	return func(cfg *EngineConfig) error {
+		if n <= 0 {
+			return fmt.Errorf("WithMaxAnnotationBytes: limit must be positive (got %d)", n)
+		}
+		cfg.MaxAnnotationBytes = n
+		return nil
+	}
+}
+
+// WithValidatorSetTransitionValidator sets the policy
+// for which validator set changes the application may make.
+// When the driver responds to a finalization request,
+// the transition from the finalized header's NextValidatorSet
+// to the validators in the response is checked with v;
+// a rejected transition halts the engine before the finalization is stored,
+// as it indicates a bug in the application.
+//
+// Proposed headers received through gossip are not checked,
+// because their NextValidatorSet was already determined by an earlier finalization.
+//
+// This option is not required.
+// If omitted, any validator set transition is accepted.
+func WithValidatorSetTransitionValidator(v tmconsensus.ValidatorSetTransitionValidator) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.ValidatorSetTransitionValidator = v
+		return nil
+	}
+}
+
+// WithIncomingHeaderPrefilter sets a function that is called
+// on every incoming proposed header before the engine verifies it.
+// Headers for which f returns false are dropped
+// and reported as [tmconsensus.HandleProposedHeaderPrefiltered],
+// without spending any time on hash or signature verification.
+//
+// Because f sees the header before verification,
+// it should only be used to cheaply drop obviously unwanted headers,
+// such as those whose ProposerPubKey is on a denylist;
+// the header may have been forged, so f must not trust any of its fields.
+// The function is called concurrently and must not block.
+//
+// This option is not required.
+// If omitted, every incoming proposed header is verified.
+func WithIncomingHeaderPrefilter(f func(tmconsensus.ProposedHeader) bool) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.IncomingHeaderPrefilter = f
+		return nil
+	}
+}
+
+// WithRequireKnownVoteBlockHash controls whether the engine only accepts
+// prevotes and precommits targeting either the nil block
+// or a block whose proposed header the engine has already seen for that round.
+// Votes for other block hashes are reported as [tmconsensus.HandleVoteProofsUnknownBlockHash].
+//
+// When enabled, peers cannot cause the engine to track votes for arbitrary block hashes,
+// but votes may be ignored if they arrive before the corresponding proposed header.
+//
+// This option is not required.
+// If omitted, votes for any block hash are accepted.
+func WithRequireKnownVoteBlockHash(enabled bool) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.RequireKnownVoteBlockHash = enabled
+		return nil
+	}
+}
+
+// WithMinimizeCommitProof controls whether the engine trims commit proofs
+// when a block is committed.
+// If enabled, the proof stored with the committed header,
+// and included as the previous commit proof in the engine's next proposed header,
+// only contains precommits for the committed block
+// from a deterministic subset of validators, favoring the lowest indices,
+// whose voting power reaches a majority.
+// This reduces the size of stored and proposed headers.
+//
+// This option is not required.
+// If omitted, commit proofs contain every precommit the engine has seen.
+func WithMinimizeCommitProof(enabled bool) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.MinimizeCommitProof = enabled
+		return nil
+	}
+}
+
+// WithMinVotePowerToGossip sets a voting power floor
+// below which a validator's votes are not forwarded to the gossip strategy.
+// The engine still counts those votes, including in commit proofs.
+//
+// This option is not required.
+// If omitted or set to zero, every vote is forwarded to the gossip strategy.
+func WithMinVotePowerToGossip(floor uint64) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.MinVotePowerToGossip = floor
+		return nil
+	}
+}
+
+// WithLateVoteGracePeriod sets how long the engine continues to record
+// prevotes and precommits for a round after abandoning that round.
+// Votes that were in flight when the network advanced rounds
+// would otherwise be rejected as out of date and lost,
+// along with the record of how each validator voted.
+// Late votes are saved to the round store,
+// but they do not affect the current round and are not gossiped.
+//
+// This option is not required.
+// If omitted, votes for an abandoned round are rejected immediately.
+func WithLateVoteGracePeriod(d time.Duration) Opt {
+	return func(cfg *EngineConfig) error {
+		if d < 0 {
+			return fmt.Errorf("WithLateVoteGracePeriod: duration must not be negative (got %s)", d)
+		}
+		cfg.LateVoteGracePeriod = d
+		return nil
+	}
+}
+
+// WithTrustedHeaderIngestion controls whether [*Engine.IngestTrustedHeaders] may be used
+// to apply committed headers from a source the driver already trusts,
+// such as a state sync provider.
+// Trusted headers are not fetched from the network,
+// and if the configured signature proof scheme supports [gcrypto.TrustedSparseMerger],
+// their commit proof signatures are not verified.
+// Header hashes and commit proof voting power are still checked.
+//
+// This option is not required.
+// If omitted, IngestTrustedHeaders always returns an error.
+func WithTrustedHeaderIngestion(enabled bool) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.AllowTrustedHeaderIngestion = enabled
 		return nil
 	}
 }
@@ -203,8 +683,8 @@ func WithLagStateChannel(ch chan<- tmelink.LagState) Opt {
 // reads replayed header requests from.
 // This option is not required, but is strongly recommended.
 func WithReplayedHeaderRequestChannel(ch <-chan tmelink.ReplayedHeaderRequest) Opt {
-	return func(e *Engine, _ *tmstate.StateMachineConfig) error {
-		e.mCfg.ReplayedHeadersIn = ch
+	return func(cfg *EngineConfig) error {
+		cfg.ReplayedHeaderRequestChannel = ch
 		return nil
 	}
 }
@@ -216,8 +696,8 @@ type roundTimer = tmstate.RoundTimer
 //
 // Non-test usage should call [WithTimeoutStrategy] to use an exported type.
 func WithInternalRoundTimer(rt roundTimer) Opt {
-	return func(_ *Engine, smc *tmstate.StateMachineConfig) error {
-		smc.RoundTimer = rt
+	return func(cfg *EngineConfig) error {
+		cfg.roundTimer = rt
 		return nil
 	}
 }
@@ -226,17 +706,284 @@ func WithInternalRoundTimer(rt roundTimer) Opt {
 // for calculating state machine timeouts during consensus.
 // The context value controls the lifecycle of the timer.
 func WithTimeoutStrategy(ctx context.Context, s TimeoutStrategy) Opt {
-	return WithInternalRoundTimer(tmstate.NewStandardRoundTimer(ctx, s))
+	return func(cfg *EngineConfig) error {
+		cfg.TimeoutStrategy = s
+		cfg.timeoutCtx = ctx
+
+		// Override any earlier WithInternalRoundTimer.
+		cfg.roundTimer = nil
+		return nil
+	}
+}
+
+// WithEndCommitWaitOnFullPrecommits controls whether the engine may end
+// the commit wait step before the commit wait timeout elapses.
+// When enabled, once the driver's finalization for the committing block has been stored
+// and precommits from 100% of the voting power are present,
+// the engine advances to the next height immediately,
+// as there are no further precommits to wait for.
+//
+// This option is not required.
+// If omitted, the engine always waits for the full commit wait timeout.
+func WithEndCommitWaitOnFullPrecommits(enabled bool) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.EndCommitWaitOnFullPrecommits = enabled
+		return nil
+	}
+}
+
+// WithSkipSingleValidatorCommitWait controls whether the engine skips
+// the commit wait step at heights whose validator set has exactly one member.
+// When enabled, on such a height the engine advances to the next height
+// as soon as the driver's finalization for the committing block has been stored,
+// without waiting for the commit wait timeout.
+// This is intended for single-validator devnets,
+// where commit wait only adds latency.
+//
+// This option is not required.
+// If omitted, single-validator chains wait for the full commit wait timeout,
+// unless [WithEndCommitWaitOnFullPrecommits] is in use.
+func WithSkipSingleValidatorCommitWait(enabled bool) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.SkipSingleValidatorCommitWait = enabled
+		return nil
+	}
+}
+
+// WithWatchdogFinalizationFlushTimeout controls how the engine behaves
+// when the watchdog terminates it while a finalization is pending.
+// The driver may have already committed a block
+// by the time its finalization response reaches the engine.
+// If d is positive and that response has not yet been saved to the finalization store,
+// the engine attempts to save it during termination, waiting at most d.
+//
+// This option is not required.
+// If omitted or zero, a pending finalization is discarded upon watchdog termination.
+func WithWatchdogFinalizationFlushTimeout(d time.Duration) Opt {
+	return func(cfg *EngineConfig) error {
+		if d < 0 {
+			return fmt.Errorf("WithWatchdogFinalizationFlushTimeout: timeout must not be negative (got %s)", d)
+		}
+		cfg.WatchdogFinalizationFlushTimeout = d
+		return nil
+	}
+}
+
+// WithMaxRoundsPerHeight limits the number of rounds the engine attempts at a single height.
+// If the engine would advance beyond round n-1 without committing a block,
+// it logs a diagnostic and halts via the watchdog,
+// rather than continuing through rounds indefinitely.
+//
+// This option is not required.
+// If omitted or zero, the number of rounds per height is unlimited.
+func WithMaxRoundsPerHeight(n uint32) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.MaxRoundsPerHeight = n
+		return nil
+	}
+}
+
+// WithSelfEquivocationGuard controls whether the engine checks its action store
+// for a conflicting vote from its own key before signing a prevote or precommit.
+// A recorded vote for a different target in the same round indicates that the key
+// was used by another process, such as a misconfigured failover;
+// when the guard is enabled, the engine logs a fatal error and halts via the watchdog
+// instead of signing a vote that would equivocate.
+//
+// This option is not required.
+// If omitted, the engine does not check for conflicting votes before signing.
+func WithSelfEquivocationGuard(enabled bool) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.SelfEquivocationGuard = enabled
+		return nil
+	}
+}
+
+// WithProposalAnnotator sets a function that the engine calls
+// whenever it builds a proposed header from the consensus strategy's proposal.
+// The function is called with the height and round of the proposed header,
+// and its non-nil return values are set as the Driver annotations
+// of the proposed header and of the header, respectively,
+// replacing any Driver annotations set by the consensus strategy.
+// This allows an application to stamp every proposed header with metadata,
+// such as its version or a timestamp.
+//
+// If fn returns an error, the engine logs it and does not propose a header in that round.
+//
+// This option is not required.
+// If omitted, proposed headers only carry the consensus strategy's annotations.
+func WithProposalAnnotator(
+	fn func(height uint64, round uint32) (proposalAnn, blockAnn []byte, err error),
+) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.ProposalAnnotator = fn
+		return nil
+	}
+}
+
+// WithEnterRoundObserver sets a function that the engine calls
+// with a copy of the round view it passes to [tmconsensus.ConsensusStrategy.EnterRound],
+// for each call,
+// so that a driver can capture every input to its consensus strategy's decisions,
+// for example to log them for debugging.
+//
+// The function is called on a dedicated goroutine, in round order,
+// so a slow observer does not block consensus,
+// but it may be called after the strategy has already entered the round.
+// Round views are queued in memory without limit until the function handles them,
+// so the function must keep up with consensus on average.
+// The round view is a copy owned by the observer.
+//
+// This option is not required.
+// If omitted, round views are only passed to the consensus strategy.
+func WithEnterRoundObserver(fn func(tmconsensus.RoundView)) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.EnterRoundObserver = fn
+		return nil
+	}
+}
+
+// WithInitialValidatorSetProvider sets a function that the engine calls at startup
+// to determine the height this node began participating in consensus,
+// and the validator set at that height.
+// A node that has fast-synced to a recent height of a running chain
+// uses this to participate with the chain's current validators,
+// instead of the genesis validators.
+//
+// The finalization for the height immediately before the returned height
+// must already be in the engine's finalization store,
+// and the committed header for that height must be in the committed header store,
+// so that the previous commit proof of headers at the returned height can be verified.
+// If the returned height is the genesis initial height,
+// the engine starts from genesis as usual, except for using the provided validators.
+//
+// The function is called on every startup,
+// because the mirror always treats the returned height as its initial height;
+// so it must return the same height and validator set every time.
+// The state machine only begins at the returned height if it has no stored progress;
+// otherwise it resumes from its stored height,
+// determining validators from the stored finalizations.
+//
+// This option is not required.
+// If omitted, a node without stored progress always starts at genesis.
+func WithInitialValidatorSetProvider(
+	fn func(context.Context) (tmconsensus.ValidatorSet, uint64, error),
+) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.InitialValidatorSetProvider = fn
+		return nil
+	}
+}
+
+// WithActionObserver sets a function that the engine calls
+// with every proposed header, prevote, and precommit produced by its state machine,
+// alongside sending the action to the rest of the network.
+// This may be used, for example, to keep an audit log of the engine's signatures.
+//
+// The function is called on a dedicated goroutine, in the order the actions were produced,
+// so a slow observer does not block consensus.
+// Actions are queued in memory without limit until the function handles them,
+// so the function must keep up with consensus on average.
+// The function must not modify the action.
+//
+// This option is not required.
+// If omitted, actions are not observed.
+func WithActionObserver(fn func(tmelink.StateMachineRoundAction)) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.ActionObserver = fn
+		return nil
+	}
+}
+
+// WithProposalPrepareNotifier sets the channel that the engine writes to
+// ahead of a round in which this node is the expected proposer:
+// when the previous height begins committing, for the first round of a height,
+// or otherwise just before the engine enters the round.
+// This gives the application lead time to gather the data for its proposed block
+// before the consensus strategy is asked to propose.
+// See [tmelink.ProposalPrepare] for details.
+//
+// The engine never blocks sending on ch,
+// so ch must be buffered, and notifications are discarded while it is full.
+//
+// This option is not required.
+// If omitted, no proposal prepare notifications are sent.
+func WithProposalPrepareNotifier(ch chan<- tmelink.ProposalPrepare) Opt {
+	return func(cfg *EngineConfig) error {
+		if cap(ch) == 0 {
+			return errors.New("WithProposalPrepareNotifier: channel must be buffered")
+		}
+
+		cfg.ProposalPrepareNotifier = ch
+		return nil
+	}
+}
+
+// WithCommitWaitNotifier sets the channel that the engine writes to
+// each time its state machine observes majority precommits for a block
+// and begins waiting to commit it.
+// The notification reports whether the commit was driven by the rest of the network,
+// rather than including this node's own precommit.
+// See [tmelink.CommitWaitEntered] for details.
+//
+// The engine never blocks sending on ch,
+// so ch must be buffered, and notifications are discarded while it is full.
+//
+// This option is not required.
+// If omitted, no commit wait notifications are sent.
+func WithCommitWaitNotifier(ch chan<- tmelink.CommitWaitEntered) Opt {
+	return func(cfg *EngineConfig) error {
+		if cap(ch) == 0 {
+			return errors.New("WithCommitWaitNotifier: channel must be buffered")
+		}
+
+		cfg.CommitWaitNotifier = ch
+		return nil
+	}
 }
 
 // WithWatchdog sets the engine's watchdog, propagating it through subsystems of the engine.
 // This option is required.
 // For tests, the caller may use [gwatchdog.NewNopWatchdog] to avoid creating unnecessary goroutines.
 func WithWatchdog(wd *gwatchdog.Watchdog) Opt {
-	return func(e *Engine, smc *tmstate.StateMachineConfig) error {
-		e.watchdog = wd
-		e.mCfg.Watchdog = wd
-		smc.Watchdog = wd
+	return func(cfg *EngineConfig) error {
+		cfg.Watchdog = wd
+		return nil
+	}
+}
+
+// WithWatchdogHeartbeat controls how the watchdog set in [WithWatchdog]
+// monitors the engine's internal subsystems:
+// the mirror kernel, the state machine, and the mirror's output to the gossip strategy.
+// Each subsystem is checked every interval, plus or minus jitter,
+// and it must respond within responseTimeout,
+// or the watchdog terminates the engine, naming the unresponsive subsystem.
+//
+// The mirror's gossip output only responds after the gossip strategy
+// has accepted any pending network view update,
+// so a gossip strategy that stops reading its updates is also detected.
+//
+// This option is not required.
+// If omitted, subsystems are checked every 10 seconds, plus or minus 1 second,
+// and must respond within 1 second.
+func WithWatchdogHeartbeat(interval, jitter, responseTimeout time.Duration) Opt {
+	return func(cfg *EngineConfig) error {
+		if interval <= 0 || jitter <= 0 || responseTimeout <= 0 {
+			return fmt.Errorf(
+				"WithWatchdogHeartbeat: durations must be positive (got interval=%s, jitter=%s, response timeout=%s)",
+				interval, jitter, responseTimeout,
+			)
+		}
+		if jitter > interval {
+			return fmt.Errorf(
+				"WithWatchdogHeartbeat: jitter must not exceed interval (got interval=%s, jitter=%s)",
+				interval, jitter,
+			)
+		}
+
+		cfg.WatchdogHeartbeatInterval = interval
+		cfg.WatchdogHeartbeatJitter = jitter
+		cfg.WatchdogHeartbeatResponseTimeout = responseTimeout
 		return nil
 	}
 }
@@ -244,11 +991,62 @@ func WithWatchdog(wd *gwatchdog.Watchdog) Opt {
 // WithMetricsChannel sets the channel where the engine
 // emits metrics for its subsystems.
 func WithMetricsChannel(ch chan<- Metrics) Opt {
-	return func(e *Engine, _ *tmstate.StateMachineConfig) error {
+	return func(cfg *EngineConfig) error {
 		if len(ch) != 0 {
 			return errors.New("WithMetricsChannel: ch must be unbuffered")
 		}
-		e.metricsCh = ch
+		cfg.MetricsChannel = ch
+		return nil
+	}
+}
+
+// WithPeerScoringOutput sets the channel where the engine sends a [PeerScoreEvent]
+// after handling a proposed header or vote proofs from a peer,
+// classifying the message as new, duplicate, carrying an invalid signature, or malformed.
+// This is intended to feed a network layer's peer scoring,
+// such as gossipsub's application-specific score.
+//
+// Events are only sent for messages whose context carries a peer ID
+// set through [tmconsensus.WithPeerID],
+// and only when the handler result says something about the sender;
+// for instance, no event is sent for votes from an old round.
+// Vote proofs containing any invalid or malformed signature are classified as such,
+// even if other signatures in the same message were accepted.
+// Events are dropped if ch is not ready to receive,
+// so ch should be buffered and read promptly.
+//
+// This option is not required.
+// If omitted, no peer score events are sent.
+func WithPeerScoringOutput(ch chan<- PeerScoreEvent) Opt {
+	return func(cfg *EngineConfig) error {
+		cfg.PeerScoringOutput = ch
+		return nil
+	}
+}
+
+// WithDivergenceAlert sets the channel where the engine sends a [DivergenceAlert]
+// when the mirror's voting height exceeds the state machine's height by more than threshold.
+// A persistent divergence usually indicates that the state machine is stalled,
+// for instance because the driver is not responding to block finalization requests.
+//
+// One alert is sent each time the divergence begins;
+// the state machine must come back within the threshold before another alert is sent.
+// If ch is not ready to receive when the alert fires,
+// the alert is held and updated with the latest heights until it can be sent.
+//
+// During normal operation, the mirror's voting height is typically
+// at most one greater than the state machine's height,
+// so threshold should usually be at least 1.
+//
+// This option is not required.
+// If omitted, no divergence alerts are sent.
+func WithDivergenceAlert(threshold uint64, ch chan<- DivergenceAlert) Opt {
+	return func(cfg *EngineConfig) error {
+		if ch == nil {
+			return errors.New("WithDivergenceAlert: ch must not be nil")
+		}
+		cfg.DivergenceAlertThreshold = threshold
+		cfg.DivergenceAlertChannel = ch
 		return nil
 	}
 }
@@ -257,9 +1055,8 @@ func WithMetricsChannel(ch chan<- Metrics) Opt {
 // It is safe to exclude this option in builds that do not have the "debug" build tag.
 // However, in debug builds, omitting this option will cause a runtime panic.
 func WithAssertEnv(assertEnv gassert.Env) Opt {
-	return func(e *Engine, smc *tmstate.StateMachineConfig) error {
-		e.mCfg.AssertEnv = assertEnv
-		smc.AssertEnv = assertEnv
+	return func(cfg *EngineConfig) error {
+		cfg.AssertEnv = assertEnv
 		return nil
 	}
 }
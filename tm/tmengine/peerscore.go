@@ -0,0 +1,142 @@
+package tmengine
+
+import (
+	"context"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmengine/internal/tmmirror"
+)
+
+// PeerScoreEvent is sent on the channel set through [WithPeerScoringOutput]
+// after the engine handles a consensus message from a peer,
+// so that the network layer can adjust the peer's score,
+// for instance through gossipsub's application-specific score.
+type PeerScoreEvent struct {
+	// The peer that sent the message,
+	// as set through [tmconsensus.WithPeerID].
+	PeerID string
+
+	Class PeerScoreClass
+}
+
+// PeerScoreClass classifies a consensus message received from a peer,
+// for the purpose of scoring that peer.
+type PeerScoreClass uint8
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type PeerScoreClass -trimprefix=PeerScore .
+
+const (
+	// Keep zero value invalid.
+	_ PeerScoreClass = iota
+
+	// The message was valid and contained information we did not already have.
+	PeerScoreValidNew
+
+	// The message was valid but we already had all of its information.
+	PeerScoreDuplicate
+
+	// The message contained at least one signature that failed verification.
+	PeerScoreInvalidSignature
+
+	// The message was invalid for a reason other than a signature,
+	// such as an incorrect hash or an unexpected validator set.
+	PeerScoreMalformed
+)
+
+// Delta returns the direction of the score change for c:
+// 1 for a useful message, 0 for a duplicate, and -1 for an invalid message.
+// Applications are expected to weigh each class according to their own scoring parameters.
+func (c PeerScoreClass) Delta() int {
+	switch c {
+	case PeerScoreValidNew:
+		return 1
+	case PeerScoreInvalidSignature, PeerScoreMalformed:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// proposedHeaderPeerScoreClass returns the peer score class for r.
+// The ok result is false if r says nothing about the sender,
+// for example because the header was for an old round,
+// or because r only reflects on the proposer or on our local view of the validators,
+// which an honest peer relaying the header cannot be expected to check.
+func proposedHeaderPeerScoreClass(r tmconsensus.HandleProposedHeaderResult) (c PeerScoreClass, ok bool) {
+	switch r {
+	case tmconsensus.HandleProposedHeaderAccepted:
+		return PeerScoreValidNew, true
+
+	case tmconsensus.HandleProposedHeaderAlreadyStored:
+		return PeerScoreDuplicate, true
+
+	case tmconsensus.HandleProposedHeaderBadSignature,
+		tmconsensus.HandleProposedHeaderBadPrevCommitProofSignature:
+		return PeerScoreInvalidSignature, true
+
+	case tmconsensus.HandleProposedHeaderBadBlockHash,
+		tmconsensus.HandleProposedHeaderBadPrevCommitProofPubKeyHash,
+		tmconsensus.HandleProposedHeaderBadPrevCommitVoteCount,
+		tmconsensus.HandleProposedHeaderBadTimestamp,
+		tmconsensus.HandleProposedHeaderAnnotationsTooLarge:
+		return PeerScoreMalformed, true
+
+	default:
+		return 0, false
+	}
+}
+
+// voteProofsPeerScoreClass returns the peer score class for r and rep.
+// Signatures discarded according to rep take precedence over r,
+// because r may report acceptance of other, valid signatures in the same message.
+// The ok result is false if r and rep say nothing about the sender,
+// for example because the votes were for a future round.
+func voteProofsPeerScoreClass(
+	r tmconsensus.HandleVoteProofsResult, rep tmmirror.VoteProofsReport,
+) (c PeerScoreClass, ok bool) {
+	if rep.InvalidSignature {
+		return PeerScoreInvalidSignature, true
+	}
+	if rep.Malformed {
+		return PeerScoreMalformed, true
+	}
+
+	switch r {
+	case tmconsensus.HandleVoteProofsAccepted:
+		return PeerScoreValidNew, true
+
+	case tmconsensus.HandleVoteProofsNoNewSignatures:
+		return PeerScoreDuplicate, true
+
+	case tmconsensus.HandleVoteProofsEmpty,
+		tmconsensus.HandleVoteProofsBadPubKeyHash:
+		return PeerScoreMalformed, true
+
+	default:
+		return 0, false
+	}
+}
+
+// sendPeerScore sends a [PeerScoreEvent] for c
+// if e has a peer scoring output and ctx has a peer ID.
+// The event is dropped if the output is not ready to receive,
+// so that slow scoring never delays message handling.
+func (e *Engine) sendPeerScore(ctx context.Context, c PeerScoreClass) {
+	if e.peerScoreOut == nil {
+		return
+	}
+
+	peerID, ok := tmconsensus.PeerIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	select {
+	case e.peerScoreOut <- PeerScoreEvent{PeerID: peerID, Class: c}:
+	default:
+		e.log.Debug(
+			"Dropping peer score event due to blocked output",
+			"peer_id", peerID, "class", c,
+		)
+	}
+}
@@ -0,0 +1,95 @@
+package tmelink
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"slices"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+)
+
+// Beacon is a per-height seed which the application may use as a source of randomness.
+// The engine emits the Beacon for a height once the header at the following height is committed.
+//
+// The seed is derived deterministically from the committed header's hash
+// and the precommit signatures for that header
+// in the canonical commit proof, i.e. the PrevCommitProof of the following header.
+// Every node commits the same following header,
+// so every node derives the same seed.
+//
+// There are important caveats to using the Beacon:
+//
+//   - Validators see their own signatures before anyone else,
+//     and the final signers to reach the majority threshold
+//     may withhold their signatures to bias the result between a small number of outcomes.
+//     The seed must not be treated as unbiasable for high-value decisions.
+//   - With non-aggregating signature schemes (such as ed25519),
+//     the seed is still deterministic for a given proof,
+//     but it is only as unpredictable as the signatures themselves.
+type Beacon struct {
+	// The height and round of the committed header.
+	Height uint64
+	Round  uint32
+
+	// The hash of the committed header.
+	BlockHash []byte
+
+	// The derived seed.
+	Seed [sha256.Size]byte
+}
+
+// beaconDomain separates beacon seeds from any other SHA-256 output
+// that may be computed over similar data.
+const beaconDomain = "gordian-beacon-v1\x00"
+
+// NewBeacon returns the Beacon for the block with the given hash at the given height,
+// which was committed with the given proof.
+//
+// Only the signatures in proof.Proofs for blockHash are considered;
+// signatures for other blocks or for nil do not influence the seed.
+// The signatures are sorted by key ID before hashing,
+// so the order of the signatures in the proof does not affect the seed.
+func NewBeacon(height uint64, blockHash []byte, proof tmconsensus.CommitProof) Beacon {
+	sigs := slices.Clone(proof.Proofs[string(blockHash)])
+	slices.SortFunc(sigs, func(a, b gcrypto.SparseSignature) int {
+		if c := bytes.Compare(a.KeyID, b.KeyID); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.Sig, b.Sig)
+	})
+
+	hasher := sha256.New()
+	_, _ = hasher.Write([]byte(beaconDomain))
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], height)
+	_, _ = hasher.Write(buf[:])
+	binary.BigEndian.PutUint32(buf[:4], proof.Round)
+	_, _ = hasher.Write(buf[:4])
+
+	writeLenPrefixed(hasher, blockHash)
+	for _, sig := range sigs {
+		writeLenPrefixed(hasher, sig.KeyID)
+		writeLenPrefixed(hasher, sig.Sig)
+	}
+
+	b := Beacon{
+		Height:    height,
+		Round:     proof.Round,
+		BlockHash: bytes.Clone(blockHash),
+	}
+	hasher.Sum(b.Seed[:0])
+	return b
+}
+
+// writeLenPrefixed writes the length of b followed by b to w,
+// so that adjacent variable-length fields cannot be confused.
+func writeLenPrefixed(w io.Writer, b []byte) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(b)))
+	_, _ = w.Write(buf[:])
+	_, _ = w.Write(b)
+}
@@ -0,0 +1,76 @@
+package tmelink_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmengine/tmelink"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBeacon_deterministic(t *testing.T) {
+	t.Parallel()
+
+	const height = 3
+	blockHash := []byte("block_hash")
+
+	newProof := func() tmconsensus.CommitProof {
+		return tmconsensus.CommitProof{
+			Round:      1,
+			PubKeyHash: "pub_key_hash",
+			Proofs: map[string][]gcrypto.SparseSignature{
+				"block_hash": {
+					{KeyID: []byte{0}, Sig: []byte("sig_0")},
+					{KeyID: []byte{2}, Sig: []byte("sig_2")},
+					{KeyID: []byte{3}, Sig: []byte("sig_3")},
+				},
+				"": {
+					{KeyID: []byte{1}, Sig: []byte("nil_sig_1")},
+				},
+			},
+		}
+	}
+
+	b := tmelink.NewBeacon(height, blockHash, newProof())
+	require.Equal(t, uint64(3), b.Height)
+	require.Equal(t, uint32(1), b.Round)
+	require.Equal(t, []byte("block_hash"), b.BlockHash)
+
+	// The seed is fixed for a given proof, across runs.
+	require.Equal(
+		t,
+		"61ba450020cfac4b8c348bca22cae6c9fa2df2bf82e000664815d91f1fddb0c2",
+		hex.EncodeToString(b.Seed[:]),
+	)
+
+	t.Run("signature order does not matter", func(t *testing.T) {
+		p := newProof()
+		sigs := p.Proofs["block_hash"]
+		sigs[0], sigs[2] = sigs[2], sigs[0]
+
+		require.Equal(t, b, tmelink.NewBeacon(height, blockHash, p))
+	})
+
+	t.Run("other block signatures do not matter", func(t *testing.T) {
+		p := newProof()
+		delete(p.Proofs, "")
+
+		require.Equal(t, b, tmelink.NewBeacon(height, blockHash, p))
+	})
+
+	t.Run("different signatures produce a different seed", func(t *testing.T) {
+		p := newProof()
+		p.Proofs["block_hash"] = p.Proofs["block_hash"][:2]
+
+		require.NotEqual(t, b.Seed, tmelink.NewBeacon(height, blockHash, p).Seed)
+	})
+
+	t.Run("different round produces a different seed", func(t *testing.T) {
+		p := newProof()
+		p.Round = 2
+
+		require.NotEqual(t, b.Seed, tmelink.NewBeacon(height, blockHash, p).Seed)
+	})
+}
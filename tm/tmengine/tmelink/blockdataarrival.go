@@ -1,5 +1,7 @@
 package tmelink
 
+import "github.com/gordian-engine/gordian/tm/tmconsensus"
+
 // BlockDataArrival is shared with the engine's state machine,
 // to indicate that a block's data has arrived.
 //
@@ -18,5 +20,5 @@ type BlockDataArrival struct {
 	Round  uint32
 
 	// The DataID of the proposed block, whose data has arrived.
-	ID string
+	ID tmconsensus.DataID
 }
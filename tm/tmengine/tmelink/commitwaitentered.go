@@ -0,0 +1,21 @@
+package tmelink
+
+// CommitWaitEntered is sent by the engine's state machine
+// when it observes majority precommits for a block
+// and enters the commit wait step for that block.
+type CommitWaitEntered struct {
+	Height uint64
+	Round  uint32
+
+	// The hash of the block being committed.
+	BlockHash []byte
+
+	// External is true when this node's own precommit for BlockHash
+	// was not among the precommits that the state machine observed reaching the majority;
+	// that is, the commit was driven by the rest of the network.
+	// This is always the case for nodes that are not validators at Height,
+	// and it is typical for a validator that has fallen slightly behind its peers.
+	//
+	// External is false when this node's precommit contributed to the majority.
+	External bool
+}
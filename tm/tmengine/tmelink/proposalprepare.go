@@ -0,0 +1,22 @@
+package tmelink
+
+// ProposalPrepare is sent by the engine's state machine
+// ahead of a round in which this node is the expected proposer,
+// according to [tmconsensus.ExpectedProposer].
+//
+// For the first round of a height, the notification is sent
+// as soon as the previous height begins committing,
+// which is before the commit wait and the block finalization have completed.
+// For later rounds, or if the previous commit was not observed live,
+// it is sent before the consensus strategy's EnterRound method is called.
+// Either way, the application has lead time to gather the data for its proposed block
+// before the strategy sends a proposal on its ProposalOut channel.
+// Only one notification is sent per height and round.
+//
+// Chains that do not follow the round robin selection of [tmconsensus.ExpectedProposer]
+// will not receive meaningful notifications.
+type ProposalPrepare struct {
+	// The height and round that this node is expected to propose in.
+	Height uint64
+	Round  uint32
+}
@@ -0,0 +1,20 @@
+package tmelink
+
+// ResourceGuardAlert is sent by the engine's state machine
+// when its resource guard pauses or resumes durable writes.
+// See tmengine.WithResourceGuardAlertOutput.
+type ResourceGuardAlert struct {
+	// The state machine's height and round
+	// when the writes were paused or resumed.
+	Height uint64
+	Round  uint32
+
+	// Paused is true when the guard reported that writes are unsafe
+	// and the state machine began holding its actions.
+	// It is false when the guard recovered and the held actions were released.
+	Paused bool
+
+	// The reason reported by the guard when pausing.
+	// Always empty when resuming.
+	Reason string
+}
@@ -0,0 +1,30 @@
+package tmelink
+
+import "github.com/gordian-engine/gordian/tm/tmconsensus"
+
+// StateMachineRoundAction is an action produced by the engine's state machine,
+// reported to the observer set with tmengine.WithActionObserver.
+//
+// Exactly one of PH, Prevote, or Precommit is set.
+type StateMachineRoundAction struct {
+	// The height and round in which the state machine took the action.
+	Height uint64
+	Round  uint32
+
+	// The proposed header the state machine signed and is proposing.
+	PH tmconsensus.ProposedHeader
+
+	// The prevote or precommit the state machine signed.
+	Prevote, Precommit VoteAction
+}
+
+// VoteAction is a prevote or precommit in a [StateMachineRoundAction].
+type VoteAction struct {
+	// The block hash being voted for.
+	// The empty string indicates a vote for nil.
+	TargetHash string
+
+	// The content that was signed, and the resulting signature.
+	SignContent []byte
+	Sig         []byte
+}
@@ -0,0 +1,32 @@
+package tmelink
+
+import (
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+)
+
+// SafetyViolation is emitted by the mirror when it observes
+// a majority of precommits for a block at a height
+// where it has already committed a different block.
+//
+// Under the consensus assumptions, this can only happen
+// if more than one third of the voting power has signed conflicting precommits,
+// so the driver should treat any SafetyViolation as critical:
+// typically by halting, alerting an operator,
+// and preserving the Evidence for later investigation.
+type SafetyViolation struct {
+	// The height at which the conflicting commits were observed.
+	Height uint64
+
+	// The round and hash of the block the mirror committed at Height.
+	CommittedRound     uint32
+	CommittedBlockHash []byte
+
+	// The round and hash of the block that also reached majority precommits at Height.
+	ConflictingRound     uint32
+	ConflictingBlockHash []byte
+
+	// The precommits for ConflictingBlockHash,
+	// with signatures already verified by the mirror.
+	// Proofs only contains the entry for ConflictingBlockHash.
+	Evidence tmconsensus.CommitProof
+}
@@ -0,0 +1,14 @@
+package tmelink
+
+import "github.com/gordian-engine/gordian/tm/tmconsensus"
+
+// ValidatorSetUpdate reports a change to the validator set,
+// as determined by the driver's finalization of a block.
+//
+// When the block at height H is finalized with a validator set
+// that differs from the one finalized at H-1,
+// the update has Height H+2, the first height at which Set is the current validator set.
+type ValidatorSetUpdate struct {
+	Height uint64
+	Set    tmconsensus.ValidatorSet
+}
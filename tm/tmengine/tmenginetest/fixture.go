@@ -152,3 +152,59 @@ func (f *Fixture) SigningOptionMap() OptionMap {
 
 	return m
 }
+
+// BaseConfig returns an [tmengine.EngineConfig]
+// equivalent to the options in [*Fixture.BaseOptionMap].
+func (f *Fixture) BaseConfig() tmengine.EngineConfig {
+	cfg := tmengine.EngineConfig{
+		Genesis: &tmconsensus.ExternalGenesis{
+			ChainID:             "my-chain",
+			InitialHeight:       1,
+			InitialAppState:     new(bytes.Buffer),
+			GenesisValidatorSet: f.Fx.ValSet(),
+		},
+
+		CommittedHeaderStore: f.CommittedHeaderStore,
+		FinalizationStore:    f.FinalizationStore,
+		MirrorStore:          f.MirrorStore,
+		RoundStore:           f.RoundStore,
+		StateMachineStore:    f.StateMachineStore,
+		ValidatorStore:       f.ValidatorStore,
+
+		HashScheme:                        f.Fx.HashScheme,
+		SignatureScheme:                   f.Fx.SignatureScheme,
+		CommonMessageSignatureProofScheme: f.Fx.CommonMessageSignatureProofScheme,
+
+		GossipStrategy:    f.GossipStrategy,
+		ConsensusStrategy: f.ConsensusStrategy,
+
+		InitChainChannel:         f.InitChainCh,
+		BlockFinalizationChannel: f.FinalizeBlockRequests,
+
+		Watchdog: f.Watchdog,
+
+		AssertEnv: gasserttest.DefaultEnv(),
+	}
+
+	// The mock round timer is internal to the engine,
+	// so it can only be set through its option.
+	if err := tmengine.WithInternalRoundTimer(f.RoundTimer)(&cfg); err != nil {
+		panic(err)
+	}
+
+	return cfg
+}
+
+// SigningConfig returns an [tmengine.EngineConfig]
+// equivalent to the options in [*Fixture.SigningOptionMap].
+func (f *Fixture) SigningConfig() tmengine.EngineConfig {
+	cfg := f.BaseConfig()
+
+	cfg.ActionStore = f.ActionStore
+	cfg.Signer = tmconsensus.PassthroughSigner{
+		Signer:          f.Fx.PrivVals[0].Signer,
+		SignatureScheme: f.Fx.SignatureScheme,
+	}
+
+	return cfg
+}
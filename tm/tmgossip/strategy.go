@@ -22,3 +22,16 @@ type Strategy interface {
 	// The engine calls this method when the engine itself is shutting down.
 	Wait()
 }
+
+// PeerCounter is an optional interface for a [Strategy]
+// that can report how many peers it is connected to.
+// A Strategy must implement PeerCounter in order to be used with an engine
+// that waits for a minimum number of peers before voting.
+type PeerCounter interface {
+	// SetPeerCountCallback is called by the engine, before Start,
+	// with a function that the strategy must call
+	// whenever its number of connected peers changes.
+	// The callback never blocks,
+	// and it may be called from any goroutine.
+	SetPeerCountCallback(func(n int))
+}
@@ -240,6 +240,10 @@ func (c *Connection) libp2pConsensusMessageValidator(
 			return pubsub.ValidationIgnore
 		}
 
+		// Attribute the message to the peer who delivered it,
+		// so handlers can apply per-peer limits.
+		ctx = tmconsensus.WithPeerID(ctx, id.String())
+
 		var f gexchange.Feedback
 		switch {
 		case cm.ProposedHeader != nil && h != nil:
@@ -261,7 +265,7 @@ func (c *Connection) exchangeFeedbackToLibp2p(f gexchange.Feedback) pubsub.Valid
 	switch f {
 	case gexchange.FeedbackAccepted:
 		return pubsub.ValidationAccept
-	case gexchange.FeedbackRejected:
+	case gexchange.FeedbackRejected, gexchange.FeedbackTooLarge:
 		return pubsub.ValidationReject
 	case gexchange.FeedbackIgnored:
 		return pubsub.ValidationIgnore
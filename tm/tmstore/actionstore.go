@@ -15,6 +15,14 @@ type ActionStore interface {
 	SavePrevoteAction(ctx context.Context, pubKey gcrypto.PubKey, vt tmconsensus.VoteTarget, sig []byte) error
 	SavePrecommitAction(ctx context.Context, pubKey gcrypto.PubKey, vt tmconsensus.VoteTarget, sig []byte) error
 
+	// HasSignedPrevote reports whether a prevote has been saved
+	// for the given height and round.
+	// The state machine checks this before signing a prevote,
+	// so that it never signs two conflicting prevotes for the same round,
+	// even if it re-enters a round it has already prevoted in.
+	// A round with no saved actions is not an error; it reports false.
+	HasSignedPrevote(ctx context.Context, height uint64, round uint32) (bool, error)
+
 	// LoadActions returns all actions recorded for this round.
 	LoadActions(ctx context.Context, height uint64, round uint32) (RoundActions, error)
 }
@@ -0,0 +1,202 @@
+package tmstore
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gordian-engine/gordian/gcrypto"
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+)
+
+// EncryptedActionStore is an [ActionStore] that encrypts the signatures
+// of every saved action before passing them to an inner ActionStore,
+// and decrypts them when loading actions.
+//
+// Only the signatures are encrypted, using AES-GCM.
+// Heights, rounds, vote targets, and proposed headers are passed to the inner store unmodified,
+// so that the inner store can continue to index on them
+// and to enforce its double action checks.
+//
+// Each signature is sealed with the action type, height, round, signing key,
+// and signed target (the proposed header's hash, or the vote's block hash)
+// as additional data.
+// A ciphertext therefore fails to decrypt if it is moved to a different action,
+// or if any of those plaintext fields are altered in the inner store.
+type EncryptedActionStore struct {
+	inner ActionStore
+	aead  cipher.AEAD
+}
+
+// NewEncryptedActionStore returns a new EncryptedActionStore wrapping inner.
+// The key must be 16, 24, or 32 bytes,
+// to select AES-128, AES-192, or AES-256 respectively.
+//
+// The inner store must not be used directly while the EncryptedActionStore is in use,
+// as any signatures saved directly to the inner store would fail to decrypt.
+func NewEncryptedActionStore(inner ActionStore, key []byte) (*EncryptedActionStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AEAD: %w", err)
+	}
+
+	return &EncryptedActionStore{inner: inner, aead: aead}, nil
+}
+
+func (s *EncryptedActionStore) SaveProposedHeaderAction(ctx context.Context, ph tmconsensus.ProposedHeader) error {
+	sealed, err := s.seal(
+		"proposal", ph.Header.Height, ph.Round,
+		ph.ProposerPubKey, ph.Header.Hash, ph.Signature,
+	)
+	if err != nil {
+		return err
+	}
+	ph.Signature = sealed
+
+	return s.inner.SaveProposedHeaderAction(ctx, ph)
+}
+
+func (s *EncryptedActionStore) SavePrevoteAction(
+	ctx context.Context, pubKey gcrypto.PubKey, vt tmconsensus.VoteTarget, sig []byte,
+) error {
+	sealed, err := s.seal("prevote", vt.Height, vt.Round, pubKey, []byte(vt.BlockHash), sig)
+	if err != nil {
+		return err
+	}
+
+	return s.inner.SavePrevoteAction(ctx, pubKey, vt, sealed)
+}
+
+func (s *EncryptedActionStore) SavePrecommitAction(
+	ctx context.Context, pubKey gcrypto.PubKey, vt tmconsensus.VoteTarget, sig []byte,
+) error {
+	sealed, err := s.seal("precommit", vt.Height, vt.Round, pubKey, []byte(vt.BlockHash), sig)
+	if err != nil {
+		return err
+	}
+
+	return s.inner.SavePrecommitAction(ctx, pubKey, vt, sealed)
+}
+
+func (s *EncryptedActionStore) HasSignedPrevote(ctx context.Context, height uint64, round uint32) (bool, error) {
+	return s.inner.HasSignedPrevote(ctx, height, round)
+}
+
+// LoadActions loads the actions from the inner store and decrypts their signatures.
+// An error is returned if any signature fails to decrypt,
+// such as when the store was written with a different key.
+func (s *EncryptedActionStore) LoadActions(ctx context.Context, height uint64, round uint32) (RoundActions, error) {
+	ra, err := s.inner.LoadActions(ctx, height, round)
+	if err != nil {
+		return ra, err
+	}
+
+	if len(ra.ProposedHeader.Signature) > 0 {
+		sig, err := s.open(
+			"proposal", height, round,
+			ra.ProposedHeader.ProposerPubKey, ra.ProposedHeader.Header.Hash,
+			ra.ProposedHeader.Signature,
+		)
+		if err != nil {
+			return RoundActions{}, err
+		}
+		ra.ProposedHeader.Signature = sig
+	}
+
+	if ra.PrevoteSignature != "" {
+		sig, err := s.open(
+			"prevote", height, round,
+			ra.PubKey, []byte(ra.PrevoteTarget), []byte(ra.PrevoteSignature),
+		)
+		if err != nil {
+			return RoundActions{}, err
+		}
+		ra.PrevoteSignature = string(sig)
+	}
+
+	if ra.PrecommitSignature != "" {
+		sig, err := s.open(
+			"precommit", height, round,
+			ra.PubKey, []byte(ra.PrecommitTarget), []byte(ra.PrecommitSignature),
+		)
+		if err != nil {
+			return RoundActions{}, err
+		}
+		ra.PrecommitSignature = string(sig)
+	}
+
+	return ra, nil
+}
+
+// seal encrypts plaintext for the given action,
+// returning the random nonce followed by the ciphertext.
+func (s *EncryptedActionStore) seal(
+	actionType string, height uint64, round uint32,
+	pubKey gcrypto.PubKey, target, plaintext []byte,
+) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	out := make([]byte, nonceSize, nonceSize+len(plaintext)+s.aead.Overhead())
+	if _, err := rand.Read(out); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce for %s: %w", actionType, err)
+	}
+
+	ad := actionAdditionalData(actionType, height, round, pubKey, target)
+	return s.aead.Seal(out, out, plaintext, ad), nil
+}
+
+// open reverses seal.
+func (s *EncryptedActionStore) open(
+	actionType string, height uint64, round uint32,
+	pubKey gcrypto.PubKey, target, sealed []byte,
+) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, fmt.Errorf(
+			"failed to decrypt %s signature at %d/%d: ciphertext too short",
+			actionType, height, round,
+		)
+	}
+
+	nonce, ciphertext := sealed[:nonceSize], sealed[nonceSize:]
+	ad := actionAdditionalData(actionType, height, round, pubKey, target)
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to decrypt %s signature at %d/%d: %w",
+			actionType, height, round, err,
+		)
+	}
+	return plaintext, nil
+}
+
+// actionAdditionalData returns the additional authenticated data
+// binding a sealed signature to its action.
+// The variable-length fields are length-prefixed,
+// so that no two distinct actions produce the same additional data.
+func actionAdditionalData(
+	actionType string, height uint64, round uint32,
+	pubKey gcrypto.PubKey, target []byte,
+) []byte {
+	var pubKeyBytes []byte
+	if pubKey != nil {
+		pubKeyBytes = pubKey.PubKeyBytes()
+	}
+
+	ad := make([]byte, 0, len(actionType)+8+4+4+len(pubKeyBytes)+4+len(target))
+	ad = append(ad, actionType...)
+	ad = binary.BigEndian.AppendUint64(ad, height)
+	ad = binary.BigEndian.AppendUint32(ad, round)
+	ad = binary.BigEndian.AppendUint32(ad, uint32(len(pubKeyBytes)))
+	ad = append(ad, pubKeyBytes...)
+	ad = binary.BigEndian.AppendUint32(ad, uint32(len(target)))
+	ad = append(ad, target...)
+	return ad
+}
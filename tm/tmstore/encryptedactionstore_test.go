@@ -0,0 +1,134 @@
+package tmstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/gordian-engine/gordian/tm/tmstore"
+	"github.com/gordian-engine/gordian/tm/tmstore/tmmemstore"
+	"github.com/gordian-engine/gordian/tm/tmstore/tmstoretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedActionStoreCompliance(t *testing.T) {
+	t.Parallel()
+
+	tmstoretest.TestActionStoreCompliance(t, func(func(func())) (tmstore.ActionStore, error) {
+		return tmstore.NewEncryptedActionStore(
+			tmmemstore.NewActionStore(), []byte("0123456789abcdef0123456789abcdef"),
+		)
+	})
+}
+
+func TestEncryptedActionStore(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(2)
+	ph := fx.NextProposedHeader([]byte("app_data_1"), 0)
+	fx.SignProposal(ctx, &ph, 0)
+
+	vt := tmconsensus.VoteTarget{Height: 1, BlockHash: string(ph.Header.Hash)}
+	prevoteSig := fx.PrevoteSignature(ctx, vt, 0)
+	precommitSig := fx.PrecommitSignature(ctx, vt, 0)
+	pubKey := fx.ValidatorPubKey(0)
+
+	inner := tmmemstore.NewActionStore()
+	key := []byte("0123456789abcdef0123456789abcdef")
+	s, err := tmstore.NewEncryptedActionStore(inner, key)
+	require.NoError(t, err)
+
+	require.NoError(t, s.SaveProposedHeaderAction(ctx, ph))
+	require.NoError(t, s.SavePrevoteAction(ctx, pubKey, vt, prevoteSig))
+	require.NoError(t, s.SavePrecommitAction(ctx, pubKey, vt, precommitSig))
+
+	t.Run("inner store does not hold plaintext signatures", func(t *testing.T) {
+		ra, err := inner.LoadActions(ctx, 1, 0)
+		require.NoError(t, err)
+
+		require.NotEqual(t, ph.Signature, ra.ProposedHeader.Signature)
+		require.NotEqual(t, string(prevoteSig), ra.PrevoteSignature)
+		require.NotEqual(t, string(precommitSig), ra.PrecommitSignature)
+
+		// Everything other than the signatures is stored as-is.
+		require.Equal(t, ph.Header, ra.ProposedHeader.Header)
+		require.Equal(t, vt.BlockHash, ra.PrevoteTarget)
+		require.Equal(t, vt.BlockHash, ra.PrecommitTarget)
+	})
+
+	t.Run("right key round trips", func(t *testing.T) {
+		s2, err := tmstore.NewEncryptedActionStore(inner, key)
+		require.NoError(t, err)
+
+		ra, err := s2.LoadActions(ctx, 1, 0)
+		require.NoError(t, err)
+
+		require.Equal(t, ph, ra.ProposedHeader)
+		require.Equal(t, string(prevoteSig), ra.PrevoteSignature)
+		require.Equal(t, string(precommitSig), ra.PrecommitSignature)
+	})
+
+	t.Run("wrong key fails to load", func(t *testing.T) {
+		s2, err := tmstore.NewEncryptedActionStore(inner, []byte("fedcba9876543210fedcba9876543210"))
+		require.NoError(t, err)
+
+		_, err = s2.LoadActions(ctx, 1, 0)
+		require.ErrorContains(t, err, "failed to decrypt")
+	})
+
+	t.Run("altered plaintext fields fail to load", func(t *testing.T) {
+		sealed, err := inner.LoadActions(ctx, 1, 0)
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			name    string
+			save    func(tmstore.ActionStore) error
+			errPart string
+		}{
+			{
+				name: "proposed header hash",
+				save: func(as tmstore.ActionStore) error {
+					altered := sealed.ProposedHeader
+					altered.Header.Hash = []byte("other_hash")
+					return as.SaveProposedHeaderAction(ctx, altered)
+				},
+				errPart: "failed to decrypt proposal",
+			},
+			{
+				name: "prevote target",
+				save: func(as tmstore.ActionStore) error {
+					nilVT := tmconsensus.VoteTarget{Height: 1}
+					return as.SavePrevoteAction(ctx, pubKey, nilVT, []byte(sealed.PrevoteSignature))
+				},
+				errPart: "failed to decrypt prevote",
+			},
+			{
+				name: "precommit pub key",
+				save: func(as tmstore.ActionStore) error {
+					return as.SavePrecommitAction(ctx, fx.ValidatorPubKey(1), vt, []byte(sealed.PrecommitSignature))
+				},
+				errPart: "failed to decrypt precommit",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				altered := tmmemstore.NewActionStore()
+				require.NoError(t, tc.save(altered))
+
+				s2, err := tmstore.NewEncryptedActionStore(altered, key)
+				require.NoError(t, err)
+
+				_, err = s2.LoadActions(ctx, 1, 0)
+				require.ErrorContains(t, err, tc.errPart)
+			})
+		}
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		_, err := tmstore.NewEncryptedActionStore(inner, []byte("too_short"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,17 @@
+package tmstore
+
+import "context"
+
+// GenesisAnnotationStore persists the per-validator annotations
+// from [tmconsensus.ExternalGenesis.GenesisValidatorAnnotations],
+// so that they remain available after the chain has been initialized.
+type GenesisAnnotationStore interface {
+	// SaveGenesisValidatorAnnotations saves the annotations,
+	// keyed by validator public key hash.
+	// It is only expected to be called once, during chain initialization.
+	SaveGenesisValidatorAnnotations(ctx context.Context, annotations map[string][]byte) error
+
+	// LoadGenesisValidatorAnnotations returns the previously saved annotations.
+	// If no annotations were saved, it returns ErrStoreUninitialized.
+	LoadGenesisValidatorAnnotations(ctx context.Context) (map[string][]byte, error)
+}
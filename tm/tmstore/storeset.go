@@ -0,0 +1,224 @@
+package tmstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+)
+
+// StoreSet is the collection of stores holding the network-derived state
+// of an engine, that is, the state that can be moved between store implementations
+// (for instance, when migrating from in-memory stores to SQLite stores)
+// through [CopyAllStores].
+//
+// The action and state machine stores are deliberately excluded,
+// as they reflect local decisions of a single validator
+// and should not be transplanted to a different store.
+type StoreSet struct {
+	CommittedHeaderStore CommittedHeaderStore
+	FinalizationStore    FinalizationStore
+	MirrorStore          MirrorStore
+	RoundStore           RoundStore
+	ValidatorStore       ValidatorStore
+
+	// Optional; only copied if set on the source.
+	GenesisAnnotationStore GenesisAnnotationStore
+}
+
+// CopyAllStores copies the committed headers, finalizations, round state, validators,
+// and genesis validator annotations from src to dst.
+// dst is expected to be empty.
+//
+// The store interfaces do not offer iteration,
+// so the heights to copy are discovered from src's MirrorStore:
+// starting at the voting height, CopyAllStores walks down one height at a time,
+// until it reaches a height below the committing height with nothing stored.
+// At each height, every round up to the highest known round for that height is copied.
+// If src's MirrorStore is uninitialized, there is nothing to copy and CopyAllStores returns nil.
+//
+// The network height and round is copied to dst's MirrorStore last,
+// so that an interrupted copy does not leave dst appearing initialized.
+func CopyAllStores(ctx context.Context, src, dst StoreSet) error {
+	vh, vr, ch, cr, err := src.MirrorStore.NetworkHeightRound(ctx)
+	if err != nil {
+		if errors.Is(err, ErrStoreUninitialized) {
+			return nil
+		}
+		return fmt.Errorf("failed to load network height and round: %w", err)
+	}
+
+	for h := vh; ; h-- {
+		var maxRound uint32
+		switch h {
+		case vh:
+			maxRound = vr
+		case ch:
+			maxRound = cr
+		}
+
+		found, err := copyHeight(ctx, src, dst, h, maxRound)
+		if err != nil {
+			return err
+		}
+
+		if (!found && h < ch) || h == 0 {
+			break
+		}
+	}
+
+	if err := copyGenesisAnnotations(ctx, src, dst); err != nil {
+		return err
+	}
+
+	if err := dst.MirrorStore.SetNetworkHeightRound(ctx, vh, vr, ch, cr); err != nil {
+		return fmt.Errorf("failed to save network height and round: %w", err)
+	}
+
+	return nil
+}
+
+// copyHeight copies everything stored at height h from src to dst,
+// checking every round up to at least maxRound.
+// It reports whether anything was found at h.
+func copyHeight(
+	ctx context.Context,
+	src, dst StoreSet,
+	h uint64, maxRound uint32,
+) (found bool, err error) {
+	chd, err := src.CommittedHeaderStore.LoadCommittedHeader(ctx, h)
+	if err == nil {
+		found = true
+		maxRound = max(maxRound, chd.Proof.Round)
+
+		if err := copyValidatorSet(ctx, dst.ValidatorStore, chd.Header.ValidatorSet); err != nil {
+			return false, err
+		}
+		if err := copyValidatorSet(ctx, dst.ValidatorStore, chd.Header.NextValidatorSet); err != nil {
+			return false, err
+		}
+		if err := dst.CommittedHeaderStore.SaveCommittedHeader(ctx, chd); err != nil {
+			return false, fmt.Errorf("failed to save committed header at height %d: %w", h, err)
+		}
+	} else if !errors.Is(err, tmconsensus.HeightUnknownError{Want: h}) {
+		return false, fmt.Errorf("failed to load committed header at height %d: %w", h, err)
+	}
+
+	fRound, blockHash, valSet, appStateHash, err := src.FinalizationStore.LoadFinalizationByHeight(ctx, h)
+	if err == nil {
+		found = true
+		maxRound = max(maxRound, fRound)
+
+		if err := copyValidatorSet(ctx, dst.ValidatorStore, valSet); err != nil {
+			return false, err
+		}
+		if err := dst.FinalizationStore.SaveFinalization(
+			ctx, h, fRound, blockHash, valSet, appStateHash,
+		); err != nil {
+			return false, fmt.Errorf("failed to save finalization at height %d: %w", h, err)
+		}
+	} else if !errors.Is(err, tmconsensus.HeightUnknownError{Want: h}) {
+		return false, fmt.Errorf("failed to load finalization at height %d: %w", h, err)
+	}
+
+	// A replayed header may be reported in more than one round,
+	// but it must only be saved once.
+	replayed := make(map[string]struct{})
+	for r := uint32(0); r <= maxRound; r++ {
+		phs, prevotes, precommits, err := src.RoundStore.LoadRoundState(ctx, h, r)
+		if err != nil {
+			if errors.Is(err, tmconsensus.RoundUnknownError{WantHeight: h, WantRound: r}) {
+				continue
+			}
+			return false, fmt.Errorf("failed to load round state at %d/%d: %w", h, r, err)
+		}
+		found = true
+
+		for _, ph := range phs {
+			if ph.ProposerPubKey == nil {
+				// Replayed headers are reported without any proposal details.
+				if _, ok := replayed[string(ph.Header.Hash)]; ok {
+					continue
+				}
+				replayed[string(ph.Header.Hash)] = struct{}{}
+
+				if err := dst.RoundStore.SaveRoundReplayedHeader(ctx, ph.Header); err != nil {
+					return false, fmt.Errorf(
+						"failed to save replayed header at %d/%d: %w", h, r, err,
+					)
+				}
+				continue
+			}
+
+			if err := dst.RoundStore.SaveRoundProposedHeader(ctx, ph); err != nil {
+				return false, fmt.Errorf(
+					"failed to save proposed header at %d/%d: %w", h, r, err,
+				)
+			}
+		}
+
+		if prevotes.BlockSignatures != nil {
+			if err := dst.RoundStore.OverwriteRoundPrevoteProofs(ctx, h, r, prevotes); err != nil {
+				return false, fmt.Errorf("failed to save prevotes at %d/%d: %w", h, r, err)
+			}
+		}
+		if precommits.BlockSignatures != nil {
+			if err := dst.RoundStore.OverwriteRoundPrecommitProofs(ctx, h, r, precommits); err != nil {
+				return false, fmt.Errorf("failed to save precommits at %d/%d: %w", h, r, err)
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// copyGenesisAnnotations copies the genesis validator annotations from src to dst,
+// if src has a GenesisAnnotationStore with saved annotations.
+func copyGenesisAnnotations(ctx context.Context, src, dst StoreSet) error {
+	if src.GenesisAnnotationStore == nil {
+		return nil
+	}
+
+	annotations, err := src.GenesisAnnotationStore.LoadGenesisValidatorAnnotations(ctx)
+	if err != nil {
+		if errors.Is(err, ErrStoreUninitialized) {
+			return nil
+		}
+		return fmt.Errorf("failed to load genesis validator annotations: %w", err)
+	}
+
+	if dst.GenesisAnnotationStore == nil {
+		return errors.New(
+			"source has genesis validator annotations but destination has no GenesisAnnotationStore",
+		)
+	}
+
+	if err := dst.GenesisAnnotationStore.SaveGenesisValidatorAnnotations(ctx, annotations); err != nil {
+		return fmt.Errorf("failed to save genesis validator annotations: %w", err)
+	}
+
+	return nil
+}
+
+// copyValidatorSet saves the public keys and vote powers of vs to dst,
+// tolerating sets that were already saved.
+func copyValidatorSet(ctx context.Context, dst ValidatorStore, vs tmconsensus.ValidatorSet) error {
+	if len(vs.Validators) == 0 {
+		return nil
+	}
+
+	if _, err := dst.SavePubKeys(ctx, tmconsensus.ValidatorsToPubKeys(vs.Validators)); err != nil {
+		if !errors.As(err, new(PubKeysAlreadyExistError)) {
+			return fmt.Errorf("failed to save public keys: %w", err)
+		}
+	}
+
+	if _, err := dst.SaveVotePowers(ctx, tmconsensus.ValidatorsToVotePowers(vs.Validators)); err != nil {
+		if !errors.As(err, new(VotePowersAlreadyExistError)) {
+			return fmt.Errorf("failed to save vote powers: %w", err)
+		}
+	}
+
+	return nil
+}
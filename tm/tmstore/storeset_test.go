@@ -0,0 +1,153 @@
+package tmstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmconsensus"
+	"github.com/gordian-engine/gordian/tm/tmconsensus/tmconsensustest"
+	"github.com/gordian-engine/gordian/tm/tmstore"
+	"github.com/gordian-engine/gordian/tm/tmstore/tmmemstore"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemStoreSet() tmstore.StoreSet {
+	return tmstore.StoreSet{
+		CommittedHeaderStore: tmmemstore.NewCommittedHeaderStore(),
+		FinalizationStore:    tmmemstore.NewFinalizationStore(),
+		MirrorStore:          tmmemstore.NewMirrorStore(),
+		RoundStore:           tmmemstore.NewRoundStore(),
+		ValidatorStore:       tmmemstore.NewValidatorStore(tmconsensustest.SimpleHashScheme{}),
+
+		GenesisAnnotationStore: tmmemstore.NewGenesisAnnotationStore(),
+	}
+}
+
+func TestCopyAllStores(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fx := tmconsensustest.NewStandardFixture(2)
+	src := newMemStoreSet()
+
+	// Height 1 commits in round 0.
+	ph1 := fx.NextProposedHeader([]byte("app_data_1"), 0)
+	fx.SignProposal(ctx, &ph1, 0)
+	require.NoError(t, src.RoundStore.SaveRoundProposedHeader(ctx, ph1))
+
+	voteMap1 := map[string][]int{string(ph1.Header.Hash): {0, 1}}
+	require.NoError(t, src.RoundStore.OverwriteRoundPrevoteProofs(
+		ctx, 1, 0, fx.SparsePrevoteSignatureCollection(ctx, 1, 0, voteMap1),
+	))
+	require.NoError(t, src.RoundStore.OverwriteRoundPrecommitProofs(
+		ctx, 1, 0, fx.SparsePrecommitSignatureCollection(ctx, 1, 0, voteMap1),
+	))
+
+	fx.CommitBlock(ph1.Header, []byte("app_state_1"), 0, fx.PrecommitProofMap(ctx, 1, 0, voteMap1))
+	require.NoError(t, src.FinalizationStore.SaveFinalization(
+		ctx, 1, 0, string(ph1.Header.Hash), fx.ValSet(), "app_state_1",
+	))
+
+	// Height 2 precommits nil in round 0 and commits in round 1.
+	ph2 := fx.NextProposedHeader([]byte("app_data_2"), 0)
+	fx.SignProposal(ctx, &ph2, 0)
+	require.NoError(t, src.RoundStore.SaveRoundProposedHeader(ctx, ph2))
+	require.NoError(t, src.CommittedHeaderStore.SaveCommittedHeader(ctx, tmconsensus.CommittedHeader{
+		Header: ph1.Header,
+		Proof:  ph2.Header.PrevCommitProof,
+	}))
+
+	nilVoteMap := map[string][]int{"": {0, 1}}
+	require.NoError(t, src.RoundStore.OverwriteRoundPrecommitProofs(
+		ctx, 2, 0, fx.SparsePrecommitSignatureCollection(ctx, 2, 0, nilVoteMap),
+	))
+
+	ph2.Round = 1
+	fx.SignProposal(ctx, &ph2, 1)
+	require.NoError(t, src.RoundStore.SaveRoundProposedHeader(ctx, ph2))
+	voteMap2 := map[string][]int{string(ph2.Header.Hash): {0, 1}}
+	require.NoError(t, src.RoundStore.OverwriteRoundPrecommitProofs(
+		ctx, 2, 1, fx.SparsePrecommitSignatureCollection(ctx, 2, 1, voteMap2),
+	))
+
+	// Height 3 is voting, with only a proposed header so far.
+	fx.CommitBlock(ph2.Header, []byte("app_state_2"), 1, fx.PrecommitProofMap(ctx, 2, 1, voteMap2))
+	ph3 := fx.NextProposedHeader([]byte("app_data_3"), 1)
+	fx.SignProposal(ctx, &ph3, 1)
+	require.NoError(t, src.RoundStore.SaveRoundProposedHeader(ctx, ph3))
+
+	require.NoError(t, src.MirrorStore.SetNetworkHeightRound(ctx, 3, 0, 2, 1))
+
+	pubKeyHash, powHash := fx.ValidatorHashes()
+	_, err := src.ValidatorStore.SavePubKeys(ctx, tmconsensus.ValidatorsToPubKeys(fx.Vals()))
+	require.NoError(t, err)
+	_, err = src.ValidatorStore.SaveVotePowers(ctx, tmconsensus.ValidatorsToVotePowers(fx.Vals()))
+	require.NoError(t, err)
+
+	annotations := map[string][]byte{
+		string(fx.Vals()[0].PubKey.PubKeyBytes()): []byte("moniker=val0"),
+	}
+	require.NoError(t, src.GenesisAnnotationStore.SaveGenesisValidatorAnnotations(ctx, annotations))
+
+	dst := newMemStoreSet()
+	require.NoError(t, tmstore.CopyAllStores(ctx, src, dst))
+
+	vh, vr, ch, cr, err := dst.MirrorStore.NetworkHeightRound(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), vh)
+	require.Zero(t, vr)
+	require.Equal(t, uint64(2), ch)
+	require.Equal(t, uint32(1), cr)
+
+	for h := uint64(1); h <= 3; h++ {
+		wantCH, wantErr := src.CommittedHeaderStore.LoadCommittedHeader(ctx, h)
+		gotCH, gotErr := dst.CommittedHeaderStore.LoadCommittedHeader(ctx, h)
+		require.Equal(t, wantErr, gotErr)
+		require.Equal(t, wantCH, gotCH)
+
+		wantR, wantHash, wantVS, wantApp, wantErr := src.FinalizationStore.LoadFinalizationByHeight(ctx, h)
+		gotR, gotHash, gotVS, gotApp, gotErr := dst.FinalizationStore.LoadFinalizationByHeight(ctx, h)
+		require.Equal(t, wantErr, gotErr)
+		require.Equal(t, wantR, gotR)
+		require.Equal(t, wantHash, gotHash)
+		require.True(t, wantVS.Equal(gotVS))
+		require.Equal(t, wantApp, gotApp)
+
+		for r := uint32(0); r <= 1; r++ {
+			wantPHs, wantPrevotes, wantPrecommits, wantErr := src.RoundStore.LoadRoundState(ctx, h, r)
+			gotPHs, gotPrevotes, gotPrecommits, gotErr := dst.RoundStore.LoadRoundState(ctx, h, r)
+			require.Equal(t, wantErr, gotErr)
+			require.Equal(t, wantPHs, gotPHs)
+			require.Equal(t, wantPrevotes, gotPrevotes)
+			require.Equal(t, wantPrecommits, gotPrecommits)
+		}
+	}
+
+	vals, err := dst.ValidatorStore.LoadValidators(ctx, pubKeyHash, powHash)
+	require.NoError(t, err)
+	require.True(t, tmconsensus.ValidatorSlicesEqual(fx.Vals(), vals))
+
+	gotAnnotations, err := dst.GenesisAnnotationStore.LoadGenesisValidatorAnnotations(ctx)
+	require.NoError(t, err)
+	require.Equal(t, annotations, gotAnnotations)
+
+	t.Run("uninitialized source", func(t *testing.T) {
+		dst := newMemStoreSet()
+		require.NoError(t, tmstore.CopyAllStores(ctx, newMemStoreSet(), dst))
+
+		_, _, _, _, err := dst.MirrorStore.NetworkHeightRound(ctx)
+		require.ErrorIs(t, err, tmstore.ErrStoreUninitialized)
+	})
+
+	t.Run("genesis annotations without a destination store", func(t *testing.T) {
+		dst := newMemStoreSet()
+		dst.GenesisAnnotationStore = nil
+		require.Error(t, tmstore.CopyAllStores(ctx, src, dst))
+
+		// The copy did not complete, so dst must not appear initialized.
+		_, _, _, _, err := dst.MirrorStore.NetworkHeightRound(ctx)
+		require.ErrorIs(t, err, tmstore.ErrStoreUninitialized)
+	})
+}
@@ -106,6 +106,14 @@ func (s *ActionStore) SavePrecommitAction(ctx context.Context, pubKey gcrypto.Pu
 	return nil
 }
 
+func (s *ActionStore) HasSignedPrevote(ctx context.Context, height uint64, round uint32) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ra, ok := s.ras[hr{H: height, R: round}]
+	return ok && ra.PrevoteSignature != "", nil
+}
+
 // LoadActions returns all actions recorded for this round.
 func (s *ActionStore) LoadActions(ctx context.Context, height uint64, round uint32) (tmstore.RoundActions, error) {
 	s.mu.RLock()
@@ -0,0 +1,54 @@
+package tmmemstore
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/gordian-engine/gordian/tm/tmstore"
+)
+
+// GenesisAnnotationStore is an in-memory implementation of [tmstore.GenesisAnnotationStore].
+type GenesisAnnotationStore struct {
+	mu sync.RWMutex
+
+	// Nil until saved.
+	annotations map[string][]byte
+}
+
+func NewGenesisAnnotationStore() *GenesisAnnotationStore {
+	return new(GenesisAnnotationStore)
+}
+
+func (s *GenesisAnnotationStore) SaveGenesisValidatorAnnotations(
+	_ context.Context, annotations map[string][]byte,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.annotations = cloneAnnotations(annotations)
+	return nil
+}
+
+func (s *GenesisAnnotationStore) LoadGenesisValidatorAnnotations(
+	_ context.Context,
+) (map[string][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.annotations == nil {
+		return nil, tmstore.ErrStoreUninitialized
+	}
+
+	return cloneAnnotations(s.annotations), nil
+}
+
+// cloneAnnotations returns a deep copy of m that is never nil,
+// so that the store does not share memory with its callers.
+func cloneAnnotations(m map[string][]byte) map[string][]byte {
+	out := make(map[string][]byte, len(m))
+	for k, v := range m {
+		out[k] = bytes.Clone(v)
+	}
+	return out
+}
@@ -0,0 +1,17 @@
+package tmmemstore_test
+
+import (
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmstore"
+	"github.com/gordian-engine/gordian/tm/tmstore/tmmemstore"
+	"github.com/gordian-engine/gordian/tm/tmstore/tmstoretest"
+)
+
+func TestMemGenesisAnnotationStore(t *testing.T) {
+	t.Parallel()
+
+	tmstoretest.TestGenesisAnnotationStoreCompliance(t, func(func(func())) (tmstore.GenesisAnnotationStore, error) {
+		return tmmemstore.NewGenesisAnnotationStore(), nil
+	})
+}
@@ -91,8 +91,24 @@ func TestActionStoreCompliance(t *testing.T, f ActionStoreFactory) {
 		attemptToSavePubKeys(t, ctx, s, ph1.Header.ValidatorSet.Validators)
 
 		pubKey := fx.ValidatorPubKey(0)
+
+		has, err := s.HasSignedPrevote(ctx, 1, 2)
+		require.NoError(t, err)
+		require.False(t, has)
+
 		require.NoError(t, s.SavePrevoteAction(ctx, pubKey, vt, sig))
 
+		t.Run("reported by HasSignedPrevote", func(t *testing.T) {
+			has, err := s.HasSignedPrevote(ctx, 1, 2)
+			require.NoError(t, err)
+			require.True(t, has)
+
+			// Other rounds are unaffected.
+			has, err = s.HasSignedPrevote(ctx, 1, 3)
+			require.NoError(t, err)
+			require.False(t, has)
+		})
+
 		t.Run("round trip", func(t *testing.T) {
 			ra, err := s.LoadActions(ctx, 1, 2)
 			require.NoError(t, err)
@@ -146,6 +162,12 @@ func TestActionStoreCompliance(t *testing.T, f ActionStoreFactory) {
 		pubKey := fx.ValidatorPubKey(0)
 		require.NoError(t, s.SavePrecommitAction(ctx, pubKey, vt, sig))
 
+		t.Run("precommit does not count as signed prevote", func(t *testing.T) {
+			has, err := s.HasSignedPrevote(ctx, 1, 2)
+			require.NoError(t, err)
+			require.False(t, has)
+		})
+
 		t.Run("round trip", func(t *testing.T) {
 			ra, err := s.LoadActions(ctx, 1, 2)
 			require.NoError(t, err)
@@ -0,0 +1,69 @@
+package tmstoretest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gordian-engine/gordian/tm/tmstore"
+	"github.com/stretchr/testify/require"
+)
+
+type GenesisAnnotationStoreFactory func(cleanup func(func())) (tmstore.GenesisAnnotationStore, error)
+
+func TestGenesisAnnotationStoreCompliance(t *testing.T, f GenesisAnnotationStoreFactory) {
+	t.Run("returns ErrStoreUninitialized before save", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		s, err := f(t.Cleanup)
+		require.NoError(t, err)
+
+		_, err = s.LoadGenesisValidatorAnnotations(ctx)
+		require.ErrorIs(t, err, tmstore.ErrStoreUninitialized)
+	})
+
+	t.Run("returns saved annotations", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		s, err := f(t.Cleanup)
+		require.NoError(t, err)
+
+		in := map[string][]byte{
+			"key_hash_0": []byte("moniker=alice"),
+			"key_hash_1": []byte("moniker=bob"),
+		}
+		require.NoError(t, s.SaveGenesisValidatorAnnotations(ctx, in))
+
+		// Modifying the input after saving does not affect the store.
+		in["key_hash_0"][0] = 'M'
+		in["key_hash_2"] = []byte("moniker=carol")
+
+		got, err := s.LoadGenesisValidatorAnnotations(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string][]byte{
+			"key_hash_0": []byte("moniker=alice"),
+			"key_hash_1": []byte("moniker=bob"),
+		}, got)
+	})
+
+	t.Run("empty annotations are distinct from uninitialized", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		s, err := f(t.Cleanup)
+		require.NoError(t, err)
+
+		require.NoError(t, s.SaveGenesisValidatorAnnotations(ctx, nil))
+
+		got, err := s.LoadGenesisValidatorAnnotations(ctx)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+}
@@ -186,6 +186,9 @@ func confirmStoreInterfaces[S any]() {
 	if _, ok := any(s).(tmstore.FinalizationStore); ok {
 		n++
 	}
+	if _, ok := any(s).(tmstore.GenesisAnnotationStore); ok {
+		n++
+	}
 	if _, ok := any(s).(tmstore.MirrorStore); ok {
 		n++
 	}